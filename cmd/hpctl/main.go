@@ -0,0 +1,501 @@
+// Command hpctl runs a single hosted cluster operation through the same helpers as the suites, for e.g. to reproduce a failure without editing a spec.
+// The provider is selected by PROVIDER, and the Rancher host, admin token and cluster templates are read from CATTLE_TEST_CONFIG.
+//
+//	PROVIDER=aks CATTLE_TEST_CONFIG=cattle-config-provisioning.yaml go run ./cmd/hpctl create -name hp-ci-debug
+//	PROVIDER=aks CATTLE_TEST_CONFIG=cattle-config-provisioning.yaml go run ./cmd/hpctl scale -name hp-ci-debug -nodes 3
+//
+// The cloud credentials and locations are read from the same env vars as the tests, for e.g. AKS_SUBSCRIPTION_ID, EKS_REGION and GKE_PROJECT_ID.
+// The resources it creates are kept; use the delete subcommand or cmd/janitor to remove them.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/clusters"
+	"github.com/rancher/shepherd/pkg/config"
+	"github.com/rancher/shepherd/pkg/session"
+
+	_ "github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
+	_ "github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
+	_ "github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// hpctl holds what every subcommand needs
+type hpctl struct {
+	provider helpers.HostedProvider
+	client   *rancher.Client
+}
+
+type command struct {
+	name  string
+	usage string
+	run   func(h *hpctl, ctx context.Context, args []string) error
+	// local commands do not need the provider nor Rancher
+	local bool
+}
+
+var commands = []command{
+	{"create", "provision a cluster via Rancher", (*hpctl).create, false},
+	{"import", "import a cluster, optionally creating it on the cloud provider first", (*hpctl).importCluster, false},
+	{"scale", "modify the node count of all the nodepools/nodegroups", (*hpctl).scale, false},
+	{"add-pool", "add nodepools/nodegroups", (*hpctl).addPool, false},
+	{"delete-pool", "delete a nodepool/nodegroup", (*hpctl).deletePool, false},
+	{"upgrade", "upgrade the k8s version of the control plane and of the nodepools/nodegroups", (*hpctl).upgrade, false},
+	{"sync-check", "compare the cluster config, its upstream spec and the cloud; exits with 1 if they differ", (*hpctl).syncCheck, false},
+	{"delete", "delete a cluster from Rancher, and optionally from the cloud provider", (*hpctl).delete, false},
+	{"versions", "list the default k8s versions, or the versions a cluster can be upgraded to", (*hpctl).versions, false},
+	{"config", "check: print the effective configuration with the secrets redacted and validate it for a suite", (*hpctl).config, true},
+}
+
+// errUsage is returned by the subcommands whose flags are invalid
+var errUsage = errors.New("invalid usage")
+
+// errDrift is returned by sync-check when the cluster has drifted
+var errDrift = errors.New("the cluster has drifted")
+
+// errInvalidConfig is returned by config check when the configuration is not valid for the suite
+var errInvalidConfig = errors.New("invalid configuration")
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == flag.Arg(0) {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	// an interrupted operation stops waiting and stops the cloud CLIs instead of leaving them running
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	h := &hpctl{}
+	if !cmd.local {
+		var err error
+		if h.provider, err = helpers.CurrentHostedProvider(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		if helpers.Config.CattleTestConfig == "" {
+			fmt.Fprintf(os.Stderr, "%s is not set\n", config.ConfigEnvironmentKey)
+			os.Exit(2)
+		}
+		if h.client, err = rancher.NewClient("", session.NewSession()); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create the Rancher client: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	err := cmd.run(h, ctx, flag.Args()[1:])
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	case errors.Is(err, errDrift), errors.Is(err, errInvalidConfig):
+		os.Exit(1)
+	default:
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: PROVIDER=<aks|eks|gke> CATTLE_TEST_CONFIG=<config> hpctl <command> [flags]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun hpctl <command> -h for the flags of a command.\n")
+}
+
+// newFlagSet returns the flag set of a subcommand with the -name flag every subcommand has
+func newFlagSet(name string, nameRequired bool) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	usage := "name of the cluster"
+	if !nameRequired {
+		usage += " (optional)"
+	}
+	return fs, fs.String("name", "", usage)
+}
+
+// parse parses the flags of a subcommand and checks that -name is given if required
+func parse(fs *flag.FlagSet, args []string, clusterName *string, nameRequired bool) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(fs.Output(), "unexpected argument %q\n", fs.Arg(0))
+		fs.Usage()
+		return errUsage
+	}
+	if nameRequired && *clusterName == "" {
+		fmt.Fprintln(fs.Output(), "-name is required")
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+func (h *hpctl) create(ctx context.Context, args []string) error {
+	fs, clusterName := newFlagSet("create", true)
+	cloudCredentialID := fs.String("cloud-credential", "", "ID of the cloud credential, i.e. <namespace>:<name>; a new one is created if empty")
+	k8sVersion := fs.String("version", "", "k8s version of the cluster; defaults to the version used by the tests")
+	wait := fs.Bool("wait", true, "wait for the cluster to be active")
+	if err := parse(fs, args, clusterName, true); err != nil {
+		return err
+	}
+
+	if err := h.defaultCloudCredential(cloudCredentialID); err != nil {
+		return err
+	}
+	if err := h.defaultK8sVersion(ctx, k8sVersion, *cloudCredentialID); err != nil {
+		return err
+	}
+	cluster, err := h.provider.CreateHostedCluster(ctx, h.client, *clusterName, *cloudCredentialID, *k8sVersion)
+	if err != nil {
+		return err
+	}
+	return h.done(ctx, cluster, *wait)
+}
+
+func (h *hpctl) importCluster(ctx context.Context, args []string) error {
+	fs, clusterName := newFlagSet("import", true)
+	cloudCredentialID := fs.String("cloud-credential", "", "ID of the cloud credential, i.e. <namespace>:<name>; a new one is created if empty")
+	createOnCloud := fs.Bool("create-on-cloud", false, "create the cluster on the cloud provider before importing it")
+	k8sVersion := fs.String("version", "", "k8s version of the cluster created with -create-on-cloud; defaults to the version used by the tests")
+	nodeCount := fs.Int64("nodes", 1, "node count of the cluster created with -create-on-cloud")
+	wait := fs.Bool("wait", true, "wait for the cluster to be active")
+	if err := parse(fs, args, clusterName, true); err != nil {
+		return err
+	}
+
+	if err := h.defaultCloudCredential(cloudCredentialID); err != nil {
+		return err
+	}
+	if *createOnCloud {
+		if err := h.defaultK8sVersion(ctx, k8sVersion, *cloudCredentialID); err != nil {
+			return err
+		}
+		if err := h.provider.CreateClusterOnCloud(ctx, *clusterName, *k8sVersion, *nodeCount); err != nil {
+			return err
+		}
+	}
+	cluster, err := h.provider.ImportHostedCluster(ctx, h.client, *clusterName, *cloudCredentialID)
+	if err != nil {
+		return err
+	}
+	return h.done(ctx, cluster, *wait)
+}
+
+func (h *hpctl) scale(ctx context.Context, args []string) error {
+	fs, clusterName := newFlagSet("scale", true)
+	nodeCount := fs.Int64("nodes", -1, "node count of the nodepools/nodegroups")
+	wait, check := updateFlags(fs)
+	if err := parse(fs, args, clusterName, true); err != nil {
+		return err
+	}
+	if *nodeCount < 0 {
+		fmt.Fprintln(fs.Output(), "-nodes is required")
+		fs.Usage()
+		return errUsage
+	}
+
+	cluster, err := h.getCluster(*clusterName)
+	if err != nil {
+		return err
+	}
+	cluster, err = h.provider.ScaleNodePool(ctx, cluster, h.client, *nodeCount, *wait, *check)
+	if err != nil {
+		return err
+	}
+	return h.done(ctx, cluster, false)
+}
+
+func (h *hpctl) addPool(ctx context.Context, args []string) error {
+	fs, clusterName := newFlagSet("add-pool", true)
+	increaseBy := fs.Int("count", 1, "number of nodepools/nodegroups to add")
+	wait, check := updateFlags(fs)
+	if err := parse(fs, args, clusterName, true); err != nil {
+		return err
+	}
+
+	cluster, err := h.getCluster(*clusterName)
+	if err != nil {
+		return err
+	}
+	cluster, err = h.provider.AddNodePool(ctx, cluster, h.client, *increaseBy, *wait, *check)
+	if err != nil {
+		return err
+	}
+	return h.done(ctx, cluster, false)
+}
+
+func (h *hpctl) deletePool(ctx context.Context, args []string) error {
+	fs, clusterName := newFlagSet("delete-pool", true)
+	wait, check := updateFlags(fs)
+	if err := parse(fs, args, clusterName, true); err != nil {
+		return err
+	}
+
+	cluster, err := h.getCluster(*clusterName)
+	if err != nil {
+		return err
+	}
+	cluster, err = h.provider.DeleteNodePool(ctx, cluster, h.client, *wait, *check)
+	if err != nil {
+		return err
+	}
+	return h.done(ctx, cluster, false)
+}
+
+func (h *hpctl) upgrade(ctx context.Context, args []string) error {
+	fs, clusterName := newFlagSet("upgrade", true)
+	upgradeToVersion := fs.String("version", "", "k8s version to upgrade to; defaults to the first version the cluster can be upgraded to")
+	controlPlaneOnly := fs.Bool("control-plane-only", false, "only upgrade the control plane")
+	wait, check := updateFlags(fs)
+	if err := parse(fs, args, clusterName, true); err != nil {
+		return err
+	}
+
+	cluster, err := h.getCluster(*clusterName)
+	if err != nil {
+		return err
+	}
+	if *upgradeToVersion == "" {
+		versions, err := h.provider.ListAvailableVersions(ctx, h.client, cluster)
+		if err != nil {
+			return errors.Wrap(err, "Failed to list the available versions")
+		}
+		if len(versions) == 0 {
+			return fmt.Errorf("cluster %s cannot be upgraded; no version available", cluster.Name)
+		}
+		*upgradeToVersion = versions[0]
+	}
+	fmt.Printf("Upgrading cluster %s to %s\n", cluster.Name, *upgradeToVersion)
+	cluster, err = h.provider.UpgradeControlPlane(ctx, cluster, h.client, *upgradeToVersion, *check)
+	if err != nil {
+		return err
+	}
+	if !*controlPlaneOnly {
+		cluster, err = h.provider.UpgradeNodePools(ctx, cluster, h.client, *upgradeToVersion, *wait, *check)
+		if err != nil {
+			return err
+		}
+	}
+	return h.done(ctx, cluster, false)
+}
+
+func (h *hpctl) syncCheck(ctx context.Context, args []string) error {
+	fs, clusterName := newFlagSet("sync-check", true)
+	ignore := fs.String("ignore", "", "comma separated list of the fields to ignore, for e.g. tags,nodePools[np1].nodeCount")
+	if err := parse(fs, args, clusterName, true); err != nil {
+		return err
+	}
+
+	cluster, err := h.getCluster(*clusterName)
+	if err != nil {
+		return err
+	}
+	report, err := helpers.DetectDrift(ctx, h.provider, cluster)
+	if err != nil {
+		return err
+	}
+	if *ignore != "" {
+		report = report.Ignoring(strings.Split(*ignore, ",")...)
+	}
+	fmt.Println(report.String())
+	if len(report.Drifts) > 0 {
+		return errDrift
+	}
+	return nil
+}
+
+func (h *hpctl) delete(ctx context.Context, args []string) error {
+	fs, clusterName := newFlagSet("delete", true)
+	onCloud := fs.Bool("on-cloud", false, "also delete the cluster from the cloud provider, for e.g. once an imported cluster has been deleted from Rancher")
+	wait := fs.Bool("wait", true, "wait for the cluster to be removed from Rancher")
+	if err := parse(fs, args, clusterName, true); err != nil {
+		return err
+	}
+
+	cluster, err := h.getCluster(*clusterName)
+	if err != nil {
+		return err
+	}
+	if err = h.provider.DeleteHostedCluster(ctx, cluster, h.client); err != nil {
+		return err
+	}
+	if *wait || *onCloud {
+		if err = helpers.WaitUntilClusterIsDeleted(ctx, cluster, h.client); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Cluster %s (%s) deleted from Rancher\n", cluster.Name, cluster.ID)
+	if *onCloud {
+		if err = h.provider.DeleteClusterOnCloud(ctx, cluster.Name); err != nil {
+			return err
+		}
+		fmt.Printf("Cluster %s deleted from %s\n", cluster.Name, h.provider.Name())
+	}
+	return nil
+}
+
+func (h *hpctl) versions(ctx context.Context, args []string) error {
+	fs, clusterName := newFlagSet("versions", false)
+	cloudCredentialID := fs.String("cloud-credential", "", "ID of the cloud credential, i.e. <namespace>:<name>; a new one is created if empty")
+	if err := parse(fs, args, clusterName, false); err != nil {
+		return err
+	}
+
+	if *clusterName != "" {
+		cluster, err := h.getCluster(*clusterName)
+		if err != nil {
+			return err
+		}
+		versions, err := h.provider.ListAvailableVersions(ctx, h.client, cluster)
+		if err != nil {
+			return errors.Wrap(err, "Failed to list the available versions")
+		}
+		fmt.Printf("Cluster %s can be upgraded to: %s\n", cluster.Name, strings.Join(versions, ", "))
+		return nil
+	}
+
+	if err := h.defaultCloudCredential(cloudCredentialID); err != nil {
+		return err
+	}
+	for _, forUpgrade := range []bool{false, true} {
+		version, err := h.provider.GetK8sVersion(ctx, h.client, *cloudCredentialID, forUpgrade)
+		if err != nil {
+			return errors.Wrap(err, "Failed to get the k8s version")
+		}
+		if forUpgrade {
+			fmt.Printf("Version for the upgrade tests: %s\n", version)
+		} else {
+			fmt.Printf("Default version: %s\n", version)
+		}
+	}
+
+	catalogue, err := helpers.LoadVersionCatalogue(ctx, h.client)
+	if err != nil {
+		return err
+	}
+	serverVersion, err := helpers.GetRancherServerVersion(h.client)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get the server-version setting")
+	}
+	// the providers listing their versions from the cloud may have no catalogue entry
+	if paths, err := catalogue.UpgradePaths(h.provider.Name(), serverVersion); err == nil {
+		var upgrades []string
+		for _, path := range paths {
+			upgrades = append(upgrades, path.String())
+		}
+		fmt.Printf("Upgrade paths of the version catalogue: %s\n", strings.Join(upgrades, ", "))
+	}
+	return nil
+}
+
+func (h *hpctl) config(_ context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "check" {
+		fmt.Fprintln(os.Stderr, "Usage: hpctl config check [-suite <suite>]")
+		return errUsage
+	}
+	fs := flag.NewFlagSet("config check", flag.ContinueOnError)
+	suite := fs.String("suite", string(helpers.SuiteHosted), fmt.Sprintf("suite to validate the configuration for, one of %v", helpers.Suites))
+	if err := parse(fs, args[1:], nil, false); err != nil {
+		return err
+	}
+
+	fmt.Println(helpers.Config.String())
+	fmt.Println()
+	if err := helpers.Config.Validate(helpers.Suite(*suite)); err != nil {
+		fmt.Println(err)
+		return errInvalidConfig
+	}
+	fmt.Printf("The configuration is valid for the %s suite\n", *suite)
+	return nil
+}
+
+// updateFlags adds the -wait and -check flags of the subcommands updating a cluster
+func updateFlags(fs *flag.FlagSet) (wait, check *bool) {
+	wait = fs.Bool("wait", true, "wait for the cluster to be active once updated")
+	check = fs.Bool("check", true, "check that the cluster config has been updated, as the tests do")
+	return
+}
+
+// defaultCloudCredential creates a cloud credential for the provider if cloudCredentialID is empty
+func (h *hpctl) defaultCloudCredential(cloudCredentialID *string) error {
+	if *cloudCredentialID != "" {
+		return nil
+	}
+	id, err := helpers.CreateCloudCredentials(h.client)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created cloud credential %s; pass -cloud-credential %s to reuse it\n", id, id)
+	*cloudCredentialID = id
+	return nil
+}
+
+// defaultK8sVersion sets k8sVersion to the version the tests would use if it is empty
+func (h *hpctl) defaultK8sVersion(ctx context.Context, k8sVersion *string, cloudCredentialID string) error {
+	if *k8sVersion != "" {
+		return nil
+	}
+	version, err := h.provider.GetK8sVersion(ctx, h.client, cloudCredentialID, false)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get the k8s version")
+	}
+	*k8sVersion = version
+	return nil
+}
+
+// getCluster returns the Rancher cluster with the given name
+func (h *hpctl) getCluster(clusterName string) (*management.Cluster, error) {
+	clusterID, err := clusters.GetClusterIDByName(h.client, clusterName)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get the cluster ID")
+	}
+	if clusterID == "" {
+		return nil, fmt.Errorf("cluster %s not found in Rancher", clusterName)
+	}
+	cluster, err := h.client.Management.Cluster.ByID(clusterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get the cluster")
+	}
+	return cluster, nil
+}
+
+// done waits for the cluster to be ready if wait is true and prints its state
+func (h *hpctl) done(ctx context.Context, cluster *management.Cluster, wait bool) error {
+	if wait {
+		var err error
+		if cluster, err = helpers.WaitUntilClusterIsReady(ctx, cluster, h.client); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Cluster %s (%s) is %s\n", cluster.Name, cluster.ID, cluster.State)
+	return nil
+}
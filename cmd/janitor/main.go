@@ -0,0 +1,184 @@
+// Command janitor deletes the clusters left behind by the tests, for e.g. when DOWNSTREAM_CLUSTER_CLEANUP is false or a suite crashed before its AfterEach.
+// It selects the clusters labelled by helpers.GetCommonMetadataLabels, or named after helpers.ClusterNamePrefixFor, in Rancher and on the cloud providers;
+// it only lists them unless -dry-run=false is given.
+//
+//	go run ./cmd/janitor -providers aks,eks,gke -older-than 6h -dry-run=false
+//
+// The cloud credentials and locations are read from the same env vars as the tests, for e.g. AKS_SUBSCRIPTION_ID, EKS_REGION and GKE_PROJECT_ID;
+// the Rancher clusters are only cleaned up if CATTLE_TEST_CONFIG contains the Rancher host and admin token.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/pkg/config"
+	"github.com/rancher/shepherd/pkg/session"
+
+	_ "github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
+	_ "github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
+	_ "github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+type janitor struct {
+	filter helpers.JanitorFilter
+	dryRun bool
+	now    time.Time
+	out    *tabwriter.Writer
+	failed bool
+}
+
+func main() {
+	providers := flag.String("providers", helpers.Provider, "comma separated list of the providers to clean up; defaults to PROVIDER")
+	namePrefix := flag.String("name-prefix", "", "prefix of the names of the clusters to delete in addition to the labelled ones; defaults to the prefix used by the tests for each provider")
+	olderThan := flag.Duration("older-than", 6*time.Hour, "minimum age of the clusters to delete, so that the clusters of the running tests are kept")
+	includeIgnored := flag.Bool("include-ignored", false, "also delete the clusters labelled with "+helpers.JanitorIgnoreLabel)
+	skipRancher := flag.Bool("skip-rancher", false, "do not delete the clusters from Rancher")
+	dryRun := flag.Bool("dry-run", true, "only list the clusters that would be deleted")
+	flag.Parse()
+
+	// an interrupted janitor stops the pending deletions instead of leaving the CLIs running
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *providers == "" {
+		fmt.Fprintln(os.Stderr, "no provider given; use -providers or PROVIDER")
+		os.Exit(2)
+	}
+
+	var client *rancher.Client
+	if !*skipRancher {
+		if os.Getenv(config.ConfigEnvironmentKey) == "" {
+			fmt.Printf("%s is not set, skipping the Rancher clusters\n", config.ConfigEnvironmentKey)
+		} else {
+			var err error
+			if client, err = rancher.NewClient("", session.NewSession()); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to create the Rancher client: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	j := &janitor{
+		dryRun: *dryRun,
+		now:    time.Now(),
+		out:    tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0),
+	}
+	fmt.Fprintln(j.out, "PROVIDER\tSOURCE\tNAME\tLOCATION\tAGE\tACTION")
+	for _, name := range strings.Split(*providers, ",") {
+		provider, err := helpers.GetHostedProvider(strings.TrimSpace(name))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		j.filter = helpers.JanitorFilter{NamePrefix: *namePrefix, OlderThan: *olderThan, IncludeIgnored: *includeIgnored}
+		if j.filter.NamePrefix == "" {
+			j.filter.NamePrefix = helpers.ClusterNamePrefixFor(provider.Name())
+		}
+		j.clean(ctx, provider, client)
+	}
+	_ = j.out.Flush()
+
+	if j.failed {
+		os.Exit(1)
+	}
+}
+
+// clean deletes the clusters of the provider from Rancher, then from the cloud provider
+func (j *janitor) clean(ctx context.Context, provider helpers.HostedProvider, client *rancher.Client) {
+	// the operator deletes the cloud resources of the clusters it provisioned
+	deletedByOperator := map[string]bool{}
+	if client != nil {
+		clusters, err := client.Management.Cluster.ListAll(nil)
+		if err != nil {
+			j.fail(provider.Name(), "rancher", errors.Wrap(err, "Failed to list clusters"))
+		} else {
+			for i := range clusters.Data {
+				cluster := &clusters.Data[i]
+				if !j.filter.MatchRancherCluster(cluster, provider.Name(), j.now) {
+					continue
+				}
+				cloudName, imported := cloudClusterName(cluster)
+				if !imported {
+					deletedByOperator[cloudName] = true
+				}
+				createdAt, _ := time.Parse(time.RFC3339, cluster.Created)
+				j.delete(provider.Name(), "rancher", cluster.Name, "", createdAt, func() error {
+					return provider.DeleteHostedCluster(ctx, cluster, client)
+				})
+			}
+		}
+	}
+
+	clusters, err := provider.ListClustersOnCloud(ctx)
+	if err != nil {
+		j.fail(provider.Name(), "cloud", err)
+		return
+	}
+	for _, cluster := range clusters {
+		if !j.filter.MatchCloudCluster(cluster, j.now) {
+			continue
+		}
+		if deletedByOperator[cluster.Name] {
+			j.report(provider.Name(), "cloud", cluster.Name, cluster.Location, cluster.CreatedAt, "deleted by the operator")
+			continue
+		}
+		j.delete(provider.Name(), "cloud", cluster.Name, cluster.Location, cluster.CreatedAt, func() error {
+			return provider.DeleteListedClusterOnCloud(ctx, cluster)
+		})
+	}
+}
+
+// delete runs deleteFunc unless dry-run is enabled and reports the result
+func (j *janitor) delete(provider, source, name, location string, createdAt time.Time, deleteFunc func() error) {
+	if j.dryRun {
+		j.report(provider, source, name, location, createdAt, "would be deleted")
+		return
+	}
+	if err := deleteFunc(); err != nil {
+		j.failed = true
+		j.report(provider, source, name, location, createdAt, "failed: "+err.Error())
+		return
+	}
+	j.report(provider, source, name, location, createdAt, "deleted")
+}
+
+func (j *janitor) report(provider, source, name, location string, createdAt time.Time, action string) {
+	age := "unknown"
+	if !createdAt.IsZero() {
+		age = j.now.Sub(createdAt).Round(time.Minute).String()
+	}
+	if location == "" {
+		location = "-"
+	}
+	fmt.Fprintf(j.out, "%s\t%s\t%s\t%s\t%s\t%s\n", provider, source, name, location, age, action)
+}
+
+func (j *janitor) fail(provider, source string, err error) {
+	j.failed = true
+	fmt.Fprintf(j.out, "%s\t%s\t-\t-\t-\tfailed: %v\n", provider, source, err)
+}
+
+// cloudClusterName returns the name of the cluster on the cloud provider and whether it is imported
+func cloudClusterName(cluster *management.Cluster) (string, bool) {
+	switch {
+	case cluster.AKSConfig != nil:
+		return cluster.AKSConfig.ClusterName, cluster.AKSConfig.Imported
+	case cluster.EKSConfig != nil:
+		return cluster.EKSConfig.DisplayName, cluster.EKSConfig.Imported
+	case cluster.GKEConfig != nil:
+		return cluster.GKEConfig.ClusterName, cluster.GKEConfig.Imported
+	}
+	return cluster.Name, false
+}
@@ -17,13 +17,15 @@ package backup_restore_test
 import (
 	. "github.com/onsi/ginkgo/v2"
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var _ = Describe("BackupRestoreProvisioning", func() {
 	k := kubectl.New()
 
-	It("Do a full backup/restore test", func() {
+	It("Do a full backup/restore test", func(specCtx SpecContext) {
 		testCaseID = 246 // Report to Qase
-		BackupRestoreChecks(k)
+		suite.BackupRestoreChecks(specCtx, backupRestoreSpec(k))
 	})
 })
@@ -15,9 +15,8 @@ limitations under the License.
 package backup_restore_test
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -31,28 +30,31 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 const (
-	increaseBy          = 1
-	backupResourceName  = "hp-backup"
-	restoreResourceName = "hp-restore"
+	increaseBy = 1
 )
 
 var (
-	testCaseID              int64
-	clusterName, backupFile string
-	ctx                     helpers.RancherContext
-	cluster                 *management.Cluster
-	location                = helpers.GetAKSLocation()
-	k3sVersion              = os.Getenv("INSTALL_K3S_VERSION")
+	testCaseID  int64
+	clusterName string
+	ctx         helpers.RancherContext
+	cluster     *management.Cluster
+	location    = helpers.GetAKSLocation()
 )
 
 func TestBackupRestore(t *testing.T) {
 	RegisterFailHandler(Fail)
-	helpers.CommonSynchronizedBeforeSuite()
-	ctx = helpers.CommonBeforeSuite()
+	Expect(helpers.Config.Validate(helpers.SuiteBackupRestore)).To(Succeed())
+	suite.CommonSynchronizedBeforeSuite()
+	ctx = suite.CommonBeforeSuite()
 	RunSpecs(t, "BackupRestore Suite")
+	// the resources created before RunSpecs are not deferred by Ginkgo, hence they are cleaned up here
+	if err := ctx.Cleanups.RunAll(context.Background()); err != nil {
+		t.Error(ctx.Cleanups.Summary())
+	}
 }
 
 var _ = ReportBeforeEach(func(report SpecReport) {
@@ -60,97 +62,59 @@ var _ = ReportBeforeEach(func(report SpecReport) {
 	testCaseID = -1
 })
 
-var _ = ReportAfterEach(func(report SpecReport) {
-	// Add result in Qase if asked
+var _ = ReportAfterEach(func(specCtx SpecContext, report SpecReport) {
+	// Add result in Qase and in the run report if asked
 	Qase(testCaseID, report)
+	suite.WriteRunReport(specCtx, report, testCaseID, ctx.RancherAdminClient)
 })
 
-var _ = BeforeEach(func() {
+var _ = BeforeEach(func(specCtx SpecContext) {
 	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
-	k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, false)
+	k8sVersion, err := helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, location, false)
 	Expect(err).NotTo(HaveOccurred())
 	GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 
 	if helpers.IsImport {
 		By("importing the cluster")
-		err = helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+		err = helper.CreateAKSClusterOnAzure(specCtx, location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
 		Expect(err).To(BeNil())
-		cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+		cluster, err = helper.ImportAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
 		Expect(err).To(BeNil())
 	} else {
 		By("provisioning the cluster")
-		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
+		cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
 		Expect(err).To(BeNil())
 	}
-	cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+	cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 	Expect(err).To(BeNil())
 })
 
-var _ = AfterEach(func() {
-	if ctx.ClusterCleanup && cluster != nil {
-		err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
-		Expect(err).To(BeNil())
-		err = helper.DeleteAKSClusteronAzure(clusterName)
-		Expect(err).To(BeNil())
-	} else {
-		fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-	}
-})
-
-func restoreNodesChecks(cluster *management.Cluster, client *rancher.Client, clusterName string) {
-	helpers.ClusterIsReadyChecks(cluster, client, clusterName)
+func restoreNodesChecks(specCtx context.Context, cluster *management.Cluster, client *rancher.Client, clusterName string) {
+	suite.ClusterIsReadyChecks(specCtx, cluster, client, clusterName)
 	configNodePools := *cluster.AKSConfig.NodePools
 	initialNodeCount := *configNodePools[0].Count
 
 	By("scaling up the nodepool", func() {
 		var err error
-		cluster, err = helper.ScaleNodePool(cluster, client, initialNodeCount+1, true, true)
+		cluster, err = helper.ScaleNodePool(specCtx, cluster, client, initialNodeCount+1, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("adding a nodepool", func() {
 		var err error
-		cluster, err = helper.AddNodePool(cluster, increaseBy, client, true, true)
+		cluster, err = helper.AddNodePool(specCtx, cluster, increaseBy, client, true, true)
 		Expect(err).To(BeNil())
 	})
 }
 
-func BackupRestoreChecks(k *kubectl.Kubectl) {
-	By("Checking hosted cluster is ready", func() {
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
-	})
-
-	By("Performing a backup", func() {
-		backupFile = helpers.ExecuteBackup(k, backupResourceName)
-	})
-
-	By("Perform restore pre-requisites: Uninstalling k3s", func() {
-		out, err := exec.Command("k3s-uninstall.sh").CombinedOutput()
-		Expect(err).To(Not(HaveOccurred()), out)
-	})
-
-	By("Perform restore pre-requisites: Getting k3s ready", func() {
-		helpers.InstallK3S(k, k3sVersion, "none", "none")
-	})
-
-	By("Performing a restore", func() {
-		helpers.ExecuteRestore(k, restoreResourceName, backupFile)
-	})
-
-	By("Performing post migration installations: Installing CertManager", func() {
-		helpers.InstallCertManager(k, "none", "none")
-	})
-
-	By("Performing post migration installations: Installing Rancher Manager", func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "none", "none")
-	})
-
-	By("Performing post migration installations: Checking Rancher Deployments", func() {
-		helpers.CheckRancherDeployments(k)
-	})
-
-	By("Checking hosted cluster can be modified", func() {
-		restoreNodesChecks(cluster, ctx.RancherAdminClient, clusterName)
-	})
+// backupRestoreSpec returns the cluster created by BeforeEach, checked by the backup-restore specs
+func backupRestoreSpec(k *kubectl.Kubectl) suite.BackupRestoreSpec {
+	return suite.BackupRestoreSpec{
+		Kubectl:         k,
+		Client:          ctx.RancherAdminClient,
+		CloudCredential: ctx.CloudCredID,
+		Cluster:         cluster,
+		ClusterName:     clusterName,
+		NodesChecks:     restoreNodesChecks,
+	}
 }
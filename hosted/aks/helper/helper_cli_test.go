@@ -0,0 +1,143 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakecli"
+)
+
+var _ = Describe("Azure CLI", func() {
+	const (
+		clusterName = "aks-cli"
+		location    = "centralindia"
+		sub         = "sub-id"
+	)
+	var runner *fakecli.Runner
+
+	BeforeEach(func() {
+		originalSubscriptionID := subscriptionID
+		subscriptionID = sub
+		DeferCleanup(func() {
+			subscriptionID = originalSubscriptionID
+		})
+
+		runner = fakecli.NewRunner()
+		DeferCleanup(runner.Install())
+	})
+
+	AfterEach(func() {
+		Expect(runner.Verify()).To(Succeed())
+	})
+
+	It("CreateAKSClusterOnAzure creates the resource group and the cluster with sorted tags", func(ctx SpecContext) {
+		runner.Expect("az", "group", "create", "--location", location, "--resource-group", clusterName, "--subscription", sub)
+		runner.Expect("az", "aks", "create", "--resource-group", clusterName, "--no-ssh-key", "--kubernetes-version", "1.31.2", "--enable-managed-identity", "--name", clusterName, "--subscription", sub, "--node-count", "2", "--location", location, "--tags", "a=1", "b=2", "--network-plugin", "kubenet")
+
+		Expect(CreateAKSClusterOnAzure(ctx, location, clusterName, "1.31.2", "2", map[string]string{"b": "2", "a": "1"}, "--network-plugin", "kubenet")).To(Succeed())
+	})
+
+	It("CreateAKSClusterOnAzure does not create the cluster if the resource group creation fails", func(ctx SpecContext) {
+		runner.Expect("az", "group", "create", "--location", location, "--resource-group", clusterName, "--subscription", sub).Fails("quota exceeded", "exit status 1")
+
+		err := CreateAKSClusterOnAzure(ctx, location, clusterName, "1.31.2", "1", nil)
+		Expect(err).To(MatchError(ContainSubstring("Failed to create resource group: quota exceeded")))
+	})
+
+	It("AddNodePoolOnAzure adds a nodepool", func(ctx SpecContext) {
+		runner.Expect("az", "aks", "nodepool", "add", "--resource-group", clusterName, "--cluster-name", clusterName, "--name", "np1", "--node-count", "3", "--subscription", sub, "--mode", "User")
+
+		Expect(AddNodePoolOnAzure(ctx, "np1", clusterName, clusterName, "3", "--mode", "User")).To(Succeed())
+	})
+
+	It("DeleteNodePoolOnAzure deletes a nodepool", func(ctx SpecContext) {
+		runner.Expect("az", "aks", "nodepool", "delete", "--resource-group", clusterName, "--cluster-name", clusterName, "--name", "np1", "--subscription", sub)
+
+		Expect(DeleteNodePoolOnAzure(ctx, "np1", clusterName, clusterName)).To(Succeed())
+	})
+
+	It("ScaleNodePoolOnAzure scales a nodepool and wraps the output on failure", func(ctx SpecContext) {
+		runner.Expect("az", "aks", "nodepool", "scale", "--resource-group", clusterName, "--cluster-name", clusterName, "--name", "np1", "--node-count", "4", "--subscription", sub).Fails("not found", "exit status 3")
+
+		err := ScaleNodePoolOnAzure(ctx, "np1", clusterName, clusterName, "4")
+		Expect(err).To(MatchError(ContainSubstring("Failed to scale node pool: not found")))
+	})
+
+	It("UpdateClusterTagOnAzure updates the tags", func(ctx SpecContext) {
+		runner.Expect("az", "aks", "update", "--resource-group", clusterName, "--name", clusterName, "--subscription", sub, "--tags", "env=ci", "owner=qa")
+
+		Expect(UpdateClusterTagOnAzure(ctx, map[string]string{"owner": "qa", "env": "ci"}, clusterName, clusterName)).To(Succeed())
+	})
+
+	DescribeTable("ClusterExistsOnAzure",
+		func(ctx SpecContext, output string, expected bool) {
+			runner.Expect("az", "aks", "show", "--subscription", sub, "--name", clusterName, "--resource-group", clusterName).Returns(output)
+
+			exists, err := ClusterExistsOnAzure(ctx, clusterName, clusterName)
+			Expect(err).To(BeNil())
+			Expect(exists).To(Equal(expected))
+		},
+		Entry("returns true for a running cluster", `{"provisioningState": "Succeeded"}`, true),
+		Entry("returns false for a cluster being deleted", `{"provisioningState": "Deleting"}`, false),
+	)
+
+	It("RunCommand logs in and invokes the command inside the cluster", func(ctx SpecContext) {
+		runner.Expect("az", "aks", "get-credentials", "--resource-group", clusterName, "--name", clusterName, "--overwrite-existing", "--subscription", sub)
+		runner.Expect("az", "aks", "command", "invoke", "--resource-group", clusterName, "--name", clusterName, "--subscription", sub, "--command", "kubectl get nodes")
+
+		Expect(RunCommand(ctx, clusterName, clusterName, "kubectl get nodes")).To(Succeed())
+	})
+
+	It("UpgradeAKSOnAzure upgrades the cluster", func(ctx SpecContext) {
+		runner.Expect("az", "aks", "upgrade", "--subscription", sub, "--resource-group", clusterName, "--name", clusterName, "--kubernetes-version", "1.32.0", "--yes", "--control-plane-only")
+
+		Expect(UpgradeAKSOnAzure(ctx, clusterName, clusterName, "1.32.0", "--control-plane-only")).To(Succeed())
+	})
+
+	It("DeleteAKSClusteronAzure deletes the resource group", func(ctx SpecContext) {
+		runner.Expect("az", "group", "delete", "--name", clusterName, "--yes", "--subscription", sub)
+
+		Expect(DeleteAKSClusteronAzure(ctx, clusterName)).To(Succeed())
+	})
+	It("ListAKSClustersOnAzure lists the clusters with their resource group, tags and creation time", func(ctx SpecContext) {
+		runner.Expect("az", "aks", "list", "--subscription", sub, "--output", "json").Returns(`[
+			{"name": "aks-cli", "location": "centralindia", "resourceGroup": "aks-cli", "tags": {"owner": "hosted-providers-qa-ci-user"}, "systemData": {"createdAt": "2024-05-01T10:00:00.123456+00:00"}},
+			{"name": "other", "location": "eastus", "resourceGroup": "shared", "tags": null}
+		]`)
+
+		clusters, err := ListAKSClustersOnAzure(ctx)
+		Expect(err).To(BeNil())
+		Expect(clusters).To(HaveLen(2))
+		Expect(clusters[0].ResourceGroup).To(Equal(clusterName))
+		Expect(clusters[0].Labels).To(Equal(map[string]string{"owner": "hosted-providers-qa-ci-user"}))
+		Expect(clusters[0].CreatedAt.UTC()).To(Equal(time.Date(2024, 5, 1, 10, 0, 0, 123456000, time.UTC)))
+		Expect(clusters[1]).To(Equal(helpers.CloudCluster{Name: "other", Location: "eastus", ResourceGroup: "shared"}))
+	})
+
+	DescribeTable("Provider.DeleteListedClusterOnCloud",
+		func(ctx SpecContext, resourceGroup string, expected ...string) {
+			runner.Expect("az", expected...)
+
+			Expect(Provider{}.DeleteListedClusterOnCloud(ctx, helpers.CloudCluster{Name: clusterName, ResourceGroup: resourceGroup})).To(Succeed())
+		},
+		Entry("deletes the resource group named after the cluster", clusterName, "group", "delete", "--name", clusterName, "--yes", "--subscription", sub),
+		Entry("deletes only the cluster from a shared resource group", "shared", "aks", "delete", "--name", clusterName, "--resource-group", "shared", "--yes", "--subscription", sub),
+	)
+})
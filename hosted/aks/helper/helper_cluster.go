@@ -1,15 +1,14 @@
 package helper
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
-	"github.com/onsi/ginkgo/v2"
-	. "github.com/onsi/gomega"
-	"github.com/rancher-sandbox/ele-testhelpers/tools"
-	"github.com/rancher/shepherd/extensions/clusters"
 	"github.com/rancher/shepherd/extensions/clusters/aks"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
@@ -22,16 +21,15 @@ import (
 	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 	"k8s.io/utils/pointer"
 
-	"github.com/epinio/epinio/acceptance/helpers/proc"
 	"github.com/pkg/errors"
 )
 
 var (
-	subscriptionID = os.Getenv("AKS_SUBSCRIPTION_ID")
+	subscriptionID = helpers.Config.AKS.SubscriptionID
 )
 
 // CreateAKSHostedCluster creates the AKS cluster on Rancher
-func CreateAKSHostedCluster(client *rancher.Client, displayName, cloudCredentialID, k8sVersion, location string, updateFunc func(clusterConfig *aks.ClusterConfig)) (*management.Cluster, error) {
+func CreateAKSHostedCluster(ctx context.Context, client *rancher.Client, displayName, cloudCredentialID, k8sVersion, location string, updateFunc func(clusterConfig *aks.ClusterConfig)) (*management.Cluster, error) {
 	var aksClusterConfig aks.ClusterConfig
 	config.LoadConfig(aks.AKSClusterConfigConfigurationFileKey, &aksClusterConfig)
 
@@ -46,11 +44,16 @@ func CreateAKSHostedCluster(client *rancher.Client, displayName, cloudCredential
 		updateFunc(&aksClusterConfig)
 	}
 
-	return aks.CreateAKSHostedCluster(client, displayName, cloudCredentialID, aksClusterConfig, false, false, false, false, nil)
+	cluster, err := aks.CreateAKSHostedCluster(client, displayName, cloudCredentialID, aksClusterConfig, false, false, false, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	helpers.RegisterHostedClusterCleanup(cluster, client, DeleteAKSHostCluster)
+	return cluster, nil
 }
 
 // ImportAKSHostedCluster imports an AKS cluster to Rancher
-func ImportAKSHostedCluster(client *rancher.Client, clusterName, cloudCredentialID, location string, tags map[string]string) (*management.Cluster, error) {
+func ImportAKSHostedCluster(ctx context.Context, client *rancher.Client, clusterName, cloudCredentialID, location string, tags map[string]string) (*management.Cluster, error) {
 	cluster := &management.Cluster{
 		DockerRootDir: "/var/lib/docker",
 		AKSConfig: &management.AKSClusterConfigSpec{
@@ -64,46 +67,63 @@ func ImportAKSHostedCluster(client *rancher.Client, clusterName, cloudCredential
 		Name: clusterName,
 	}
 
-	return client.Management.Cluster.Create(cluster)
+	importedCluster, err := client.Management.Cluster.Create(cluster)
+	if err != nil {
+		return nil, err
+	}
+	helpers.RegisterHostedClusterCleanup(importedCluster, client, DeleteAKSHostCluster)
+	return importedCluster, nil
 }
 
 // DeleteAKSHostCluster deletes the AKS cluster
-func DeleteAKSHostCluster(cluster *management.Cluster, client *rancher.Client) error {
-	return client.Management.Cluster.Delete(cluster)
+func DeleteAKSHostCluster(ctx context.Context, cluster *management.Cluster, client *rancher.Client) error {
+	if err := client.Management.Cluster.Delete(cluster); err != nil {
+		return err
+	}
+	helpers.ForgetHostedClusterCleanup(cluster)
+	return nil
 }
 
 // UpgradeClusterKubernetesVersion upgrades the k8s version to the value defined by upgradeToVersion;
 // if checkClusterConfig is set to true, it will validate that the cluster control plane has been upgrade successfully
-func UpgradeClusterKubernetesVersion(cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, checkClusterConfig bool) (*management.Cluster, error) {
+func UpgradeClusterKubernetesVersion(ctx context.Context, cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	currentVersion := *cluster.AKSConfig.KubernetesVersion
 	upgradedCluster.AKSConfig.KubernetesVersion = &upgradeToVersion
 
 	var err error
 	cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
-	Expect(err).To(BeNil())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to upgrade cluster")
+	}
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
-		Expect(*cluster.AKSConfig.KubernetesVersion).To(Equal(upgradeToVersion))
+		if err = helpers.CheckField(cluster, "AKSConfig.KubernetesVersion", upgradeToVersion, *cluster.AKSConfig.KubernetesVersion); err != nil {
+			return cluster, err
+		}
 		// ensure nodepool version is still the same when config is applied
 		// NOTE: this check will fail if nodepool version at the beginning is different from cluster version
-		for _, np := range *cluster.AKSConfig.NodePools {
-			Expect(*np.OrchestratorVersion).To(Equal(currentVersion))
+		if err = checkNodePoolVersions(cluster, "AKSConfig", *cluster.AKSConfig.NodePools, currentVersion); err != nil {
+			return cluster, err
 		}
 
-		// Check if the desired config has been applied in Rancher
-		Eventually(func() string {
-			ginkgo.GinkgoLogr.Info("Waiting for k8s upgrade to appear in AKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return *cluster.AKSStatus.UpstreamSpec.KubernetesVersion
-		}, tools.SetTimeout(10*time.Minute), 5*time.Second).Should(Equal(upgradeToVersion))
+		// Check if the desired config has been applied in Rancher; the upgrade is timed until it appears in AKSStatus.UpstreamSpec
+		start := time.Now()
+		err = helpers.WaitFor(ctx, "the k8s upgrade to appear in AKSStatus.UpstreamSpec", 10*time.Minute, 5*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
+			}
+			return true, helpers.CheckField(cluster, "AKSStatus.UpstreamSpec.KubernetesVersion", upgradeToVersion, *cluster.AKSStatus.UpstreamSpec.KubernetesVersion)
+		})
+		if err != nil {
+			return cluster, err
+		}
+		helpers.CheckLifecycleOperation(helpers.OperationControlPlaneUpgrade, time.Since(start))
 		// ensure nodepool version is same in Rancher
-		for _, np := range *cluster.AKSStatus.UpstreamSpec.NodePools {
-			Expect(*np.OrchestratorVersion).To(Equal(currentVersion))
+		if err = checkNodePoolVersions(cluster, "AKSStatus.UpstreamSpec", *cluster.AKSStatus.UpstreamSpec.NodePools, currentVersion); err != nil {
+			return cluster, err
 		}
-
 	}
 	return cluster, nil
 }
@@ -111,7 +131,7 @@ func UpgradeClusterKubernetesVersion(cluster *management.Cluster, upgradeToVersi
 // UpgradeNodeKubernetesVersion upgrades the k8s version of nodepool to the value defined by upgradeToVersion;
 // if wait is set to true, it will wait until the cluster finishes upgrading;
 // if checkClusterConfig is set to true, it will validate that nodepool has been upgraded successfully
-func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+func UpgradeNodeKubernetesVersion(ctx context.Context, cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	configNodePools := *upgradedCluster.AKSConfig.NodePools
 	for i := range configNodePools {
@@ -119,40 +139,51 @@ func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion
 	}
 	var err error
 	cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
-	Expect(err).To(BeNil())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to upgrade nodepools")
+	}
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
-		for _, np := range *cluster.AKSConfig.NodePools {
-			Expect(*np.OrchestratorVersion).To(Equal(upgradeToVersion))
+		if err = checkNodePoolVersions(cluster, "AKSConfig", *cluster.AKSConfig.NodePools, upgradeToVersion); err != nil {
+			return cluster, err
 		}
 	}
 
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
-		Expect(err).To(BeNil())
+		if err = helpers.WaitClusterToBeUpgraded(ctx, client, cluster.ID, helpers.OperationNodePoolUpgrade); err != nil {
+			return cluster, errors.Wrap(err, "Failed to wait for the nodepools upgrade")
+		}
 	}
 
 	if checkClusterConfig {
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() bool {
-			ginkgo.GinkgoLogr.Info("waiting for the nodepool upgrade to appear in AKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			for _, np := range *cluster.AKSStatus.UpstreamSpec.NodePools {
-				if *np.OrchestratorVersion != upgradeToVersion {
-					return false
-				}
+		err = helpers.WaitFor(ctx, "the nodepool upgrade to appear in AKSStatus.UpstreamSpec", 12*time.Minute, 10*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
 			}
-			return true
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeTrue())
+			return true, checkNodePoolVersions(cluster, "AKSStatus.UpstreamSpec", *cluster.AKSStatus.UpstreamSpec.NodePools, upgradeToVersion)
+		})
+		if err != nil {
+			return cluster, err
+		}
 	}
 	return cluster, nil
 }
 
+// checkNodePoolVersions returns a helpers.ConfigMismatchError if the version of a nodepool of the given config is not the expected one
+func checkNodePoolVersions(cluster *management.Cluster, config string, nodePools []management.AKSNodePool, version string) error {
+	for _, np := range nodePools {
+		if err := helpers.CheckField(cluster, fmt.Sprintf("%s.NodePools[%s].OrchestratorVersion", config, *np.Name), version, *np.OrchestratorVersion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ListSingleVariantAKSAllVersions returns a list of single variants of minor versions in descending order
 // For e.g 1.27.5, 1.26.6, 1.25.8
-func ListSingleVariantAKSAllVersions(client *rancher.Client, cloudCredentialID, region string) (availableVersions []string, err error) {
+func ListSingleVariantAKSAllVersions(ctx context.Context, client *rancher.Client, cloudCredentialID, region string) (availableVersions []string, err error) {
 	availableVersions, err = kubernetesversions.ListAKSAllVersions(client, cloudCredentialID, region)
 	if err != nil {
 		return nil, err
@@ -167,12 +198,16 @@ func ListSingleVariantAKSAllVersions(client *rancher.Client, cloudCredentialID,
 			oldMinor = currentMinor
 		}
 	}
-	return helpers.FilterUIUnsupportedVersions(singleVersionList, client), nil
+	singleVersionList, err = helpers.FilterCatalogueVersions(ctx, client, "aks", singleVersionList)
+	if err != nil {
+		return nil, err
+	}
+	return helpers.FilterUIUnsupportedVersions(singleVersionList, client)
 }
 
 // GetK8sVersionVariantAKS returns a variant of a given minor K8s version
-func GetK8sVersionVariantAKS(minorVersion string, client *rancher.Client, cloudCredentialID, region string) (string, error) {
-	versions, err := ListSingleVariantAKSAllVersions(client, cloudCredentialID, region)
+func GetK8sVersionVariantAKS(ctx context.Context, minorVersion string, client *rancher.Client, cloudCredentialID, region string) (string, error) {
+	versions, err := ListSingleVariantAKSAllVersions(ctx, client, cloudCredentialID, region)
 	if err != nil {
 		return "", err
 	}
@@ -188,7 +223,7 @@ func GetK8sVersionVariantAKS(minorVersion string, client *rancher.Client, cloudC
 // AddNodePool adds a nodepool to the list; it uses the nodepool template defined in CATTLE_TEST_CONFIG file
 // if wait is set to true, it will wait until the cluster finishes upgrading;
 // if checkClusterConfig is set to true, it will validate that nodepool has been added successfully
-func AddNodePool(cluster *management.Cluster, increaseBy int, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+func AddNodePool(ctx context.Context, cluster *management.Cluster, increaseBy int, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	currentNodePoolNumber := len(*cluster.AKSConfig.NodePools)
 
@@ -226,31 +261,35 @@ func AddNodePool(cluster *management.Cluster, increaseBy int, client *rancher.Cl
 
 	var err error
 	cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
-	Expect(err).To(BeNil())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to add nodepools")
+	}
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
-		Expect(len(*cluster.AKSConfig.NodePools)).Should(BeNumerically("==", currentNodePoolNumber+increaseBy))
-		for i, np := range *cluster.AKSConfig.NodePools {
-			Expect(np.Name).To(Equal(updateNodePoolsList[i].Name))
+		if err = checkNodePoolNames(cluster, "AKSConfig", *cluster.AKSConfig.NodePools, updateNodePoolsList); err != nil {
+			return cluster, err
 		}
 	}
 
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
-		Expect(err).To(BeNil())
+		if err = helpers.WaitClusterToBeUpgraded(ctx, client, cluster.ID, helpers.OperationAddNodePool); err != nil {
+			return cluster, errors.Wrap(err, "Failed to wait for the nodepools addition")
+		}
 	}
 	if checkClusterConfig {
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() int {
-			ginkgo.GinkgoLogr.Info("Waiting for the total nodepool count to increase in AKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return len(*cluster.AKSStatus.UpstreamSpec.NodePools)
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeNumerically("==", currentNodePoolNumber+increaseBy))
-
-		for i, np := range *cluster.AKSStatus.UpstreamSpec.NodePools {
-			Expect(np.Name).To(Equal(updateNodePoolsList[i].Name))
+		err = helpers.WaitFor(ctx, "the total nodepool count to increase in AKSStatus.UpstreamSpec", 12*time.Minute, 10*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
+			}
+			return true, helpers.CheckField(cluster, "len(AKSStatus.UpstreamSpec.NodePools)", currentNodePoolNumber+increaseBy, len(*cluster.AKSStatus.UpstreamSpec.NodePools))
+		})
+		if err != nil {
+			return cluster, err
+		}
+		if err = checkNodePoolNames(cluster, "AKSStatus.UpstreamSpec", *cluster.AKSStatus.UpstreamSpec.NodePools, updateNodePoolsList); err != nil {
+			return cluster, err
 		}
 	}
 	return cluster, nil
@@ -259,7 +298,7 @@ func AddNodePool(cluster *management.Cluster, increaseBy int, client *rancher.Cl
 // DeleteNodePool deletes a nodepool from the list; if wait is set to true, it will wait until the cluster finishes upgrading;
 // if checkClusterConfig is set to true, it will validate that nodepool has been deleted successfully
 // TODO: Modify this method to delete a custom qty of DeleteNodePool, perhaps by adding an `decreaseBy int` arg
-func DeleteNodePool(cluster *management.Cluster, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+func DeleteNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
 	currentNodePoolNumber := len(*cluster.AKSConfig.NodePools)
 
 	upgradedCluster := cluster
@@ -269,39 +308,57 @@ func DeleteNodePool(cluster *management.Cluster, client *rancher.Client, wait, c
 
 	var err error
 	cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
-	Expect(err).To(BeNil())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to delete nodepool")
+	}
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
-		Expect(len(*cluster.AKSConfig.NodePools)).Should(BeNumerically("==", currentNodePoolNumber-1))
-		for i, np := range *cluster.AKSConfig.NodePools {
-			Expect(np.Name).To(Equal(updatedNodePoolsList[i].Name))
+		if err = checkNodePoolNames(cluster, "AKSConfig", *cluster.AKSConfig.NodePools, updatedNodePoolsList); err != nil {
+			return cluster, err
 		}
 	}
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
-		Expect(err).To(BeNil())
+		if err = helpers.WaitClusterToBeUpgraded(ctx, client, cluster.ID, helpers.OperationDeleteNodePool); err != nil {
+			return cluster, errors.Wrap(err, "Failed to wait for the nodepool deletion")
+		}
 	}
 	if checkClusterConfig {
 
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() int {
-			ginkgo.GinkgoLogr.Info("Waiting for the total nodepool count to decrease in AKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return len(*cluster.AKSStatus.UpstreamSpec.NodePools)
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeNumerically("==", currentNodePoolNumber-1))
-		for i, np := range *cluster.AKSStatus.UpstreamSpec.NodePools {
-			Expect(np.Name).To(Equal(updatedNodePoolsList[i].Name))
+		err = helpers.WaitFor(ctx, "the total nodepool count to decrease in AKSStatus.UpstreamSpec", 12*time.Minute, 10*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
+			}
+			return true, helpers.CheckField(cluster, "len(AKSStatus.UpstreamSpec.NodePools)", currentNodePoolNumber-1, len(*cluster.AKSStatus.UpstreamSpec.NodePools))
+		})
+		if err != nil {
+			return cluster, err
+		}
+		if err = checkNodePoolNames(cluster, "AKSStatus.UpstreamSpec", *cluster.AKSStatus.UpstreamSpec.NodePools, updatedNodePoolsList); err != nil {
+			return cluster, err
 		}
 	}
 	return cluster, nil
 }
 
+// checkNodePoolNames returns a helpers.ConfigMismatchError if the nodepools of the given config are not the expected ones, in the same order
+func checkNodePoolNames(cluster *management.Cluster, config string, nodePools, expectedNodePools []management.AKSNodePool) error {
+	if err := helpers.CheckField(cluster, fmt.Sprintf("len(%s.NodePools)", config), len(expectedNodePools), len(nodePools)); err != nil {
+		return err
+	}
+	for i, np := range nodePools {
+		if err := helpers.CheckField(cluster, fmt.Sprintf("%s.NodePools[%d].Name", config, i), *expectedNodePools[i].Name, *np.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ScaleNodePool modifies the number of initialNodeCount of all the nodepools as defined by nodeCount;
 // if wait is set to true, it will wait until the cluster finishes upgrading;
 // if checkClusterConfig is set to true, it will validate that nodepool has been scaled successfully
-func ScaleNodePool(cluster *management.Cluster, client *rancher.Client, nodeCount int64, wait, checkClusterConfig bool) (*management.Cluster, error) {
+func ScaleNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, nodeCount int64, wait, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	configNodePools := *upgradedCluster.AKSConfig.NodePools
 	for i := range configNodePools {
@@ -310,42 +367,55 @@ func ScaleNodePool(cluster *management.Cluster, client *rancher.Client, nodeCoun
 
 	var err error
 	cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
-	Expect(err).To(BeNil())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to scale nodepools")
+	}
 
 	if checkClusterConfig {
-		configNodePools := *cluster.AKSConfig.NodePools
 		// Check if the desired config is set correctly
-		for i := range configNodePools {
-			Expect(*configNodePools[i].Count).To(BeNumerically("==", nodeCount))
+		if err = checkNodePoolCounts(cluster, "AKSConfig", *cluster.AKSConfig.NodePools, nodeCount); err != nil {
+			return cluster, err
 		}
 	}
 
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
-		Expect(err).To(BeNil())
+		if err = helpers.WaitClusterToBeUpgraded(ctx, client, cluster.ID, helpers.OperationScale); err != nil {
+			return cluster, errors.Wrap(err, "Failed to wait for the nodepools scaling")
+		}
 	}
 
 	if checkClusterConfig {
 		// check that the desired config is applied on Rancher
-		Eventually(func() bool {
-			ginkgo.GinkgoLogr.Info("Waiting for the node count change to appear in AKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			upstreamNodePools := *cluster.AKSStatus.UpstreamSpec.NodePools
-			for i := range upstreamNodePools {
-				if *upstreamNodePools[i].Count != nodeCount {
-					return false
-				}
+		err = helpers.WaitFor(ctx, "the node count change to appear in AKSStatus.UpstreamSpec", 12*time.Minute, 10*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
 			}
-			return true
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeTrue())
+			return true, checkNodePoolCounts(cluster, "AKSStatus.UpstreamSpec", *cluster.AKSStatus.UpstreamSpec.NodePools, nodeCount)
+		})
+		if err != nil {
+			return cluster, err
+		}
 	}
 
 	return cluster, nil
 }
 
+// checkNodePoolCounts returns a helpers.ConfigMismatchError if the node count of a nodepool of the given config is not the expected one
+func checkNodePoolCounts(cluster *management.Cluster, config string, nodePools []management.AKSNodePool, nodeCount int64) error {
+	for _, np := range nodePools {
+		if err := helpers.CheckField(cluster, fmt.Sprintf("%s.NodePools[%s].Count", config, *np.Name), nodeCount, *np.Count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ListAKSAvailableVersions lists all the available and UI supported AKS versions for cluster upgrade; in ascending order: 1.28.0, 1.28.3, etc.
-func ListAKSAvailableVersions(client *rancher.Client, clusterID string) ([]string, error) {
+func ListAKSAvailableVersions(ctx context.Context, client *rancher.Client, clusterID string) ([]string, error) {
+	// the shepherd calls below do not take a context, so a cancelled spec stops before them
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	// kubernetesversions.ListAKSAvailableVersions expects cluster.Version.GitVersion to be available, which it is not sometimes, so we fetch the cluster again to ensure it has all the available data
 	cluster, err := client.Management.Cluster.ByID(clusterID)
 	if err != nil {
@@ -355,12 +425,12 @@ func ListAKSAvailableVersions(client *rancher.Client, clusterID string) ([]strin
 	if err != nil {
 		return nil, err
 	}
-	return helpers.FilterUIUnsupportedVersions(allAvailableVersions, client), nil
+	return helpers.FilterUIUnsupportedVersions(allAvailableVersions, client)
 }
 
 // UpdateAutoScaling updates the management.AKSNodePool Autoscaling for all the node pools of an AKS cluster
 // if checkClusterConfig is true, it validates the update
-func UpdateAutoScaling(cluster *management.Cluster, client *rancher.Client, enabled bool, maxCount, minCount int64, checkClusterConfig bool) (*management.Cluster, error) {
+func UpdateAutoScaling(ctx context.Context, cluster *management.Cluster, client *rancher.Client, enabled bool, maxCount, minCount int64, checkClusterConfig bool) (*management.Cluster, error) {
 	if enabled {
 		if minCount == 0 && maxCount == 0 {
 			return nil, fmt.Errorf("minCount and maxCount cannot be zero when enabling autoscaling")
@@ -397,71 +467,71 @@ func UpdateAutoScaling(cluster *management.Cluster, client *rancher.Client, enab
 	var err error
 	cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "Failed to update autoscaling")
 	}
 
 	if checkClusterConfig {
-		for _, np := range *cluster.AKSConfig.NodePools {
-			Expect(*np.EnableAutoScaling).To(BeEquivalentTo(enabled))
-			if enabled {
-				if np.MaxCount != nil {
-					Expect(*np.MaxCount).To(BeEquivalentTo(maxCount))
-				}
-
-				if np.MinCount != nil {
-					Expect(*np.MinCount).To(BeEquivalentTo(minCount))
-				}
-
-				if npCount != 0 {
-					Expect(*np.Count).To(BeEquivalentTo(npCount))
-				}
-			} else {
-				Expect(np.MaxCount).To(BeNil())
-				Expect(np.MinCount).To(BeNil())
-			}
+		if err = checkAutoScaling(cluster, false, *cluster.AKSConfig.NodePools, enabled, maxCount, minCount, npCount); err != nil {
+			return cluster, err
 		}
 	}
 
 	if checkClusterConfig {
-		Eventually(func() bool {
-			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Waiting for the autoscaling update (enable: %v) to appear in AKSStatus.UpstreamSpec ...", enabled))
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			for _, np := range *cluster.AKSStatus.UpstreamSpec.NodePools {
-				if enabled {
-					if np.EnableAutoScaling != nil && !*np.EnableAutoScaling {
-						return false
-					}
-					if np.MaxCount != nil && *np.MaxCount != maxCount {
-						return false
-
-					}
-					if np.MinCount != nil && *np.MinCount != minCount {
-						return false
-
-					}
-					if npCount != 0 {
-						if *np.Count != npCount {
-							return false
-						}
-					}
-				} else {
-					if *np.EnableAutoScaling {
-						return false
-					}
-					if np.MaxCount != nil && np.MinCount != nil {
-						return false
-					}
-				}
+		err = helpers.WaitFor(ctx, fmt.Sprintf("the autoscaling update (enable: %v) to appear in AKSStatus.UpstreamSpec", enabled), 10*time.Minute, 15*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
 			}
-			return true
-		}, tools.SetTimeout(10*time.Minute), 15*time.Second).Should(BeTrue())
+			return true, checkAutoScaling(cluster, true, *cluster.AKSStatus.UpstreamSpec.NodePools, enabled, maxCount, minCount, npCount)
+		})
+		if err != nil {
+			return cluster, err
+		}
 	}
 	return cluster, nil
 }
 
+// checkAutoScaling returns a helpers.ConfigMismatchError if the autoscaling of a nodepool of the given config is not the expected one;
+// the min and max count are only checked if they are set, and the node count only if it was raised to the min count, i.e. npCount is not 0
+func checkAutoScaling(cluster *management.Cluster, upstream bool, nodePools []management.AKSNodePool, enabled bool, maxCount, minCount, npCount int64) error {
+	config := "AKSConfig"
+	if upstream {
+		config = "AKSStatus.UpstreamSpec"
+	}
+	for _, np := range nodePools {
+		field := fmt.Sprintf("%s.NodePools[%s].", config, *np.Name)
+		if np.EnableAutoScaling != nil || !enabled {
+			if err := helpers.CheckField(cluster, field+"EnableAutoScaling", enabled, np.EnableAutoScaling != nil && *np.EnableAutoScaling); err != nil {
+				return err
+			}
+		}
+		if !enabled {
+			// the upstream spec may keep one of the counts once the autoscaling is disabled
+			if (np.MaxCount != nil || np.MinCount != nil) && (!upstream || np.MaxCount != nil && np.MinCount != nil) {
+				return &helpers.ConfigMismatchError{ClusterName: cluster.Name, Field: field + "MinCount/MaxCount", Expected: "unset", Actual: fmt.Sprintf("%d/%d", pointer.Int64Deref(np.MinCount, 0), pointer.Int64Deref(np.MaxCount, 0))}
+			}
+			continue
+		}
+		if np.MaxCount != nil {
+			if err := helpers.CheckField(cluster, field+"MaxCount", maxCount, *np.MaxCount); err != nil {
+				return err
+			}
+		}
+		if np.MinCount != nil {
+			if err := helpers.CheckField(cluster, field+"MinCount", minCount, *np.MinCount); err != nil {
+				return err
+			}
+		}
+		if npCount != 0 {
+			if err := helpers.CheckField(cluster, field+"Count", npCount, *np.Count); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // UpdateCluster is a generic function to update a cluster
-func UpdateCluster(cluster *management.Cluster, client *rancher.Client, updateFunc func(*management.Cluster)) (*management.Cluster, error) {
+func UpdateCluster(ctx context.Context, cluster *management.Cluster, client *rancher.Client, updateFunc func(*management.Cluster)) (*management.Cluster, error) {
 	upgradedCluster := cluster
 
 	updateFunc(upgradedCluster)
@@ -471,8 +541,8 @@ func UpdateCluster(cluster *management.Cluster, client *rancher.Client, updateFu
 
 // ====================================================================Azure CLI (start)=================================
 // Create Azure AKS cluster using AZ CLI
-func CreateAKSClusterOnAzure(location string, clusterName string, k8sVersion string, nodes string, tags map[string]string, extraArgs ...string) error {
-	err := CreateAKSRGOnAzure(clusterName, location)
+func CreateAKSClusterOnAzure(ctx context.Context, location string, clusterName string, k8sVersion string, nodes string, tags map[string]string, extraArgs ...string) error {
+	err := CreateAKSRGOnAzure(ctx, clusterName, location)
 	if err != nil {
 		return err
 	}
@@ -490,9 +560,10 @@ func CreateAKSClusterOnAzure(location string, clusterName string, k8sVersion str
 		args = append(args, extraArgs...)
 	}
 
-	fmt.Printf("Running command: az %v\n", args)
 	var out string
-	out, err = proc.RunW("az", args...)
+	out, err = helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return createAKSClusterWithSDK(ctx, location, clusterName, k8sVersion, nodes, tags, extraArgs)
+	}, "az", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to create cluster: "+out)
 	}
@@ -503,28 +574,34 @@ func CreateAKSClusterOnAzure(location string, clusterName string, k8sVersion str
 }
 
 // CreateAKSRGOnAzure creates resource group on azure via CLI
-func CreateAKSRGOnAzure(name, location string) error {
+func CreateAKSRGOnAzure(ctx context.Context, name, location string) error {
 	fmt.Println("Creating AKS resource group ...")
 	rgargs := []string{"group", "create", "--location", location, "--resource-group", name, "--subscription", subscriptionID}
-	fmt.Printf("Running command: az %v\n", rgargs)
 
-	out, err := proc.RunW("az", rgargs...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return createAKSRGWithSDK(ctx, name, location)
+	}, "az", rgargs...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to create resource group: "+out)
 	}
 	fmt.Println("Created AKS resource group: ", name)
+	// deleting the resource group deletes the cluster created in it too
+	helpers.RegisterClusterCleanup(helpers.CleanupKey("aks-resource-group", name), fmt.Sprintf("AKS resource group %s", name), func(ctx context.Context) error {
+		return DeleteAKSClusteronAzure(ctx, name)
+	})
 	return nil
 }
 
 // AddNodePoolOnAzure adds nodepool to an AKS cluster via CLI; helpful when creating a cluster with multiple nodepools
-func AddNodePoolOnAzure(npName, clusterName, resourceGroupName, nodeCount string, extraArgs ...string) error {
+func AddNodePoolOnAzure(ctx context.Context, npName, clusterName, resourceGroupName, nodeCount string, extraArgs ...string) error {
 	fmt.Println("Adding node pool ...")
 	args := []string{"aks", "nodepool", "add", "--resource-group", resourceGroupName, "--cluster-name", clusterName, "--name", npName, "--node-count", nodeCount, "--subscription", subscriptionID}
 	if len(extraArgs) > 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: az %v\n", args)
-	out, err := proc.RunW("az", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return addNodePoolWithSDK(ctx, npName, clusterName, resourceGroupName, nodeCount, extraArgs)
+	}, "az", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to add node pool: "+out)
 	}
@@ -533,14 +610,15 @@ func AddNodePoolOnAzure(npName, clusterName, resourceGroupName, nodeCount string
 }
 
 // DeleteNodePoolOnAzure deletes nodepool from an AKS cluster via CLI
-func DeleteNodePoolOnAzure(npName, clusterName, resourceGroupName string, extraArgs ...string) error {
+func DeleteNodePoolOnAzure(ctx context.Context, npName, clusterName, resourceGroupName string, extraArgs ...string) error {
 	fmt.Println("Deleting node pool ...")
 	args := []string{"aks", "nodepool", "delete", "--resource-group", resourceGroupName, "--cluster-name", clusterName, "--name", npName, "--subscription", subscriptionID}
 	if len(extraArgs) > 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: az %v\n", args)
-	out, err := proc.RunW("az", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return deleteNodePoolWithSDK(ctx, npName, clusterName, resourceGroupName, extraArgs)
+	}, "az", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to delete node pool: "+out)
 	}
@@ -549,14 +627,15 @@ func DeleteNodePoolOnAzure(npName, clusterName, resourceGroupName string, extraA
 }
 
 // ScaleNodePoolOnAzure scales nodepool of an AKS cluster via CLI
-func ScaleNodePoolOnAzure(npName, clusterName, resourceGroupName, nodeCount string, extraArgs ...string) error {
+func ScaleNodePoolOnAzure(ctx context.Context, npName, clusterName, resourceGroupName, nodeCount string, extraArgs ...string) error {
 	fmt.Println("Scaling node pool ...")
 	args := []string{"aks", "nodepool", "scale", "--resource-group", resourceGroupName, "--cluster-name", clusterName, "--name", npName, "--node-count", nodeCount, "--subscription", subscriptionID}
 	if len(extraArgs) > 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: az %v\n", args)
-	out, err := proc.RunW("az", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return scaleNodePoolWithSDK(ctx, npName, clusterName, resourceGroupName, nodeCount, extraArgs)
+	}, "az", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to scale node pool: "+out)
 	}
@@ -565,7 +644,7 @@ func ScaleNodePoolOnAzure(npName, clusterName, resourceGroupName, nodeCount stri
 }
 
 // UpdateClusterTagOnAzure updates the tags of an existing AKS cluster via CLI
-func UpdateClusterTagOnAzure(tags map[string]string, clusterName, resourceGroupName string, extraArgs ...string) error {
+func UpdateClusterTagOnAzure(ctx context.Context, tags map[string]string, clusterName, resourceGroupName string, extraArgs ...string) error {
 	fmt.Println("Adding tags on Azure ...")
 	args := []string{"aks", "update", "--resource-group", resourceGroupName, "--name", clusterName, "--subscription", subscriptionID}
 
@@ -578,8 +657,9 @@ func UpdateClusterTagOnAzure(tags map[string]string, clusterName, resourceGroupN
 	if len(extraArgs) > 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: az %v\n", args)
-	out, err := proc.RunW("az", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return updateClusterTagWithSDK(ctx, tags, clusterName, resourceGroupName, extraArgs)
+	}, "az", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to add tag on Azure: "+out)
 	}
@@ -589,11 +669,18 @@ func UpdateClusterTagOnAzure(tags map[string]string, clusterName, resourceGroupN
 
 // ClusterExistsOnAzure gets a list of cluster based on the name filter and returns true if the cluster is not in Deleting state;
 // it returns false if the cluster does not exist or is in Deleting state.
-func ClusterExistsOnAzure(clusterName, resourceGroup string) (bool, error) {
+func ClusterExistsOnAzure(ctx context.Context, clusterName, resourceGroup string) (bool, error) {
 	fmt.Println("Showing AKS cluster ...")
+	if helpers.UseCloudSDK() {
+		exists, err := clusterExistsWithSDK(ctx, clusterName, resourceGroup)
+		if err != nil {
+			return false, errors.Wrap(err, "Failed to show cluster")
+		}
+		return exists, nil
+	}
 	args := []string{"aks", "show", "--subscription", subscriptionID, "--name", clusterName, "--resource-group", resourceGroup}
 	fmt.Printf("Running command: az %v\n", args)
-	out, err := proc.RunW("az", args...)
+	out, err := helpers.RunCLI(ctx, "az", args...)
 	if err != nil {
 		return false, errors.Wrap(err, "Failed to show cluster: "+out)
 	}
@@ -604,7 +691,11 @@ func ClusterExistsOnAzure(clusterName, resourceGroup string) (bool, error) {
 }
 
 // RunCommand executes `aks command invoke` which runs a command inside a cluster;  useful when registering a private cluster with rancher
-func RunCommand(clusterName, resourceGroup, command string) error {
+func RunCommand(ctx context.Context, clusterName, resourceGroup, command string) error {
+	if helpers.UseCloudSDK() {
+		return errors.Wrap(runCommandWithSDK(ctx, clusterName, resourceGroup, command), "Failed to run command")
+	}
+
 	currentKubeconfig := os.Getenv("KUBECONFIG")
 	downstreamKubeconfig := helpers.DownstreamKubeconfig(clusterName)
 	defer func() {
@@ -616,7 +707,7 @@ func RunCommand(clusterName, resourceGroup, command string) error {
 	fmt.Printf("Logging into the cluster")
 	loginArgs := []string{"aks", "get-credentials", "--resource-group", resourceGroup, "--name", clusterName, "--overwrite-existing", "--subscription", subscriptionID}
 	fmt.Printf("Running command: az %v\n", loginArgs)
-	out, err := proc.RunW("az", loginArgs...)
+	out, err := helpers.RunCLI(ctx, "az", loginArgs...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to run command: "+out)
 	}
@@ -624,7 +715,7 @@ func RunCommand(clusterName, resourceGroup, command string) error {
 	args := []string{"aks", "command", "invoke", "--resource-group", resourceGroup, "--name", clusterName, "--subscription", subscriptionID, "--command", command}
 	fmt.Printf("Running command inside the cluster: az %v\n", args)
 
-	out, err = proc.RunW("az", args...)
+	out, err = helpers.RunCLI(ctx, "az", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to run command: "+out)
 	}
@@ -634,14 +725,15 @@ func RunCommand(clusterName, resourceGroup, command string) error {
 // UpgradeAKSOnAzure upgrade the AKS cluster using az CLI
 // `--control-plane-only` flag can be passed to only upgrade Control Plane version. (Default) If not specified, both control plane AND all node pools will be upgraded.
 // `--node-image-only` flag can be passed to only upgrade Node Pool version
-func UpgradeAKSOnAzure(clusterName, resourceGroup, upgradeToVersion string, additionalArgs ...string) error {
+func UpgradeAKSOnAzure(ctx context.Context, clusterName, resourceGroup, upgradeToVersion string, additionalArgs ...string) error {
 	fmt.Println("Upgrading AKS cluster ...")
 	args := []string{"aks", "upgrade", "--subscription", subscriptionID, "--resource-group", resourceGroup, "--name", clusterName, "--kubernetes-version", upgradeToVersion, "--yes"}
 	if len(additionalArgs) > 0 {
 		args = append(args, additionalArgs...)
 	}
-	fmt.Printf("Running command: az %v\n", args)
-	out, err := proc.RunW("az", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return upgradeAKSWithSDK(ctx, clusterName, resourceGroup, upgradeToVersion, additionalArgs)
+	}, "az", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to upgrade cluster: "+out)
 	}
@@ -649,28 +741,90 @@ func UpgradeAKSOnAzure(clusterName, resourceGroup, upgradeToVersion string, addi
 	return nil
 }
 
-// convertMapToAKSString converts the map of labels to a string format acceptable by azure CLI
+// convertMapToAKSString converts the map of labels to a string format acceptable by azure CLI; the keys are sorted so that the arguments are deterministic
 func convertMapToAKSString(tags map[string]string) []string {
 	var convertedString []string
 	for key, value := range tags {
 		convertedString = append(convertedString, fmt.Sprintf("%s=%s", key, value))
 	}
+	sort.Strings(convertedString)
 	return convertedString
 }
 
 // DeleteAKSClusteronAzure Complete cleanup steps for Azure AKS
-func DeleteAKSClusteronAzure(clusterName string) error {
+func DeleteAKSClusteronAzure(ctx context.Context, clusterName string) error {
 
 	fmt.Println("Deleting AKS resource group which will delete cluster too ...")
 	args := []string{"group", "delete", "--name", clusterName, "--yes", "--subscription", subscriptionID}
-	fmt.Printf("Running command: az %v\n", args)
 
-	out, err := proc.RunW("az", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return deleteAKSClusterWithSDK(ctx, clusterName)
+	}, "az", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to delete resource group: "+out)
 	}
 
 	fmt.Println("Deleted AKS resource group: ", clusterName)
+	helpers.ForgetCleanup(helpers.CleanupKey("aks-resource-group", clusterName))
+
+	return nil
+}
+
+// ListAKSClustersOnAzure lists the AKS clusters of the subscription in all the locations
+func ListAKSClustersOnAzure(ctx context.Context) ([]helpers.CloudCluster, error) {
+	fmt.Println("Listing AKS clusters ...")
+	if helpers.UseCloudSDK() {
+		clusters, err := listAKSClustersWithSDK(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to list clusters")
+		}
+		return clusters, nil
+	}
+	args := []string{"aks", "list", "--subscription", subscriptionID, "--output", "json"}
+	fmt.Printf("Running command: az %v\n", args)
+	out, err := helpers.RunCLI(ctx, "az", args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list clusters: "+out)
+	}
+
+	var listed []struct {
+		Name          string            `json:"name"`
+		Location      string            `json:"location"`
+		ResourceGroup string            `json:"resourceGroup"`
+		Tags          map[string]string `json:"tags"`
+		SystemData    struct {
+			CreatedAt time.Time `json:"createdAt"`
+		} `json:"systemData"`
+	}
+	if err = json.Unmarshal([]byte(out), &listed); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse the list of clusters")
+	}
+	var clusters []helpers.CloudCluster
+	for _, cluster := range listed {
+		clusters = append(clusters, helpers.CloudCluster{
+			Name:          cluster.Name,
+			Location:      cluster.Location,
+			ResourceGroup: cluster.ResourceGroup,
+			Labels:        cluster.Tags,
+			CreatedAt:     cluster.SystemData.CreatedAt,
+		})
+	}
+	return clusters, nil
+}
+
+// DeleteAKSClusterFromRGOnAzure deletes the AKS cluster only, for e.g. when its resource group is shared with other clusters
+func DeleteAKSClusterFromRGOnAzure(ctx context.Context, clusterName, resourceGroup string) error {
+	fmt.Println("Deleting AKS cluster ...")
+	args := []string{"aks", "delete", "--name", clusterName, "--resource-group", resourceGroup, "--yes", "--subscription", subscriptionID}
+
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return deleteAKSClusterFromRGWithSDK(ctx, clusterName, resourceGroup)
+	}, "az", args...)
+	if err != nil {
+		return errors.Wrap(err, "Failed to delete cluster: "+out)
+	}
+
+	fmt.Println("Deleted AKS cluster: ", clusterName)
 
 	return nil
 }
@@ -680,11 +834,11 @@ func DeleteAKSClusteronAzure(clusterName string) error {
 // GetK8sVersion returns the k8s version to be used by the test;
 // this value can either be a variant of envvar DOWNSTREAM_K8S_MINOR_VERSION or the highest available version
 // or second-highest minor version in case of upgrade scenarios
-func GetK8sVersion(client *rancher.Client, cloudCredentialID, region string, forUpgrade bool) (string, error) {
+func GetK8sVersion(ctx context.Context, client *rancher.Client, cloudCredentialID, region string, forUpgrade bool) (string, error) {
 	if k8sMinorVersion := helpers.DownstreamK8sMinorVersion; k8sMinorVersion != "" {
-		return GetK8sVersionVariantAKS(k8sMinorVersion, client, cloudCredentialID, region)
+		return GetK8sVersionVariantAKS(ctx, k8sMinorVersion, client, cloudCredentialID, region)
 	}
-	allVariants, err := ListSingleVariantAKSAllVersions(client, cloudCredentialID, region)
+	allVariants, err := ListSingleVariantAKSAllVersions(ctx, client, cloudCredentialID, region)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/pkg/config"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakerancher"
+)
+
+// TestScaleNodePool runs the helper outside a Ginkgo spec and without a Gomega fail handler, as a tool would
+func TestScaleNodePool(t *testing.T) {
+	t.Setenv(config.ConfigEnvironmentKey, "")
+	server := fakerancher.NewServer()
+	defer server.Close()
+	configPath, err := server.WriteConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(configPath)
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the cluster becomes active as soon as it is updated so that the upstream spec is synced before the first check
+	server.UpdateTransitions = []fakerancher.ClusterState{fakerancher.StateActive}
+
+	id := server.AddCluster(&management.Cluster{
+		Name: "fake-aks",
+		AKSConfig: &management.AKSClusterConfigSpec{
+			ClusterName:       "fake-aks",
+			KubernetesVersion: pointer.String("1.31.4"),
+			NodePools:         &[]management.AKSNodePool{{Name: pointer.String("np"), Count: pointer.Int64(1)}},
+		},
+	}, fakerancher.StateActive)
+	cluster, err := client.Management.Cluster.ByID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cluster, err = ScaleNodePool(context.Background(), cluster, client, 3, false, true)
+	if err != nil {
+		t.Fatalf("ScaleNodePool() error = %v", err)
+	}
+	if count := *(*cluster.AKSStatus.UpstreamSpec.NodePools)[0].Count; count != 3 {
+		t.Errorf("upstream node count = %d, want 3", count)
+	}
+}
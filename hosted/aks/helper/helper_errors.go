@@ -0,0 +1,29 @@
+package helper
+
+import (
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// The known errors of the aks-operator and of AKS, as set in cluster.TransitioningMessage; see suite.HaveTransitionError
+var (
+	ErrSystemPoolRemoval = helpers.RegisterOperatorError("aks", "system pool removal",
+		helpers.ErrorMessage{Pattern: `cannot remove node pool \[?\S+?\]? with mode System from cluster`})
+	ErrNoSystemPool = helpers.RegisterOperatorError("aks", "no system pool",
+		helpers.ErrorMessage{Pattern: `^at least one NodePool with mode System is required$`})
+	ErrDuplicateNodePoolName = helpers.RegisterOperatorError("aks", "duplicate nodepool name",
+		helpers.ErrorMessage{Pattern: `nodePool names must be unique within the \[.*\] cluster`})
+	ErrDuplicateClusterConfig = helpers.RegisterOperatorError("aks", "duplicate AKSClusterConfig",
+		helpers.ErrorMessage{Pattern: `an AKSClusterConfig exists with the same name`})
+	ErrNodePoolVersionIncompatible = helpers.RegisterOperatorError("aks", "nodepool version incompatible",
+		helpers.ErrorMessage{Pattern: `Node pool version \S+ and control plane version \S+ are incompatible`})
+	ErrNodeCountOutOfRange = helpers.RegisterOperatorError("aks", "node count out of range",
+		helpers.ErrorMessage{Pattern: `It must be greater or equal to minCount:\d+ and less than or equal to maxCount:\d+`})
+	ErrInsufficientMaxPods = helpers.RegisterOperatorError("aks", "insufficient max pods",
+		helpers.ErrorMessage{Pattern: `InsufficientMaxPods`})
+	ErrAvailabilityZonesChange = helpers.RegisterOperatorError("aks", "availability zones change",
+		helpers.ErrorMessage{Pattern: `Changing availability zones for node pool .* is not permitted`})
+	ErrAvailabilityZoneUnsupported = helpers.RegisterOperatorError("aks", "availability zone unsupported",
+		helpers.ErrorMessage{Pattern: `Availability zone is not supported in region`})
+	ErrClusterAgentDisconnected = helpers.RegisterOperatorError("aks", "cluster agent disconnected",
+		helpers.ErrorMessage{Pattern: `failed to communicate with cluster: error generating service account token.*cluster agent disconnected`})
+)
@@ -0,0 +1,165 @@
+package helper
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+func init() {
+	helpers.RegisterHostedProvider(Provider{})
+}
+
+// Provider implements helpers.HostedProvider for AKS; the location is obtained via helpers.GetAKSLocation
+type Provider struct{}
+
+func (Provider) Name() string {
+	return "aks"
+}
+
+func (Provider) GetK8sVersion(ctx context.Context, client *rancher.Client, cloudCredentialID string, forUpgrade bool) (string, error) {
+	return GetK8sVersion(ctx, client, cloudCredentialID, helpers.GetAKSLocation(), forUpgrade)
+}
+
+func (Provider) ListAvailableVersions(ctx context.Context, client *rancher.Client, cluster *management.Cluster) ([]string, error) {
+	return ListAKSAvailableVersions(ctx, client, cluster.ID)
+}
+
+func (Provider) CreateHostedCluster(ctx context.Context, client *rancher.Client, clusterName, cloudCredentialID, k8sVersion string) (*management.Cluster, error) {
+	return CreateAKSHostedCluster(ctx, client, clusterName, cloudCredentialID, k8sVersion, helpers.GetAKSLocation(), nil)
+}
+
+func (Provider) ImportHostedCluster(ctx context.Context, client *rancher.Client, clusterName, cloudCredentialID string) (*management.Cluster, error) {
+	return ImportAKSHostedCluster(ctx, client, clusterName, cloudCredentialID, helpers.GetAKSLocation(), helpers.GetCommonMetadataLabels())
+}
+
+func (Provider) DeleteHostedCluster(ctx context.Context, cluster *management.Cluster, client *rancher.Client) error {
+	return DeleteAKSHostCluster(ctx, cluster, client)
+}
+
+func (Provider) NodePoolCount(cluster *management.Cluster) int {
+	return len(*cluster.AKSConfig.NodePools)
+}
+
+func (Provider) NodeCount(cluster *management.Cluster) int64 {
+	return *(*cluster.AKSConfig.NodePools)[0].Count
+}
+
+func (Provider) ScaleNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, nodeCount int64, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	return ScaleNodePool(ctx, cluster, client, nodeCount, wait, checkClusterConfig)
+}
+
+func (Provider) AddNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, increaseBy int, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	return AddNodePool(ctx, cluster, increaseBy, client, wait, checkClusterConfig)
+}
+
+func (Provider) DeleteNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	return DeleteNodePool(ctx, cluster, client, wait, checkClusterConfig)
+}
+
+func (Provider) UpgradeControlPlane(ctx context.Context, cluster *management.Cluster, client *rancher.Client, upgradeToVersion string, checkClusterConfig bool) (*management.Cluster, error) {
+	return UpgradeClusterKubernetesVersion(ctx, cluster, upgradeToVersion, client, checkClusterConfig)
+}
+
+func (Provider) UpgradeNodePools(ctx context.Context, cluster *management.Cluster, client *rancher.Client, upgradeToVersion string, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	return UpgradeNodeKubernetesVersion(ctx, cluster, upgradeToVersion, client, wait, checkClusterConfig)
+}
+
+func (Provider) CreateClusterOnCloud(ctx context.Context, clusterName, k8sVersion string, nodeCount int64) error {
+	return CreateAKSClusterOnAzure(ctx, helpers.GetAKSLocation(), clusterName, k8sVersion, strconv.FormatInt(nodeCount, 10), helpers.GetCommonMetadataLabels())
+}
+
+func (Provider) DeleteClusterOnCloud(ctx context.Context, clusterName string) error {
+	return DeleteAKSClusteronAzure(ctx, clusterName)
+}
+
+func (Provider) ListClustersOnCloud(ctx context.Context) ([]helpers.CloudCluster, error) {
+	return ListAKSClustersOnAzure(ctx)
+}
+
+// DeleteListedClusterOnCloud deletes the resource group of the cluster if it is named after the cluster, as done by CreateAKSClusterOnAzure
+func (Provider) DeleteListedClusterOnCloud(ctx context.Context, cluster helpers.CloudCluster) error {
+	if cluster.ResourceGroup == cluster.Name {
+		return DeleteAKSClusteronAzure(ctx, cluster.Name)
+	}
+	return DeleteAKSClusterFromRGOnAzure(ctx, cluster.Name, cluster.ResourceGroup)
+}
+
+func (Provider) UpdateClusterTags(ctx context.Context, cluster *management.Cluster, client *rancher.Client, tags map[string]string) (*management.Cluster, error) {
+	return UpdateCluster(ctx, cluster, client, func(upgradedCluster *management.Cluster) {
+		upgradedCluster.AKSConfig.Tags = tags
+	})
+}
+
+func (Provider) ConfigSpec(cluster *management.Cluster) helpers.ClusterSpec {
+	return clusterSpec(cluster.AKSConfig)
+}
+
+func (Provider) UpstreamSpec(cluster *management.Cluster) helpers.ClusterSpec {
+	if cluster.AKSStatus == nil {
+		return helpers.ClusterSpec{}
+	}
+	return clusterSpec(cluster.AKSStatus.UpstreamSpec)
+}
+
+func (Provider) ScaleNodePoolOnCloud(ctx context.Context, cluster *management.Cluster, nodeCount int64) error {
+	npName := *(*cluster.AKSConfig.NodePools)[0].Name
+	return ScaleNodePoolOnAzure(ctx, npName, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, strconv.FormatInt(nodeCount, 10))
+}
+
+func (Provider) AddNodePoolOnCloud(ctx context.Context, cluster *management.Cluster, poolName string) error {
+	return AddNodePoolOnAzure(ctx, poolName, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, "1")
+}
+
+func (Provider) UpgradeControlPlaneOnCloud(ctx context.Context, cluster *management.Cluster, upgradeToVersion string) error {
+	return UpgradeAKSOnAzure(ctx, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, upgradeToVersion, "--control-plane-only")
+}
+
+func (Provider) ClusterSpecOnCloud(ctx context.Context, cluster *management.Cluster) (helpers.ClusterSpec, error) {
+	aksCluster, err := GetAKSClusterOnAzure(ctx, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup)
+	if err != nil {
+		return helpers.ClusterSpec{}, err
+	}
+	return azureClusterSpec(aksCluster), nil
+}
+
+// clusterSpec converts an AKS cluster config or upstream spec to a helpers.ClusterSpec
+func clusterSpec(config *management.AKSClusterConfigSpec) helpers.ClusterSpec {
+	var spec helpers.ClusterSpec
+	if config == nil {
+		return spec
+	}
+	spec.KubernetesVersion = pointer.StringDeref(config.KubernetesVersion, "")
+	spec.Tags = config.Tags
+	if config.Monitoring != nil {
+		spec.Logging = monitoringLogging(*config.Monitoring)
+	}
+	spec.PrivateAccess = config.PrivateCluster
+	if config.NodePools != nil {
+		for _, np := range *config.NodePools {
+			spec.NodePools = append(spec.NodePools, helpers.NodePoolSpec{
+				Name:              pointer.StringDeref(np.Name, ""),
+				NodeCount:         pointer.Int64Deref(np.Count, 0),
+				KubernetesVersion: pointer.StringDeref(np.OrchestratorVersion, ""),
+				Labels:            np.NodeLabels,
+				Autoscaling:       np.EnableAutoScaling,
+				MinCount:          np.MinCount,
+				MaxCount:          np.MaxCount,
+			})
+		}
+	}
+	return spec
+}
+
+// monitoringLogging returns the ClusterSpec logging of an AKS cluster with or without monitoring
+func monitoringLogging(monitoring bool) []string {
+	if monitoring {
+		return []string{"monitoring"}
+	}
+	return []string{}
+}
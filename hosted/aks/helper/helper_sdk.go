@@ -0,0 +1,416 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// ====================================================================Azure SDK (start)=================================
+// The functions below implement the Azure CLI helpers when helpers.UseCloudSDK() is true;
+// they are called by the *OnAzure functions and should not be used directly.
+
+var (
+	// azureClientOptions are passed to every Azure SDK client; unit tests use them to point the clients to a fake server
+	azureClientOptions *arm.ClientOptions
+	// azureCredential returns the credential used by the Azure SDK clients; it uses the same service principal as the Azure cloud credential
+	azureCredential = func() (azcore.TokenCredential, error) {
+		return azidentity.NewClientSecretCredential(helpers.Config.AKS.TenantID, helpers.Config.AKS.ClientID, helpers.Config.AKS.ClientSecret, nil)
+	}
+	// azurePollFrequency is the interval between two checks of a long-running operation
+	azurePollFrequency = 15 * time.Second
+	// azureDefaultVMSize is the VM size used by `az aks create` and `az aks nodepool add` when none is provided
+	azureDefaultVMSize = "Standard_DS2_v2"
+)
+
+type azureClients struct {
+	resourceGroups *armresources.ResourceGroupsClient
+	clusters       *armcontainerservice.ManagedClustersClient
+	agentPools     *armcontainerservice.AgentPoolsClient
+}
+
+func newAzureClients() (*azureClients, error) {
+	credential, err := azureCredential()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get Azure credential")
+	}
+	clients := &azureClients{}
+	if clients.resourceGroups, err = armresources.NewResourceGroupsClient(subscriptionID, credential, azureClientOptions); err != nil {
+		return nil, err
+	}
+	if clients.clusters, err = armcontainerservice.NewManagedClustersClient(subscriptionID, credential, azureClientOptions); err != nil {
+		return nil, err
+	}
+	if clients.agentPools, err = armcontainerservice.NewAgentPoolsClient(subscriptionID, credential, azureClientOptions); err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+// azureTags converts the tags to the format expected by the Azure SDK
+func azureTags(tags map[string]string) map[string]*string {
+	azureTags := map[string]*string{}
+	for key, value := range tags {
+		azureTags[key] = to.Ptr(value)
+	}
+	return azureTags
+}
+
+func azurePollOptions() *runtime.PollUntilDoneOptions {
+	return &runtime.PollUntilDoneOptions{Frequency: azurePollFrequency}
+}
+
+// GetAKSClusterOnAzure returns the AKS cluster as reported by the Azure API; it always uses the Azure SDK
+func GetAKSClusterOnAzure(ctx context.Context, clusterName, resourceGroup string) (*armcontainerservice.ManagedCluster, error) {
+	clients, err := newAzureClients()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := clients.clusters.Get(ctx, resourceGroup, clusterName, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get cluster")
+	}
+	return &resp.ManagedCluster, nil
+}
+
+// azureClusterSpec converts an AKS cluster as reported by the Azure API to a helpers.ClusterSpec
+func azureClusterSpec(cluster *armcontainerservice.ManagedCluster) helpers.ClusterSpec {
+	spec := helpers.ClusterSpec{Tags: map[string]string{}}
+	for key, value := range cluster.Tags {
+		spec.Tags[key] = pointer.StringDeref(value, "")
+	}
+	properties := cluster.Properties
+	if properties == nil {
+		return spec
+	}
+	spec.KubernetesVersion = pointer.StringDeref(properties.KubernetesVersion, "")
+	monitoring := false
+	if addon, ok := properties.AddonProfiles["omsagent"]; ok && addon != nil {
+		monitoring = pointer.BoolDeref(addon.Enabled, false)
+	}
+	spec.Logging = monitoringLogging(monitoring)
+	privateCluster := false
+	if properties.APIServerAccessProfile != nil {
+		privateCluster = pointer.BoolDeref(properties.APIServerAccessProfile.EnablePrivateCluster, false)
+	}
+	spec.PrivateAccess = &privateCluster
+	for _, np := range properties.AgentPoolProfiles {
+		nodePool := helpers.NodePoolSpec{
+			Name:              pointer.StringDeref(np.Name, ""),
+			NodeCount:         int64(pointer.Int32Deref(np.Count, 0)),
+			KubernetesVersion: pointer.StringDeref(np.OrchestratorVersion, ""),
+			Labels:            map[string]string{},
+			Autoscaling:       pointer.Bool(pointer.BoolDeref(np.EnableAutoScaling, false)),
+		}
+		for key, value := range np.NodeLabels {
+			nodePool.Labels[key] = pointer.StringDeref(value, "")
+		}
+		if np.MinCount != nil {
+			nodePool.MinCount = pointer.Int64(int64(*np.MinCount))
+		}
+		if np.MaxCount != nil {
+			nodePool.MaxCount = pointer.Int64(int64(*np.MaxCount))
+		}
+		spec.NodePools = append(spec.NodePools, nodePool)
+	}
+	return spec
+}
+
+func createAKSRGWithSDK(ctx context.Context, name, location string) error {
+	clients, err := newAzureClients()
+	if err != nil {
+		return err
+	}
+	_, err = clients.resourceGroups.CreateOrUpdate(ctx, name, armresources.ResourceGroup{Location: to.Ptr(location)}, nil)
+	return err
+}
+
+func createAKSClusterWithSDK(ctx context.Context, location, clusterName, k8sVersion, nodes string, tags map[string]string, extraArgs []string) error {
+	if _, err := helpers.ParseSDKArgs(extraArgs, nil, nil); err != nil {
+		return err
+	}
+	nodeCount, err := strconv.ParseInt(nodes, 10, 32)
+	if err != nil {
+		return err
+	}
+	clients, err := newAzureClients()
+	if err != nil {
+		return err
+	}
+
+	cluster := armcontainerservice.ManagedCluster{
+		Location: to.Ptr(location),
+		Tags:     azureTags(tags),
+		Identity: &armcontainerservice.ManagedClusterIdentity{Type: to.Ptr(armcontainerservice.ResourceIdentityTypeSystemAssigned)},
+		Properties: &armcontainerservice.ManagedClusterProperties{
+			KubernetesVersion: to.Ptr(k8sVersion),
+			DNSPrefix:         to.Ptr(clusterName),
+			AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+				{
+					Name:   to.Ptr("nodepool1"),
+					Count:  to.Ptr(int32(nodeCount)),
+					VMSize: to.Ptr(azureDefaultVMSize),
+					Mode:   to.Ptr(armcontainerservice.AgentPoolModeSystem),
+					OSType: to.Ptr(armcontainerservice.OSTypeLinux),
+				},
+			},
+		},
+	}
+	poller, err := clients.clusters.BeginCreateOrUpdate(ctx, clusterName, clusterName, cluster, nil)
+	if err != nil {
+		return err
+	}
+	_, err = poller.PollUntilDone(ctx, azurePollOptions())
+	return err
+}
+
+func addNodePoolWithSDK(ctx context.Context, npName, clusterName, resourceGroupName, nodeCount string, extraArgs []string) error {
+	args, err := helpers.ParseSDKArgs(extraArgs, []string{"--mode"}, nil)
+	if err != nil {
+		return err
+	}
+	count, err := strconv.ParseInt(nodeCount, 10, 32)
+	if err != nil {
+		return err
+	}
+	mode := armcontainerservice.AgentPoolModeUser
+	if value, ok := args["--mode"]; ok {
+		mode = armcontainerservice.AgentPoolMode(value)
+	}
+	clients, err := newAzureClients()
+	if err != nil {
+		return err
+	}
+
+	agentPool := armcontainerservice.AgentPool{
+		Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+			Count:  to.Ptr(int32(count)),
+			VMSize: to.Ptr(azureDefaultVMSize),
+			Mode:   to.Ptr(mode),
+			OSType: to.Ptr(armcontainerservice.OSTypeLinux),
+		},
+	}
+	poller, err := clients.agentPools.BeginCreateOrUpdate(ctx, resourceGroupName, clusterName, npName, agentPool, nil)
+	if err != nil {
+		return err
+	}
+	_, err = poller.PollUntilDone(ctx, azurePollOptions())
+	return err
+}
+
+func deleteNodePoolWithSDK(ctx context.Context, npName, clusterName, resourceGroupName string, extraArgs []string) error {
+	if _, err := helpers.ParseSDKArgs(extraArgs, nil, nil); err != nil {
+		return err
+	}
+	clients, err := newAzureClients()
+	if err != nil {
+		return err
+	}
+	poller, err := clients.agentPools.BeginDelete(ctx, resourceGroupName, clusterName, npName, nil)
+	if err != nil {
+		return err
+	}
+	_, err = poller.PollUntilDone(ctx, azurePollOptions())
+	return err
+}
+
+func scaleNodePoolWithSDK(ctx context.Context, npName, clusterName, resourceGroupName, nodeCount string, extraArgs []string) error {
+	if _, err := helpers.ParseSDKArgs(extraArgs, nil, nil); err != nil {
+		return err
+	}
+	count, err := strconv.ParseInt(nodeCount, 10, 32)
+	if err != nil {
+		return err
+	}
+	clients, err := newAzureClients()
+	if err != nil {
+		return err
+	}
+	resp, err := clients.agentPools.Get(ctx, resourceGroupName, clusterName, npName, nil)
+	if err != nil {
+		return err
+	}
+	agentPool := resp.AgentPool
+	if agentPool.Properties == nil {
+		agentPool.Properties = &armcontainerservice.ManagedClusterAgentPoolProfileProperties{}
+	}
+	agentPool.Properties.Count = to.Ptr(int32(count))
+	poller, err := clients.agentPools.BeginCreateOrUpdate(ctx, resourceGroupName, clusterName, npName, agentPool, nil)
+	if err != nil {
+		return err
+	}
+	_, err = poller.PollUntilDone(ctx, azurePollOptions())
+	return err
+}
+
+func updateClusterTagWithSDK(ctx context.Context, tags map[string]string, clusterName, resourceGroupName string, extraArgs []string) error {
+	if _, err := helpers.ParseSDKArgs(extraArgs, nil, nil); err != nil {
+		return err
+	}
+	clients, err := newAzureClients()
+	if err != nil {
+		return err
+	}
+	poller, err := clients.clusters.BeginUpdateTags(ctx, resourceGroupName, clusterName, armcontainerservice.TagsObject{Tags: azureTags(tags)}, nil)
+	if err != nil {
+		return err
+	}
+	_, err = poller.PollUntilDone(ctx, azurePollOptions())
+	return err
+}
+
+func clusterExistsWithSDK(ctx context.Context, clusterName, resourceGroup string) (bool, error) {
+	cluster, err := GetAKSClusterOnAzure(ctx, clusterName, resourceGroup)
+	if err != nil {
+		return false, err
+	}
+	if cluster.Properties != nil && cluster.Properties.ProvisioningState != nil && *cluster.Properties.ProvisioningState == "Deleting" {
+		return false, nil
+	}
+	return true, nil
+}
+
+// runCommandWithSDK does not need to fetch the kubeconfig since the command is run by the Azure API
+func runCommandWithSDK(ctx context.Context, clusterName, resourceGroup, command string) error {
+	clients, err := newAzureClients()
+	if err != nil {
+		return err
+	}
+	poller, err := clients.clusters.BeginRunCommand(ctx, resourceGroup, clusterName, armcontainerservice.RunCommandRequest{Command: to.Ptr(command)}, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := poller.PollUntilDone(ctx, azurePollOptions())
+	if err != nil {
+		return err
+	}
+	if result := resp.Properties; result != nil && result.ExitCode != nil && *result.ExitCode != 0 {
+		var logs string
+		if result.Logs != nil {
+			logs = *result.Logs
+		}
+		return fmt.Errorf("command exited with code %d: %s", *result.ExitCode, logs)
+	}
+	return nil
+}
+
+// upgradeAKSWithSDK supports the same modes as `az aks upgrade`: --control-plane-only, --node-image-only or both control plane and node pools by default
+func upgradeAKSWithSDK(ctx context.Context, clusterName, resourceGroup, upgradeToVersion string, additionalArgs []string) error {
+	args, err := helpers.ParseSDKArgs(additionalArgs, nil, []string{"--control-plane-only", "--node-image-only"})
+	if err != nil {
+		return err
+	}
+	clients, err := newAzureClients()
+	if err != nil {
+		return err
+	}
+	resp, err := clients.clusters.Get(ctx, resourceGroup, clusterName, nil)
+	if err != nil {
+		return err
+	}
+	cluster := resp.ManagedCluster
+
+	if args["--node-image-only"] != "" {
+		for _, profile := range cluster.Properties.AgentPoolProfiles {
+			poller, err := clients.agentPools.BeginUpgradeNodeImageVersion(ctx, resourceGroup, clusterName, *profile.Name, nil)
+			if err != nil {
+				return err
+			}
+			if _, err = poller.PollUntilDone(ctx, azurePollOptions()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cluster.Properties.KubernetesVersion = to.Ptr(upgradeToVersion)
+	if args["--control-plane-only"] == "" {
+		for _, profile := range cluster.Properties.AgentPoolProfiles {
+			profile.OrchestratorVersion = to.Ptr(upgradeToVersion)
+		}
+	}
+	poller, err := clients.clusters.BeginCreateOrUpdate(ctx, resourceGroup, clusterName, cluster, nil)
+	if err != nil {
+		return err
+	}
+	_, err = poller.PollUntilDone(ctx, azurePollOptions())
+	return err
+}
+
+func deleteAKSClusterWithSDK(ctx context.Context, clusterName string) error {
+	clients, err := newAzureClients()
+	if err != nil {
+		return err
+	}
+	poller, err := clients.resourceGroups.BeginDelete(ctx, clusterName, nil)
+	if err != nil {
+		return err
+	}
+	_, err = poller.PollUntilDone(ctx, azurePollOptions())
+	return err
+}
+
+func listAKSClustersWithSDK(ctx context.Context) ([]helpers.CloudCluster, error) {
+	clients, err := newAzureClients()
+	if err != nil {
+		return nil, err
+	}
+	var clusters []helpers.CloudCluster
+	pager := clients.clusters.NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, listed := range page.Value {
+			cluster := helpers.CloudCluster{
+				Name:     pointer.StringDeref(listed.Name, ""),
+				Location: pointer.StringDeref(listed.Location, ""),
+				Labels:   map[string]string{},
+			}
+			if listed.ID != nil {
+				id, err := arm.ParseResourceID(*listed.ID)
+				if err != nil {
+					return nil, err
+				}
+				cluster.ResourceGroup = id.ResourceGroupName
+			}
+			for key, value := range listed.Tags {
+				cluster.Labels[key] = pointer.StringDeref(value, "")
+			}
+			if listed.SystemData != nil && listed.SystemData.CreatedAt != nil {
+				cluster.CreatedAt = *listed.SystemData.CreatedAt
+			}
+			clusters = append(clusters, cluster)
+		}
+	}
+	return clusters, nil
+}
+
+func deleteAKSClusterFromRGWithSDK(ctx context.Context, clusterName, resourceGroup string) error {
+	clients, err := newAzureClients()
+	if err != nil {
+		return err
+	}
+	poller, err := clients.clusters.BeginDelete(ctx, resourceGroup, clusterName, nil)
+	if err != nil {
+		return err
+	}
+	_, err = poller.PollUntilDone(ctx, azurePollOptions())
+	return err
+}
+
+//====================================================================Azure SDK (end)=================================
@@ -0,0 +1,116 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/fake"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("Azure SDK", func() {
+	const (
+		clusterName = "aks-sdk"
+		sub         = "sub-id"
+		clusterPath = "/subscriptions/" + sub + "/resourceGroups/" + clusterName + "/providers/Microsoft.ContainerService/managedClusters/" + clusterName
+	)
+	var (
+		requests          []string
+		bodies            map[string]map[string]any
+		provisioningState string
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		bodies = map[string]map[string]any{}
+		provisioningState = "Succeeded"
+
+		mux := http.NewServeMux()
+		handle := func(pattern string, response func() any) {
+			mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+					body := map[string]any{}
+					Expect(json.Unmarshal(data, &body)).To(Succeed())
+					bodies[r.Method+" "+r.URL.Path] = body
+				}
+				w.Header().Set("Content-Type", "application/json")
+				Expect(json.NewEncoder(w).Encode(response())).To(Succeed())
+			})
+		}
+		handle("GET "+clusterPath, func() any {
+			return map[string]any{"name": clusterName, "properties": map[string]any{"provisioningState": provisioningState}}
+		})
+		handle("GET "+clusterPath+"/agentPools/{name}", func() any {
+			return map[string]any{"name": "np1", "properties": map[string]any{"count": 1, "mode": "User", "provisioningState": "Succeeded"}}
+		})
+		handle("PUT "+clusterPath+"/agentPools/{name}", func() any {
+			return map[string]any{"name": "np1", "properties": map[string]any{"count": 3, "mode": "User", "provisioningState": "Succeeded"}}
+		})
+		srv := httptest.NewTLSServer(mux)
+		DeferCleanup(srv.Close)
+
+		originalSubscriptionID, originalOptions, originalCredential := subscriptionID, azureClientOptions, azureCredential
+		DeferCleanup(func() {
+			subscriptionID, azureClientOptions, azureCredential = originalSubscriptionID, originalOptions, originalCredential
+		})
+		subscriptionID = sub
+		azureClientOptions = &arm.ClientOptions{ClientOptions: policy.ClientOptions{
+			Cloud:     cloud.Configuration{Services: map[cloud.ServiceName]cloud.ServiceConfiguration{cloud.ResourceManager: {Endpoint: srv.URL, Audience: srv.URL}}},
+			Transport: srv.Client(),
+		}}
+		azureCredential = func() (azcore.TokenCredential, error) {
+			return &fake.TokenCredential{}, nil
+		}
+
+		DeferCleanup(func(backend string) { helpers.CloudBackend = backend }, helpers.CloudBackend)
+		helpers.CloudBackend = helpers.SDKBackend
+	})
+
+	DescribeTable("ClusterExistsOnAzure",
+		func(ctx SpecContext, state string, expected bool) {
+			provisioningState = state
+
+			exists, err := ClusterExistsOnAzure(ctx, clusterName, clusterName)
+			Expect(err).To(BeNil())
+			Expect(exists).To(Equal(expected))
+		},
+		Entry("returns true for a running cluster", "Succeeded", true),
+		Entry("returns false for a deleting cluster", "Deleting", false),
+	)
+
+	It("ScaleNodePoolOnAzure updates the node count of the existing nodepool", func(ctx SpecContext) {
+		Expect(ScaleNodePoolOnAzure(ctx, "np1", clusterName, clusterName, "3")).To(Succeed())
+
+		Expect(requests).To(Equal([]string{"GET " + clusterPath + "/agentPools/np1", "PUT " + clusterPath + "/agentPools/np1"}))
+		Expect(bodies["PUT "+clusterPath+"/agentPools/np1"]).To(HaveKeyWithValue("properties", And(HaveKeyWithValue("count", 3.0), HaveKeyWithValue("mode", "User"))))
+	})
+
+	It("rejects the extra arguments it cannot translate", func(ctx SpecContext) {
+		Expect(AddNodePoolOnAzure(ctx, "np2", clusterName, clusterName, "1", "--node-vm-size", "Standard_D4s_v3")).To(MatchError(ContainSubstring(`argument "--node-vm-size" is not supported by the sdk cloud backend`)))
+		Expect(requests).To(BeEmpty())
+	})
+})
@@ -1,8 +1,6 @@
 package k8s_chart_support_test
 
 import (
-	"fmt"
-
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
@@ -14,28 +12,18 @@ import (
 var _ = Describe("K8sChartSupportImport", func() {
 	var cluster *management.Cluster
 
-	BeforeEach(func() {
-		err := helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+	BeforeEach(func(specCtx SpecContext) {
+		err := helper.CreateAKSClusterOnAzure(specCtx, location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
 		Expect(err).To(BeNil())
-		cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+		cluster, err = helper.ImportAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 	})
-	AfterEach(func() {
-		if ctx.ClusterCleanup && cluster != nil {
-			err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
-			Expect(err).To(BeNil())
-			err = helper.DeleteAKSClusteronAzure(clusterName)
-			Expect(err).To(BeNil())
-		} else {
-			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-		}
-	})
 
-	It("should successfully test k8s chart support import", func() {
+	It("should successfully test k8s chart support import", func(specCtx SpecContext) {
 		testCaseID = 254 // Report to Qase
-		commonchecks(ctx.RancherAdminClient, cluster)
+		commonchecks(specCtx, ctx.RancherAdminClient, cluster)
 
 	})
 
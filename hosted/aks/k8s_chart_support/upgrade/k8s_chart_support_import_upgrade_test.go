@@ -14,30 +14,20 @@ import (
 var _ = Describe("K8sChartSupportUpgradeImport", func() {
 	var cluster *management.Cluster
 
-	BeforeEach(func() {
-		err := helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+	BeforeEach(func(specCtx SpecContext) {
+		err := helper.CreateAKSClusterOnAzure(specCtx, location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
 		Expect(err).To(BeNil())
-		cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+		cluster, err = helper.ImportAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 	})
-	AfterEach(func() {
-		if ctx.ClusterCleanup && cluster != nil {
-			err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
-			Expect(err).To(BeNil())
-			err = helper.DeleteAKSClusteronAzure(clusterName)
-			Expect(err).To(BeNil())
-		} else {
-			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-		}
-	})
 
-	It("should successfully test k8s chart support import in an upgrade scenario", func() {
+	It("should successfully test k8s chart support import in an upgrade scenario", func(specCtx SpecContext) {
 		GinkgoLogr.Info(fmt.Sprintf("Testing K8s %s chart support for import on Rancher upgraded from %s to %s", helpers.K8sUpgradedMinorVersion, helpers.RancherFullVersion, helpers.RancherUpgradeFullVersion))
 
 		testCaseID = 253 // Report to Qase
-		commonchecks(&ctx, cluster, clusterName, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
+		commonchecks(specCtx, &ctx, cluster, clusterName, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
 	})
 
 })
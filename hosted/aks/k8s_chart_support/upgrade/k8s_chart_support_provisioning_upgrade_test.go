@@ -15,28 +15,19 @@ var _ = Describe("K8sChartSupportUpgradeProvisioning", func() {
 	var (
 		cluster *management.Cluster
 	)
-	BeforeEach(func() {
+	BeforeEach(func(specCtx SpecContext) {
 		var err error
-		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
+		cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 	})
 
-	AfterEach(func() {
-		if ctx.ClusterCleanup && cluster != nil {
-			err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
-			Expect(err).To(BeNil())
-		} else {
-			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-		}
-	})
-
-	It("should successfully test k8s chart support provisioning in an upgrade scenario", func() {
+	It("should successfully test k8s chart support provisioning in an upgrade scenario", func(specCtx SpecContext) {
 		GinkgoLogr.Info(fmt.Sprintf("Testing K8s %s chart support for provisioning on Rancher upgraded from %s to %s", helpers.K8sUpgradedMinorVersion, helpers.RancherFullVersion, helpers.RancherUpgradeFullVersion))
 
 		testCaseID = 251 // Report to Qase
-		commonchecks(&ctx, cluster, clusterName, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
+		commonchecks(specCtx, &ctx, cluster, clusterName, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
 	})
 
 })
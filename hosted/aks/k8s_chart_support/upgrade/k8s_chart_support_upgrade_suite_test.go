@@ -1,6 +1,7 @@
 package k8s_chart_support_upgrade_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -12,7 +13,6 @@ import (
 	. "github.com/rancher-sandbox/qase-ginkgo"
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	"github.com/rancher/shepherd/extensions/clusters"
 	nodestat "github.com/rancher/shepherd/extensions/nodes"
 	"github.com/rancher/shepherd/extensions/workloads/pods"
 	"github.com/rancher/shepherd/pkg/config"
@@ -20,6 +20,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var (
@@ -35,7 +36,21 @@ func TestK8sChartSupportUpgrade(t *testing.T) {
 	RunSpecs(t, "K8sChartSupportUpgrade Suite")
 }
 
-var _ = BeforeEach(func() {
+var _ = BeforeEach(func(specCtx SpecContext) {
+	// deferred rather than run in AfterEach so that it runs after the teardown of the downstream cluster; see helpers.CleanupRegistry
+	DeferCleanup(func(specCtx SpecContext) {
+		// The test must restore the env to its original state, so we install rancher back to its original version and uninstall the operator charts
+		By(fmt.Sprintf("Installing Rancher back to its original version %s", helpers.RancherFullVersion), func() {
+			rancherChannel, rancherVersion, rancherHeadVersion := suite.GetRancherVersions(helpers.RancherFullVersion)
+			suite.InstallRancherManager(specCtx, k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
+			suite.CheckRancherDeployments(specCtx, k)
+		})
+
+		By("Uninstalling the existing operator charts", func() {
+			suite.UninstallOperatorCharts(specCtx)
+		})
+	})
+
 	// For upgrade tests, the rancher version should not be an unreleased version (for e.g. 2.9-head)
 	Expect(helpers.RancherFullVersion).To(SatisfyAll(Not(BeEmpty()), Not(ContainSubstring("devel"))))
 
@@ -44,17 +59,17 @@ var _ = BeforeEach(func() {
 	Expect(helpers.Kubeconfig).ToNot(BeEmpty())
 
 	By("Adding the necessary chart repos", func() {
-		helpers.AddRancherCharts()
+		suite.AddRancherCharts(specCtx)
 	})
 
 	By(fmt.Sprintf("Installing Rancher Manager %s", helpers.RancherFullVersion), func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
-		helpers.CheckRancherDeployments(k)
+		rancherChannel, rancherVersion, rancherHeadVersion := suite.GetRancherVersions(helpers.RancherFullVersion)
+		suite.InstallRancherManager(specCtx, k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
+		suite.CheckRancherDeployments(specCtx, k)
 	})
 
-	helpers.CommonSynchronizedBeforeSuite()
-	ctx = helpers.CommonBeforeSuite()
+	suite.CommonSynchronizedBeforeSuite()
+	ctx = suite.CommonBeforeSuite()
 
 	By("creating and using a more permanent token", func() {
 		token, err := ctx.RancherAdminClient.Management.Token.Create(&management.Token{})
@@ -72,51 +87,41 @@ var _ = BeforeEach(func() {
 	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
 	var err error
 	// For k8s chart support upgrade we want to begin with the default k8s version; we will upgrade rancher and then upgrade k8s to the default available there.
-	k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, false)
+	k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, location, false)
 	Expect(err).To(BeNil())
 	Expect(k8sVersion).ToNot(BeEmpty())
 	GinkgoLogr.Info(fmt.Sprintf("Using AKS version %s for cluster %s", k8sVersion, clusterName))
 
 })
 
-var _ = AfterEach(func() {
-	// The test must restore the env to its original state, so we install rancher back to its original version and uninstall the operator charts
-	By(fmt.Sprintf("Installing Rancher back to its original version %s", helpers.RancherFullVersion), func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
-		helpers.CheckRancherDeployments(k)
-	})
-
-	By("Uninstalling the existing operator charts", func() {
-		helpers.UninstallOperatorCharts()
-	})
-})
-
 var _ = ReportBeforeEach(func(report SpecReport) {
 	// Reset case ID
 	testCaseID = -1
 })
 
-var _ = ReportAfterEach(func(report SpecReport) {
-	// Add result in Qase if asked
+var _ = ReportAfterEach(func(specCtx SpecContext, report SpecReport) {
+	// Add result in Qase and in the run report if asked
 	Qase(testCaseID, report)
+	suite.WriteRunReport(specCtx, report, testCaseID, ctx.RancherAdminClient)
 })
 
-func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clusterName, rancherUpgradedVersion, k8sUpgradedVersion string) {
-	helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+func commonchecks(specCtx context.Context, ctx *helpers.RancherContext, cluster *management.Cluster, clusterName, rancherUpgradedVersion, k8sUpgradedVersion string) {
+	suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 
 	var originalChartVersion string
 
 	By("checking the chart version", func() {
-		originalChartVersion = helpers.GetCurrentOperatorChartVersion()
+		var err error
+		originalChartVersion, err = helpers.GetCurrentOperatorChartVersion(specCtx)
+		Expect(err).To(BeNil())
 		Expect(originalChartVersion).ToNot(BeEmpty())
 		GinkgoLogr.Info("Original chart version: " + originalChartVersion)
 	})
 
 	By("upgrading rancher", func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(rancherUpgradedVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
-		helpers.CheckRancherDeployments(k)
+		rancherChannel, rancherVersion, rancherHeadVersion := suite.GetRancherVersions(rancherUpgradedVersion)
+		suite.InstallRancherManager(specCtx, k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
+		suite.CheckRancherDeployments(specCtx, k)
 
 		By("ensuring operator pods are also up", func() {
 			Eventually(func() error {
@@ -145,8 +150,10 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 
 	var upgradedChartVersion string
 	By("checking the chart version and validating it is > the old version", func() {
-		helpers.WaitUntilOperatorChartInstallation(originalChartVersion, "==", 1)
-		upgradedChartVersion = helpers.GetCurrentOperatorChartVersion()
+		suite.WaitUntilOperatorChartInstallation(specCtx, originalChartVersion, "==", 1)
+		var err error
+		upgradedChartVersion, err = helpers.GetCurrentOperatorChartVersion(specCtx)
+		Expect(err).To(BeNil())
 		GinkgoLogr.Info("Upgraded chart version: " + upgradedChartVersion)
 	})
 
@@ -154,7 +161,7 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 		var err error
 		cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 		Expect(err).To(BeNil())
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 
 		// since no changes have been made to the cluster so far, we need reinstantiate AKSConfig after fetching the cluster
 		if helpers.IsImport {
@@ -164,7 +171,7 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 
 	var latestK8sVersion string
 	By(fmt.Sprintf("fetching a list of available k8s versions and ensure the v%s is present in the list and upgrading the cluster to it", k8sUpgradedVersion), func() {
-		versions, err := helper.ListAKSAvailableVersions(ctx.RancherAdminClient, cluster.ID)
+		versions, err := helper.ListAKSAvailableVersions(specCtx, ctx.RancherAdminClient, cluster.ID)
 		Expect(err).To(BeNil())
 		Expect(versions).ToNot(BeEmpty())
 		GinkgoLogr.Info(fmt.Sprintf("Available AKS versions: %v", versions))
@@ -172,41 +179,43 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 		latestK8sVersion = versions[len(versions)-1]
 		Expect(latestK8sVersion).To(ContainSubstring(k8sUpgradedVersion))
 		Expect(helpers.VersionCompare(latestK8sVersion, cluster.Version.GitVersion)).To(BeNumerically("==", 1))
-		cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, latestK8sVersion, ctx.RancherAdminClient, true)
+		cluster, err = helper.UpgradeClusterKubernetesVersion(specCtx, cluster, latestK8sVersion, ctx.RancherAdminClient, true)
 		Expect(err).To(BeNil())
 	})
 
 	var downgradeVersion string
 	By("fetching a value to downgrade to", func() {
-		downgradeVersion = helpers.GetDowngradeOperatorChartVersion(upgradedChartVersion)
+		var err error
+		downgradeVersion, err = helpers.GetDowngradeOperatorChartVersion(specCtx, upgradedChartVersion)
+		Expect(err).To(BeNil())
 	})
 
 	By("downgrading the chart version", func() {
-		helpers.DowngradeProviderChart(downgradeVersion)
+		suite.DowngradeProviderChart(specCtx, downgradeVersion)
 	})
 
 	By("making a change to the cluster (upgrade nodepool k8s version) to validate functionality after chart downgrade", func() {
 		var err error
-		cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, latestK8sVersion, ctx.RancherAdminClient, true, true)
+		cluster, err = helper.UpgradeNodeKubernetesVersion(specCtx, cluster, latestK8sVersion, ctx.RancherAdminClient, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("uninstalling the operator chart", func() {
-		helpers.UninstallOperatorCharts()
+		suite.UninstallOperatorCharts(specCtx)
 	})
 
 	By("making a change(adding a nodepool) to the cluster to re-install the operator and validating it is re-installed to the latest/upgraded version", func() {
 		currentNodePoolNumber := len(*cluster.AKSConfig.NodePools)
 		var err error
-		cluster, err = helper.AddNodePool(cluster, 1, ctx.RancherAdminClient, false, false)
+		cluster, err = helper.AddNodePool(specCtx, cluster, 1, ctx.RancherAdminClient, false, false)
 		Expect(err).To(BeNil())
 		Expect(len(*cluster.AKSConfig.NodePools)).To(BeNumerically("==", currentNodePoolNumber+1))
 
 		By("ensuring that the chart is re-installed to the latest/upgraded version", func() {
-			helpers.WaitUntilOperatorChartInstallation(upgradedChartVersion, "", 0)
+			suite.WaitUntilOperatorChartInstallation(specCtx, upgradedChartVersion, "", 0)
 		})
 
-		err = clusters.WaitClusterToBeUpgraded(ctx.RancherAdminClient, cluster.ID)
+		err = helpers.WaitClusterToBeUpdated(specCtx, ctx.RancherAdminClient, cluster.ID)
 		Expect(err).To(BeNil())
 		// Check if the desired config has been applied in Rancher
 		Eventually(func() int {
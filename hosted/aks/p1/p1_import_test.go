@@ -9,92 +9,76 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var _ = Describe("P1Import", func() {
 	var k8sVersion string
-	BeforeEach(func() {
+	BeforeEach(func(specCtx SpecContext) {
 		// assigning cluster nil value so that every new test has a fresh value of the variable
 		// this is to avoid using residual value of a cluster in a test that does not use it
 		cluster = nil
 		GinkgoLogr.Info(fmt.Sprintf("Running on process: %d", GinkgoParallelProcess()))
 
 		var err error
-		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, false)
+		k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, location, false)
 		Expect(err).NotTo(HaveOccurred())
 		GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 	})
 
-	AfterEach(func() {
-		if ctx.ClusterCleanup {
-			if cluster != nil && cluster.ID != "" {
-				GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
-				err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
-				Expect(err).To(BeNil())
-			}
-			err := helper.DeleteAKSClusteronAzure(clusterName)
-			Expect(err).To(BeNil())
-
-		} else {
-			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-		}
-	})
-
 	When("a cluster is created and imported", func() {
-		BeforeEach(func() {
-			err := helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+		BeforeEach(func(specCtx SpecContext) {
+			err := helper.CreateAKSClusterOnAzure(specCtx, location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
 			Expect(err).To(BeNil())
 
-			cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+			cluster, err = helper.ImportAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
 
-		It("should successfully update with new cloud credentials", func() {
+		It("should successfully update with new cloud credentials", func(specCtx SpecContext) {
 			testCaseID = 292
-			updateCloudCredentialsCheck(cluster, ctx.RancherAdminClient)
+			updateCloudCredentialsCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should fail to update with invalid (deleted) cloud credential and update when the cloud credentials becomes valid", func() {
+		It("should fail to update with invalid (deleted) cloud credential and update when the cloud credentials becomes valid", func(specCtx SpecContext) {
 			testCaseID = 238
-			invalidateCloudCredentialsCheck(cluster, ctx.RancherAdminClient, ctx.CloudCredID)
+			invalidateCloudCredentialsCheck(specCtx, cluster, ctx.RancherAdminClient, ctx.CloudCredID)
 		})
 
-		It("should be able to update autoscaling", func() {
+		It("should be able to update autoscaling", func(specCtx SpecContext) {
 			testCaseID = 266
-			updateAutoScaling(cluster, ctx.RancherAdminClient)
+			updateAutoScaling(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should be able to update tags", func() {
+		It("should be able to update tags", func(specCtx SpecContext) {
 			testCaseID = 270
-			updateTagsCheck(cluster, ctx.RancherAdminClient)
+			updateTagsCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should fail to change system nodepool count to 0", func() {
+		It("should fail to change system nodepool count to 0", func(specCtx SpecContext) {
 			testCaseID = 290
-			updateSystemNodePoolCountToZeroCheck(cluster, ctx.RancherAdminClient)
+			updateSystemNodePoolCountToZeroCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should be able to update cluster monitoring", func() {
-			if helpers.SkipUpgradeTests {
-				Skip(helpers.SkipUpgradeTestsLog)
-			}
+		It("should be able to update cluster monitoring", func(specCtx SpecContext) {
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 			testCaseID = 271
-			updateMonitoringCheck(cluster, ctx.RancherAdminClient)
+			updateMonitoringCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should fail to reimport an imported cluster", func() {
+		It("should fail to reimport an imported cluster", func(specCtx SpecContext) {
 			testCaseID = 235
-			_, err := helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+			_, err := helper.ImportAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
 			Expect(err).To(HaveOccurred())
 
 			Expect(err.Error()).To(ContainSubstring("cluster already exists for AKS cluster"))
 		})
 
-		It("should be possible to re-import a deleted cluster", func() {
+		It("should be possible to re-import a deleted cluster", func(specCtx SpecContext) {
 			testCaseID = 239
-			err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
+			err := helper.DeleteAKSHostCluster(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 			clusterID := cluster.ID
 			Eventually(func() error {
@@ -102,54 +86,50 @@ var _ = Describe("P1Import", func() {
 				_, err := ctx.RancherAdminClient.Management.Cluster.ByID(clusterID)
 				return err
 			}, "30s", "3s").ShouldNot(BeNil())
-			cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+			cluster, err = helper.ImportAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
 	})
 
-	It("should successfully Import a cluster in Region without AZ", func() {
-		if helpers.SkipUpgradeTests {
-			Skip(helpers.SkipUpgradeTestsLog)
-		}
+	It("should successfully Import a cluster in Region without AZ", func(specCtx SpecContext) {
+		suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 		location = "ukwest"
 		testCaseID = 276
 
 		var err error
-		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, true)
+		k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, location, true)
 		Expect(err).NotTo(HaveOccurred())
 		GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 
-		err = helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+		err = helper.CreateAKSClusterOnAzure(specCtx, location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
 		Expect(err).To(BeNil())
 
-		cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+		cluster, err = helper.ImportAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 
-		noAvailabilityZoneP0Checks(cluster, ctx.RancherAdminClient)
+		noAvailabilityZoneP0Checks(specCtx, cluster, ctx.RancherAdminClient)
 	})
 
-	It("should be able to register a cluster with no rbac", func() {
+	It("should be able to register a cluster with no rbac", func(specCtx SpecContext) {
 		testCaseID = 237
-		err := helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels(), "--disable-rbac")
+		err := helper.CreateAKSClusterOnAzure(specCtx, location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels(), "--disable-rbac")
 		Expect(err).To(BeNil())
 
-		cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+		cluster, err = helper.ImportAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 	})
 
 	When("a cluster with custom kubelet and os config is created and imported for upgrade", func() {
 		var upgradeToVersion string
-		BeforeEach(func() {
-			if helpers.SkipUpgradeTests {
-				Skip(helpers.SkipUpgradeTestsLog)
-			}
+		BeforeEach(func(specCtx SpecContext) {
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 
 			kubeletConfigJsonData := `{"cpuManagerPolicy": "static", "cpuCfsQuota": true, "cpuCfsQuotaPeriod": "200ms", "imageGcHighThreshold": 90, "imageGcLowThreshold": 70, "topologyManagerPolicy": "best-effort", "allowedUnsafeSysctls": ["kernel.msg*","net.*"], "failSwapOn": false}`
 			kubeletConfigDotJson, err := os.CreateTemp("", "custom-kubelet-*.json")
@@ -163,112 +143,106 @@ var _ = Describe("P1Import", func() {
 			err = os.WriteFile(osConfigDotJson.Name(), []byte(osConfigJsonData), 0644)
 			Expect(err).ToNot(HaveOccurred())
 
-			k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, true)
+			k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, location, true)
 			Expect(err).NotTo(HaveOccurred())
 			GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 
-			err = helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels(), "--kubelet-config", kubeletConfigDotJson.Name(), "--linux-os-config", osConfigDotJson.Name())
+			err = helper.CreateAKSClusterOnAzure(specCtx, location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels(), "--kubelet-config", kubeletConfigDotJson.Name(), "--linux-os-config", osConfigDotJson.Name())
 			Expect(err).To(BeNil())
-			cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+			cluster, err = helper.ImportAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 
-			availableVersions, err := helper.ListAKSAvailableVersions(ctx.RancherAdminClient, cluster.ID)
+			availableVersions, err := helper.ListAKSAvailableVersions(specCtx, ctx.RancherAdminClient, cluster.ID)
 			Expect(err).To(BeNil())
 			upgradeToVersion = availableVersions[0]
 		})
 
-		It("should successfully upgrade the cluster", func() {
+		It("should successfully upgrade the cluster", func(specCtx SpecContext) {
 			testCaseID = 260
 			var err error
 			By("upgrading control plane version", func() {
-				cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeToVersion, ctx.RancherAdminClient, true)
+				cluster, err = helper.UpgradeClusterKubernetesVersion(specCtx, cluster, upgradeToVersion, ctx.RancherAdminClient, true)
 				Expect(err).To(BeNil())
 			})
 			By("upgrading nodepool version", func() {
-				cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, upgradeToVersion, ctx.RancherAdminClient, true, true)
+				cluster, err = helper.UpgradeNodeKubernetesVersion(specCtx, cluster, upgradeToVersion, ctx.RancherAdminClient, true, true)
 				Expect(err).To(BeNil())
 			})
 		})
 	})
 
 	When("a cluster is created with multiple nodepools", func() {
-		BeforeEach(func() {
+		BeforeEach(func(specCtx SpecContext) {
 			var err error
-			err = helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+			err = helper.CreateAKSClusterOnAzure(specCtx, location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
 			Expect(err).To(BeNil())
 
 			// AKS already consists on one system pool, so we add a user pool
-			err = helper.AddNodePoolOnAzure("userpool", clusterName, clusterName, "2", "--mode", "User")
+			err = helper.AddNodePoolOnAzure(specCtx, "userpool", clusterName, clusterName, "2", "--mode", "User")
 			Expect(err).To(BeNil())
 
-			cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+			cluster, err = helper.ImportAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
 
-		It("should not be able to remove system nodepool", func() {
-			if helpers.SkipTest {
-				Skip("Skipping test for v2.8, v2.9 ...")
-			}
+		It("should not be able to remove system nodepool", func(specCtx SpecContext) {
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureAKSNodePoolValidation)
 			testCaseID = 267
-			removeSystemNpCheck(cluster, ctx.RancherAdminClient)
+			removeSystemNpCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should to able to delete a nodepool and add a new one", func() {
+		It("should to able to delete a nodepool and add a new one", func(specCtx SpecContext) {
 			// Blocked by: https://github.com/rancher/aks-operator/issues/667#issuecomment-2370798904
 			testCaseID = 268
-			deleteAndAddNpCheck(cluster, ctx.RancherAdminClient)
+			deleteAndAddNpCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should successfully edit System NodePool", func() {
+		It("should successfully edit System NodePool", func(specCtx SpecContext) {
 			testCaseID = 289
-			updateSystemNodePoolCheck(cluster, ctx.RancherAdminClient)
+			updateSystemNodePoolCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should successfully edit mode of the nodepool", func() {
+		It("should successfully edit mode of the nodepool", func(specCtx SpecContext) {
 			testCaseID = 291
-			updateNodePoolModeCheck(cluster, ctx.RancherAdminClient)
+			updateNodePoolModeCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
 	})
 
 	When("a cluster is created and imported for upgrade", func() {
 		var upgradeK8sVersion string
-		BeforeEach(func() {
-			if helpers.SkipUpgradeTests {
-				Skip(helpers.SkipUpgradeTestsLog)
-			}
+		BeforeEach(func(specCtx SpecContext) {
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 
 			var err error
-			k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, true)
+			k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, location, true)
 			Expect(err).NotTo(HaveOccurred())
 			GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 
-			err = helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+			err = helper.CreateAKSClusterOnAzure(specCtx, location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
 			Expect(err).To(BeNil())
 
-			cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+			cluster, err = helper.ImportAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
-			availableVersions, err := helper.ListAKSAvailableVersions(ctx.RancherAdminClient, cluster.ID)
+			availableVersions, err := helper.ListAKSAvailableVersions(specCtx, ctx.RancherAdminClient, cluster.ID)
 			Expect(err).To(BeNil())
 			upgradeK8sVersion = availableVersions[0]
 		})
 
-		It("NP cannot be upgraded to k8s version greater than CP k8s version", func() {
-			if helpers.SkipUpgradeTests {
-				Skip(helpers.SkipUpgradeTestsLog)
-			}
+		It("NP cannot be upgraded to k8s version greater than CP k8s version", func(specCtx SpecContext) {
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 			testCaseID = 269
-			npUpgradeToVersionGTCPCheck(cluster, ctx.RancherAdminClient, upgradeK8sVersion)
+			npUpgradeToVersionGTCPCheck(specCtx, cluster, ctx.RancherAdminClient, upgradeK8sVersion)
 		})
-		It("should Update a cluster when a cluster is in Updating State", func() {
+		It("should Update a cluster when a cluster is in Updating State", func(specCtx SpecContext) {
 			testCaseID = 303
-			updateClusterWhenUpdating(cluster, ctx.RancherAdminClient, upgradeK8sVersion)
+			updateClusterWhenUpdating(specCtx, cluster, ctx.RancherAdminClient, upgradeK8sVersion)
 		})
 	})
 
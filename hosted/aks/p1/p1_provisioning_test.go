@@ -2,7 +2,6 @@ package p1_test
 
 import (
 	"fmt"
-	"os"
 	"os/user"
 	"strconv"
 	"strings"
@@ -12,7 +11,6 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	"github.com/rancher/shepherd/extensions/clusters"
 	"github.com/rancher/shepherd/extensions/clusters/aks"
 	"github.com/rancher/shepherd/extensions/tokenregistration"
 	namegen "github.com/rancher/shepherd/pkg/namegenerator"
@@ -20,41 +18,30 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var _ = Describe("P1Provisioning", func() {
 	var k8sVersion string
 
-	BeforeEach(func() {
+	BeforeEach(func(specCtx SpecContext) {
 		// assigning cluster nil value so that every new test has a fresh value of the variable
 		// this is to avoid using residual value of a cluster in a test that does not use it
 		cluster = nil
 		GinkgoLogr.Info(fmt.Sprintf("Running on process: %d", GinkgoParallelProcess()))
 		var err error
-		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, false)
+		k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, location, false)
 		Expect(err).NotTo(HaveOccurred())
 		GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 	})
 
-	AfterEach(func() {
-		if ctx.ClusterCleanup && (cluster != nil && cluster.ID != "") {
-			GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
-			err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
-			Expect(err).To(BeNil())
-		} else {
-			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-		}
-	})
-
-	It("should successfully Create a cluster in Region without AZ", func() {
-		if helpers.SkipUpgradeTests {
-			Skip(helpers.SkipUpgradeTestsLog)
-		}
+	It("should successfully Create a cluster in Region without AZ", func(specCtx SpecContext) {
+		suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 		location = "ukwest"
 		testCaseID = 275
 
 		var err error
-		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, true)
+		k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, location, true)
 		Expect(err).NotTo(HaveOccurred())
 		GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 
@@ -65,15 +52,15 @@ var _ = Describe("P1Provisioning", func() {
 				nodepools[i].AvailabilityZones = nil
 			}
 		}
-		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
+		cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 
-		noAvailabilityZoneP0Checks(cluster, ctx.RancherAdminClient)
+		noAvailabilityZoneP0Checks(specCtx, cluster, ctx.RancherAdminClient)
 	})
 
-	It("should successfully create cluster with multiple nodepools in multiple AZs", func() {
+	It("should successfully create cluster with multiple nodepools in multiple AZs", func(specCtx SpecContext) {
 		testCaseID = 193
 		updateFunc := func(aksConfig *aks.ClusterConfig) {
 			nodepools := *aksConfig.NodePools
@@ -102,11 +89,11 @@ var _ = Describe("P1Provisioning", func() {
 			aksConfig.NodePools = &updatedNodePools
 		}
 		var err error
-		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
+		cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 		for _, np := range *cluster.AKSConfig.NodePools {
 			npName := *np.Name
 			az := npName[len(npName)-1]
@@ -114,19 +101,19 @@ var _ = Describe("P1Provisioning", func() {
 		}
 	})
 
-	It("should be able to create a cluster with empty tag", func() {
+	It("should be able to create a cluster with empty tag", func(specCtx SpecContext) {
 		testCaseID = 205
 		updateFunc := func(aksConfig *aks.ClusterConfig) {
 			aksConfig.Tags["empty-tag"] = ""
 		}
 		var err error
-		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
+		cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 		Expect(err).To(BeNil())
 		Expect(cluster.AKSConfig.Tags).To(HaveKeyWithValue("empty-tag", ""))
 
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 		Eventually(func() bool {
 			cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 			Expect(err).NotTo(HaveOccurred())
@@ -136,34 +123,34 @@ var _ = Describe("P1Provisioning", func() {
 		Expect(cluster.AKSStatus.UpstreamSpec.Tags).To(HaveKeyWithValue("empty-tag", ""))
 	})
 
-	It("should be able to create cluster with container monitoring enabled", func() {
+	It("should be able to create cluster with container monitoring enabled", func(specCtx SpecContext) {
 		// Refer: https://github.com/rancher/shepherd/issues/274
 		testCaseID = 199
 		updateFunc := func(aksConfig *aks.ClusterConfig) {
 			aksConfig.Monitoring = pointer.Bool(true)
 		}
 		var err error
-		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
+		cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 		Expect(err).To(BeNil())
 		Expect(*cluster.AKSConfig.Monitoring).To(BeTrue())
 
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 		Expect(*cluster.AKSStatus.UpstreamSpec.Monitoring).To(BeTrue())
 	})
 
 	// TODO: Discuss why only one nodepool is taken into account
-	XIt("updating a cluster while it is still provisioning", func() {
+	XIt("updating a cluster while it is still provisioning", func(specCtx SpecContext) {
 		// Blocked by: https://github.com/rancher/aks-operator/issues/667
 		testCaseID = 222
 		var err error
-		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, true)
+		k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, location, true)
 		Expect(err).NotTo(HaveOccurred())
 		GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 
-		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
+		cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
 		Expect(err).To(BeNil())
 
 		Eventually(func() string {
@@ -175,7 +162,7 @@ var _ = Describe("P1Provisioning", func() {
 		// Wait until the cluster appears on cloud before updating it
 		Eventually(func() bool {
 			var existsOnCloud bool
-			existsOnCloud, err = helper.ClusterExistsOnAzure(clusterName, clusterName)
+			existsOnCloud, err = helper.ClusterExistsOnAzure(specCtx, clusterName, clusterName)
 			if err != nil && strings.Contains(err.Error(), "NotFound") {
 				return false
 			}
@@ -185,26 +172,26 @@ var _ = Describe("P1Provisioning", func() {
 		Expect(*cluster.AKSConfig.KubernetesVersion).To(Equal(k8sVersion))
 
 		initialNPCount := len(*cluster.AKSConfig.NodePools)
-		cluster, err = helper.AddNodePool(cluster, 3, ctx.RancherAdminClient, false, false)
+		cluster, err = helper.AddNodePool(specCtx, cluster, 3, ctx.RancherAdminClient, false, false)
 		Expect(err).To(BeNil())
 		Expect(*cluster.AKSConfig.NodePools).To(HaveLen(initialNPCount + 3))
 
 		var upgradeK8sVersion string
-		upgradeK8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, false)
+		upgradeK8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, location, false)
 		Expect(err).NotTo(HaveOccurred())
 		GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 
-		cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeK8sVersion, ctx.RancherAdminClient, false)
+		cluster, err = helper.UpgradeClusterKubernetesVersion(specCtx, cluster, upgradeK8sVersion, ctx.RancherAdminClient, false)
 		Expect(err).To(BeNil())
 		Expect(*cluster.AKSConfig.KubernetesVersion).To(Equal(upgradeK8sVersion))
 
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 
-		err = clusters.WaitClusterToBeUpgraded(ctx.RancherAdminClient, cluster.ID)
+		err = helpers.WaitClusterToBeUpdated(specCtx, ctx.RancherAdminClient, cluster.ID)
 		Expect(err).To(BeNil())
 
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 
 		cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 		Expect(err).NotTo(HaveOccurred())
@@ -212,16 +199,16 @@ var _ = Describe("P1Provisioning", func() {
 		Expect(*cluster.AKSStatus.UpstreamSpec.KubernetesVersion).To(Equal(upgradeK8sVersion))
 	})
 
-	It("create cluster with network policy: calico and plugin: kubenet", func() {
+	It("create cluster with network policy: calico and plugin: kubenet", func(specCtx SpecContext) {
 		testCaseID = 210
 		updateFunc := func(aksConfig *aks.ClusterConfig) {
 			aksConfig.NetworkPolicy = pointer.String("calico")
 			aksConfig.NetworkPlugin = pointer.String("kubenet")
 		}
 		var err error
-		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
+		cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 
 		Expect(*cluster.AKSConfig.NetworkPolicy).To(Equal("calico"))
@@ -229,10 +216,10 @@ var _ = Describe("P1Provisioning", func() {
 		Expect(*cluster.AKSStatus.UpstreamSpec.NetworkPolicy).To(Equal("calico"))
 		Expect(*cluster.AKSStatus.UpstreamSpec.NetworkPlugin).To(Equal("kubenet"))
 
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 	})
 
-	XIt("should successfully create cluster with underscore in the name", func() {
+	XIt("should successfully create cluster with underscore in the name", func(specCtx SpecContext) {
 		// Blocked by https://github.com/rancher/dashboard/issues/9416
 		testCaseID = 261
 		if ctx.ClusterCleanup {
@@ -242,14 +229,14 @@ var _ = Describe("P1Provisioning", func() {
 			clusterName = namegen.AppendRandomString(fmt.Sprintf("%s_%s_hp_ci", helpers.Provider, testuser.Username))
 		}
 		var err error
-		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
+		cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 	})
 
-	It("should successfully create cluster with custom nodepool parameters", func() {
+	It("should successfully create cluster with custom nodepool parameters", func(specCtx SpecContext) {
 		testCaseID = 209
 		updateFunc := func(aksConfig *aks.ClusterConfig) {
 			nodepools := *aksConfig.NodePools
@@ -269,29 +256,29 @@ var _ = Describe("P1Provisioning", func() {
 			}
 		}
 		var err error
-		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
+		cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 	})
 
 	When("a cluster with invalid config is created", func() {
-		It("should fail to create 2 clusters with same name in 2 different resource groups", func() {
+		It("should fail to create 2 clusters with same name in 2 different resource groups", func(specCtx SpecContext) {
 			testCaseID = 217
 			var err error
-			cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
+			cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
 			Expect(err).To(BeNil())
 			resourceGroup2 := namegen.AppendRandomString(helpers.ClusterNamePrefix)
 			updateFunc := func(aksConfig *aks.ClusterConfig) {
 				aksConfig.ResourceGroup = resourceGroup2
 			}
-			_, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
+			_, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 			Expect(err).ToNot(BeNil())
 			Expect(err.Error()).To(ContainSubstring("cluster already exists"))
 		})
 
-		It("should fail to create a cluster with 0 nodecount", func() {
+		It("should fail to create a cluster with 0 nodecount", func(specCtx SpecContext) {
 			testCaseID = 186
 			updateFunc := func(aksConfig *aks.ClusterConfig) {
 				nodepools := *aksConfig.NodePools
@@ -301,43 +288,43 @@ var _ = Describe("P1Provisioning", func() {
 				aksConfig.NodePools = &nodepools
 			}
 			var err error
-			cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
+			cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 			Expect(err).NotTo(HaveOccurred())
 
-			Eventually(func() bool {
+			Eventually(func() *management.Cluster {
 				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 				Expect(err).NotTo(HaveOccurred())
-				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "agentPoolProfile.count was 0. It must be greater or equal to minCount:1 and less than or equal to maxCount:1000")
-			}, "1m", "2s").Should(BeTrue())
+				return cluster
+			}, "1m", "2s").Should(suite.HaveTransitionError(helper.ErrNodeCountOutOfRange))
 		})
 
-		It("should fail to create a cluster with nil nodepool", func() {
+		It("should fail to create a cluster with nil nodepool", func(specCtx SpecContext) {
 			testCaseID = 187
 			updateFunc := func(aksConfig *aks.ClusterConfig) {
 				aksConfig.NodePools = nil
 			}
 			var err error
-			cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
+			cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 			Expect(err).NotTo(HaveOccurred())
-			Eventually(func() bool {
+			Eventually(func() *management.Cluster {
 				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 				Expect(err).NotTo(HaveOccurred())
 				GinkgoLogr.Info(fmt.Sprintf("cluster.Transitioning=%s cluster.TransitioningMessage=%s", cluster.Transitioning, cluster.TransitioningMessage))
-				return cluster.Transitioning == "error" && cluster.TransitioningMessage == "at least one NodePool with mode System is required"
-			}, "1m", "2s").Should(BeTrue())
+				return cluster
+			}, "1m", "2s").Should(suite.HaveTransitionError(helper.ErrNoSystemPool))
 		})
 
-		It("should fail to create a cluster with an empty nodepool array", func() {
+		It("should fail to create a cluster with an empty nodepool array", func(specCtx SpecContext) {
 			updateFunc := func(aksConfig *aks.ClusterConfig) {
 				aksConfig.NodePools = &[]aks.NodePool{}
 			}
 			var err error
-			_, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
+			_, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 			Expect(err).NotTo(BeNil())
 			Expect(err.Error()).To(ContainSubstring("must have at least one nodepool"))
 		})
 
-		It("should fail to create cluster with Nodepool Max pods per node 9", func() {
+		It("should fail to create cluster with Nodepool Max pods per node 9", func(specCtx SpecContext) {
 			testCaseID = 203
 			updateFunc := func(aksConfig *aks.ClusterConfig) {
 				nodepools := *aksConfig.NodePools
@@ -347,40 +334,38 @@ var _ = Describe("P1Provisioning", func() {
 				aksConfig.NodePools = &nodepools
 			}
 			var err error
-			cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
+			cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 			Expect(err).NotTo(HaveOccurred())
-			Eventually(func() bool {
+			Eventually(func() *management.Cluster {
 				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 				Expect(err).NotTo(HaveOccurred())
-				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "InsufficientMaxPods")
-			}, "1m", "2s").Should(BeTrue())
+				return cluster
+			}, "1m", "2s").Should(suite.HaveTransitionError(helper.ErrInsufficientMaxPods))
 
 		})
 	})
 
 	When("a cluster is created", func() {
-		BeforeEach(func() {
+		BeforeEach(func(specCtx SpecContext) {
 			var err error
-			cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
+			cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("should successfully update with new cloud credentials", func() {
+		It("should successfully update with new cloud credentials", func(specCtx SpecContext) {
 			testCaseID = 221
-			updateCloudCredentialsCheck(cluster, ctx.RancherAdminClient)
+			updateCloudCredentialsCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should fail to update with invalid (deleted) cloud credential and update when the cloud credentials becomes valid", func() {
+		It("should fail to update with invalid (deleted) cloud credential and update when the cloud credentials becomes valid", func(specCtx SpecContext) {
 			testCaseID = 299
-			invalidateCloudCredentialsCheck(cluster, ctx.RancherAdminClient, ctx.CloudCredID)
+			invalidateCloudCredentialsCheck(specCtx, cluster, ctx.RancherAdminClient, ctx.CloudCredID)
 		})
 
-		It("should not be able to edit availability zone of a nodepool", func() {
-			if helpers.SkipTest {
-				Skip("Skipping test for v2.8, v2.9 ...")
-			}
+		It("should not be able to edit availability zone of a nodepool", func(specCtx SpecContext) {
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureAKSNodePoolValidation)
 
 			// Refer: https://github.com/rancher/aks-operator/issues/669
 			testCaseID = 195
@@ -394,48 +379,48 @@ var _ = Describe("P1Provisioning", func() {
 				}
 			}
 			var err error
-			cluster, err = helper.UpdateCluster(cluster, ctx.RancherAdminClient, updateFunc)
+			cluster, err = helper.UpdateCluster(specCtx, cluster, ctx.RancherAdminClient, updateFunc)
 			Expect(err).To(BeNil())
 			for _, np := range *cluster.AKSConfig.NodePools {
 				Expect(*np.AvailabilityZones).To(Equal(newAZ))
 			}
 
-			Eventually(func() bool {
+			Eventually(func() *management.Cluster {
 				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 				Expect(err).NotTo(HaveOccurred())
-				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "Changing availability zones for node pool") && strings.Contains(cluster.TransitioningMessage, "is not permitted")
-			}, "3m", "3s").Should(BeTrue())
+				return cluster
+			}, "3m", "3s").Should(suite.HaveTransitionError(helper.ErrAvailabilityZonesChange))
 		})
 
-		It("should not delete the resource group when cluster is deleted", func() {
+		It("should not delete the resource group when cluster is deleted", func(specCtx SpecContext) {
 			testCaseID = 207
-			err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
+			err := helper.DeleteAKSHostCluster(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 			// marking as nil so that AfterEach does not raise an error
 			cluster = nil
 
 			// wait until the cluster is deleted from cloud console
 			Eventually(func() (exists bool) {
-				exists, err = helper.ClusterExistsOnAzure(clusterName, clusterName)
+				exists, err = helper.ClusterExistsOnAzure(specCtx, clusterName, clusterName)
 				Expect(err).To(BeNil())
 				return exists
 			}, "5m", "5s").Should(BeFalse())
 
 			// check that the resource group still exists
 			var out string
-			out, err = proc.RunW("az", "group", "show", "--subscription", os.Getenv("AKS_SUBSCRIPTION_ID"), "--name", clusterName)
+			out, err = proc.RunW("az", "group", "show", "--subscription", helpers.Config.AKS.SubscriptionID, "--name", clusterName)
 			Expect(err).To(BeNil())
 			Expect(out).To(ContainSubstring(fmt.Sprintf("\"name\": \"%s\"", clusterName)))
 		})
 
-		It("should be able to update autoscaling", func() {
+		It("should be able to update autoscaling", func(specCtx SpecContext) {
 			testCaseID = 176
-			updateAutoScaling(cluster, ctx.RancherAdminClient)
+			updateAutoScaling(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should be able to update tags", func() {
+		It("should be able to update tags", func(specCtx SpecContext) {
 			testCaseID = 177
-			updateTagsCheck(cluster, ctx.RancherAdminClient)
+			updateTagsCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
 		It("should have cluster monitoring disabled by default", func() {
@@ -444,62 +429,56 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(cluster.AKSStatus.UpstreamSpec.Monitoring).To(BeNil())
 		})
 
-		It("should fail to change system nodepool count to 0", func() {
+		It("should fail to change system nodepool count to 0", func(specCtx SpecContext) {
 			testCaseID = 202
-			updateSystemNodePoolCountToZeroCheck(cluster, ctx.RancherAdminClient)
+			updateSystemNodePoolCountToZeroCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should be able to update cluster monitoring", func() {
-			if helpers.SkipUpgradeTests {
-				Skip(helpers.SkipUpgradeTestsLog)
-			}
+		It("should be able to update cluster monitoring", func(specCtx SpecContext) {
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 			testCaseID = 200
-			updateMonitoringCheck(cluster, ctx.RancherAdminClient)
+			updateMonitoringCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("recreating a cluster while it is being deleted should recreate the cluster", func() {
+		It("recreating a cluster while it is being deleted should recreate the cluster", func(specCtx SpecContext) {
 			testCaseID = 219
 
-			err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
+			err := helper.DeleteAKSHostCluster(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 
 			// Wait until the cluster begins deletion process before recreating
 			Eventually(func() bool {
-				exists, err := helper.ClusterExistsOnAzure(clusterName, cluster.AKSConfig.ResourceGroup)
+				exists, err := helper.ClusterExistsOnAzure(specCtx, clusterName, cluster.AKSConfig.ResourceGroup)
 				Expect(err).To(BeNil())
 				return exists
 			}, "1m", "5s").Should(BeFalse())
 
-			cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
+			cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
 			Expect(err).To(BeNil())
 
 			// wait until the error is visible on the provisioned cluster
-			Eventually(func() bool {
+			Eventually(func() *management.Cluster {
 				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 				Expect(err).To(BeNil())
 				GinkgoLogr.Info(fmt.Sprintf("cluster.State=%s cluster.Transitioning=%s cluster.TransitioningMessage=%s", cluster.State, cluster.Transitioning, cluster.TransitioningMessage))
-				return cluster.State == "provisioning" && cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "an AKSClusterConfig exists with the same name")
-			}, "30s", "2s").Should(BeTrue())
+				return cluster
+			}, "30s", "2s").Should(And(HaveField("State", "provisioning"), suite.HaveTransitionError(helper.ErrDuplicateClusterConfig)))
 
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
 
 	})
 
 	// Refer: https://github.com/rancher/hosted-providers-e2e/issues/192
-	It("should successfully create 2 clusters in the same RG", func() {
+	It("should successfully create 2 clusters in the same RG", func(specCtx SpecContext) {
 		testCaseID = 214
 
 		// Create the resource group via CLI
 		rgName := namegen.AppendRandomString(helpers.ClusterNamePrefix + "-custom-rg")
-		err := helper.CreateAKSRGOnAzure(rgName, location)
+		// the resource group is deleted after both clusters since its teardown is registered first
+		err := helper.CreateAKSRGOnAzure(specCtx, rgName, location)
 		Expect(err).To(BeNil())
-		defer func() {
-			// This is to delete the resource group
-			err = helper.DeleteAKSClusteronAzure(rgName)
-			Expect(err).To(BeNil())
-		}()
 
 		updateFunc := func(aksConfig *aks.ClusterConfig) {
 			aksConfig.ResourceGroup = rgName
@@ -511,15 +490,15 @@ var _ = Describe("P1Provisioning", func() {
 				defer GinkgoRecover()
 				defer wg.Done()
 				clusterName := namegen.AppendRandomString(helpers.ClusterNamePrefix)
-				cluster1, err := helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
+				cluster1, err := helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 				if err != nil {
 					Fail(err.Error())
 				}
-				cluster1, err = helpers.WaitUntilClusterIsReady(cluster1, ctx.RancherAdminClient)
+				cluster1, err = helpers.WaitUntilClusterIsReady(specCtx, cluster1, ctx.RancherAdminClient)
 				if err != nil {
 					Fail(err.Error())
 				}
-				err = helper.DeleteAKSHostCluster(cluster1, ctx.RancherAdminClient)
+				err = helper.DeleteAKSHostCluster(specCtx, cluster1, ctx.RancherAdminClient)
 				if err != nil {
 					Fail(err.Error())
 				}
@@ -530,49 +509,45 @@ var _ = Describe("P1Provisioning", func() {
 
 	When("a cluster is created for upgrade", func() {
 		var upgradeK8sVersion string
-		BeforeEach(func() {
-			if helpers.SkipUpgradeTests {
-				Skip(helpers.SkipUpgradeTestsLog)
-			}
+		BeforeEach(func(specCtx SpecContext) {
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 
 			var err error
-			k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, true)
+			k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, location, true)
 			Expect(err).NotTo(HaveOccurred())
 			GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 
-			cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
+			cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).NotTo(HaveOccurred())
-			availableVersions, err := helper.ListAKSAvailableVersions(ctx.RancherAdminClient, cluster.ID)
+			availableVersions, err := helper.ListAKSAvailableVersions(specCtx, ctx.RancherAdminClient, cluster.ID)
 			Expect(err).To(BeNil())
 			upgradeK8sVersion = availableVersions[0]
 		})
 
-		It("NP cannot be upgraded to k8s version greater than CP k8s version", func() {
-			if helpers.SkipUpgradeTests {
-				Skip(helpers.SkipUpgradeTestsLog)
-			}
+		It("NP cannot be upgraded to k8s version greater than CP k8s version", func(specCtx SpecContext) {
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 			testCaseID = 183
-			npUpgradeToVersionGTCPCheck(cluster, ctx.RancherAdminClient, upgradeK8sVersion)
+			npUpgradeToVersionGTCPCheck(specCtx, cluster, ctx.RancherAdminClient, upgradeK8sVersion)
 		})
 
-		It("should Update a cluster when a cluster is in Updating State", func() {
+		It("should Update a cluster when a cluster is in Updating State", func(specCtx SpecContext) {
 			// Ref: https://github.com/rancher/aks-operator/issues/826
 			testCaseID = 223
-			updateClusterWhenUpdating(cluster, ctx.RancherAdminClient, upgradeK8sVersion)
+			updateClusterWhenUpdating(specCtx, cluster, ctx.RancherAdminClient, upgradeK8sVersion)
 		})
 	})
 
-	It("deleting a cluster while it is in creation state should delete it from rancher and cloud console", func() {
+	It("deleting a cluster while it is in creation state should delete it from rancher and cloud console", func(specCtx SpecContext) {
 		testCaseID = 218
 		var err error
-		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
+		cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
 		Expect(err).To(BeNil())
 
 		// Wait for the cluster to appear on cloud console before deleting it
 		Eventually(func() bool {
-			exists, err := helper.ClusterExistsOnAzure(clusterName, cluster.AKSConfig.ResourceGroup)
+			exists, err := helper.ClusterExistsOnAzure(specCtx, clusterName, cluster.AKSConfig.ResourceGroup)
 			// ignore the error that occurs when resource group or cluster could not be found
 			if err != nil {
 				if strings.Contains(err.Error(), fmt.Sprintf("Resource group '%s' could not be found", cluster.AKSConfig.ResourceGroup)) || strings.Contains(err.Error(), "not found") {
@@ -585,12 +560,12 @@ var _ = Describe("P1Provisioning", func() {
 
 		cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 		Expect(err).To(BeNil())
-		err = helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
+		err = helper.DeleteAKSHostCluster(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 
 		// Wait until the cluster finishes provisioning and then begins deletion process
 		Eventually(func() bool {
-			exists, err := helper.ClusterExistsOnAzure(clusterName, cluster.AKSConfig.ResourceGroup)
+			exists, err := helper.ClusterExistsOnAzure(specCtx, clusterName, cluster.AKSConfig.ResourceGroup)
 			if err != nil {
 				if strings.Contains(err.Error(), fmt.Sprintf("Resource group '%s' could not be found", cluster.AKSConfig.ResourceGroup)) || strings.Contains(err.Error(), "not found") {
 					err = nil
@@ -606,13 +581,11 @@ var _ = Describe("P1Provisioning", func() {
 		Expect(err.Error()).To(ContainSubstring("not found"))
 	})
 
-	It("should not be able to select NP K8s version; CP K8s version should take precedence", func() {
-		if helpers.SkipUpgradeTests {
-			Skip(helpers.SkipUpgradeTestsLog)
-		}
+	It("should not be able to select NP K8s version; CP K8s version should take precedence", func(specCtx SpecContext) {
+		suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 
 		testCaseID = 182
-		k8sVersions, err := helper.ListSingleVariantAKSAllVersions(ctx.RancherAdminClient, ctx.CloudCredID, location)
+		k8sVersions, err := helper.ListSingleVariantAKSAllVersions(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, location)
 		Expect(err).To(BeNil())
 		Expect(len(k8sVersions)).To(BeNumerically(">=", 2))
 		// CP > NP
@@ -629,7 +602,7 @@ var _ = Describe("P1Provisioning", func() {
 			*clusterConfig.NodePools = nodePools
 		}
 
-		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, cpK8sVersion, location, updateFunc)
+		cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, cpK8sVersion, location, updateFunc)
 		Expect(err).To(BeNil())
 
 		Expect(*cluster.AKSConfig.KubernetesVersion).To(Equal(cpK8sVersion))
@@ -637,7 +610,7 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(*np.OrchestratorVersion).To(Equal(cpK8sVersion))
 		}
 
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 
 		Eventually(func() bool {
@@ -657,27 +630,27 @@ var _ = Describe("P1Provisioning", func() {
 		}, "5m", "5s").Should(BeTrue(), "Failed while waiting for k8s upgrade.")
 	})
 
-	It("should Create NP with AZ for region where AZ is not supported", func() {
+	It("should Create NP with AZ for region where AZ is not supported", func(specCtx SpecContext) {
 		testCaseID = 196
 		// none of the availability zones are supported in this location
 		location = "westus"
 		var err error
 		// re-fetching k8s version based on the location to avoid unsupported k8s version errors
-		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, false)
+		k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, location, false)
 		Expect(err).NotTo(HaveOccurred())
 		GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
-		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
+		cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
 		Expect(err).ToNot(HaveOccurred())
 
-		Eventually(func() bool {
+		Eventually(func() *management.Cluster {
 			cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 			Expect(err).To(BeNil())
-			return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "Availability zone is not supported in region")
-		}, "2m", "2s").Should(BeTrue(), "Timed out while waiting for cluster to error out")
+			return cluster
+		}, "2m", "2s").Should(suite.HaveTransitionError(helper.ErrAvailabilityZoneUnsupported), "Timed out while waiting for cluster to error out")
 	})
 
 	When("a cluster is created for with user and system mode nodepool", func() {
-		BeforeEach(func() {
+		BeforeEach(func(specCtx SpecContext) {
 			updateFunc := func(clusterConfig *aks.ClusterConfig) {
 				nodePools := *clusterConfig.NodePools
 				npTemplate := nodePools[0]
@@ -704,43 +677,41 @@ var _ = Describe("P1Provisioning", func() {
 				*clusterConfig.NodePools = updatedNodePools
 			}
 			var err error
-			cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
+			cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
 
-		It("should successfully create the cluster", func() {
+		It("should successfully create the cluster", func(specCtx SpecContext) {
 			testCaseID = 189
-			helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+			suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 
 			Expect(len(*cluster.AKSConfig.NodePools)).To(Equal(2))
 			Expect(len(*cluster.AKSStatus.UpstreamSpec.NodePools)).To(Equal(2))
 		})
 
-		It("should to able to delete a nodepool and add a new one with different availability zone", func() {
+		It("should to able to delete a nodepool and add a new one with different availability zone", func(specCtx SpecContext) {
 			// Blocked by: https://github.com/rancher/aks-operator/issues/667#issuecomment-2370798904
 			testCaseID = 190
 			// also covers testCaseID = 194
-			deleteAndAddNpCheck(cluster, ctx.RancherAdminClient)
+			deleteAndAddNpCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should not be able to remove system nodepool", func() {
-			if helpers.SkipTest {
-				Skip("Skipping test for v2.8, v2.9 ...")
-			}
+		It("should not be able to remove system nodepool", func(specCtx SpecContext) {
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureAKSNodePoolValidation)
 			testCaseID = 191
-			removeSystemNpCheck(cluster, ctx.RancherAdminClient)
+			removeSystemNpCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should successfully edit System NodePool", func() {
+		It("should successfully edit System NodePool", func(specCtx SpecContext) {
 			testCaseID = 204
-			updateSystemNodePoolCheck(cluster, ctx.RancherAdminClient)
+			updateSystemNodePoolCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should successfully edit mode of the nodepool", func() {
+		It("should successfully edit mode of the nodepool", func(specCtx SpecContext) {
 			testCaseID = 230
-			updateNodePoolModeCheck(cluster, ctx.RancherAdminClient)
+			updateNodePoolModeCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 	})
 	Context("Network Policy and plugin", func() {
@@ -749,8 +720,8 @@ var _ = Describe("P1Provisioning", func() {
 			kubenetPlugin = "kubenet"
 			azure         = "azure"
 			none          = "null"
-			vnet          = os.Getenv("AKS_VNET")
-			vnetRG        = os.Getenv("AKS_VNET_RG")
+			vnet          = helpers.Config.AKS.VNet
+			vnetRG        = helpers.Config.AKS.VNetResourceGroup
 			subnet        = "default"
 		)
 
@@ -783,7 +754,7 @@ var _ = Describe("P1Provisioning", func() {
 			},
 		} {
 			data := data
-			It(fmt.Sprintf("Create cluster with NetworkPolicy %s & Network plugin %s", data.networkPolicy, data.networkPlugin), func() {
+			It(fmt.Sprintf("Create cluster with NetworkPolicy %s & Network plugin %s", data.networkPolicy, data.networkPlugin), func(specCtx SpecContext) {
 				testCaseID = data.testCaseID
 				createFunc := func(clusterConfig *aks.ClusterConfig) {
 					clusterConfig.NetworkPlugin = &data.networkPlugin
@@ -797,25 +768,21 @@ var _ = Describe("P1Provisioning", func() {
 					}
 				}
 				var err error
-				cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, createFunc)
+				cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, createFunc)
 				Expect(err).To(BeNil())
-				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
-				helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+				suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 			})
 		}
 	})
 
 	Context("Private Cluster", func() {
 		// Previously blocked on: https://github.com/rancher/rancher/issues/43772
-		BeforeEach(func() {
+		BeforeEach(func(specCtx SpecContext) {
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureAKSPrivateCluster)
 			var err error
-			serverVersion, err := helpers.GetRancherServerVersion(ctx.RancherAdminClient)
-			Expect(err).NotTo(HaveOccurred())
-			if !strings.Contains(serverVersion, "2.12") {
-				Skip("Not supported on version < 2.12")
-			}
-			k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, true)
+			k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, location, true)
 			Expect(err).NotTo(HaveOccurred())
 			GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 
@@ -823,51 +790,51 @@ var _ = Describe("P1Provisioning", func() {
 				clusterConfig.PrivateCluster = pointer.Bool(true)
 			}
 
-			cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, createFunc)
+			cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, createFunc)
 			Expect(err).ToNot(HaveOccurred())
 
-			Eventually(func() bool {
+			Eventually(func() *management.Cluster {
 				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 				Expect(err).To(BeNil())
-				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "failed to communicate with cluster: error generating service account token") && strings.Contains(cluster.TransitioningMessage, "cluster agent disconnected")
-			}, "12m", "10s").Should(BeTrue(), "Timed out while waiting for cluster to be ready for registration")
+				return cluster
+			}, "12m", "10s").Should(suite.HaveTransitionError(helper.ErrClusterAgentDisconnected), "Timed out while waiting for cluster to be ready for registration")
 
 			registrationToken, err1 := tokenregistration.GetRegistrationToken(ctx.RancherAdminClient, cluster.ID)
 			Expect(err1).To(BeNil())
-			err = helper.RunCommand(cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, registrationToken.InsecureCommand)
+			err = helper.RunCommand(specCtx, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, registrationToken.InsecureCommand)
 			Expect(err).To(BeNil())
 
 			//Failed to communicate with cluster: error generating service account token: Post "https://auto-aks-pvala-hp-ci-rqbte-dns-gs1f40rx.006641f3-b627-466d-917b-c2c6bca16c4c.privatelink.centralindia.azmk8s.io:443/api/v1/namespaces": dial tcp: address auto-aks-pvala-hp-ci-rqbte-dns-gs1f40rx.006641f3-b627-466d-917b-c2c6bca16c4c.priva
 
 			//Failed to communicate with cluster: error generating service account token: Post "https://pvala-aks-dns-i0lznum3.9f8ba64d-e36e-4aaa-84d2-d0a8935cdb32.privatelink.centralindia.azmk8s.io:443/api/v1/namespaces": cluster agent disconnected
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
-		It("should successfully Create a private cluster", func() {
+		It("should successfully Create a private cluster", func(specCtx SpecContext) {
 			testCaseID = 240 // 241, 242
-			helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+			suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 
-			availableVersions, err := helper.ListAKSAvailableVersions(ctx.RancherAdminClient, cluster.ID)
+			availableVersions, err := helper.ListAKSAvailableVersions(specCtx, ctx.RancherAdminClient, cluster.ID)
 			Expect(err).To(BeNil())
 			upgradeK8sVersion := availableVersions[0]
 
 			By("upgrading control plane k8s version", func() {
-				cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeK8sVersion, ctx.RancherAdminClient, true)
+				cluster, err = helper.UpgradeClusterKubernetesVersion(specCtx, cluster, upgradeK8sVersion, ctx.RancherAdminClient, true)
 				Expect(err).To(BeNil())
 			})
 
 			By("upgrading nodepool k8s version", func() {
-				cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, upgradeK8sVersion, ctx.RancherAdminClient, true, true)
+				cluster, err = helper.UpgradeNodeKubernetesVersion(specCtx, cluster, upgradeK8sVersion, ctx.RancherAdminClient, true, true)
 				Expect(err).To(BeNil())
 			})
 
 			By("adding a nodepool", func() {
-				cluster, err = helper.AddNodePool(cluster, 1, ctx.RancherAdminClient, true, true)
+				cluster, err = helper.AddNodePool(specCtx, cluster, 1, ctx.RancherAdminClient, true, true)
 				Expect(err).To(BeNil())
 			})
 
 			By("updating autoscaling", func() {
-				cluster, err = helper.UpdateAutoScaling(cluster, ctx.RancherAdminClient, true, 5, 2, true)
+				cluster, err = helper.UpdateAutoScaling(specCtx, cluster, ctx.RancherAdminClient, true, 5, 2, true)
 				Expect(err).To(BeNil())
 			})
 		})
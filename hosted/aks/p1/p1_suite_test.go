@@ -1,9 +1,11 @@
 package p1_test
 
 import (
+	"context"
 	"fmt"
-	"strings"
+	"regexp"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -16,6 +18,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var (
@@ -31,10 +34,10 @@ func TestP1(t *testing.T) {
 }
 
 var _ = SynchronizedBeforeSuite(func() []byte {
-	helpers.CommonSynchronizedBeforeSuite()
+	suite.CommonSynchronizedBeforeSuite()
 	return nil
 }, func() {
-	ctx = helpers.CommonBeforeSuite()
+	ctx = suite.CommonBeforeSuite()
 })
 
 var _ = BeforeEach(func() {
@@ -49,29 +52,30 @@ var _ = ReportBeforeEach(func(report SpecReport) {
 	testCaseID = -1
 })
 
-var _ = ReportAfterEach(func(report SpecReport) {
-	// Add result in Qase if asked
+var _ = ReportAfterEach(func(specCtx SpecContext, report SpecReport) {
+	// Add result in Qase and in the run report if asked
 	Qase(testCaseID, report)
+	suite.WriteRunReport(specCtx, report, testCaseID, ctx.RancherAdminClient)
 })
 
 // updateAutoScaling tests updating `autoscaling` for AKS node pools
 // Qase ID: 176 and 266
-func updateAutoScaling(cluster *management.Cluster, client *rancher.Client) {
+func updateAutoScaling(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	By("enabling autoscaling with custom minCount and maxCount", func() {
 		var err error
-		cluster, err = helper.UpdateAutoScaling(cluster, client, true, 5, 2, true)
+		cluster, err = helper.UpdateAutoScaling(specCtx, cluster, client, true, 5, 2, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("disabling autoscaling", func() {
 		var err error
-		cluster, err = helper.UpdateAutoScaling(cluster, client, false, 0, 0, true)
+		cluster, err = helper.UpdateAutoScaling(specCtx, cluster, client, false, 0, 0, true)
 		Expect(err).To(BeNil())
 	})
 }
 
 // Qase ID: 191 and 267
-func removeSystemNpCheck(cluster *management.Cluster, client *rancher.Client) {
+func removeSystemNpCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	updateFunc := func(cluster *management.Cluster) {
 		var updatedNodePools []management.AKSNodePool
 		for _, np := range *cluster.AKSConfig.NodePools {
@@ -81,18 +85,14 @@ func removeSystemNpCheck(cluster *management.Cluster, client *rancher.Client) {
 		}
 		cluster.AKSConfig.NodePools = &updatedNodePools
 	}
-	var err error
-	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+	timeline := helpers.StartClusterTimeline(specCtx, client, cluster)
+	_, err := helper.UpdateCluster(specCtx, cluster, client, updateFunc)
 	Expect(err).To(BeNil())
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
-		Expect(err).To(BeNil())
-		return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "cannot remove node pool") && strings.Contains(cluster.TransitioningMessage, "with mode System from cluster")
-	}, "5m", "5s").Should(BeTrue())
+	Expect(timeline.WaitUntilEntered(specCtx, "error", suite.TransitionErrorRegexp(specCtx, helper.ErrSystemPoolRemoval), 5*time.Minute)).To(Succeed())
 }
 
 // Qase ID: 194, 190, and 268
-func deleteAndAddNpCheck(cluster *management.Cluster, client *rancher.Client) {
+func deleteAndAddNpCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	originalLen := len(*cluster.AKSConfig.NodePools)
 	var npToBeDeleted management.AKSNodePool
 	newPoolName := fmt.Sprintf("newpool%s", namegen.RandStringLower(3))
@@ -116,7 +116,7 @@ func deleteAndAddNpCheck(cluster *management.Cluster, client *rancher.Client) {
 		cluster.AKSConfig.NodePools = &updatedNodePools
 	}
 	var err error
-	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+	cluster, err = helper.UpdateCluster(specCtx, cluster, client, updateFunc)
 	Expect(err).To(BeNil())
 	var (
 		npDeleted = true
@@ -134,7 +134,7 @@ func deleteAndAddNpCheck(cluster *management.Cluster, client *rancher.Client) {
 	Expect(npAdded).To(BeTrue())
 	Expect(npDeleted).To(BeTrue())
 	Expect(len(*cluster.AKSConfig.NodePools)).To(BeEquivalentTo(originalLen))
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	err = helpers.WaitClusterToBeUpdated(specCtx, client, cluster.ID)
 	Expect(err).To(BeNil())
 
 	Eventually(func() bool {
@@ -164,31 +164,28 @@ func deleteAndAddNpCheck(cluster *management.Cluster, client *rancher.Client) {
 
 // npUpgradeToVersionGTCPCheck runs checks when node pool is upgraded to a version greater than control plane version
 // Qase ID: 183 and 269
-func npUpgradeToVersionGTCPCheck(cluster *management.Cluster, client *rancher.Client, upgradeK8sVersion string) {
+func npUpgradeToVersionGTCPCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client, upgradeK8sVersion string) {
 	k8sVersion := *cluster.AKSConfig.KubernetesVersion
-	var err error
-	cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, upgradeK8sVersion, client, false, false)
+	timeline := helpers.StartClusterTimeline(specCtx, client, cluster)
+	_, err := helper.UpgradeNodeKubernetesVersion(specCtx, cluster, upgradeK8sVersion, client, false, false)
 	Expect(err).To(BeNil())
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
-		Expect(err).NotTo(HaveOccurred())
-		return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, fmt.Sprintf("Node pool version %s and control plane version %s are incompatible.", upgradeK8sVersion, k8sVersion))
-	}, "1m", "2s").Should(BeTrue())
+	Expect(timeline.WaitUntilEntered(specCtx, "error", suite.TransitionErrorRegexp(specCtx, helper.ErrNodePoolVersionIncompatible), time.Minute)).To(Succeed())
+	Expect(timeline.EverEntered("error", fmt.Sprintf(`version %s and control plane version %s`, regexp.QuoteMeta(upgradeK8sVersion), regexp.QuoteMeta(k8sVersion)))).To(BeTrue())
 }
 
 // Qase ID: 223 and 303
-func updateClusterWhenUpdating(cluster *management.Cluster, client *rancher.Client, upgradeK8sVersion string) {
+func updateClusterWhenUpdating(specCtx context.Context, cluster *management.Cluster, client *rancher.Client, upgradeK8sVersion string) {
 	var err error
 	initialNPLength := len(*cluster.AKSConfig.NodePools)
-	cluster, err = helper.AddNodePool(cluster, 1, client, false, false)
+	cluster, err = helper.AddNodePool(specCtx, cluster, 1, client, false, false)
 	Expect(err).To(BeNil())
 
 	err = clusters.WaitClusterToBeInUpgrade(client, cluster.ID)
 	Expect(err).To(BeNil())
-	cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeK8sVersion, client, true)
+	cluster, err = helper.UpgradeClusterKubernetesVersion(specCtx, cluster, upgradeK8sVersion, client, true)
 	Expect(err).To(BeNil())
 
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	err = helpers.WaitClusterToBeUpdated(specCtx, client, cluster.ID)
 	Expect(err).To(BeNil())
 
 	Eventually(func() int {
@@ -200,7 +197,7 @@ func updateClusterWhenUpdating(cluster *management.Cluster, client *rancher.Clie
 
 // updateTagsCheck runs checks to add and delete the cluster with a new tag and an empty tag
 // Qase ID: 177 and 270
-func updateTagsCheck(cluster *management.Cluster, client *rancher.Client) {
+func updateTagsCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 
 	By("adding new tags", func() {
 		updateFunc := func(cluster *management.Cluster) {
@@ -208,7 +205,7 @@ func updateTagsCheck(cluster *management.Cluster, client *rancher.Client) {
 			cluster.AKSConfig.Tags["new"] = "tag"
 		}
 		var err error
-		cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+		cluster, err = helper.UpdateCluster(specCtx, cluster, client, updateFunc)
 		Expect(err).To(BeNil())
 		Expect(cluster.AKSConfig.Tags).To(HaveKeyWithValue("empty-tag", ""))
 		Expect(cluster.AKSConfig.Tags).To(HaveKeyWithValue("new", "tag"))
@@ -234,7 +231,7 @@ func updateTagsCheck(cluster *management.Cluster, client *rancher.Client) {
 			delete(cluster.AKSConfig.Tags, "new")
 		}
 		var err error
-		cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+		cluster, err = helper.UpdateCluster(specCtx, cluster, client, updateFunc)
 		Expect(err).To(BeNil())
 
 		Expect(cluster.AKSConfig.Tags).ToNot(HaveKeyWithValue("empty-tag", ""))
@@ -256,13 +253,13 @@ func updateTagsCheck(cluster *management.Cluster, client *rancher.Client) {
 }
 
 // Qase ID: 200 and 271
-func updateMonitoringCheck(cluster *management.Cluster, client *rancher.Client) {
+func updateMonitoringCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	By("enabling the monitoring", func() {
 		updateFunc := func(cluster *management.Cluster) {
 			cluster.AKSConfig.Monitoring = pointer.Bool(true)
 		}
 		var err error
-		cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+		cluster, err = helper.UpdateCluster(specCtx, cluster, client, updateFunc)
 		Expect(err).To(BeNil())
 		Expect(*cluster.AKSConfig.Monitoring).To(BeTrue())
 		Eventually(func() bool {
@@ -277,7 +274,7 @@ func updateMonitoringCheck(cluster *management.Cluster, client *rancher.Client)
 			cluster.AKSConfig.Monitoring = pointer.Bool(false)
 		}
 		var err error
-		cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+		cluster, err = helper.UpdateCluster(specCtx, cluster, client, updateFunc)
 		Expect(err).To(BeNil())
 
 		Expect(*cluster.AKSConfig.Monitoring).To(BeFalse())
@@ -291,7 +288,7 @@ func updateMonitoringCheck(cluster *management.Cluster, client *rancher.Client)
 }
 
 // Qase ID: 202 and 290
-func updateSystemNodePoolCountToZeroCheck(cluster *management.Cluster, client *rancher.Client) {
+func updateSystemNodePoolCountToZeroCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	updateFunc := func(cluster *management.Cluster) {
 		nodepools := *cluster.AKSConfig.NodePools
 		for i := range nodepools {
@@ -302,18 +299,18 @@ func updateSystemNodePoolCountToZeroCheck(cluster *management.Cluster, client *r
 		cluster.AKSConfig.NodePools = &nodepools
 	}
 	var err error
-	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+	cluster, err = helper.UpdateCluster(specCtx, cluster, client, updateFunc)
 	Expect(err).ToNot(HaveOccurred())
-	Eventually(func() bool {
+	Eventually(func() *management.Cluster {
 		cluster, err = client.Management.Cluster.ByID(cluster.ID)
 		Expect(err).NotTo(HaveOccurred())
 		GinkgoLogr.Info(fmt.Sprintf("cluster.Transitioning=%s cluster.TransitioningMessage=%s", cluster.Transitioning, cluster.TransitioningMessage))
-		return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "It must be greater or equal to minCount:1 and less than or equal to maxCount:1000")
-	}, "1m", "2s").Should(BeTrue())
+		return cluster
+	}, "1m", "2s").Should(suite.HaveTransitionError(helper.ErrNodeCountOutOfRange))
 }
 
 // Qase ID: 204 and 289
-func updateSystemNodePoolCheck(cluster *management.Cluster, client *rancher.Client) {
+func updateSystemNodePoolCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	var (
 		count              int64 = 4
 		minCount           int64 = 2
@@ -334,7 +331,7 @@ func updateSystemNodePoolCheck(cluster *management.Cluster, client *rancher.Clie
 		cluster.AKSConfig.NodePools = &nodepools
 	}
 	var err error
-	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+	cluster, err = helper.UpdateCluster(specCtx, cluster, client, updateFunc)
 	Expect(err).To(BeNil())
 
 	for _, np := range *cluster.AKSConfig.NodePools {
@@ -346,7 +343,7 @@ func updateSystemNodePoolCheck(cluster *management.Cluster, client *rancher.Clie
 		}
 	}
 
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	err = helpers.WaitClusterToBeUpdated(specCtx, client, cluster.ID)
 	Expect(err).To(BeNil())
 
 	Eventually(func() bool {
@@ -364,7 +361,7 @@ func updateSystemNodePoolCheck(cluster *management.Cluster, client *rancher.Clie
 }
 
 // Qase ID: 230 and 291
-func updateNodePoolModeCheck(cluster *management.Cluster, client *rancher.Client) {
+func updateNodePoolModeCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	var originalModeMap = make(map[string]string)
 	updateFunc := func(cluster *management.Cluster) {
 		nodepools := *cluster.AKSConfig.NodePools
@@ -378,7 +375,7 @@ func updateNodePoolModeCheck(cluster *management.Cluster, client *rancher.Client
 		}
 	}
 	var err error
-	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+	cluster, err = helper.UpdateCluster(specCtx, cluster, client, updateFunc)
 	Expect(err).To(BeNil())
 	for _, np := range *cluster.AKSConfig.NodePools {
 		Expect(np.Mode).ToNot(Equal(originalModeMap[*np.Name]))
@@ -399,13 +396,13 @@ func updateNodePoolModeCheck(cluster *management.Cluster, client *rancher.Client
 }
 
 // Qase ID: 221 and 292
-func updateCloudCredentialsCheck(cluster *management.Cluster, client *rancher.Client) {
+func updateCloudCredentialsCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	newCCID, err := helpers.CreateCloudCredentials(client)
 	Expect(err).To(BeNil())
 	updateFunc := func(cluster *management.Cluster) {
 		cluster.AKSConfig.AzureCredentialSecret = newCCID
 	}
-	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+	cluster, err = helper.UpdateCluster(specCtx, cluster, client, updateFunc)
 	Expect(err).To(BeNil())
 	Expect(cluster.AKSConfig.AzureCredentialSecret).To(Equal(newCCID))
 	Eventually(func() bool {
@@ -414,16 +411,16 @@ func updateCloudCredentialsCheck(cluster *management.Cluster, client *rancher.Cl
 		return cluster.AKSStatus.UpstreamSpec.AzureCredentialSecret == newCCID
 	}, "5m", "5s").Should(BeTrue(), "Failed while upstream cloud credentials update")
 
-	cluster, err = helper.AddNodePool(cluster, 1, client, true, true)
+	cluster, err = helper.AddNodePool(specCtx, cluster, 1, client, true, true)
 	Expect(err).To(BeNil())
 }
 
 // Qase ID: 224 and 293
-func syncAddNodePoolFromAzureAndRancher(cluster *management.Cluster, client *rancher.Client) {
+func syncAddNodePoolFromAzureAndRancher(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	initialNPCount := len(*cluster.AKSConfig.NodePools)
 	const npAzure = "npazure"
 	By("adding nodepool from Azure", func() {
-		err := helper.AddNodePoolOnAzure(npAzure, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, "2")
+		err := helper.AddNodePoolOnAzure(specCtx, npAzure, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, "2")
 		Expect(err).To(BeNil())
 
 		Eventually(func() bool {
@@ -449,15 +446,15 @@ func syncAddNodePoolFromAzureAndRancher(cluster *management.Cluster, client *ran
 			cluster.AKSConfig = cluster.AKSStatus.UpstreamSpec
 		}
 		var err error
-		cluster, err = helper.AddNodePool(cluster, 1, client, true, true)
+		cluster, err = helper.AddNodePool(specCtx, cluster, 1, client, true, true)
 		Expect(err).To(BeNil())
 	})
 }
 
 // Qase ID: 225 and 294
-func upgradeCPK8sFromAzureAndNPFromRancherCheck(cluster *management.Cluster, client *rancher.Client, k8sVersion, upgradeToVersion string) {
+func upgradeCPK8sFromAzureAndNPFromRancherCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client, k8sVersion, upgradeToVersion string) {
 	By("upgrading control plane k8s version from Azure", func() {
-		err := helper.UpgradeAKSOnAzure(clusterName, cluster.AKSConfig.ResourceGroup, upgradeToVersion, "--control-plane-only")
+		err := helper.UpgradeAKSOnAzure(specCtx, clusterName, cluster.AKSConfig.ResourceGroup, upgradeToVersion, "--control-plane-only")
 		Expect(err).To(BeNil())
 		Eventually(func() bool {
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -486,14 +483,14 @@ func upgradeCPK8sFromAzureAndNPFromRancherCheck(cluster *management.Cluster, cli
 			cluster.AKSConfig = cluster.AKSStatus.UpstreamSpec
 		}
 		var err error
-		cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, upgradeToVersion, client, true, true)
+		cluster, err = helper.UpgradeNodeKubernetesVersion(specCtx, cluster, upgradeToVersion, client, true, true)
 		Expect(err).To(BeNil())
 	})
 }
 
 // Qase ID: 275 and 276
-func noAvailabilityZoneP0Checks(cluster *management.Cluster, client *rancher.Client) {
-	helpers.ClusterIsReadyChecks(cluster, client, clusterName)
+func noAvailabilityZoneP0Checks(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
+	suite.ClusterIsReadyChecks(specCtx, cluster, client, clusterName)
 
 	for _, nodepool := range *cluster.AKSConfig.NodePools {
 		Expect(nodepool.AvailabilityZones).To(BeNil())
@@ -505,17 +502,17 @@ func noAvailabilityZoneP0Checks(cluster *management.Cluster, client *rancher.Cli
 
 	var err error
 	var availableVersions []string
-	availableVersions, err = helper.ListAKSAvailableVersions(client, cluster.ID)
+	availableVersions, err = helper.ListAKSAvailableVersions(specCtx, client, cluster.ID)
 	Expect(err).To(BeNil())
 	upgradeToVersion := availableVersions[0]
 
 	By("upgrading the cluster control plane", func() {
-		cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeToVersion, client, true)
+		cluster, err = helper.UpgradeClusterKubernetesVersion(specCtx, cluster, upgradeToVersion, client, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("upgrading the cluster nodepools", func() {
-		cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, upgradeToVersion, client, true, true)
+		cluster, err = helper.UpgradeNodeKubernetesVersion(specCtx, cluster, upgradeToVersion, client, true, true)
 		Expect(err).To(BeNil())
 	})
 
@@ -530,10 +527,10 @@ func noAvailabilityZoneP0Checks(cluster *management.Cluster, client *rancher.Cli
 			nodepools = append(nodepools, newNP)
 			cluster.AKSConfig.NodePools = &nodepools
 		}
-		cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+		cluster, err = helper.UpdateCluster(specCtx, cluster, client, updateFunc)
 		Expect(err).To(BeNil())
 		Expect(len(*cluster.AKSConfig.NodePools)).Should(BeNumerically("==", initialNPCount+1))
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpdated(specCtx, client, cluster.ID)
 		Expect(err).To(BeNil())
 		Eventually(func() int {
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -543,26 +540,26 @@ func noAvailabilityZoneP0Checks(cluster *management.Cluster, client *rancher.Cli
 	})
 
 	By("Deleting the nodepool", func() {
-		cluster, err = helper.DeleteNodePool(cluster, client, true, true)
+		cluster, err = helper.DeleteNodePool(specCtx, cluster, client, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("Scaling the nodepool", func() {
-		cluster, err = helper.ScaleNodePool(cluster, client, 2, true, true)
+		cluster, err = helper.ScaleNodePool(specCtx, cluster, client, 2, true, true)
 		Expect(err).To(BeNil())
 	})
 
 }
 
 // Qase ID: 299, and 238
-func invalidateCloudCredentialsCheck(cluster *management.Cluster, client *rancher.Client, cloudCredID string) {
+func invalidateCloudCredentialsCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client, cloudCredID string) {
 	currentCC, err := client.Management.CloudCredential.ByID(cloudCredID)
 	Expect(err).To(BeNil())
 	err = client.Management.CloudCredential.Delete(currentCC)
 	Expect(err).To(BeNil())
 	GinkgoLogr.Info(fmt.Sprintf("Deleting existing Cloud Credentials: %s:%s", currentCC.Name, currentCC.ID))
 	const scaleCount int64 = 2
-	cluster, err = helper.ScaleNodePool(cluster, client, scaleCount, false, false)
+	cluster, err = helper.ScaleNodePool(specCtx, cluster, client, scaleCount, false, false)
 	Expect(err).To(BeNil())
 	Eventually(func() string {
 		cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -576,10 +573,10 @@ func invalidateCloudCredentialsCheck(cluster *management.Cluster, client *ranche
 	updateFunc := func(cluster *management.Cluster) {
 		cluster.AKSConfig.AzureCredentialSecret = newCCID
 	}
-	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+	cluster, err = helper.UpdateCluster(specCtx, cluster, client, updateFunc)
 	Expect(err).To(BeNil())
 	Expect(cluster.AKSConfig.AzureCredentialSecret).To(Equal(newCCID))
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	err = helpers.WaitClusterToBeUpdated(specCtx, client, cluster.ID)
 	Expect(err).To(BeNil())
 	Eventually(func() bool {
 		cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -609,9 +606,9 @@ func invalidateCloudCredentialsCheck(cluster *management.Cluster, client *ranche
 }
 
 // Qase ID: 302, and 233
-func azureSyncCheck(cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
+func azureSyncCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
 	By("upgrading the control plane and nodepool k8s version", func() {
-		err := helper.UpgradeAKSOnAzure(cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, upgradeToVersion)
+		err := helper.UpgradeAKSOnAzure(specCtx, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, upgradeToVersion)
 		Expect(err).To(BeNil())
 
 		Eventually(func() bool {
@@ -646,7 +643,7 @@ func azureSyncCheck(cluster *management.Cluster, client *rancher.Client, upgrade
 	// Using upstreamSpec so that it also works with import tests
 	currentNPCount := len(*cluster.AKSStatus.UpstreamSpec.NodePools)
 	By("Adding a nodepool", func() {
-		err := helper.AddNodePoolOnAzure(npName, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, fmt.Sprint(nodeCount))
+		err := helper.AddNodePoolOnAzure(specCtx, npName, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, fmt.Sprint(nodeCount))
 		Expect(err).To(BeNil())
 		Eventually(func() bool {
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -671,7 +668,7 @@ func azureSyncCheck(cluster *management.Cluster, client *rancher.Client, upgrade
 
 	By("Scaling the nodepool", func() {
 		const scaleCount = nodeCount + 2
-		err := helper.ScaleNodePoolOnAzure(npName, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, fmt.Sprint(scaleCount))
+		err := helper.ScaleNodePoolOnAzure(specCtx, npName, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, fmt.Sprint(scaleCount))
 		Expect(err).To(BeNil())
 		Eventually(func() bool {
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -695,7 +692,7 @@ func azureSyncCheck(cluster *management.Cluster, client *rancher.Client, upgrade
 	})
 
 	By("Deleting a nodepool", func() {
-		err := helper.DeleteNodePoolOnAzure(npName, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup)
+		err := helper.DeleteNodePoolOnAzure(specCtx, npName, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup)
 		Expect(err).To(BeNil())
 		Eventually(func() bool {
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -728,7 +725,7 @@ func azureSyncCheck(cluster *management.Cluster, client *rancher.Client, upgrade
 		updatedTags["foo"] = "bar"
 		updatedTags["empty-tags"] = ""
 
-		err := helper.UpdateClusterTagOnAzure(updatedTags, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup)
+		err := helper.UpdateClusterTagOnAzure(specCtx, updatedTags, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup)
 		Expect(err).To(BeNil())
 		Eventually(func() bool {
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -749,7 +746,7 @@ func azureSyncCheck(cluster *management.Cluster, client *rancher.Client, upgrade
 	})
 
 	By("Removing tags from cluster", func() {
-		err := helper.UpdateClusterTagOnAzure(originalTags, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup)
+		err := helper.UpdateClusterTagOnAzure(specCtx, originalTags, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup)
 		Expect(err).To(BeNil())
 		Eventually(func() bool {
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
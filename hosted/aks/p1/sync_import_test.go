@@ -8,83 +8,72 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var _ = Describe("SyncImport", func() {
 	var k8sVersion string
-	BeforeEach(func() {
+	BeforeEach(func(specCtx SpecContext) {
 		// assigning cluster nil value so that every new test has a fresh value of the variable
 		// this is to avoid using residual value of a cluster in a test that does not use it
 		cluster = nil
 
 		var err error
-		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, false)
+		k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, location, false)
 		Expect(err).NotTo(HaveOccurred())
 		GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 	})
 
-	AfterEach(func() {
-		if ctx.ClusterCleanup {
-			if cluster != nil && cluster.ID != "" {
-				err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
-				Expect(err).To(BeNil())
-			}
-			err := helper.DeleteAKSClusteronAzure(clusterName)
-			Expect(err).To(BeNil())
-
-		} else {
-			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-		}
-	})
 	When("a cluster is created and imported", func() {
-		BeforeEach(func() {
+		BeforeEach(func(specCtx SpecContext) {
 			var err error
-			err = helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+			err = helper.CreateAKSClusterOnAzure(specCtx, location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
 			Expect(err).To(BeNil())
-			cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+			cluster, err = helper.ImportAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
 
-		It("should successfully Add NP from Azure and then from Rancher", func() {
+		It("should successfully Add NP from Azure and then from Rancher", func(specCtx SpecContext) {
 			testCaseID = 293
-			syncAddNodePoolFromAzureAndRancher(cluster, ctx.RancherAdminClient)
+			syncAddNodePoolFromAzureAndRancher(specCtx, cluster, ctx.RancherAdminClient)
+			suite.ExpectNoDrift(specCtx, helper.Provider{}, ctx.RancherAdminClient, cluster)
 		})
 	})
 
 	When("a cluster is created and imported for upgrade", func() {
 		var availableUpgradeVersions []string
 
-		BeforeEach(func() {
-			if helpers.SkipUpgradeTests {
-				Skip(helpers.SkipUpgradeTestsLog)
-			}
+		BeforeEach(func(specCtx SpecContext) {
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 			var err error
-			k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, true)
+			k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, location, true)
 			Expect(err).NotTo(HaveOccurred())
 			GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 
-			err = helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+			err = helper.CreateAKSClusterOnAzure(specCtx, location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
 			Expect(err).To(BeNil())
 
-			cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+			cluster, err = helper.ImportAKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 
-			availableUpgradeVersions, err = helper.ListAKSAvailableVersions(ctx.RancherAdminClient, cluster.ID)
+			availableUpgradeVersions, err = helper.ListAKSAvailableVersions(specCtx, ctx.RancherAdminClient, cluster.ID)
 			Expect(err).To(BeNil())
 		})
 
-		It("should successfully Change k8s version from Azure should change the CP k8s version and list of available version for NPs", func() {
+		It("should successfully Change k8s version from Azure should change the CP k8s version and list of available version for NPs", func(specCtx SpecContext) {
 			testCaseID = 294
-			upgradeCPK8sFromAzureAndNPFromRancherCheck(cluster, ctx.RancherAdminClient, k8sVersion, availableUpgradeVersions[0])
+			upgradeCPK8sFromAzureAndNPFromRancherCheck(specCtx, cluster, ctx.RancherAdminClient, k8sVersion, availableUpgradeVersions[0])
+			suite.ExpectNoDrift(specCtx, helper.Provider{}, ctx.RancherAdminClient, cluster)
 		})
 
-		It("should sync changes from Azure console back to Rancher", func() {
+		It("should sync changes from Azure console back to Rancher", func(specCtx SpecContext) {
 			testCaseID = 233
-			azureSyncCheck(cluster, ctx.RancherAdminClient, availableUpgradeVersions[0])
+			azureSyncCheck(specCtx, cluster, ctx.RancherAdminClient, availableUpgradeVersions[0])
+			suite.ExpectNoDrift(specCtx, helper.Provider{}, ctx.RancherAdminClient, cluster)
 		})
 	})
 
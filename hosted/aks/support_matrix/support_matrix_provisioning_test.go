@@ -25,6 +25,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var _ = Describe("SupportMatrixProvisioning", func() {
@@ -37,30 +38,20 @@ var _ = Describe("SupportMatrixProvisioning", func() {
 				clusterName string
 				cluster     *management.Cluster
 			)
-			BeforeEach(func() {
+			BeforeEach(func(specCtx SpecContext) {
 				clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
 				var err error
-				cluster, err = helper.CreateAKSHostedCluster(ctx.StdUserClient, clusterName, ctx.CloudCredID, version, location, nil)
+				cluster, err = helper.CreateAKSHostedCluster(specCtx, ctx.StdUserClient, clusterName, ctx.CloudCredID, version, location, nil)
 				Expect(err).To(BeNil())
 				// Requires RancherAdminClient
-				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
 			})
-			AfterEach(func() {
-				if ctx.ClusterCleanup && cluster != nil {
-					err := helper.DeleteAKSHostCluster(cluster, ctx.StdUserClient)
-					Expect(err).To(BeNil())
-					err = helper.DeleteAKSClusteronAzure(clusterName)
-					Expect(err).To(BeNil())
-				} else {
-					fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-				}
-			})
 
-			It("should successfully provision the cluster", func() {
+			It("should successfully provision the cluster", func(specCtx SpecContext) {
 				// Report to Qase
 				testCaseID = 249
-				helpers.ClusterIsReadyChecks(cluster, ctx.StdUserClient, clusterName)
+				suite.ClusterIsReadyChecks(specCtx, cluster, ctx.StdUserClient, clusterName)
 			})
 		})
 	}
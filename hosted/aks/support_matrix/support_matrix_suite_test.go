@@ -15,6 +15,7 @@ limitations under the License.
 package support_matrix_test
 
 import (
+	"context"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -23,6 +24,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var (
@@ -34,13 +36,17 @@ var (
 
 func TestSupportMatrix(t *testing.T) {
 	RegisterFailHandler(Fail)
-	helpers.CommonSynchronizedBeforeSuite()
-	ctx = helpers.CommonBeforeSuite()
-	helpers.CreateStdUserClient(&ctx)
+	suite.CommonSynchronizedBeforeSuite()
+	ctx = suite.CommonBeforeSuite()
+	suite.CreateStdUserClient(&ctx)
 	var err error
-	availableVersionList, err = helper.ListSingleVariantAKSAllVersions(ctx.StdUserClient, ctx.CloudCredID, location)
+	availableVersionList, err = helper.ListSingleVariantAKSAllVersions(context.Background(), ctx.StdUserClient, ctx.CloudCredID, location)
 	Expect(err).To(BeNil())
 	RunSpecs(t, "SupportMatrix Suite")
+	// the resources created before RunSpecs are not deferred by Ginkgo, hence they are cleaned up here
+	if err := ctx.Cleanups.RunAll(context.Background()); err != nil {
+		t.Error(ctx.Cleanups.Summary())
+	}
 }
 
 var _ = ReportBeforeEach(func(report SpecReport) {
@@ -48,7 +54,8 @@ var _ = ReportBeforeEach(func(report SpecReport) {
 	testCaseID = -1
 })
 
-var _ = ReportAfterEach(func(report SpecReport) {
-	// Add result in Qase if asked
+var _ = ReportAfterEach(func(specCtx SpecContext, report SpecReport) {
+	// Add result in Qase and in the run report if asked
 	Qase(testCaseID, report)
+	suite.WriteRunReport(specCtx, report, testCaseID, ctx.RancherAdminClient)
 })
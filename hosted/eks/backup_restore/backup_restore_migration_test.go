@@ -0,0 +1,31 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup_restore_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
+)
+
+var _ = Describe("BackupRestoreMigration", func() {
+	k := kubectl.New()
+
+	It("Do a backup/restore test migrating Rancher to a new hostname and, if RANCHER_MIGRATION_KUBECONFIG is set, to a new upstream", func(specCtx SpecContext) {
+		testCaseID = -1 // the Qase test case does not exist yet
+		suite.MigrationBackupRestoreChecks(specCtx, backupRestoreSpec(k))
+	})
+})
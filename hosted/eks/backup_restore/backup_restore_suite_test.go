@@ -15,9 +15,8 @@ limitations under the License.
 package backup_restore_test
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -31,28 +30,31 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 const (
-	increaseBy          = 1
-	backupResourceName  = "hp-backup"
-	restoreResourceName = "hp-restore"
+	increaseBy = 1
 )
 
 var (
-	testCaseID              int64
-	clusterName, backupFile string
-	ctx                     helpers.RancherContext
-	cluster                 *management.Cluster
-	region                  = helpers.GetEKSRegion()
-	k3sVersion              = os.Getenv("INSTALL_K3S_VERSION")
+	testCaseID  int64
+	clusterName string
+	ctx         helpers.RancherContext
+	cluster     *management.Cluster
+	region      = helpers.GetEKSRegion()
 )
 
 func TestBackupRestore(t *testing.T) {
 	RegisterFailHandler(Fail)
-	helpers.CommonSynchronizedBeforeSuite()
-	ctx = helpers.CommonBeforeSuite()
+	Expect(helpers.Config.Validate(helpers.SuiteBackupRestore)).To(Succeed())
+	suite.CommonSynchronizedBeforeSuite()
+	ctx = suite.CommonBeforeSuite()
 	RunSpecs(t, "BackupRestore Suite")
+	// the resources created before RunSpecs are not deferred by Ginkgo, hence they are cleaned up here
+	if err := ctx.Cleanups.RunAll(context.Background()); err != nil {
+		t.Error(ctx.Cleanups.Summary())
+	}
 }
 
 var _ = ReportBeforeEach(func(report SpecReport) {
@@ -60,99 +62,59 @@ var _ = ReportBeforeEach(func(report SpecReport) {
 	testCaseID = -1
 })
 
-var _ = ReportAfterEach(func(report SpecReport) {
-	// Add result in Qase if asked
+var _ = ReportAfterEach(func(specCtx SpecContext, report SpecReport) {
+	// Add result in Qase and in the run report if asked
 	Qase(testCaseID, report)
+	suite.WriteRunReport(specCtx, report, testCaseID, ctx.RancherAdminClient)
 })
 
-var _ = BeforeEach(func() {
+var _ = BeforeEach(func(specCtx SpecContext) {
 	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
-	k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, false)
+	k8sVersion, err := helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, false)
 	Expect(err).To(BeNil())
 	GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 
 	if helpers.IsImport {
 		By("importing the cluster")
-		err = helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+		err = helper.CreateEKSClusterOnAWS(specCtx, region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
 		Expect(err).To(BeNil())
-		cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
+		cluster, err = helper.ImportEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
 		Expect(err).To(BeNil())
 	} else {
 		By("provisioning the cluster")
-		cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
+		cluster, err = helper.CreateEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
 		Expect(err).To(BeNil())
 	}
-	cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+	cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 	Expect(err).To(BeNil())
 })
 
-var _ = AfterEach(func() {
-	if ctx.ClusterCleanup && cluster != nil {
-		err := helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
-		Expect(err).To(BeNil())
-		if helpers.IsImport {
-			err = helper.DeleteEKSClusterOnAWS(region, clusterName)
-			Expect(err).To(BeNil())
-		}
-	} else {
-		fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-	}
-})
-
-func restoreNodesChecks(cluster *management.Cluster, client *rancher.Client, clusterName string) {
-	helpers.ClusterIsReadyChecks(cluster, client, clusterName)
+func restoreNodesChecks(specCtx context.Context, cluster *management.Cluster, client *rancher.Client, clusterName string) {
+	suite.ClusterIsReadyChecks(specCtx, cluster, client, clusterName)
 	configNodeGroups := *cluster.EKSConfig.NodeGroups
 	initialNodeCount := *configNodeGroups[0].DesiredSize
 
 	By("scaling up the NodeGroup", func() {
 		var err error
-		cluster, err = helper.ScaleNodeGroup(cluster, client, initialNodeCount+increaseBy, true, true)
+		cluster, err = helper.ScaleNodeGroup(specCtx, cluster, client, initialNodeCount+increaseBy, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("adding a NodeGroup", func() {
 		var err error
-		cluster, err = helper.AddNodeGroup(cluster, increaseBy, client, true, true)
+		cluster, err = helper.AddNodeGroup(specCtx, cluster, increaseBy, client, true, true)
 		Expect(err).To(BeNil())
 	})
 }
 
-func BackupRestoreChecks(k *kubectl.Kubectl) {
-	By("Checking hosted cluster is ready", func() {
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
-	})
-
-	By("Performing a backup", func() {
-		backupFile = helpers.ExecuteBackup(k, backupResourceName)
-	})
-
-	By("Perform restore pre-requisites: Uninstalling k3s", func() {
-		out, err := exec.Command("k3s-uninstall.sh").CombinedOutput()
-		Expect(err).To(Not(HaveOccurred()), out)
-	})
-
-	By("Perform restore pre-requisites: Getting k3s ready", func() {
-		helpers.InstallK3S(k, k3sVersion, "none", "none")
-	})
-
-	By("Performing a restore", func() {
-		helpers.ExecuteRestore(k, restoreResourceName, backupFile)
-	})
-
-	By("Performing post migration installations: Installing CertManager", func() {
-		helpers.InstallCertManager(k, "none", "none")
-	})
-
-	By("Performing post migration installations: Installing Rancher Manager", func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "none", "none")
-	})
-
-	By("Performing post migration installations: Checking Rancher Deployments", func() {
-		helpers.CheckRancherDeployments(k)
-	})
-
-	By("Checking hosted cluster can be modified", func() {
-		restoreNodesChecks(cluster, ctx.RancherAdminClient, clusterName)
-	})
+// backupRestoreSpec returns the cluster created by BeforeEach, checked by the backup-restore specs
+func backupRestoreSpec(k *kubectl.Kubectl) suite.BackupRestoreSpec {
+	return suite.BackupRestoreSpec{
+		Kubectl:         k,
+		Client:          ctx.RancherAdminClient,
+		CloudCredential: ctx.CloudCredID,
+		Cluster:         cluster,
+		ClusterName:     clusterName,
+		NodesChecks:     restoreNodesChecks,
+	}
 }
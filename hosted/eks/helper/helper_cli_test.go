@@ -0,0 +1,193 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakecli"
+)
+
+var _ = Describe("EKS and AWS CLI", func() {
+	const (
+		clusterName = "eks-cli"
+		region      = "us-west-2"
+		arn         = "arn:aws:eks:us-west-2:123456789012:cluster/eks-cli"
+	)
+	var runner *fakecli.Runner
+
+	BeforeEach(func() {
+		runner = fakecli.NewRunner()
+		DeferCleanup(runner.Install())
+	})
+
+	AfterEach(func() {
+		Expect(runner.Verify()).To(Succeed())
+	})
+
+	It("CreateEKSClusterOnAWS creates the cluster and keeps the current kubeconfig", func(ctx SpecContext) {
+		DeferCleanup(os.Setenv, "KUBECONFIG", os.Getenv("KUBECONFIG"))
+		Expect(os.Setenv("KUBECONFIG", "/tmp/local.yaml")).To(Succeed())
+		DeferCleanup(func() {
+			_ = os.Remove(os.Getenv(helpers.DownstreamKubeconfig(clusterName)))
+			_ = os.Unsetenv(helpers.DownstreamKubeconfig(clusterName))
+		})
+
+		runner.Expect("eksctl", "create", "cluster", "--region="+region, "--name="+clusterName, "--version=1.31", "--nodegroup-name", "ranchernodes", "--nodes", "2", "--tags", "a=1,b=2", "--node-private-networking")
+
+		Expect(CreateEKSClusterOnAWS(ctx, region, clusterName, "1.31", "2", map[string]string{"b": "2", "a": "1"}, "--node-private-networking")).To(Succeed())
+		Expect(os.Getenv("KUBECONFIG")).To(Equal("/tmp/local.yaml"))
+	})
+
+	It("UpgradeEKSClusterOnAWS upgrades the control plane", func(ctx SpecContext) {
+		runner.Expect("eksctl", "upgrade", "cluster", "--region="+region, "--name="+clusterName, "--version=1.32", "--approve")
+
+		Expect(UpgradeEKSClusterOnAWS(ctx, region, clusterName, "1.32")).To(Succeed())
+	})
+
+	It("AddNodeGroupOnAWS adds a nodegroup", func(ctx SpecContext) {
+		runner.Expect("eksctl", "create", "nodegroup", "--region="+region, "--cluster", clusterName, "--name", "ng1", "--nodes", "1")
+
+		Expect(AddNodeGroupOnAWS(ctx, "ng1", clusterName, region, "--nodes", "1")).To(Succeed())
+	})
+
+	It("ScaleNodeGroupOnAWS scales a nodegroup", func(ctx SpecContext) {
+		runner.Expect("eksctl", "scale", "nodegroup", "--region", region, "--name", "ng1", "--cluster", clusterName, "--nodes", "3", "--nodes-max", "5", "--nodes-min", "1", "--wait")
+
+		Expect(ScaleNodeGroupOnAWS(ctx, "ng1", clusterName, region, 3, 5, 1)).To(Succeed())
+	})
+
+	DescribeTable("UpdateNodeGroupLabelsOnAWS",
+		func(ctx SpecContext, add map[string]string, remove []string, expectedLabels string) {
+			runner.Expect("aws", "eks", "update-nodegroup-config", "--cluster-name", clusterName, "--nodegroup-name", "ng1", "--region", region, "--labels", expectedLabels)
+
+			Expect(UpdateNodeGroupLabelsOnAWS(ctx, clusterName, "ng1", region, add, remove)).To(Succeed())
+		},
+		Entry("adds labels", map[string]string{"b": "2", "a": "1"}, nil, "addOrUpdateLabels={a=1,b=2}"),
+		Entry("removes labels", nil, []string{"a", "b"}, "removeLabels=a,b"),
+		Entry("adds and removes labels", map[string]string{"a": "1"}, []string{"b"}, "addOrUpdateLabels={a=1},removeLabels=b"),
+	)
+
+	It("UpdateNodeGroupLabelsOnAWS does not run anything without labels", func(ctx SpecContext) {
+		Expect(UpdateNodeGroupLabelsOnAWS(ctx, clusterName, "ng1", region, nil, nil)).ToNot(Succeed())
+		Expect(runner.Calls()).To(BeEmpty())
+	})
+
+	It("AddClusterTagsOnAWS tags the cluster ARN", func(ctx SpecContext) {
+		runner.Expect("bash", "-c", "eksctl get cluster --region="+region+" --name="+clusterName+" -ojson | jq -r .[].Arn").Returns(arn + "\n")
+		runner.Expect("aws", "eks", "tag-resource", "--resource-arn", arn, "--tags", "env=ci", "--region", region)
+
+		Expect(AddClusterTagsOnAWS(ctx, clusterName, region, map[string]string{"env": "ci"})).To(Succeed())
+	})
+
+	It("RemoveClusterTagsOnAWS untags the cluster ARN", func(ctx SpecContext) {
+		runner.Expect("bash", "-c", "eksctl get cluster --region="+region+" --name="+clusterName+" -ojson | jq -r .[].Arn").Returns(arn)
+		runner.Expect("aws", "eks", "untag-resource", "--resource-arn", arn, "--region", region, "--tag-keys", "env", "owner")
+
+		Expect(RemoveClusterTagsOnAWS(ctx, clusterName, region, []string{"env", "owner"})).To(Succeed())
+	})
+
+	It("AddClusterTagsOnAWS fails if the ARN can not be fetched", func(ctx SpecContext) {
+		runner.Expect("bash", "-c", "eksctl get cluster --region="+region+" --name="+clusterName+" -ojson | jq -r .[].Arn").Fails("not found", "exit status 1")
+
+		Expect(AddClusterTagsOnAWS(ctx, clusterName, region, map[string]string{"env": "ci"})).To(MatchError(ContainSubstring("failed to get ARN")))
+	})
+
+	It("UpdateLoggingOnAWS enables and disables the logging types", func(ctx SpecContext) {
+		runner.Expect("eksctl", "utils", "update-cluster-logging", "--region", region, "--cluster", clusterName, "--approve", "--enable-types", "api,audit", "--disable-types", "scheduler")
+
+		Expect(UpdateLoggingOnAWS(ctx, clusterName, region, []string{"api", "audit"}, []string{"scheduler"})).To(Succeed())
+	})
+
+	It("UpdateVPCAccess updates the endpoint access", func(ctx SpecContext) {
+		runner.Expect("eksctl", "utils", "update-cluster-vpc-config", "--region", region, "--cluster", clusterName, "--approve", "--public-access", "--private-access", "--public-access-cidrs", "1.1.1.1/32,2.2.2.2/32")
+
+		Expect(UpdateVPCAccess(ctx, clusterName, region, true, true, []string{"1.1.1.1/32", "2.2.2.2/32"})).To(Succeed())
+	})
+
+	It("UpgradeEKSNodegroupOnAWS upgrades a nodegroup", func(ctx SpecContext) {
+		runner.Expect("eksctl", "upgrade", "nodegroup", "--region="+region, "--name=ng1", "--cluster="+clusterName, "--kubernetes-version=1.32")
+
+		Expect(UpgradeEKSNodegroupOnAWS(ctx, region, clusterName, "ng1", "1.32")).To(Succeed())
+	})
+
+	It("GetFromEKS queries the nodegroups and trims the output", func(ctx SpecContext) {
+		runner.Expect("bash", "-c", "eksctl get nodegroup --region="+region+" --cluster="+clusterName+" -ojson --name ng1 | jq -r .[].Status").Returns("ACTIVE\n")
+
+		out, err := GetFromEKS(ctx, region, clusterName, "nodegroup", ".[].Status", "--name", "ng1")
+		Expect(err).To(BeNil())
+		Expect(out).To(Equal("ACTIVE"))
+	})
+
+	It("ModifyEKSNodegroupOnAWS disables the eviction when deleting", func(ctx SpecContext) {
+		runner.Expect("eksctl", "delete", "nodegroup", "--region="+region, "--name=ng1", "--cluster="+clusterName, "--disable-eviction", "--wait")
+
+		Expect(ModifyEKSNodegroupOnAWS(ctx, region, clusterName, "ng1", "delete", "--wait")).To(Succeed())
+	})
+
+	It("DeleteEKSClusterOnAWS deletes all the nodegroups before the cluster", func(ctx SpecContext) {
+		runner.Expect("bash", "-c", "eksctl get nodegroup --region="+region+" --cluster="+clusterName+" -ojson | jq -r .[].Name").Returns("ng1\nng2\n")
+		runner.Expect("eksctl", "delete", "nodegroup", "--region="+region, "--name=ng1", "--cluster="+clusterName, "--disable-eviction", "--wait")
+		runner.Expect("eksctl", "delete", "nodegroup", "--region="+region, "--name=ng2", "--cluster="+clusterName, "--disable-eviction", "--wait")
+		runner.Expect("eksctl", "delete", "cluster", "--region="+region, "--name="+clusterName)
+
+		Expect(DeleteEKSClusterOnAWS(ctx, region, clusterName)).To(Succeed())
+	})
+	It("ListEKSClustersOnAWS describes every cluster to get its tags and creation time", func(ctx SpecContext) {
+		runner.Expect("aws", "eks", "list-clusters", "--region", region, "--output", "json").Returns(`{"clusters": ["eks-cli"]}`)
+		runner.Expect("aws", "eks", "describe-cluster", "--name", clusterName, "--region", region, "--output", "json").
+			Returns(`{"cluster": {"name": "eks-cli", "tags": {"owner": "hosted-providers-qa-ci-user"}, "createdAt": "2024-05-01T15:30:00.000000+05:30"}}`)
+
+		clusters, err := ListEKSClustersOnAWS(ctx, region)
+		Expect(err).To(BeNil())
+		Expect(clusters).To(HaveLen(1))
+		Expect(clusters[0].Name).To(Equal(clusterName))
+		Expect(clusters[0].Location).To(Equal(region))
+		Expect(clusters[0].Labels).To(Equal(map[string]string{"owner": "hosted-providers-qa-ci-user"}))
+		Expect(clusters[0].CreatedAt.UTC()).To(Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)))
+	})
+
+	It("ListEKSClustersOnAWS fails if a cluster can not be described", func(ctx SpecContext) {
+		runner.Expect("aws", "eks", "list-clusters", "--region", region, "--output", "json").Returns(`{"clusters": ["eks-cli"]}`)
+		runner.Expect("aws", "eks", "describe-cluster", "--name", clusterName, "--region", region, "--output", "json").Fails("AccessDenied", "exit status 254")
+
+		_, err := ListEKSClustersOnAWS(ctx, region)
+		Expect(err).To(MatchError(ContainSubstring("Failed to describe cluster: AccessDenied")))
+	})
+
+	It("GetEKSNodegroupOnAWS describes the nodegroup with AWS CLI", func(ctx SpecContext) {
+		runner.Expect("aws", "eks", "describe-nodegroup", "--cluster-name", clusterName, "--nodegroup-name", "ng1", "--region", region, "--output", "json").
+			Returns(`{"nodegroup": {"nodegroupName": "ng1", "status": "ACTIVE", "version": "1.31", "amiType": "AL2_x86_64_GPU", "releaseVersion": "1.31.0-20241024", "instanceTypes": ["g4dn.xlarge"], "scalingConfig": {"desiredSize": 2, "minSize": 1, "maxSize": 3}}}`)
+
+		nodegroup, err := GetEKSNodegroupOnAWS(ctx, region, clusterName, "ng1")
+		Expect(err).To(BeNil())
+		Expect(nodegroup).To(Equal(EKSNodegroup{
+			Name:           "ng1",
+			Status:         "ACTIVE",
+			Version:        "1.31",
+			AMIType:        "AL2_x86_64_GPU",
+			ReleaseVersion: "1.31.0-20241024",
+			InstanceTypes:  []string{"g4dn.xlarge"},
+			DesiredSize:    2,
+			MinSize:        1,
+			MaxSize:        3,
+		}))
+	})
+})
@@ -1,25 +1,24 @@
 package helper
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"maps"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
-	"github.com/onsi/ginkgo/v2"
-	. "github.com/onsi/gomega"
-	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/aws/aws-sdk-go/aws"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 
-	"github.com/epinio/epinio/acceptance/helpers/proc"
 	"github.com/pkg/errors"
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	"github.com/rancher/shepherd/extensions/clusters"
 	"github.com/rancher/shepherd/extensions/clusters/eks"
 	"github.com/rancher/shepherd/pkg/config"
 	namegen "github.com/rancher/shepherd/pkg/namegenerator"
@@ -28,7 +27,7 @@ import (
 )
 
 // CreateEKSHostedCluster is a helper function that creates an EKS hosted cluster
-func CreateEKSHostedCluster(client *rancher.Client, displayName, cloudCredentialID, kubernetesVersion, region string, updateFunc func(clusterConfig *eks.ClusterConfig)) (*management.Cluster, error) {
+func CreateEKSHostedCluster(ctx context.Context, client *rancher.Client, displayName, cloudCredentialID, kubernetesVersion, region string, updateFunc func(clusterConfig *eks.ClusterConfig)) (*management.Cluster, error) {
 	var eksClusterConfig eks.ClusterConfig
 	config.LoadConfig(eks.EKSClusterConfigConfigurationFileKey, &eksClusterConfig)
 	eksClusterConfig.Region = region
@@ -38,10 +37,15 @@ func CreateEKSHostedCluster(client *rancher.Client, displayName, cloudCredential
 	if updateFunc != nil {
 		updateFunc(&eksClusterConfig)
 	}
-	return eks.CreateEKSHostedCluster(client, displayName, cloudCredentialID, eksClusterConfig, false, false, false, false, nil)
+	cluster, err := eks.CreateEKSHostedCluster(client, displayName, cloudCredentialID, eksClusterConfig, false, false, false, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	helpers.RegisterHostedClusterCleanup(cluster, client, DeleteEKSHostCluster)
+	return cluster, nil
 }
 
-func ImportEKSHostedCluster(client *rancher.Client, displayName, cloudCredentialID, region string) (*management.Cluster, error) {
+func ImportEKSHostedCluster(ctx context.Context, client *rancher.Client, displayName, cloudCredentialID, region string) (*management.Cluster, error) {
 	cluster := &management.Cluster{
 		DockerRootDir: "/var/lib/docker",
 		EKSConfig: &management.EKSClusterConfigSpec{
@@ -57,45 +61,62 @@ func ImportEKSHostedCluster(client *rancher.Client, displayName, cloudCredential
 	if err != nil {
 		return nil, err
 	}
+	helpers.RegisterHostedClusterCleanup(clusterResp, client, DeleteEKSHostCluster)
 	return clusterResp, err
 }
 
 // DeleteEKSHostCluster deletes the EKS cluster
-func DeleteEKSHostCluster(cluster *management.Cluster, client *rancher.Client) error {
-	return client.Management.Cluster.Delete(cluster)
-
+func DeleteEKSHostCluster(ctx context.Context, cluster *management.Cluster, client *rancher.Client) error {
+	if err := client.Management.Cluster.Delete(cluster); err != nil {
+		return err
+	}
+	helpers.ForgetHostedClusterCleanup(cluster)
+	return nil
 }
 
 // UpgradeClusterKubernetesVersion upgrades the k8s version to the value defined by upgradeToVersion.
 // if checkClusterConfig is set to true, it will validate that the cluster control plane has been upgrade successfully
-func UpgradeClusterKubernetesVersion(cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, checkClusterConfig bool) (*management.Cluster, error) {
+func UpgradeClusterKubernetesVersion(ctx context.Context, cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	currentVersion := *cluster.EKSConfig.KubernetesVersion
 	upgradedCluster.EKSConfig.KubernetesVersion = &upgradeToVersion
 
 	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
-	Expect(err).To(BeNil())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to upgrade cluster")
+	}
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
-		Expect(*cluster.EKSConfig.KubernetesVersion).To(Equal(upgradeToVersion))
+		if err = helpers.CheckField(cluster, "EKSConfig.KubernetesVersion", upgradeToVersion, *cluster.EKSConfig.KubernetesVersion); err != nil {
+			return cluster, err
+		}
 		// ensure nodegroup version is still the same when config is applied
-		for _, ng := range *cluster.EKSConfig.NodeGroups {
-			Expect(*ng.Version).To(Equal(currentVersion))
+		if err = checkNodeGroupVersions(cluster, "EKSConfig", *cluster.EKSConfig.NodeGroups, currentVersion); err != nil {
+			return cluster, err
 		}
 
 		// Check if the desired config has been applied in Rancher
 		// Check if EKSConfig has correct KubernetesVersion after upgrade (Ref: eks-operator/issues/668)
-		Eventually(func() bool {
-			ginkgo.GinkgoLogr.Info("Waiting for k8s upgrade to appear in EKSStatus.UpstreamSpec & EKSConfig ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return *cluster.EKSStatus.UpstreamSpec.KubernetesVersion == upgradeToVersion && *cluster.EKSConfig.KubernetesVersion == upgradeToVersion
-		}, tools.SetTimeout(15*time.Minute), 30*time.Second).Should(BeTrue())
+		// the upgrade is timed until it appears in EKSStatus.UpstreamSpec
+		start := time.Now()
+		err = helpers.WaitFor(ctx, "the k8s upgrade to appear in EKSStatus.UpstreamSpec & EKSConfig", 15*time.Minute, 30*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
+			}
+			if err = helpers.CheckField(cluster, "EKSStatus.UpstreamSpec.KubernetesVersion", upgradeToVersion, *cluster.EKSStatus.UpstreamSpec.KubernetesVersion); err != nil {
+				return false, err
+			}
+			return true, helpers.CheckField(cluster, "EKSConfig.KubernetesVersion", upgradeToVersion, *cluster.EKSConfig.KubernetesVersion)
+		})
+		if err != nil {
+			return cluster, err
+		}
+		helpers.CheckLifecycleOperation(helpers.OperationControlPlaneUpgrade, time.Since(start))
 
 		// ensure nodegroup version is same in Rancher
-		for _, ng := range *cluster.EKSStatus.UpstreamSpec.NodeGroups {
-			Expect(*ng.Version).To(Equal(currentVersion))
+		if err = checkNodeGroupVersions(cluster, "EKSStatus.UpstreamSpec", *cluster.EKSStatus.UpstreamSpec.NodeGroups, currentVersion); err != nil {
+			return cluster, err
 		}
 	}
 	return cluster, nil
@@ -105,7 +126,7 @@ func UpgradeClusterKubernetesVersion(cluster *management.Cluster, upgradeToVersi
 // if wait is set to true, it will wait until the cluster finishes upgrading;
 // if checkClusterConfig is set to true, it will validate that nodegroup has been upgraded successfully
 // if useEksctl is set to true, nodegroup will be upgraded using eksctl utility instead of updating it from Rancher
-func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, wait, checkClusterConfig, useEksctl bool) (*management.Cluster, error) {
+func UpgradeNodeKubernetesVersion(ctx context.Context, cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, wait, checkClusterConfig, useEksctl bool) (*management.Cluster, error) {
 	var err error
 
 	if !useEksctl {
@@ -116,46 +137,54 @@ func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion
 		}
 
 		cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
-		Expect(err).To(BeNil())
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to upgrade nodegroups")
+		}
 
 		if wait {
-			err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
-			Expect(err).To(BeNil())
+			if err = helpers.WaitClusterToBeUpgraded(ctx, client, cluster.ID, helpers.OperationNodePoolUpgrade); err != nil {
+				return cluster, errors.Wrap(err, "Failed to wait for the nodegroups upgrade")
+			}
 		}
 	} else {
 		// Upgrade Nodegroup using eksctl due to custom Launch template
 		for _, ng := range *cluster.EKSConfig.NodeGroups {
-			err = UpgradeEKSNodegroupOnAWS(helpers.GetEKSRegion(), cluster.EKSConfig.DisplayName, *ng.NodegroupName, upgradeToVersion)
-			Expect(err).To(BeNil())
+			if err = UpgradeEKSNodegroupOnAWS(ctx, helpers.GetEKSRegion(), cluster.EKSConfig.DisplayName, *ng.NodegroupName, upgradeToVersion); err != nil {
+				return cluster, err
+			}
 		}
 	}
 
 	if checkClusterConfig {
-		Eventually(func() bool {
-			// Check if the desired config has been applied
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			ginkgo.GinkgoLogr.Info("waiting for the nodegroup upgrade to appear in EKSStatus.UpstreamSpec ...")
-			for _, ng := range *cluster.EKSStatus.UpstreamSpec.NodeGroups {
-				if ng.Version == nil || *ng.Version != upgradeToVersion {
-					return false
-				}
+		// Check if the desired config has been applied
+		err = helpers.WaitFor(ctx, "the nodegroup upgrade to appear in EKSStatus.UpstreamSpec", 15*time.Minute, 30*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
 			}
-			return true
-		}, tools.SetTimeout(15*time.Minute), 30*time.Second).Should(BeTrue())
+			return true, checkNodeGroupVersions(cluster, "EKSStatus.UpstreamSpec", *cluster.EKSStatus.UpstreamSpec.NodeGroups, upgradeToVersion)
+		})
+		if err != nil {
+			return cluster, err
+		}
 	}
 
 	// Ensure nodegroup version is correct in Rancher after upgrade
-	for _, ng := range *cluster.EKSConfig.NodeGroups {
-		Expect(*ng.Version).To(Equal(upgradeToVersion))
-	}
+	return cluster, checkNodeGroupVersions(cluster, "EKSConfig", *cluster.EKSConfig.NodeGroups, upgradeToVersion)
+}
 
-	return cluster, nil
+// checkNodeGroupVersions returns a helpers.ConfigMismatchError if the version of a nodegroup of the given config is not the expected one
+func checkNodeGroupVersions(cluster *management.Cluster, config string, nodeGroups []management.NodeGroup, version string) error {
+	for _, ng := range nodeGroups {
+		if err := helpers.CheckField(cluster, fmt.Sprintf("%s.NodeGroups[%s].Version", config, *ng.NodegroupName), version, pointer.StringDeref(ng.Version, "")); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // AddNodeGroup adds a nodegroup to the list; it uses the nodegroup template defined in CATTLE_TEST_CONFIG file
 // if checkClusterConfig is set to true, it will validate that nodegroup has been added successfully
-func AddNodeGroup(cluster *management.Cluster, increaseBy int, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+func AddNodeGroup(ctx context.Context, cluster *management.Cluster, increaseBy int, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	currentNodeGroupNumber := len(*cluster.EKSConfig.NodeGroups)
 
@@ -181,32 +210,36 @@ func AddNodeGroup(cluster *management.Cluster, increaseBy int, client *rancher.C
 	upgradedCluster.EKSConfig.NodeGroups = &updateNodeGroupsList
 
 	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
-	Expect(err).To(BeNil())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to add nodegroups")
+	}
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
-		Expect(len(*cluster.EKSConfig.NodeGroups)).Should(BeNumerically("==", currentNodeGroupNumber+increaseBy))
-		for i, ng := range *cluster.EKSConfig.NodeGroups {
-			Expect(ng.NodegroupName).To(Equal(updateNodeGroupsList[i].NodegroupName))
+		if err = checkNodeGroupNames(cluster, "EKSConfig", *cluster.EKSConfig.NodeGroups, updateNodeGroupsList); err != nil {
+			return cluster, err
 		}
 	}
 
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
-		Expect(err).To(BeNil())
+		if err = helpers.WaitClusterToBeUpgraded(ctx, client, cluster.ID, helpers.OperationAddNodePool); err != nil {
+			return cluster, errors.Wrap(err, "Failed to wait for the nodegroups addition")
+		}
 	}
 
 	if checkClusterConfig {
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() int {
-			ginkgo.GinkgoLogr.Info("Waiting for the total nodegroup count to increase in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return len(*cluster.EKSStatus.UpstreamSpec.NodeGroups)
-		}, tools.SetTimeout(15*time.Minute), 10*time.Second).Should(BeNumerically("==", currentNodeGroupNumber+increaseBy))
-
-		for i, ng := range *cluster.EKSStatus.UpstreamSpec.NodeGroups {
-			Expect(ng.NodegroupName).To(Equal(updateNodeGroupsList[i].NodegroupName))
+		err = helpers.WaitFor(ctx, "the total nodegroup count to increase in EKSStatus.UpstreamSpec", 15*time.Minute, 10*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
+			}
+			return true, helpers.CheckField(cluster, "len(EKSStatus.UpstreamSpec.NodeGroups)", currentNodeGroupNumber+increaseBy, len(*cluster.EKSStatus.UpstreamSpec.NodeGroups))
+		})
+		if err != nil {
+			return cluster, err
+		}
+		if err = checkNodeGroupNames(cluster, "EKSStatus.UpstreamSpec", *cluster.EKSStatus.UpstreamSpec.NodeGroups, updateNodeGroupsList); err != nil {
+			return cluster, err
 		}
 	}
 
@@ -232,7 +265,7 @@ func AddNodeGroupToConfig(eksClusterConfig eks.ClusterConfig, ngCount int) (eks.
 // DeleteNodeGroup deletes a nodegroup from the list
 // if checkClusterConfig is set to true, it will validate that nodegroup has been deleted successfully
 // TODO: Modify this method to delete a custom qty of DeleteNodeGroup, perhaps by adding an `decreaseBy int` arg
-func DeleteNodeGroup(cluster *management.Cluster, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+func DeleteNodeGroup(ctx context.Context, cluster *management.Cluster, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	currentNodeGroupNumber := len(*cluster.EKSConfig.NodeGroups)
 	configNodeGroups := *cluster.EKSConfig.NodeGroups
@@ -240,39 +273,57 @@ func DeleteNodeGroup(cluster *management.Cluster, client *rancher.Client, wait,
 	upgradedCluster.EKSConfig.NodeGroups = &updateNodeGroupsList
 
 	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
-	Expect(err).To(BeNil())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to delete nodegroup")
+	}
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
-		Expect(len(*cluster.EKSConfig.NodeGroups)).Should(BeNumerically("==", currentNodeGroupNumber-1))
-		for i, ng := range *cluster.EKSConfig.NodeGroups {
-			Expect(ng.NodegroupName).To(Equal(updateNodeGroupsList[i].NodegroupName))
+		if err = checkNodeGroupNames(cluster, "EKSConfig", *cluster.EKSConfig.NodeGroups, updateNodeGroupsList); err != nil {
+			return cluster, err
 		}
 	}
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
-		Expect(err).To(BeNil())
+		if err = helpers.WaitClusterToBeUpgraded(ctx, client, cluster.ID, helpers.OperationDeleteNodePool); err != nil {
+			return cluster, errors.Wrap(err, "Failed to wait for the nodegroup deletion")
+		}
 	}
 	if checkClusterConfig {
 
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() int {
-			ginkgo.GinkgoLogr.Info("Waiting for the total nodegroup count to decrease in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return len(*cluster.EKSStatus.UpstreamSpec.NodeGroups)
-		}, tools.SetTimeout(15*time.Minute), 10*time.Second).Should(BeNumerically("==", currentNodeGroupNumber-1))
-		for i, ng := range *cluster.EKSStatus.UpstreamSpec.NodeGroups {
-			Expect(ng.NodegroupName).To(Equal(updateNodeGroupsList[i].NodegroupName))
+		err = helpers.WaitFor(ctx, "the total nodegroup count to decrease in EKSStatus.UpstreamSpec", 15*time.Minute, 10*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
+			}
+			return true, helpers.CheckField(cluster, "len(EKSStatus.UpstreamSpec.NodeGroups)", currentNodeGroupNumber-1, len(*cluster.EKSStatus.UpstreamSpec.NodeGroups))
+		})
+		if err != nil {
+			return cluster, err
+		}
+		if err = checkNodeGroupNames(cluster, "EKSStatus.UpstreamSpec", *cluster.EKSStatus.UpstreamSpec.NodeGroups, updateNodeGroupsList); err != nil {
+			return cluster, err
 		}
 	}
 	return cluster, nil
 }
 
+// checkNodeGroupNames returns a helpers.ConfigMismatchError if the nodegroups of the given config are not the expected ones, in the same order
+func checkNodeGroupNames(cluster *management.Cluster, config string, nodeGroups, expectedNodeGroups []management.NodeGroup) error {
+	if err := helpers.CheckField(cluster, fmt.Sprintf("len(%s.NodeGroups)", config), len(expectedNodeGroups), len(nodeGroups)); err != nil {
+		return err
+	}
+	for i, ng := range nodeGroups {
+		if err := helpers.CheckField(cluster, fmt.Sprintf("%s.NodeGroups[%d].NodegroupName", config, i), *expectedNodeGroups[i].NodegroupName, *ng.NodegroupName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ScaleNodeGroup modifies the number of initialNodeCount of all the nodegroups as defined by nodeCount
 // if wait is set to true, it will wait until the cluster finishes updating;
 // if checkClusterConfig is set to true, it will validate that nodegroup has been scaled successfully
-func ScaleNodeGroup(cluster *management.Cluster, client *rancher.Client, nodeCount int64, wait, checkClusterConfig bool) (*management.Cluster, error) {
+func ScaleNodeGroup(ctx context.Context, cluster *management.Cluster, client *rancher.Client, nodeCount int64, wait, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	configNodeGroups := *upgradedCluster.EKSConfig.NodeGroups
 	for i := range configNodeGroups {
@@ -281,129 +332,181 @@ func ScaleNodeGroup(cluster *management.Cluster, client *rancher.Client, nodeCou
 	}
 
 	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
-	Expect(err).To(BeNil())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to scale nodegroups")
+	}
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
-		configNodeGroups = *cluster.EKSConfig.NodeGroups
-		for i := range configNodeGroups {
-			Expect(*configNodeGroups[i].DesiredSize).To(BeNumerically("==", nodeCount))
+		if err = checkNodeGroupSizes(cluster, "EKSConfig", *cluster.EKSConfig.NodeGroups, nodeCount); err != nil {
+			return cluster, err
 		}
 	}
 
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
-		Expect(err).To(BeNil())
+		if err = helpers.WaitClusterToBeUpgraded(ctx, client, cluster.ID, helpers.OperationScale); err != nil {
+			return cluster, errors.Wrap(err, "Failed to wait for the nodegroups scaling")
+		}
 	}
 
 	if checkClusterConfig {
 		// check that the desired config is applied on Rancher
-		Eventually(func() bool {
-			ginkgo.GinkgoLogr.Info("Waiting for the node count change to appear in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			upstreamNodeGroups := *cluster.EKSStatus.UpstreamSpec.NodeGroups
-			for i := range upstreamNodeGroups {
-				if ng := upstreamNodeGroups[i]; *ng.DesiredSize != nodeCount {
-					return false
-				}
+		err = helpers.WaitFor(ctx, "the node count change to appear in EKSStatus.UpstreamSpec", 15*time.Minute, 10*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
 			}
-			return true
-		}, tools.SetTimeout(15*time.Minute), 10*time.Second).Should(BeTrue())
+			return true, checkNodeGroupSizes(cluster, "EKSStatus.UpstreamSpec", *cluster.EKSStatus.UpstreamSpec.NodeGroups, nodeCount)
+		})
+		if err != nil {
+			return cluster, err
+		}
 	}
 
 	return cluster, nil
 }
 
+// checkNodeGroupSizes returns a helpers.ConfigMismatchError if the desired size of a nodegroup of the given config is not the expected one
+func checkNodeGroupSizes(cluster *management.Cluster, config string, nodeGroups []management.NodeGroup, nodeCount int64) error {
+	for _, ng := range nodeGroups {
+		if err := helpers.CheckField(cluster, fmt.Sprintf("%s.NodeGroups[%s].DesiredSize", config, *ng.NodegroupName), nodeCount, *ng.DesiredSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // UpdateLogging updates the logging of a EKS cluster, Types: api, audit, authenticator, controllerManager, scheduler
 // if checkClusterConfig is true, it validates the update
-func UpdateLogging(cluster *management.Cluster, client *rancher.Client, loggingTypes []string, checkClusterConfig bool) (*management.Cluster, error) {
+func UpdateLogging(ctx context.Context, cluster *management.Cluster, client *rancher.Client, loggingTypes []string, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	upgradedCluster.EKSConfig.LoggingTypes = &loggingTypes
 
 	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
-	Expect(err).To(BeNil())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to update logging")
+	}
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
-		Expect(*upgradedCluster.EKSConfig.LoggingTypes).Should(HaveExactElements(loggingTypes))
+		if err = checkStrings(cluster, "EKSConfig.LoggingTypes", loggingTypes, *upgradedCluster.EKSConfig.LoggingTypes); err != nil {
+			return cluster, err
+		}
 
-		Eventually(func() []string {
-			ginkgo.GinkgoLogr.Info("Waiting for the logging changes to appear in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return *cluster.EKSStatus.UpstreamSpec.LoggingTypes
-		}, tools.SetTimeout(10*time.Minute), 15*time.Second).Should(HaveExactElements(loggingTypes))
+		err = helpers.WaitFor(ctx, "the logging changes to appear in EKSStatus.UpstreamSpec", 10*time.Minute, 15*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
+			}
+			return true, checkStrings(cluster, "EKSStatus.UpstreamSpec.LoggingTypes", loggingTypes, *cluster.EKSStatus.UpstreamSpec.LoggingTypes)
+		})
+		if err != nil {
+			return cluster, err
+		}
 	}
 	return cluster, nil
 }
 
+// checkStrings returns a helpers.ConfigMismatchError if the list does not have exactly the expected elements, in the same order
+func checkStrings(cluster *management.Cluster, field string, expected, actual []string) error {
+	if slices.Equal(expected, actual) {
+		return nil
+	}
+	return &helpers.ConfigMismatchError{ClusterName: cluster.Name, Field: field, Expected: expected, Actual: actual}
+}
+
 // UpdateAccess updates the network access of a EKS cluster, Types: publicAccess, privateAccess
 // if checkClusterConfig is true, it validates the update
-func UpdateAccess(cluster *management.Cluster, client *rancher.Client, publicAccess, privateAccess bool, checkClusterConfig bool) (*management.Cluster, error) {
+func UpdateAccess(ctx context.Context, cluster *management.Cluster, client *rancher.Client, publicAccess, privateAccess bool, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	upgradedCluster.EKSConfig.PublicAccess = &publicAccess
 	upgradedCluster.EKSConfig.PrivateAccess = &privateAccess
 
 	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to update access")
+	}
 
 	if checkClusterConfig {
-		Eventually(func() bool {
-			// Check if the desired config is set correctly
-			Expect(*upgradedCluster.EKSConfig.PublicAccess).Should(Equal(publicAccess))
-			Expect(*upgradedCluster.EKSConfig.PrivateAccess).Should(Equal(privateAccess))
-
-			ginkgo.GinkgoLogr.Info("Waiting for the access changes to appear in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return *cluster.EKSStatus.UpstreamSpec.PublicAccess == publicAccess && *cluster.EKSStatus.UpstreamSpec.PrivateAccess == privateAccess
-		}, tools.SetTimeout(10*time.Minute), 15*time.Second).Should(BeTrue())
+		err = helpers.WaitFor(ctx, "the access changes to appear in EKSStatus.UpstreamSpec", 10*time.Minute, 15*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
+			}
+			if err = helpers.CheckField(cluster, "EKSStatus.UpstreamSpec.PublicAccess", publicAccess, *cluster.EKSStatus.UpstreamSpec.PublicAccess); err != nil {
+				return false, err
+			}
+			return true, helpers.CheckField(cluster, "EKSStatus.UpstreamSpec.PrivateAccess", privateAccess, *cluster.EKSStatus.UpstreamSpec.PrivateAccess)
+		})
+		if err != nil {
+			return cluster, err
+		}
 	}
-	return cluster, err
+	return cluster, nil
 }
 
 // UpdatePublicAccessSources updates the network access sources of a EKS cluster
 // if checkClusterConfig is true, it validates the update
-func UpdatePublicAccessSources(cluster *management.Cluster, client *rancher.Client, publicAccessSources []string, checkClusterConfig bool) (*management.Cluster, error) {
+func UpdatePublicAccessSources(ctx context.Context, cluster *management.Cluster, client *rancher.Client, publicAccessSources []string, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	*upgradedCluster.EKSConfig.PublicAccessSources = append(*upgradedCluster.EKSConfig.PublicAccessSources, publicAccessSources...)
 	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to update public access sources")
+	}
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
-		Expect(*upgradedCluster.EKSConfig.PublicAccessSources).Should(ContainElements(publicAccessSources))
+		if err = checkContainsStrings(cluster, "EKSConfig.PublicAccessSources", publicAccessSources, *cluster.EKSConfig.PublicAccessSources); err != nil {
+			return cluster, err
+		}
 
-		Eventually(func() []string {
-			ginkgo.GinkgoLogr.Info("Waiting for the publicaccess sources changes to appear in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return *cluster.EKSStatus.UpstreamSpec.PublicAccessSources
-		}, tools.SetTimeout(10*time.Minute), 15*time.Second).Should(ContainElements(publicAccessSources))
+		err = helpers.WaitFor(ctx, "the publicaccess sources changes to appear in EKSStatus.UpstreamSpec", 10*time.Minute, 15*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
+			}
+			return true, checkContainsStrings(cluster, "EKSStatus.UpstreamSpec.PublicAccessSources", publicAccessSources, *cluster.EKSStatus.UpstreamSpec.PublicAccessSources)
+		})
+		if err != nil {
+			return cluster, err
+		}
 	}
 	return cluster, nil
 }
 
+// checkContainsStrings returns a helpers.ConfigMismatchError if the list does not contain all the expected elements
+func checkContainsStrings(cluster *management.Cluster, field string, expected, actual []string) error {
+	for _, element := range expected {
+		if !slices.Contains(actual, element) {
+			return &helpers.ConfigMismatchError{ClusterName: cluster.Name, Field: field, Expected: fmt.Sprintf("to contain %s", element), Actual: actual}
+		}
+	}
+	return nil
+}
+
 // UpdateClusterTags updates the tags of a EKS cluster;
 // the given tag list will replace the existing tags; this is required to be able to delete tag removal using this function
 // if wait is set to true, it waits until the update is complete; if checkClusterConfig is true, it validates the update
-func UpdateClusterTags(cluster *management.Cluster, client *rancher.Client, tags map[string]string, checkClusterConfig bool) (*management.Cluster, error) {
+func UpdateClusterTags(ctx context.Context, cluster *management.Cluster, client *rancher.Client, tags map[string]string, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	upgradedCluster.EKSConfig.Tags = &tags
 
 	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
-	Expect(err).To(BeNil())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to update cluster tags")
+	}
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
-		for key, value := range tags {
-			Expect(*cluster.EKSConfig.Tags).Should(HaveKeyWithValue(key, value))
+		if err = checkContainsKeys(cluster, "EKSConfig.Tags", tags, *cluster.EKSConfig.Tags); err != nil {
+			return cluster, err
+		}
+		err = helpers.WaitFor(ctx, "the cluster tag changes to appear in EKSStatus.UpstreamSpec", 10*time.Minute, 15*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
+			}
+			return true, checkMap(cluster, "EKSStatus.UpstreamSpec.Tags", tags, *cluster.EKSStatus.UpstreamSpec.Tags)
+		})
+		if err != nil {
+			return cluster, err
 		}
-		Eventually(func() bool {
-			ginkgo.GinkgoLogr.Info("Waiting for the cluster tag changes to appear in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return maps.Equal(tags, *cluster.EKSStatus.UpstreamSpec.Tags)
-		}, tools.SetTimeout(10*time.Minute), 15*time.Second).Should(BeTrue())
 	}
 	return cluster, nil
 }
@@ -411,7 +514,7 @@ func UpdateClusterTags(cluster *management.Cluster, client *rancher.Client, tags
 // UpdateNodegroupMetadata updates the tags & labels of a EKS Node groups
 // the given tags and labels will replace the existing counterparts
 // if wait is set to true, it waits until the update is complete; if checkClusterConfig is true, it validates the update
-func UpdateNodegroupMetadata(cluster *management.Cluster, client *rancher.Client, tags, labels map[string]string, checkClusterConfig bool) (*management.Cluster, error) {
+func UpdateNodegroupMetadata(ctx context.Context, cluster *management.Cluster, client *rancher.Client, tags, labels map[string]string, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	configNodeGroups := *upgradedCluster.EKSConfig.NodeGroups
 	for i := range configNodeGroups {
@@ -421,37 +524,61 @@ func UpdateNodegroupMetadata(cluster *management.Cluster, client *rancher.Client
 
 	var err error
 	cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
-	Expect(err).To(BeNil())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to update nodegroup metadata")
+	}
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
 		for _, ng := range *cluster.EKSConfig.NodeGroups {
-			for key, value := range tags {
-				Expect(*ng.Tags).Should(HaveKeyWithValue(key, value))
+			field := fmt.Sprintf("EKSConfig.NodeGroups[%s].", *ng.NodegroupName)
+			if err = checkContainsKeys(cluster, field+"Tags", tags, *ng.Tags); err != nil {
+				return cluster, err
 			}
-			for key, value := range labels {
-				Expect(*ng.Labels).Should(HaveKeyWithValue(key, value))
+			if err = checkContainsKeys(cluster, field+"Labels", labels, *ng.Labels); err != nil {
+				return cluster, err
 			}
 		}
 
-		Eventually(func() bool {
-			ginkgo.GinkgoLogr.Info("Waiting for the nodegroup metadata changes to appear in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
+		err = helpers.WaitFor(ctx, "the nodegroup metadata changes to appear in EKSStatus.UpstreamSpec", 10*time.Minute, 15*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
+			}
 
 			for _, ng := range *cluster.EKSStatus.UpstreamSpec.NodeGroups {
 				if maps.Equal(tags, *ng.Tags) && maps.Equal(labels, *ng.Labels) {
-					return true
+					return true, nil
 				}
 			}
-			return false
-		}, tools.SetTimeout(10*time.Minute), 15*time.Second).Should(BeTrue())
+			return false, &helpers.ConfigMismatchError{ClusterName: cluster.Name, Field: "EKSStatus.UpstreamSpec.NodeGroups", Expected: fmt.Sprintf("a nodegroup with tags %v and labels %v", tags, labels), Actual: "none"}
+		})
+		if err != nil {
+			return cluster, err
+		}
 	}
 	return cluster, nil
 }
 
+// checkContainsKeys returns a helpers.ConfigMismatchError if the map does not contain all the expected keys with their value
+func checkContainsKeys(cluster *management.Cluster, field string, expected, actual map[string]string) error {
+	for key, value := range expected {
+		if err := helpers.CheckField(cluster, fmt.Sprintf("%s[%s]", field, key), value, actual[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkMap returns a helpers.ConfigMismatchError if the map does not have exactly the expected keys and values
+func checkMap(cluster *management.Cluster, field string, expected, actual map[string]string) error {
+	if maps.Equal(expected, actual) {
+		return nil
+	}
+	return &helpers.ConfigMismatchError{ClusterName: cluster.Name, Field: field, Expected: expected, Actual: actual}
+}
+
 // UpdateCluster is a generic function to update a cluster
-func UpdateCluster(cluster *management.Cluster, client *rancher.Client, updateFunc func(*management.Cluster)) (*management.Cluster, error) {
+func UpdateCluster(ctx context.Context, cluster *management.Cluster, client *rancher.Client, updateFunc func(*management.Cluster)) (*management.Cluster, error) {
 	upgradedCluster := cluster
 
 	updateFunc(upgradedCluster)
@@ -461,13 +588,13 @@ func UpdateCluster(cluster *management.Cluster, client *rancher.Client, updateFu
 
 // ListEKSAvailableVersions lists all the available and UI supported EKS versions for cluster upgrade.
 // this function is a fork of r/shepherd ListEKSAvailableVersions
-func ListEKSAvailableVersions(client *rancher.Client, cluster *management.Cluster) (availableVersions []string, err error) {
+func ListEKSAvailableVersions(ctx context.Context, client *rancher.Client, cluster *management.Cluster) (availableVersions []string, err error) {
 	currentVersion, err := semver.NewVersion(cluster.Version.GitVersion)
 	if err != nil {
 		return
 	}
 	var validMasterVersions []*semver.Version
-	allAvailableVersions, err := ListEKSAllVersions(client)
+	allAvailableVersions, err := ListEKSAllVersions(ctx, client)
 	if err != nil {
 		return
 	}
@@ -487,45 +614,32 @@ func ListEKSAvailableVersions(client *rancher.Client, cluster *management.Cluste
 	}
 
 	sort.SliceStable(availableVersions, func(i, j int) bool { return i > j })
-	return helpers.FilterUIUnsupportedVersions(availableVersions, client), nil
+	return helpers.FilterUIUnsupportedVersions(availableVersions, client)
 }
 
 // ListEKSAllVersions lists all the versions supported by UI;
-// this is a separate static list maintained by hosted-providers-e2e, similar to the  UI lists:
-// https://raw.githubusercontent.com/rancher/dashboard/refs/heads/master/pkg/eks/assets/data/eks-versions.js and
-// https://raw.githubusercontent.com/rancher/ui/master/lib/shared/addon/utils/amazon.js
-// the static list only contains officially supported EKS versions, i.e. 1.24 to 1.32;
-// refer: https://docs.aws.amazon.com/eks/latest/userguide/kubernetes-versions.html.
-func ListEKSAllVersions(client *rancher.Client) (allVersions []string, err error) {
-	serverVersion, err := helpers.GetRancherServerVersion(client)
+// this is a separate static list maintained by hosted-providers-e2e in the version catalogue (hosted/helpers/assets/k8s-versions.yaml),
+// similar to the UI lists; see LoadVersionCatalogue.
+func ListEKSAllVersions(ctx context.Context, client *rancher.Client) (allVersions []string, err error) {
+	allVersions, err = helpers.ListCatalogueVersions(ctx, client, "eks")
 	if err != nil {
 		return
 	}
 
-	allVersions = []string{"1.32", "1.31", "1.30"}
-
-	if strings.Contains(serverVersion, "2.10") {
-		allVersions = []string{"1.31", "1.30", "1.29", "1.28"}
-	} else if strings.Contains(serverVersion, "2.9") {
-		allVersions = []string{"1.30", "1.29", "1.28", "1.27"}
-	} else if strings.Contains(serverVersion, "2.8") {
-		allVersions = []string{"1.28", "1.27", "1.26", "1.25"}
-	} else if strings.Contains(serverVersion, "2.7") {
-		allVersions = []string{"1.27", "1.26", "1.25", "1.24"}
-	}
-
 	// as a safety net, we ensure all the versions are UI supported
-	return helpers.FilterUIUnsupportedVersions(allVersions, client), nil
+	return helpers.FilterUIUnsupportedVersions(allVersions, client)
 }
 
 // <==============================EKS CLI==============================>
 
 // Create AWS EKS cluster using EKS CLI
-func CreateEKSClusterOnAWS(region string, clusterName string, k8sVersion string, nodes string, tags map[string]string, extraArgs ...string) error {
+func CreateEKSClusterOnAWS(ctx context.Context, region string, clusterName string, k8sVersion string, nodes string, tags map[string]string, extraArgs ...string) error {
 	currentKubeconfig := os.Getenv("KUBECONFIG")
 	defer os.Setenv("KUBECONFIG", currentKubeconfig)
 
-	helpers.SetTempKubeConfig(clusterName)
+	if err := helpers.SetTempKubeConfig(clusterName); err != nil {
+		return err
+	}
 
 	formattedTags := k8slabels.SelectorFromSet(tags).String()
 	fmt.Println("Creating EKS cluster ...")
@@ -533,23 +647,28 @@ func CreateEKSClusterOnAWS(region string, clusterName string, k8sVersion string,
 	if len(extraArgs) != 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return createEKSClusterWithSDK(ctx, region, clusterName, k8sVersion, nodes, tags, extraArgs)
+	}, "eksctl", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to create cluster: "+out)
 	}
 	fmt.Println("Created EKS cluster: ", clusterName)
+	helpers.RegisterClusterCleanup(helpers.CleanupKey("eks-cluster", clusterName), fmt.Sprintf("EKS cluster %s", clusterName), func(ctx context.Context) error {
+		return DeleteEKSClusterOnAWS(ctx, region, clusterName)
+	})
 
 	return nil
 }
 
 // Upgrade EKS cluster using EKS CLI
-func UpgradeEKSClusterOnAWS(region string, clusterName string, upgradeToVersion string) error {
+func UpgradeEKSClusterOnAWS(ctx context.Context, region string, clusterName string, upgradeToVersion string) error {
 
 	fmt.Println("Upgrading EKS cluster controlplane ...")
 	args := []string{"upgrade", "cluster", "--region=" + region, "--name=" + clusterName, "--version=" + upgradeToVersion, "--approve"}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return upgradeEKSClusterWithSDK(ctx, region, clusterName, upgradeToVersion)
+	}, "eksctl", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to upgrade cluster: "+out)
 	}
@@ -559,14 +678,15 @@ func UpgradeEKSClusterOnAWS(region string, clusterName string, upgradeToVersion
 }
 
 // AddNodeGroupOnAWS adds nodegroup ot a cluster using EKS CLI
-func AddNodeGroupOnAWS(nodeName, clusterName, region string, extraArgs ...string) error {
+func AddNodeGroupOnAWS(ctx context.Context, nodeName, clusterName, region string, extraArgs ...string) error {
 	fmt.Println("Adding nodegroup to EKS cluster ...")
 	args := []string{"create", "nodegroup", "--region=" + region, "--cluster", clusterName, "--name", nodeName}
 	if len(extraArgs) != 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return addNodeGroupWithSDK(ctx, nodeName, clusterName, region, extraArgs)
+	}, "eksctl", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to add nodegroup: "+out)
 	}
@@ -576,14 +696,15 @@ func AddNodeGroupOnAWS(nodeName, clusterName, region string, extraArgs ...string
 }
 
 // ScaleNodeGroupOnAWS scales nodegroup of a cluster using EKS CLI
-func ScaleNodeGroupOnAWS(ngName, clusterName, region string, numOfNodes, maxCount, minCount int64, extraArgs ...string) error {
+func ScaleNodeGroupOnAWS(ctx context.Context, ngName, clusterName, region string, numOfNodes, maxCount, minCount int64, extraArgs ...string) error {
 	fmt.Println("Scaling nodegroup of EKS cluster ...")
 	args := []string{"scale", "nodegroup", "--region", region, "--name", ngName, "--cluster", clusterName, "--nodes", fmt.Sprintf("%d", numOfNodes), "--nodes-max", fmt.Sprintf("%d", maxCount), "--nodes-min", fmt.Sprintf("%d", minCount), "--wait"}
 	if len(extraArgs) != 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return scaleNodeGroupWithSDK(ctx, ngName, clusterName, region, numOfNodes, maxCount, minCount, extraArgs)
+	}, "eksctl", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to scale nodegroup: "+out)
 	}
@@ -592,7 +713,7 @@ func ScaleNodeGroupOnAWS(ngName, clusterName, region string, numOfNodes, maxCoun
 }
 
 // UpdateNodeGroupLabelsOnAWS deletes or add/updates labels on nodegroup of a cluster;
-func UpdateNodeGroupLabelsOnAWS(clusterName, nodegroupName, region string, addOrUpdatelabels map[string]string, removeLabels []string, extraArgs ...string) error {
+func UpdateNodeGroupLabelsOnAWS(ctx context.Context, clusterName, nodegroupName, region string, addOrUpdatelabels map[string]string, removeLabels []string, extraArgs ...string) error {
 	if len(addOrUpdatelabels) == 0 && len(removeLabels) == 0 {
 		return fmt.Errorf("no labels provided to remove or update/add")
 	}
@@ -622,8 +743,9 @@ func UpdateNodeGroupLabelsOnAWS(clusterName, nodegroupName, region string, addOr
 		args = append(args, extraArgs...)
 	}
 
-	fmt.Printf("Running command: aws %v\n", args)
-	out, err := proc.RunW("aws", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return updateNodeGroupLabelsWithSDK(ctx, clusterName, nodegroupName, region, addOrUpdatelabels, removeLabels, extraArgs)
+	}, "aws", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to update labels to nodegroup: "+out)
 	}
@@ -632,33 +754,34 @@ func UpdateNodeGroupLabelsOnAWS(clusterName, nodegroupName, region string, addOr
 }
 
 // AddClusterTagsOnAWS adds label to cluster using AWS cli
-func AddClusterTagsOnAWS(clusterName, region string, tags map[string]string, extraArgs ...string) error {
-	arn, err := GetFromEKS(region, clusterName, "cluster", ".[].Arn")
+func AddClusterTagsOnAWS(ctx context.Context, clusterName, region string, tags map[string]string, extraArgs ...string) error {
+	arn, err := getEKSClusterArn(ctx, region, clusterName)
 	if err != nil {
 		return fmt.Errorf("failed to get ARN for cluster %s: %v", clusterName, err)
 	}
-	return UpdateResoureTagsOnAWS(arn, clusterName, region, tags, extraArgs...)
+	return UpdateResoureTagsOnAWS(ctx, arn, clusterName, region, tags, extraArgs...)
 }
 
 // RemoveClusterTagsOnAWS removes label from cluster using AWS cli
-func RemoveClusterTagsOnAWS(clusterName, region string, tags []string, extraArgs ...string) error {
-	arn, err := GetFromEKS(region, clusterName, "cluster", ".[].Arn")
+func RemoveClusterTagsOnAWS(ctx context.Context, clusterName, region string, tags []string, extraArgs ...string) error {
+	arn, err := getEKSClusterArn(ctx, region, clusterName)
 	if err != nil {
 		return fmt.Errorf("failed to get ARN for cluster %s: %v", clusterName, err)
 	}
-	return RemoveResourceTagsOnAWS(arn, clusterName, region, tags, extraArgs...)
+	return RemoveResourceTagsOnAWS(ctx, arn, clusterName, region, tags, extraArgs...)
 }
 
 // UpdateClusterTagOnAWS tags resource using AWS CLI
-func UpdateResoureTagsOnAWS(resourceArn, clusterName, region string, tags map[string]string, extraArgs ...string) error {
+func UpdateResoureTagsOnAWS(ctx context.Context, resourceArn, clusterName, region string, tags map[string]string, extraArgs ...string) error {
 	formattedTags := k8slabels.SelectorFromSet(tags).String()
 	fmt.Println("Updating tag on EKS cluster ...")
 	args := []string{"eks", "tag-resource", "--resource-arn", resourceArn, "--tags", formattedTags, "--region", region}
 	if len(extraArgs) != 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: aws %v\n", args)
-	out, err := proc.RunW("aws", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return updateResourceTagsWithSDK(ctx, resourceArn, region, tags, extraArgs)
+	}, "aws", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to update tag: "+out)
 	}
@@ -667,7 +790,7 @@ func UpdateResoureTagsOnAWS(resourceArn, clusterName, region string, tags map[st
 }
 
 // RemoveResourceTagsOnAWS untags the resources using AWS CLI
-func RemoveResourceTagsOnAWS(resourceArn, clusterName, region string, tags []string, extraArgs ...string) error {
+func RemoveResourceTagsOnAWS(ctx context.Context, resourceArn, clusterName, region string, tags []string, extraArgs ...string) error {
 	fmt.Println("Removing tag on EKS cluster ...")
 	args := []string{"eks", "untag-resource", "--resource-arn", resourceArn, "--region", region}
 	args = append(args, "--tag-keys")
@@ -677,8 +800,9 @@ func RemoveResourceTagsOnAWS(resourceArn, clusterName, region string, tags []str
 	if len(extraArgs) != 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: aws %v\n", args)
-	out, err := proc.RunW("aws", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return removeResourceTagsWithSDK(ctx, resourceArn, region, tags, extraArgs)
+	}, "aws", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to remove tag: "+out)
 	}
@@ -688,7 +812,7 @@ func RemoveResourceTagsOnAWS(resourceArn, clusterName, region string, tags []str
 
 // UpdateLoggingOnAWS enabled and disabled the logging of a cluster
 // types: all, api, audit, authenticator, controllerManager, scheduler
-func UpdateLoggingOnAWS(clusterName, region string, enableLoggingTypes, disableLoggingTypes []string, extraArgs ...string) error {
+func UpdateLoggingOnAWS(ctx context.Context, clusterName, region string, enableLoggingTypes, disableLoggingTypes []string, extraArgs ...string) error {
 	fmt.Println("Updating Logging of EKS cluster ...")
 	args := []string{"utils", "update-cluster-logging", "--region", region, "--cluster", clusterName, "--approve"}
 	if len(enableLoggingTypes) != 0 {
@@ -702,8 +826,9 @@ func UpdateLoggingOnAWS(clusterName, region string, enableLoggingTypes, disableL
 	if len(extraArgs) != 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return updateLoggingWithSDK(ctx, clusterName, region, enableLoggingTypes, disableLoggingTypes, extraArgs)
+	}, "eksctl", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to update logging: "+out)
 	}
@@ -711,7 +836,7 @@ func UpdateLoggingOnAWS(clusterName, region string, enableLoggingTypes, disableL
 	return nil
 }
 
-func UpdateVPCAccess(clusterName, region string, enablePublic, enablePrivate bool, publicAccessCIDR []string, extraArgs ...string) error {
+func UpdateVPCAccess(ctx context.Context, clusterName, region string, enablePublic, enablePrivate bool, publicAccessCIDR []string, extraArgs ...string) error {
 	fmt.Println("Updating VPC access of control plane ...")
 	args := []string{"utils", "update-cluster-vpc-config", "--region", region, "--cluster", clusterName, "--approve"}
 	if enablePublic {
@@ -726,8 +851,9 @@ func UpdateVPCAccess(clusterName, region string, enablePublic, enablePrivate boo
 	if len(extraArgs) != 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return updateVPCAccessWithSDK(ctx, clusterName, region, enablePublic, enablePrivate, publicAccessCIDR, extraArgs)
+	}, "eksctl", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to update VPC access: "+out)
 	}
@@ -736,11 +862,12 @@ func UpdateVPCAccess(clusterName, region string, enablePublic, enablePrivate boo
 }
 
 // Upgrade EKS cluster nodegroup using EKS CLI
-func UpgradeEKSNodegroupOnAWS(region string, clusterName string, ngName string, upgradeToVersion string) error {
+func UpgradeEKSNodegroupOnAWS(ctx context.Context, region string, clusterName string, ngName string, upgradeToVersion string) error {
 	fmt.Println("Upgrading EKS cluster nodegroup ...")
 	args := []string{"upgrade", "nodegroup", "--region=" + region, "--name=" + ngName, "--cluster=" + clusterName, "--kubernetes-version=" + upgradeToVersion}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return upgradeEKSNodegroupWithSDK(ctx, region, clusterName, ngName, upgradeToVersion)
+	}, "eksctl", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to upgrade nodegroup: "+out)
 	}
@@ -749,7 +876,13 @@ func UpgradeEKSNodegroupOnAWS(region string, clusterName string, ngName string,
 	return nil
 }
 
-func GetFromEKS(region string, clusterName string, cmd string, query string, extraArgs ...string) (out string, err error) {
+// GetFromEKS runs the jq query on the output of `eksctl get cluster|nodegroup -ojson`; it is not supported by the sdk cloud backend,
+// use DescribeEKSClusterOnAWS, GetEKSNodegroupOnAWS or Provider.ClusterSpecOnCloud instead
+func GetFromEKS(ctx context.Context, region string, clusterName string, cmd string, query string, extraArgs ...string) (out string, err error) {
+	if helpers.UseCloudSDK() {
+		return "", fmt.Errorf("GetFromEKS is not supported by the %s cloud backend", helpers.SDKBackend)
+	}
+
 	clusterArgs := []string{"eksctl", "get", "cluster", "--region=" + region, "--name=" + clusterName, "-ojson"}
 	ngArgs := []string{"eksctl", "get", "nodegroup", "--region=" + region, "--cluster=" + clusterName, "-ojson"}
 	queryArgs := []string{"|", "jq", "-r", query}
@@ -771,19 +904,111 @@ func GetFromEKS(region string, clusterName string, cmd string, query string, ext
 	}
 
 	fmt.Printf("Running command: %s\n", cmd)
-	out, err = proc.RunW("bash", "-c", cmd)
+	out, err = helpers.RunCLI(ctx, "bash", "-c", cmd)
 	return strings.TrimSpace(out), err
 }
 
+// getEKSClusterArn returns the ARN of the cluster using eksctl or the AWS SDK, depending on the cloud backend
+func getEKSClusterArn(ctx context.Context, region, clusterName string) (string, error) {
+	if !helpers.UseCloudSDK() {
+		return GetFromEKS(ctx, region, clusterName, "cluster", ".[].Arn")
+	}
+	cluster, err := DescribeEKSClusterOnAWS(ctx, region, clusterName)
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(cluster.Arn), nil
+}
+
+// GetEKSNodegroupOnAWS returns the state of a nodegroup as reported by the AWS API using AWS CLI or the AWS SDK, depending on the cloud backend
+func GetEKSNodegroupOnAWS(ctx context.Context, region, clusterName, nodegroupName string) (EKSNodegroup, error) {
+	var nodegroup EKSNodegroup
+	args := []string{"eks", "describe-nodegroup", "--cluster-name", clusterName, "--nodegroup-name", nodegroupName, "--region", region, "--output", "json"}
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		client, err := newEKSClient(region)
+		if err != nil {
+			return err
+		}
+		ng, err := describeEKSNodegroup(ctx, client, clusterName, nodegroupName)
+		if err != nil {
+			return err
+		}
+		nodegroup = awsNodegroup(ng)
+		return nil
+	}, "aws", args...)
+	if err != nil {
+		return EKSNodegroup{}, errors.Wrap(err, "Failed to describe nodegroup: "+out)
+	}
+	if helpers.UseCloudSDK() {
+		return nodegroup, nil
+	}
+
+	var described struct {
+		Nodegroup struct {
+			NodegroupName  string   `json:"nodegroupName"`
+			Status         string   `json:"status"`
+			Version        string   `json:"version"`
+			AmiType        string   `json:"amiType"`
+			ReleaseVersion string   `json:"releaseVersion"`
+			InstanceTypes  []string `json:"instanceTypes"`
+			LaunchTemplate struct {
+				Name string `json:"name"`
+			} `json:"launchTemplate"`
+			ScalingConfig struct {
+				DesiredSize int64 `json:"desiredSize"`
+				MinSize     int64 `json:"minSize"`
+				MaxSize     int64 `json:"maxSize"`
+			} `json:"scalingConfig"`
+		} `json:"nodegroup"`
+	}
+	if err = json.Unmarshal([]byte(out), &described); err != nil {
+		return EKSNodegroup{}, errors.Wrap(err, "Failed to parse nodegroup "+nodegroupName)
+	}
+	ng := described.Nodegroup
+	return EKSNodegroup{
+		Name:           ng.NodegroupName,
+		Status:         ng.Status,
+		Version:        ng.Version,
+		AMIType:        ng.AmiType,
+		ReleaseVersion: ng.ReleaseVersion,
+		LaunchTemplate: ng.LaunchTemplate.Name,
+		InstanceTypes:  ng.InstanceTypes,
+		DesiredSize:    ng.ScalingConfig.DesiredSize,
+		MinSize:        ng.ScalingConfig.MinSize,
+		MaxSize:        ng.ScalingConfig.MaxSize,
+	}, nil
+}
+
+// listEKSNodegroupNames returns the names of the nodegroups of the cluster using eksctl or the AWS SDK, depending on the cloud backend
+func listEKSNodegroupNames(ctx context.Context, region, clusterName string) ([]string, error) {
+	if !helpers.UseCloudSDK() {
+		out, err := GetFromEKS(ctx, region, clusterName, "nodegroup", ".[].Name")
+		if err != nil || out == "" {
+			return nil, err
+		}
+		return strings.Split(out, "\n"), nil
+	}
+	nodegroups, err := ListEKSNodegroupsOnAWS(ctx, region, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, ng := range nodegroups {
+		names = append(names, aws.StringValue(ng.NodegroupName))
+	}
+	return names, nil
+}
+
 // Creates/Deletes EKS cluster nodegroup using EKS CLI
-func ModifyEKSNodegroupOnAWS(region string, clusterName string, ngName string, operation string, extraArgs ...string) error {
+func ModifyEKSNodegroupOnAWS(ctx context.Context, region string, clusterName string, ngName string, operation string, extraArgs ...string) error {
 	args := []string{operation, "nodegroup", "--region=" + region, "--name=" + ngName, "--cluster=" + clusterName}
 	if operation == "delete" {
 		args = append(args, "--disable-eviction")
 	}
 	args = append(args, extraArgs...)
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return modifyEKSNodegroupWithSDK(ctx, region, clusterName, ngName, operation, extraArgs)
+	}, "eksctl", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to modify nodegroup: "+out)
 	}
@@ -791,7 +1016,7 @@ func ModifyEKSNodegroupOnAWS(region string, clusterName string, ngName string, o
 }
 
 // Complete cleanup steps for Amazon EKS
-func DeleteEKSClusterOnAWS(region string, clusterName string) error {
+func DeleteEKSClusterOnAWS(ctx context.Context, region string, clusterName string) error {
 	currentKubeconfig := os.Getenv("KUBECONFIG")
 	downstreamKubeconfig := os.Getenv(helpers.DownstreamKubeconfig(clusterName))
 	defer func() {
@@ -801,44 +1026,93 @@ func DeleteEKSClusterOnAWS(region string, clusterName string) error {
 	_ = os.Setenv("KUBECONFIG", downstreamKubeconfig)
 
 	fmt.Println("Deleting all nodegroups ...")
-	ngNames, err := GetFromEKS(region, clusterName, "nodegroup", ".[].Name")
+	ngNames, err := listEKSNodegroupNames(ctx, region, clusterName)
 	if err != nil {
 		return errors.Wrap(err, "Failed to list nodegroup for deletion")
 	}
 
-	if ngNames != "" {
-		for _, ngName := range strings.Split(ngNames, "\n") {
-			err = ModifyEKSNodegroupOnAWS(region, clusterName, ngName, "delete", "--wait")
-			if err != nil {
-				return errors.Wrap(err, "Failed to delete nodegroup")
-			}
+	for _, ngName := range ngNames {
+		err = ModifyEKSNodegroupOnAWS(ctx, region, clusterName, ngName, "delete", "--wait")
+		if err != nil {
+			return errors.Wrap(err, "Failed to delete nodegroup")
 		}
 	}
 
 	fmt.Println("Deleting EKS cluster ...")
 
 	args := []string{"delete", "cluster", "--region=" + region, "--name=" + clusterName}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error { return deleteEKSClusterWithSDK(ctx, region, clusterName) }, "eksctl", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to delete cluster: "+out)
 	}
 
 	fmt.Println("Deleted EKS cluster: ", clusterName)
+	helpers.ForgetCleanup(helpers.CleanupKey("eks-cluster", clusterName))
 
 	return nil
 }
 
+// ListEKSClustersOnAWS lists the EKS clusters of the region using AWS CLI
+func ListEKSClustersOnAWS(ctx context.Context, region string) ([]helpers.CloudCluster, error) {
+	fmt.Println("Listing EKS clusters ...")
+	if helpers.UseCloudSDK() {
+		clusters, err := listEKSClustersWithSDK(ctx, region)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to list clusters")
+		}
+		return clusters, nil
+	}
+	args := []string{"eks", "list-clusters", "--region", region, "--output", "json"}
+	fmt.Printf("Running command: aws %v\n", args)
+	out, err := helpers.RunCLI(ctx, "aws", args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list clusters: "+out)
+	}
+	var listed struct {
+		Clusters []string `json:"clusters"`
+	}
+	if err = json.Unmarshal([]byte(out), &listed); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse the list of clusters")
+	}
+
+	// the tags and creation time are only returned by describe-cluster
+	var clusters []helpers.CloudCluster
+	for _, clusterName := range listed.Clusters {
+		args = []string{"eks", "describe-cluster", "--name", clusterName, "--region", region, "--output", "json"}
+		fmt.Printf("Running command: aws %v\n", args)
+		out, err = helpers.RunCLI(ctx, "aws", args...)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to describe cluster: "+out)
+		}
+		var described struct {
+			Cluster struct {
+				Tags      map[string]string `json:"tags"`
+				CreatedAt time.Time         `json:"createdAt"`
+			} `json:"cluster"`
+		}
+		if err = json.Unmarshal([]byte(out), &described); err != nil {
+			return nil, errors.Wrap(err, "Failed to parse cluster "+clusterName)
+		}
+		clusters = append(clusters, helpers.CloudCluster{
+			Name:      clusterName,
+			Location:  region,
+			Labels:    described.Cluster.Tags,
+			CreatedAt: described.Cluster.CreatedAt,
+		})
+	}
+	return clusters, nil
+}
+
 // <==============================EKS CLI(end)==============================>
 
 // GetK8sVersion returns the k8s version to be used by the test;
 // this value can either be a variant of envvar DOWNSTREAM_K8S_MINOR_VERSION or the highest available version
 // or second-highest minor version in case of upgrade scenarios
-func GetK8sVersion(client *rancher.Client, forUpgrade bool) (string, error) {
+func GetK8sVersion(ctx context.Context, client *rancher.Client, forUpgrade bool) (string, error) {
 	if k8sVersion := helpers.DownstreamK8sMinorVersion; k8sVersion != "" {
 		return k8sVersion, nil
 	}
-	allVariants, err := ListEKSAllVersions(client)
+	allVariants, err := ListEKSAllVersions(ctx, client)
 	if err != nil {
 		return "", err
 	}
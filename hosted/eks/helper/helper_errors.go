@@ -0,0 +1,24 @@
+package helper
+
+import (
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// The known errors of the eks-operator and of EKS, as set in cluster.TransitioningMessage; see suite.HaveTransitionError.
+// The messages changed by the later operator releases are only expected from the releases that had them.
+var (
+	ErrNoNodeGroup = helpers.RegisterOperatorError("eks", "no nodegroup",
+		helpers.ErrorMessage{Pattern: `Cluster must have at least one managed nodegroup or one self-managed node`})
+	ErrDuplicateNodeGroupName = helpers.RegisterOperatorError("eks", "duplicate nodegroup name",
+		helpers.ErrorMessage{Operator: "<=1.10", Pattern: `node group name \[\S+\] is not unique within the cluster`},
+		helpers.ErrorMessage{Pattern: `(?i)nodePool names must be unique within the \[.*\] cluster`})
+	ErrDuplicateClusterConfig = helpers.RegisterOperatorError("eks", "duplicate eksclusterconfig",
+		helpers.ErrorMessage{Pattern: `an eksclusterconfig exists with the same name`})
+	ErrNodeGroupVersionMismatch = helpers.RegisterOperatorError("eks", "nodegroup version mismatch on create",
+		helpers.ErrorMessage{Pattern: `nodegroup \[\S+\] version must match cluster`})
+	ErrNodeGroupVersionIncompatible = helpers.RegisterOperatorError("eks", "nodegroup version incompatible",
+		helpers.ErrorMessage{Operator: "<=1.10", Pattern: `versions for cluster \[\S+\] and node group \[\S+\] are not compatible`},
+		helpers.ErrorMessage{Pattern: `versions for cluster \[\S+\] and nodegroup \[\S+\] not compatible`})
+	ErrInvalidPublicAccessCIDRs = helpers.RegisterOperatorError("eks", "invalid public access CIDRs",
+		helpers.ErrorMessage{Pattern: `The following CIDRs are invalid in publicAccessCidrs`})
+)
@@ -0,0 +1,171 @@
+package helper
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+func init() {
+	helpers.RegisterHostedProvider(Provider{})
+}
+
+// Provider implements helpers.HostedProvider for EKS; the region is obtained via helpers.GetEKSRegion
+type Provider struct{}
+
+func (Provider) Name() string {
+	return "eks"
+}
+
+func (Provider) GetK8sVersion(ctx context.Context, client *rancher.Client, _ string, forUpgrade bool) (string, error) {
+	return GetK8sVersion(ctx, client, forUpgrade)
+}
+
+func (Provider) ListAvailableVersions(ctx context.Context, client *rancher.Client, cluster *management.Cluster) ([]string, error) {
+	// ListEKSAvailableVersions expects cluster.Version.GitVersion to be available, so we fetch the cluster again to ensure it has all the available data
+	cluster, err := client.Management.Cluster.ByID(cluster.ID)
+	if err != nil {
+		return nil, err
+	}
+	return ListEKSAvailableVersions(ctx, client, cluster)
+}
+
+func (Provider) CreateHostedCluster(ctx context.Context, client *rancher.Client, clusterName, cloudCredentialID, k8sVersion string) (*management.Cluster, error) {
+	return CreateEKSHostedCluster(ctx, client, clusterName, cloudCredentialID, k8sVersion, helpers.GetEKSRegion(), nil)
+}
+
+func (Provider) ImportHostedCluster(ctx context.Context, client *rancher.Client, clusterName, cloudCredentialID string) (*management.Cluster, error) {
+	return ImportEKSHostedCluster(ctx, client, clusterName, cloudCredentialID, helpers.GetEKSRegion())
+}
+
+func (Provider) DeleteHostedCluster(ctx context.Context, cluster *management.Cluster, client *rancher.Client) error {
+	return DeleteEKSHostCluster(ctx, cluster, client)
+}
+
+func (Provider) NodePoolCount(cluster *management.Cluster) int {
+	return len(*cluster.EKSConfig.NodeGroups)
+}
+
+func (Provider) NodeCount(cluster *management.Cluster) int64 {
+	return *(*cluster.EKSConfig.NodeGroups)[0].DesiredSize
+}
+
+func (Provider) ScaleNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, nodeCount int64, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	return ScaleNodeGroup(ctx, cluster, client, nodeCount, wait, checkClusterConfig)
+}
+
+func (Provider) AddNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, increaseBy int, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	return AddNodeGroup(ctx, cluster, increaseBy, client, wait, checkClusterConfig)
+}
+
+func (Provider) DeleteNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	return DeleteNodeGroup(ctx, cluster, client, wait, checkClusterConfig)
+}
+
+func (Provider) UpgradeControlPlane(ctx context.Context, cluster *management.Cluster, client *rancher.Client, upgradeToVersion string, checkClusterConfig bool) (*management.Cluster, error) {
+	return UpgradeClusterKubernetesVersion(ctx, cluster, upgradeToVersion, client, checkClusterConfig)
+}
+
+// UpgradeNodePools uses eksctl to upgrade the nodegroups of an imported cluster since they are created with a custom launch template
+func (Provider) UpgradeNodePools(ctx context.Context, cluster *management.Cluster, client *rancher.Client, upgradeToVersion string, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	return UpgradeNodeKubernetesVersion(ctx, cluster, upgradeToVersion, client, wait, checkClusterConfig, helpers.IsImport)
+}
+
+func (Provider) CreateClusterOnCloud(ctx context.Context, clusterName, k8sVersion string, nodeCount int64) error {
+	return CreateEKSClusterOnAWS(ctx, helpers.GetEKSRegion(), clusterName, k8sVersion, strconv.FormatInt(nodeCount, 10), helpers.GetCommonMetadataLabels())
+}
+
+func (Provider) DeleteClusterOnCloud(ctx context.Context, clusterName string) error {
+	return DeleteEKSClusterOnAWS(ctx, helpers.GetEKSRegion(), clusterName)
+}
+
+func (Provider) ListClustersOnCloud(ctx context.Context) ([]helpers.CloudCluster, error) {
+	return ListEKSClustersOnAWS(ctx, helpers.GetEKSRegion())
+}
+
+func (Provider) DeleteListedClusterOnCloud(ctx context.Context, cluster helpers.CloudCluster) error {
+	return DeleteEKSClusterOnAWS(ctx, cluster.Location, cluster.Name)
+}
+
+func (Provider) UpdateClusterTags(ctx context.Context, cluster *management.Cluster, client *rancher.Client, tags map[string]string) (*management.Cluster, error) {
+	return UpdateCluster(ctx, cluster, client, func(upgradedCluster *management.Cluster) {
+		upgradedCluster.EKSConfig.Tags = &tags
+	})
+}
+
+func (Provider) ConfigSpec(cluster *management.Cluster) helpers.ClusterSpec {
+	return clusterSpec(cluster.EKSConfig)
+}
+
+func (Provider) UpstreamSpec(cluster *management.Cluster) helpers.ClusterSpec {
+	if cluster.EKSStatus == nil {
+		return helpers.ClusterSpec{}
+	}
+	return clusterSpec(cluster.EKSStatus.UpstreamSpec)
+}
+
+// ScaleNodePoolOnCloud widens the min and max size of the nodegroup if the node count falls outside of them
+func (Provider) ScaleNodePoolOnCloud(ctx context.Context, cluster *management.Cluster, nodeCount int64) error {
+	ng := (*cluster.EKSConfig.NodeGroups)[0]
+	maxSize := max(nodeCount, pointer.Int64Deref(ng.MaxSize, nodeCount))
+	minSize := min(nodeCount, pointer.Int64Deref(ng.MinSize, nodeCount))
+	return ScaleNodeGroupOnAWS(ctx, *ng.NodegroupName, cluster.EKSConfig.DisplayName, cluster.EKSConfig.Region, nodeCount, maxSize, minSize)
+}
+
+func (Provider) AddNodePoolOnCloud(ctx context.Context, cluster *management.Cluster, poolName string) error {
+	return AddNodeGroupOnAWS(ctx, poolName, cluster.EKSConfig.DisplayName, cluster.EKSConfig.Region)
+}
+
+func (Provider) UpgradeControlPlaneOnCloud(ctx context.Context, cluster *management.Cluster, upgradeToVersion string) error {
+	return UpgradeEKSClusterOnAWS(ctx, cluster.EKSConfig.Region, cluster.EKSConfig.DisplayName, upgradeToVersion)
+}
+
+func (Provider) ClusterSpecOnCloud(ctx context.Context, cluster *management.Cluster) (helpers.ClusterSpec, error) {
+	eksCluster, err := DescribeEKSClusterOnAWS(ctx, cluster.EKSConfig.Region, cluster.EKSConfig.DisplayName)
+	if err != nil {
+		return helpers.ClusterSpec{}, err
+	}
+	nodegroups, err := ListEKSNodegroupsOnAWS(ctx, cluster.EKSConfig.Region, cluster.EKSConfig.DisplayName)
+	if err != nil {
+		return helpers.ClusterSpec{}, err
+	}
+	return awsClusterSpec(eksCluster, nodegroups), nil
+}
+
+// clusterSpec converts an EKS cluster config or upstream spec to a helpers.ClusterSpec
+func clusterSpec(config *management.EKSClusterConfigSpec) helpers.ClusterSpec {
+	var spec helpers.ClusterSpec
+	if config == nil {
+		return spec
+	}
+	spec.KubernetesVersion = pointer.StringDeref(config.KubernetesVersion, "")
+	if config.Tags != nil {
+		spec.Tags = *config.Tags
+	}
+	if config.LoggingTypes != nil {
+		spec.Logging = *config.LoggingTypes
+	}
+	spec.PrivateAccess = config.PrivateAccess
+	spec.PublicAccess = config.PublicAccess
+	if config.NodeGroups != nil {
+		for _, ng := range *config.NodeGroups {
+			nodePool := helpers.NodePoolSpec{
+				Name:              pointer.StringDeref(ng.NodegroupName, ""),
+				NodeCount:         pointer.Int64Deref(ng.DesiredSize, 0),
+				KubernetesVersion: pointer.StringDeref(ng.Version, ""),
+				MinCount:          ng.MinSize,
+				MaxCount:          ng.MaxSize,
+			}
+			if ng.Labels != nil {
+				nodePool.Labels = *ng.Labels
+			}
+			spec.NodePools = append(spec.NodePools, nodePool)
+		}
+	}
+	return spec
+}
@@ -0,0 +1,484 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// <==============================EKS SDK==============================>
+// The functions below implement the eksctl and aws CLI helpers when helpers.UseCloudSDK() is true;
+// they are called by the *OnAWS functions and should not be used directly.
+//
+// Unlike eksctl, the AWS API does not create the VPC and IAM roles of a cluster; they must be provided via
+// EKS_CLUSTER_ROLE_ARN, EKS_NODE_ROLE_ARN and EKS_SUBNET_IDS (comma separated) to create a cluster.
+
+var (
+	// awsConfig returns the configuration of the AWS SDK clients; unit tests use it to point the clients to a fake server
+	awsConfig = func(region string) *aws.Config {
+		return aws.NewConfig().WithRegion(region)
+	}
+	// awsWaitDelay is the interval between two checks of a cluster, nodegroup or update status
+	awsWaitDelay = 30 * time.Second
+	// eksDefaultNodes and eksDefaultInstanceType are the values used by `eksctl create nodegroup` when none is provided
+	eksDefaultNodes        int64 = 2
+	eksDefaultInstanceType       = "m5.large"
+	// eksAllLogTypes is the list of log types `eksctl utils update-cluster-logging` uses for "all"
+	eksAllLogTypes = []string{eks.LogTypeApi, eks.LogTypeAudit, eks.LogTypeAuthenticator, eks.LogTypeControllerManager, eks.LogTypeScheduler}
+)
+
+func newEKSClient(region string) (*eks.EKS, error) {
+	sess, err := session.NewSession(awsConfig(region))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create AWS session")
+	}
+	return eks.New(sess), nil
+}
+
+func awsWaiterOptions() []request.WaiterOption {
+	return []request.WaiterOption{
+		request.WithWaiterDelay(request.ConstantWaiterDelay(awsWaitDelay)),
+		request.WithWaiterMaxAttempts(int(helpers.Timeout / awsWaitDelay)),
+	}
+}
+
+// waitForEKSUpdate waits until the update of a cluster, or of a nodegroup if nodegroupName is not empty, is complete
+func waitForEKSUpdate(ctx context.Context, client *eks.EKS, clusterName, nodegroupName string, update *eks.Update) error {
+	input := &eks.DescribeUpdateInput{Name: aws.String(clusterName), UpdateId: update.Id}
+	if nodegroupName != "" {
+		input.NodegroupName = aws.String(nodegroupName)
+	}
+	for deadline := time.Now().Add(helpers.Timeout); time.Now().Before(deadline); {
+		output, err := client.DescribeUpdateWithContext(ctx, input)
+		if err != nil {
+			return err
+		}
+		switch aws.StringValue(output.Update.Status) {
+		case eks.UpdateStatusSuccessful:
+			return nil
+		case eks.UpdateStatusFailed, eks.UpdateStatusCancelled:
+			var messages []string
+			for _, updateError := range output.Update.Errors {
+				messages = append(messages, aws.StringValue(updateError.ErrorMessage))
+			}
+			return fmt.Errorf("update %s is %s: %s", aws.StringValue(update.Id), aws.StringValue(output.Update.Status), strings.Join(messages, "; "))
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for update %s: %w", aws.StringValue(update.Id), context.Cause(ctx))
+		case <-time.After(awsWaitDelay):
+		}
+	}
+	return fmt.Errorf("timed out waiting for update %s", aws.StringValue(update.Id))
+}
+
+// DescribeEKSClusterOnAWS returns the EKS cluster as reported by the AWS API; it always uses the AWS SDK
+func DescribeEKSClusterOnAWS(ctx context.Context, region, clusterName string) (*eks.Cluster, error) {
+	client, err := newEKSClient(region)
+	if err != nil {
+		return nil, err
+	}
+	output, err := client.DescribeClusterWithContext(ctx, &eks.DescribeClusterInput{Name: aws.String(clusterName)})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to describe cluster")
+	}
+	return output.Cluster, nil
+}
+
+// ListEKSNodegroupsOnAWS returns the nodegroups of an EKS cluster as reported by the AWS API; it always uses the AWS SDK
+func ListEKSNodegroupsOnAWS(ctx context.Context, region, clusterName string) ([]*eks.Nodegroup, error) {
+	client, err := newEKSClient(region)
+	if err != nil {
+		return nil, err
+	}
+	var names []*string
+	err = client.ListNodegroupsPagesWithContext(ctx, &eks.ListNodegroupsInput{ClusterName: aws.String(clusterName)}, func(page *eks.ListNodegroupsOutput, _ bool) bool {
+		names = append(names, page.Nodegroups...)
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list nodegroups")
+	}
+	var nodegroups []*eks.Nodegroup
+	for _, name := range names {
+		nodegroup, err := describeEKSNodegroup(ctx, client, clusterName, aws.StringValue(name))
+		if err != nil {
+			return nil, err
+		}
+		nodegroups = append(nodegroups, nodegroup)
+	}
+	return nodegroups, nil
+}
+
+// EKSNodegroup is the state of an EKS nodegroup as reported by the AWS API
+type EKSNodegroup struct {
+	Name    string
+	Status  string
+	Version string
+	// AMIType is the type of the EKS optimized AMI used by the nodes, e.g. AL2_x86_64_GPU; it is CUSTOM when the launch template of the nodegroup sets the AMI
+	AMIType string
+	// ReleaseVersion is the version of the EKS optimized AMI, e.g. 1.31.0-20241024; it is empty when the AMI is a custom one
+	ReleaseVersion string
+	// LaunchTemplate is the name of the launch template of the nodegroup, if any
+	LaunchTemplate string
+	InstanceTypes  []string
+	DesiredSize    int64
+	MinSize        int64
+	MaxSize        int64
+}
+
+func describeEKSNodegroup(ctx context.Context, client *eks.EKS, clusterName, nodegroupName string) (*eks.Nodegroup, error) {
+	output, err := client.DescribeNodegroupWithContext(ctx, &eks.DescribeNodegroupInput{ClusterName: aws.String(clusterName), NodegroupName: aws.String(nodegroupName)})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to describe nodegroup")
+	}
+	return output.Nodegroup, nil
+}
+
+// awsNodegroup converts an EKS nodegroup as reported by the AWS API to an EKSNodegroup
+func awsNodegroup(ng *eks.Nodegroup) EKSNodegroup {
+	nodegroup := EKSNodegroup{
+		Name:           aws.StringValue(ng.NodegroupName),
+		Status:         aws.StringValue(ng.Status),
+		Version:        aws.StringValue(ng.Version),
+		AMIType:        aws.StringValue(ng.AmiType),
+		ReleaseVersion: aws.StringValue(ng.ReleaseVersion),
+		InstanceTypes:  aws.StringValueSlice(ng.InstanceTypes),
+	}
+	if ng.LaunchTemplate != nil {
+		nodegroup.LaunchTemplate = aws.StringValue(ng.LaunchTemplate.Name)
+	}
+	if ng.ScalingConfig != nil {
+		nodegroup.DesiredSize = aws.Int64Value(ng.ScalingConfig.DesiredSize)
+		nodegroup.MinSize = aws.Int64Value(ng.ScalingConfig.MinSize)
+		nodegroup.MaxSize = aws.Int64Value(ng.ScalingConfig.MaxSize)
+	}
+	return nodegroup
+}
+
+// awsClusterSpec converts an EKS cluster and its nodegroups as reported by the AWS API to a helpers.ClusterSpec
+func awsClusterSpec(cluster *eks.Cluster, nodegroups []*eks.Nodegroup) helpers.ClusterSpec {
+	spec := helpers.ClusterSpec{
+		KubernetesVersion: aws.StringValue(cluster.Version),
+		Tags:              aws.StringValueMap(cluster.Tags),
+		Logging:           []string{},
+	}
+	if cluster.Logging != nil {
+		for _, setup := range cluster.Logging.ClusterLogging {
+			if aws.BoolValue(setup.Enabled) {
+				spec.Logging = append(spec.Logging, aws.StringValueSlice(setup.Types)...)
+			}
+		}
+	}
+	if cluster.ResourcesVpcConfig != nil {
+		spec.PrivateAccess = cluster.ResourcesVpcConfig.EndpointPrivateAccess
+		spec.PublicAccess = cluster.ResourcesVpcConfig.EndpointPublicAccess
+	}
+	for _, ng := range nodegroups {
+		nodePool := helpers.NodePoolSpec{
+			Name:              aws.StringValue(ng.NodegroupName),
+			KubernetesVersion: aws.StringValue(ng.Version),
+			Labels:            aws.StringValueMap(ng.Labels),
+		}
+		if ng.ScalingConfig != nil {
+			nodePool.NodeCount = aws.Int64Value(ng.ScalingConfig.DesiredSize)
+			nodePool.MinCount = ng.ScalingConfig.MinSize
+			nodePool.MaxCount = ng.ScalingConfig.MaxSize
+		}
+		spec.NodePools = append(spec.NodePools, nodePool)
+	}
+	return spec
+}
+
+func createEKSClusterWithSDK(ctx context.Context, region, clusterName, k8sVersion, nodes string, tags map[string]string, extraArgs []string) error {
+	if _, err := helpers.ParseSDKArgs(extraArgs, nil, nil); err != nil {
+		return err
+	}
+	nodeCount, err := strconv.ParseInt(nodes, 10, 64)
+	if err != nil {
+		return err
+	}
+	clusterRoleARN, nodeRoleARN, subnetIDs := helpers.Config.EKS.ClusterRoleARN, helpers.Config.EKS.NodeRoleARN, helpers.Config.EKS.SubnetIDs
+	if clusterRoleARN == "" || nodeRoleARN == "" || subnetIDs == "" {
+		return fmt.Errorf("EKS_CLUSTER_ROLE_ARN, EKS_NODE_ROLE_ARN and EKS_SUBNET_IDS must be set to create a cluster with the %s cloud backend", helpers.SDKBackend)
+	}
+	subnets := aws.StringSlice(strings.Split(subnetIDs, ","))
+	client, err := newEKSClient(region)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.CreateClusterWithContext(ctx, &eks.CreateClusterInput{
+		Name:               aws.String(clusterName),
+		Version:            aws.String(k8sVersion),
+		RoleArn:            aws.String(clusterRoleARN),
+		ResourcesVpcConfig: &eks.VpcConfigRequest{SubnetIds: subnets},
+		Tags:               aws.StringMap(tags),
+	})
+	if err != nil {
+		return err
+	}
+	if err = client.WaitUntilClusterActiveWithContext(ctx, &eks.DescribeClusterInput{Name: aws.String(clusterName)}, awsWaiterOptions()...); err != nil {
+		return err
+	}
+
+	_, err = client.CreateNodegroupWithContext(ctx, &eks.CreateNodegroupInput{
+		ClusterName:   aws.String(clusterName),
+		NodegroupName: aws.String("ranchernodes"),
+		NodeRole:      aws.String(nodeRoleARN),
+		Subnets:       subnets,
+		InstanceTypes: aws.StringSlice([]string{eksDefaultInstanceType}),
+		ScalingConfig: &eks.NodegroupScalingConfig{DesiredSize: aws.Int64(nodeCount), MinSize: aws.Int64(nodeCount), MaxSize: aws.Int64(nodeCount)},
+		Tags:          aws.StringMap(tags),
+	})
+	if err != nil {
+		return err
+	}
+	return client.WaitUntilNodegroupActiveWithContext(ctx, &eks.DescribeNodegroupInput{ClusterName: aws.String(clusterName), NodegroupName: aws.String("ranchernodes")}, awsWaiterOptions()...)
+}
+
+func upgradeEKSClusterWithSDK(ctx context.Context, region, clusterName, upgradeToVersion string) error {
+	client, err := newEKSClient(region)
+	if err != nil {
+		return err
+	}
+	output, err := client.UpdateClusterVersionWithContext(ctx, &eks.UpdateClusterVersionInput{Name: aws.String(clusterName), Version: aws.String(upgradeToVersion)})
+	if err != nil {
+		return err
+	}
+	return waitForEKSUpdate(ctx, client, clusterName, "", output.Update)
+}
+
+// addNodeGroupWithSDK uses the IAM role and subnets of an existing nodegroup since the AWS API does not create them
+func addNodeGroupWithSDK(ctx context.Context, nodeName, clusterName, region string, extraArgs []string) error {
+	if _, err := helpers.ParseSDKArgs(extraArgs, nil, nil); err != nil {
+		return err
+	}
+	nodegroups, err := ListEKSNodegroupsOnAWS(ctx, region, clusterName)
+	if err != nil {
+		return err
+	}
+	if len(nodegroups) == 0 {
+		return fmt.Errorf("cluster %s has no nodegroup to copy the IAM role and subnets from", clusterName)
+	}
+	client, err := newEKSClient(region)
+	if err != nil {
+		return err
+	}
+	_, err = client.CreateNodegroupWithContext(ctx, &eks.CreateNodegroupInput{
+		ClusterName:   aws.String(clusterName),
+		NodegroupName: aws.String(nodeName),
+		NodeRole:      nodegroups[0].NodeRole,
+		Subnets:       nodegroups[0].Subnets,
+		InstanceTypes: aws.StringSlice([]string{eksDefaultInstanceType}),
+		ScalingConfig: &eks.NodegroupScalingConfig{DesiredSize: aws.Int64(eksDefaultNodes), MinSize: aws.Int64(eksDefaultNodes), MaxSize: aws.Int64(eksDefaultNodes)},
+		Tags:          nodegroups[0].Tags,
+	})
+	if err != nil {
+		return err
+	}
+	return client.WaitUntilNodegroupActiveWithContext(ctx, &eks.DescribeNodegroupInput{ClusterName: aws.String(clusterName), NodegroupName: aws.String(nodeName)}, awsWaiterOptions()...)
+}
+
+func scaleNodeGroupWithSDK(ctx context.Context, ngName, clusterName, region string, numOfNodes, maxCount, minCount int64, extraArgs []string) error {
+	if _, err := helpers.ParseSDKArgs(extraArgs, nil, nil); err != nil {
+		return err
+	}
+	client, err := newEKSClient(region)
+	if err != nil {
+		return err
+	}
+	output, err := client.UpdateNodegroupConfigWithContext(ctx, &eks.UpdateNodegroupConfigInput{
+		ClusterName:   aws.String(clusterName),
+		NodegroupName: aws.String(ngName),
+		ScalingConfig: &eks.NodegroupScalingConfig{DesiredSize: aws.Int64(numOfNodes), MaxSize: aws.Int64(maxCount), MinSize: aws.Int64(minCount)},
+	})
+	if err != nil {
+		return err
+	}
+	return waitForEKSUpdate(ctx, client, clusterName, ngName, output.Update)
+}
+
+func updateNodeGroupLabelsWithSDK(ctx context.Context, clusterName, nodegroupName, region string, addOrUpdatelabels map[string]string, removeLabels []string, extraArgs []string) error {
+	if _, err := helpers.ParseSDKArgs(extraArgs, nil, nil); err != nil {
+		return err
+	}
+	client, err := newEKSClient(region)
+	if err != nil {
+		return err
+	}
+	labels := &eks.UpdateLabelsPayload{}
+	if len(addOrUpdatelabels) > 0 {
+		labels.AddOrUpdateLabels = aws.StringMap(addOrUpdatelabels)
+	}
+	if len(removeLabels) > 0 {
+		labels.RemoveLabels = aws.StringSlice(removeLabels)
+	}
+	_, err = client.UpdateNodegroupConfigWithContext(ctx, &eks.UpdateNodegroupConfigInput{
+		ClusterName:   aws.String(clusterName),
+		NodegroupName: aws.String(nodegroupName),
+		Labels:        labels,
+	})
+	return err
+}
+
+func updateResourceTagsWithSDK(ctx context.Context, resourceArn, region string, tags map[string]string, extraArgs []string) error {
+	if _, err := helpers.ParseSDKArgs(extraArgs, nil, nil); err != nil {
+		return err
+	}
+	client, err := newEKSClient(region)
+	if err != nil {
+		return err
+	}
+	_, err = client.TagResourceWithContext(ctx, &eks.TagResourceInput{ResourceArn: aws.String(resourceArn), Tags: aws.StringMap(tags)})
+	return err
+}
+
+func removeResourceTagsWithSDK(ctx context.Context, resourceArn, region string, tags []string, extraArgs []string) error {
+	if _, err := helpers.ParseSDKArgs(extraArgs, nil, nil); err != nil {
+		return err
+	}
+	client, err := newEKSClient(region)
+	if err != nil {
+		return err
+	}
+	_, err = client.UntagResourceWithContext(ctx, &eks.UntagResourceInput{ResourceArn: aws.String(resourceArn), TagKeys: aws.StringSlice(tags)})
+	return err
+}
+
+func updateLoggingWithSDK(ctx context.Context, clusterName, region string, enableLoggingTypes, disableLoggingTypes []string, extraArgs []string) error {
+	if _, err := helpers.ParseSDKArgs(extraArgs, nil, nil); err != nil {
+		return err
+	}
+	expand := func(types []string) []string {
+		if len(types) == 1 && types[0] == "all" {
+			return eksAllLogTypes
+		}
+		return types
+	}
+	logging := &eks.Logging{}
+	if len(enableLoggingTypes) != 0 {
+		logging.ClusterLogging = append(logging.ClusterLogging, &eks.LogSetup{Enabled: aws.Bool(true), Types: aws.StringSlice(expand(enableLoggingTypes))})
+	}
+	if len(disableLoggingTypes) != 0 {
+		logging.ClusterLogging = append(logging.ClusterLogging, &eks.LogSetup{Enabled: aws.Bool(false), Types: aws.StringSlice(expand(disableLoggingTypes))})
+	}
+	client, err := newEKSClient(region)
+	if err != nil {
+		return err
+	}
+	output, err := client.UpdateClusterConfigWithContext(ctx, &eks.UpdateClusterConfigInput{Name: aws.String(clusterName), Logging: logging})
+	if err != nil {
+		return err
+	}
+	return waitForEKSUpdate(ctx, client, clusterName, "", output.Update)
+}
+
+func updateVPCAccessWithSDK(ctx context.Context, clusterName, region string, enablePublic, enablePrivate bool, publicAccessCIDR []string, extraArgs []string) error {
+	if _, err := helpers.ParseSDKArgs(extraArgs, nil, nil); err != nil {
+		return err
+	}
+	vpcConfig := &eks.VpcConfigRequest{
+		EndpointPublicAccess:  aws.Bool(enablePublic),
+		EndpointPrivateAccess: aws.Bool(enablePrivate),
+	}
+	if len(publicAccessCIDR) != 0 {
+		vpcConfig.PublicAccessCidrs = aws.StringSlice(publicAccessCIDR)
+	}
+	client, err := newEKSClient(region)
+	if err != nil {
+		return err
+	}
+	output, err := client.UpdateClusterConfigWithContext(ctx, &eks.UpdateClusterConfigInput{Name: aws.String(clusterName), ResourcesVpcConfig: vpcConfig})
+	if err != nil {
+		return err
+	}
+	return waitForEKSUpdate(ctx, client, clusterName, "", output.Update)
+}
+
+func upgradeEKSNodegroupWithSDK(ctx context.Context, region, clusterName, ngName, upgradeToVersion string) error {
+	client, err := newEKSClient(region)
+	if err != nil {
+		return err
+	}
+	output, err := client.UpdateNodegroupVersionWithContext(ctx, &eks.UpdateNodegroupVersionInput{ClusterName: aws.String(clusterName), NodegroupName: aws.String(ngName), Version: aws.String(upgradeToVersion)})
+	if err != nil {
+		return err
+	}
+	return waitForEKSUpdate(ctx, client, clusterName, ngName, output.Update)
+}
+
+func modifyEKSNodegroupWithSDK(ctx context.Context, region, clusterName, ngName, operation string, extraArgs []string) error {
+	switch operation {
+	case "create":
+		return addNodeGroupWithSDK(ctx, ngName, clusterName, region, extraArgs)
+	case "delete":
+		args, err := helpers.ParseSDKArgs(extraArgs, nil, []string{"--wait"})
+		if err != nil {
+			return err
+		}
+		client, err := newEKSClient(region)
+		if err != nil {
+			return err
+		}
+		if _, err = client.DeleteNodegroupWithContext(ctx, &eks.DeleteNodegroupInput{ClusterName: aws.String(clusterName), NodegroupName: aws.String(ngName)}); err != nil {
+			return err
+		}
+		if args["--wait"] == "" {
+			return nil
+		}
+		return client.WaitUntilNodegroupDeletedWithContext(ctx, &eks.DescribeNodegroupInput{ClusterName: aws.String(clusterName), NodegroupName: aws.String(ngName)}, awsWaiterOptions()...)
+	default:
+		return fmt.Errorf("nodegroup operation %q is not supported by the %s cloud backend", operation, helpers.SDKBackend)
+	}
+}
+
+func deleteEKSClusterWithSDK(ctx context.Context, region, clusterName string) error {
+	client, err := newEKSClient(region)
+	if err != nil {
+		return err
+	}
+	_, err = client.DeleteClusterWithContext(ctx, &eks.DeleteClusterInput{Name: aws.String(clusterName)})
+	return err
+}
+
+func listEKSClustersWithSDK(ctx context.Context, region string) ([]helpers.CloudCluster, error) {
+	client, err := newEKSClient(region)
+	if err != nil {
+		return nil, err
+	}
+	var names []*string
+	err = client.ListClustersPagesWithContext(ctx, &eks.ListClustersInput{}, func(page *eks.ListClustersOutput, _ bool) bool {
+		names = append(names, page.Clusters...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	var clusters []helpers.CloudCluster
+	for _, name := range names {
+		output, err := client.DescribeClusterWithContext(ctx, &eks.DescribeClusterInput{Name: name})
+		if err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, helpers.CloudCluster{
+			Name:      aws.StringValue(name),
+			Location:  region,
+			Labels:    aws.StringValueMap(output.Cluster.Tags),
+			CreatedAt: aws.TimeValue(output.Cluster.CreatedAt),
+		})
+	}
+	return clusters, nil
+}
+
+// <==============================EKS SDK (end)==============================>
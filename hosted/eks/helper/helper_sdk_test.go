@@ -0,0 +1,167 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/eks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("EKS SDK", func() {
+	const (
+		clusterName = "eks-sdk"
+		region      = "us-west-2"
+		arn         = "arn:aws:eks:us-west-2:123456789012:cluster/eks-sdk"
+	)
+	var (
+		mu       sync.Mutex
+		requests []string
+		bodies   map[string]map[string]any
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		bodies = map[string]map[string]any{}
+		nodegroup := func(name string) map[string]any {
+			return map[string]any{"nodegroup": map[string]any{
+				"nodegroupName": name, "clusterName": clusterName, "status": "ACTIVE", "version": "1.31", "amiType": "AL2_x86_64", "releaseVersion": "1.31.0-20241024",
+				"instanceTypes": []string{"m5.large"}, "scalingConfig": map[string]any{"minSize": 1, "maxSize": 3, "desiredSize": 2},
+			}}
+		}
+		mux := http.NewServeMux()
+		handle := func(pattern string, response func(r *http.Request) any) {
+			mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+					body := map[string]any{}
+					Expect(json.Unmarshal(data, &body)).To(Succeed())
+					bodies[r.Method+" "+r.URL.Path] = body
+				}
+				mu.Unlock()
+				w.Header().Set("Content-Type", "application/json")
+				Expect(json.NewEncoder(w).Encode(response(r))).To(Succeed())
+			})
+		}
+		handle("GET /clusters/"+clusterName, func(*http.Request) any {
+			return map[string]any{"cluster": map[string]any{"name": clusterName, "arn": arn, "status": "ACTIVE", "version": "1.31"}}
+		})
+		handle("GET /clusters/"+clusterName+"/node-groups", func(*http.Request) any {
+			return map[string]any{"nodegroups": []string{"ranchernodes", "ng1"}}
+		})
+		handle("GET /clusters/"+clusterName+"/node-groups/{name}", func(r *http.Request) any {
+			return nodegroup(r.PathValue("name"))
+		})
+		handle("POST /clusters/"+clusterName+"/node-groups/{name}/update-config", func(*http.Request) any {
+			return map[string]any{"update": map[string]any{"id": "update-1", "status": "InProgress"}}
+		})
+		handle("GET /clusters/"+clusterName+"/updates/update-1", func(*http.Request) any {
+			return map[string]any{"update": map[string]any{"id": "update-1", "status": "Successful"}}
+		})
+		handle("POST /tags/{arn...}", func(*http.Request) any {
+			return map[string]any{}
+		})
+		srv := httptest.NewServer(mux)
+		DeferCleanup(srv.Close)
+
+		DeferCleanup(func(config func(string) *aws.Config, delay time.Duration) {
+			awsConfig, awsWaitDelay = config, delay
+		}, awsConfig, awsWaitDelay)
+		awsConfig = func(region string) *aws.Config {
+			return aws.NewConfig().WithRegion(region).WithEndpoint(srv.URL).WithCredentials(credentials.NewStaticCredentials("id", "secret", ""))
+		}
+		awsWaitDelay = 10 * time.Millisecond
+
+		DeferCleanup(func(backend string) { helpers.CloudBackend = backend }, helpers.CloudBackend)
+		helpers.CloudBackend = helpers.SDKBackend
+	})
+
+	It("GetEKSNodegroupOnAWS returns the state of the nodegroup", func(ctx SpecContext) {
+		nodegroup, err := GetEKSNodegroupOnAWS(ctx, region, clusterName, "ng1")
+		Expect(err).To(BeNil())
+		Expect(nodegroup).To(Equal(EKSNodegroup{
+			Name: "ng1", Status: "ACTIVE", Version: "1.31", AMIType: "AL2_x86_64", ReleaseVersion: "1.31.0-20241024",
+			InstanceTypes: []string{"m5.large"}, DesiredSize: 2, MinSize: 1, MaxSize: 3,
+		}))
+	})
+
+	It("GetFromEKS is not supported", func(ctx SpecContext) {
+		_, err := GetFromEKS(ctx, region, clusterName, "cluster", ".[].Arn")
+		Expect(err).To(MatchError(ContainSubstring("not supported by the sdk cloud backend")))
+		Expect(requests).To(BeEmpty())
+	})
+
+	It("ScaleNodeGroupOnAWS updates the scaling config and waits for the update", func(ctx SpecContext) {
+		Expect(ScaleNodeGroupOnAWS(ctx, "ng1", clusterName, region, 3, 4, 1)).To(Succeed())
+
+		Expect(requests).To(Equal([]string{
+			"POST /clusters/" + clusterName + "/node-groups/ng1/update-config",
+			"GET /clusters/" + clusterName + "/updates/update-1",
+		}))
+		Expect(bodies["POST /clusters/"+clusterName+"/node-groups/ng1/update-config"]).To(HaveKeyWithValue("scalingConfig", map[string]any{"desiredSize": 3.0, "maxSize": 4.0, "minSize": 1.0}))
+	})
+
+	It("AddClusterTagsOnAWS tags the cluster ARN", func(ctx SpecContext) {
+		Expect(AddClusterTagsOnAWS(ctx, clusterName, region, map[string]string{"owner": "qa"})).To(Succeed())
+
+		Expect(requests).To(ContainElement("POST /tags/" + arn))
+		Expect(bodies["POST /tags/"+arn]).To(HaveKeyWithValue("tags", map[string]any{"owner": "qa"}))
+	})
+
+	It("rejects the extra arguments it cannot translate", func(ctx SpecContext) {
+		Expect(AddNodeGroupOnAWS(ctx, "ng2", clusterName, region, "--spot")).To(MatchError(ContainSubstring(`argument "--spot" is not supported by the sdk cloud backend`)))
+		Expect(requests).To(BeEmpty())
+	})
+
+	It("awsClusterSpec normalizes the cluster and nodegroups reported by the API", func() {
+		spec := awsClusterSpec(&eks.Cluster{
+			Version: aws.String("1.31"),
+			Tags:    aws.StringMap(map[string]string{"owner": "qa"}),
+			Logging: &eks.Logging{ClusterLogging: []*eks.LogSetup{
+				{Enabled: aws.Bool(true), Types: aws.StringSlice([]string{"api", "audit"})},
+				{Enabled: aws.Bool(false), Types: aws.StringSlice([]string{"scheduler"})},
+			}},
+			ResourcesVpcConfig: &eks.VpcConfigResponse{EndpointPrivateAccess: aws.Bool(false), EndpointPublicAccess: aws.Bool(true)},
+		}, []*eks.Nodegroup{{
+			NodegroupName: aws.String("ng1"),
+			Version:       aws.String("1.31"),
+			Labels:        aws.StringMap(map[string]string{"team": "qa"}),
+			ScalingConfig: &eks.NodegroupScalingConfig{DesiredSize: aws.Int64(2), MinSize: aws.Int64(1), MaxSize: aws.Int64(3)},
+		}})
+
+		Expect(spec).To(Equal(helpers.ClusterSpec{
+			KubernetesVersion: "1.31",
+			Tags:              map[string]string{"owner": "qa"},
+			Logging:           []string{"api", "audit"},
+			PrivateAccess:     aws.Bool(false),
+			PublicAccess:      aws.Bool(true),
+			NodePools: []helpers.NodePoolSpec{
+				{Name: "ng1", NodeCount: 2, KubernetesVersion: "1.31", Labels: map[string]string{"team": "qa"}, MinCount: aws.Int64(1), MaxCount: aws.Int64(3)},
+			},
+		}))
+	})
+})
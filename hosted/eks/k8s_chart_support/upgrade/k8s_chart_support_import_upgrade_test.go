@@ -13,29 +13,19 @@ import (
 
 var _ = Describe("K8sChartSupportUpgradeImport", func() {
 	var cluster *management.Cluster
-	BeforeEach(func() {
-		err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+	BeforeEach(func(specCtx SpecContext) {
+		err := helper.CreateEKSClusterOnAWS(specCtx, region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
 		Expect(err).To(BeNil())
 
-		cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
+		cluster, err = helper.ImportEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 	})
-	AfterEach(func() {
-		if ctx.ClusterCleanup && cluster != nil {
-			err := helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
-			Expect(err).To(BeNil())
-			err = helper.DeleteEKSClusterOnAWS(region, clusterName)
-			Expect(err).To(BeNil())
-		} else {
-			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-		}
-	})
-	It("should successfully test k8s chart support import in an upgrade scenario", func() {
+	It("should successfully test k8s chart support import in an upgrade scenario", func(specCtx SpecContext) {
 		GinkgoLogr.Info(fmt.Sprintf("Testing K8s %s chart support for import on Rancher upgraded from %s to %s", helpers.K8sUpgradedMinorVersion, helpers.RancherFullVersion, helpers.RancherUpgradeFullVersion))
 		testCaseID = 167 // Report to Qase
 
-		commonchecks(&ctx, cluster, clusterName, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
+		commonchecks(specCtx, &ctx, cluster, clusterName, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
 	})
 })
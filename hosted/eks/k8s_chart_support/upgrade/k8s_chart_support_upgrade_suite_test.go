@@ -1,6 +1,7 @@
 package k8s_chart_support_upgrade_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -12,7 +13,6 @@ import (
 	. "github.com/rancher-sandbox/qase-ginkgo"
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	"github.com/rancher/shepherd/extensions/clusters"
 	nodestat "github.com/rancher/shepherd/extensions/nodes"
 	"github.com/rancher/shepherd/extensions/workloads/pods"
 	"github.com/rancher/shepherd/pkg/config"
@@ -20,6 +20,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var (
@@ -35,7 +36,23 @@ func TestK8sChartSupportUpgrade(t *testing.T) {
 	RunSpecs(t, "K8sChartSupportUpgrade Suite")
 }
 
-var _ = BeforeEach(func() {
+var _ = BeforeEach(func(specCtx SpecContext) {
+	// deferred rather than run in AfterEach so that it runs after the teardown of the downstream cluster; see helpers.CleanupRegistry
+	DeferCleanup(func(specCtx SpecContext) {
+		// The test must restore the env to its original state, so we install rancher back to its original version and uninstall the operator charts
+		// Restoring rancher back to its original state is necessary because in case DOWNSTREAM_CLUSTER_CLEANUP is set to false; in which case clusters will be retained for the next test.
+		// Once the operator is uninstalled, it might be reinstalled since the cluster exists, and installing rancher back to its original state ensures that the version is not the one we want to test.
+		By(fmt.Sprintf("Installing Rancher back to its original version %s", helpers.RancherFullVersion), func() {
+			rancherChannel, rancherVersion, rancherHeadVersion := suite.GetRancherVersions(helpers.RancherFullVersion)
+			suite.InstallRancherManager(specCtx, k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
+			suite.CheckRancherDeployments(specCtx, k)
+		})
+
+		By("Uninstalling the existing operator charts", func() {
+			suite.UninstallOperatorCharts(specCtx)
+		})
+	})
+
 	// For upgrade tests, the rancher version should not be an unreleased version (for e.g. 2.9-head)
 	Expect(helpers.RancherFullVersion).To(SatisfyAll(Not(BeEmpty()), Not(ContainSubstring("devel"))))
 	Expect(helpers.RancherUpgradeFullVersion).ToNot(BeEmpty())
@@ -43,17 +60,17 @@ var _ = BeforeEach(func() {
 	Expect(helpers.Kubeconfig).ToNot(BeEmpty())
 
 	By("Adding the necessary chart repos", func() {
-		helpers.AddRancherCharts()
+		suite.AddRancherCharts(specCtx)
 	})
 
 	By(fmt.Sprintf("Installing Rancher Manager %s", helpers.RancherFullVersion), func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
-		helpers.CheckRancherDeployments(k)
+		rancherChannel, rancherVersion, rancherHeadVersion := suite.GetRancherVersions(helpers.RancherFullVersion)
+		suite.InstallRancherManager(specCtx, k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
+		suite.CheckRancherDeployments(specCtx, k)
 	})
 
-	helpers.CommonSynchronizedBeforeSuite()
-	ctx = helpers.CommonBeforeSuite()
+	suite.CommonSynchronizedBeforeSuite()
+	ctx = suite.CommonBeforeSuite()
 
 	By("creating and using a more permanent token", func() {
 		token, err := ctx.RancherAdminClient.Management.Token.Create(&management.Token{})
@@ -70,53 +87,41 @@ var _ = BeforeEach(func() {
 
 	var err error
 	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
-	k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
+	k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, false)
 	Expect(err).To(BeNil())
 	Expect(k8sVersion).ToNot(BeEmpty())
 	GinkgoLogr.Info(fmt.Sprintf("Using EKS version %s for cluster %s", k8sVersion, clusterName))
 
 })
 
-var _ = AfterEach(func() {
-	// The test must restore the env to its original state, so we install rancher back to its original version and uninstall the operator charts
-	// Restoring rancher back to its original state is necessary because in case DOWNSTREAM_CLUSTER_CLEANUP is set to false; in which case clusters will be retained for the next test.
-	// Once the operator is uninstalled, it might be reinstalled since the cluster exists, and installing rancher back to its original state ensures that the version is not the one we want to test.
-	By(fmt.Sprintf("Installing Rancher back to its original version %s", helpers.RancherFullVersion), func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
-		helpers.CheckRancherDeployments(k)
-	})
-
-	By("Uninstalling the existing operator charts", func() {
-		helpers.UninstallOperatorCharts()
-	})
-})
-
 var _ = ReportBeforeEach(func(report SpecReport) {
 	// Reset case ID
 	testCaseID = -1
 })
 
-var _ = ReportAfterEach(func(report SpecReport) {
-	// Add result in Qase if asked
+var _ = ReportAfterEach(func(specCtx SpecContext, report SpecReport) {
+	// Add result in Qase and in the run report if asked
 	Qase(testCaseID, report)
+	suite.WriteRunReport(specCtx, report, testCaseID, ctx.RancherAdminClient)
 })
 
-func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clusterName, rancherUpgradedVersion, k8sUpgradedVersion string) {
+func commonchecks(specCtx context.Context, ctx *helpers.RancherContext, cluster *management.Cluster, clusterName, rancherUpgradedVersion, k8sUpgradedVersion string) {
 
-	helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+	suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 
 	var originalChartVersion string
 	By("checking the chart version", func() {
-		originalChartVersion = helpers.GetCurrentOperatorChartVersion()
+		var err error
+		originalChartVersion, err = helpers.GetCurrentOperatorChartVersion(specCtx)
+		Expect(err).To(BeNil())
 		Expect(originalChartVersion).ToNot(BeEmpty())
 		GinkgoLogr.Info("Original chart version: " + originalChartVersion)
 	})
 
 	By(fmt.Sprintf("upgrading rancher to %v", rancherUpgradedVersion), func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(rancherUpgradedVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "none", "none")
-		helpers.CheckRancherDeployments(k)
+		rancherChannel, rancherVersion, rancherHeadVersion := suite.GetRancherVersions(rancherUpgradedVersion)
+		suite.InstallRancherManager(specCtx, k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "none", "none")
+		suite.CheckRancherDeployments(specCtx, k)
 
 		By("ensuring operator pods are also up", func() {
 			Eventually(func() error {
@@ -146,8 +151,10 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 
 	var upgradedChartVersion string
 	By("checking the chart version and validating it is > the old version", func() {
-		helpers.WaitUntilOperatorChartInstallation(originalChartVersion, "==", 1)
-		upgradedChartVersion = helpers.GetCurrentOperatorChartVersion()
+		suite.WaitUntilOperatorChartInstallation(specCtx, originalChartVersion, "==", 1)
+		var err error
+		upgradedChartVersion, err = helpers.GetCurrentOperatorChartVersion(specCtx)
+		Expect(err).To(BeNil())
 		GinkgoLogr.Info("Upgraded chart version: " + upgradedChartVersion)
 	})
 
@@ -155,7 +162,7 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 		var err error
 		cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 		Expect(err).To(BeNil())
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 
 		// since no changes have been made to the cluster so far, we need reinstantiate EKSConfig after fetching the cluster
 		if helpers.IsImport {
@@ -165,7 +172,7 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 
 	var latestVersion *string
 	By(fmt.Sprintf("fetching a list of available k8s versions and ensure the v%s is present in the list and upgrading the cluster to it", k8sUpgradedVersion), func() {
-		versions, err := helper.ListEKSAvailableVersions(ctx.RancherAdminClient, cluster)
+		versions, err := helper.ListEKSAvailableVersions(specCtx, ctx.RancherAdminClient, cluster)
 		Expect(err).To(BeNil())
 		Expect(versions).ToNot(BeEmpty())
 		GinkgoLogr.Info(fmt.Sprintf("Available EKS versions: %v", versions))
@@ -174,47 +181,49 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 		Expect(*latestVersion).To(ContainSubstring(k8sUpgradedVersion))
 		Expect(helpers.VersionCompare(*latestVersion, cluster.Version.GitVersion)).To(BeNumerically("==", 1))
 
-		cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, *latestVersion, ctx.RancherAdminClient, true)
+		cluster, err = helper.UpgradeClusterKubernetesVersion(specCtx, cluster, *latestVersion, ctx.RancherAdminClient, true)
 		Expect(err).To(BeNil())
 
 		By("upgrading the NodeGroups", func() {
-			cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, *latestVersion, ctx.RancherAdminClient, true, true, helpers.IsImport)
+			cluster, err = helper.UpgradeNodeKubernetesVersion(specCtx, cluster, *latestVersion, ctx.RancherAdminClient, true, true, helpers.IsImport)
 			Expect(err).To(BeNil())
 		})
 	})
 
 	var downgradeVersion string
 	By("fetching a value to downgrade to", func() {
-		downgradeVersion = helpers.GetDowngradeOperatorChartVersion(upgradedChartVersion)
+		var err error
+		downgradeVersion, err = helpers.GetDowngradeOperatorChartVersion(specCtx, upgradedChartVersion)
+		Expect(err).To(BeNil())
 	})
 
 	By("downgrading the chart version", func() {
-		helpers.DowngradeProviderChart(downgradeVersion)
+		suite.DowngradeProviderChart(specCtx, downgradeVersion)
 	})
 
 	By("making a change to the cluster (scaling the node up) to validate functionality after chart downgrade", func() {
 		var err error
 		configNodeGroups := *cluster.EKSConfig.NodeGroups
 		initialNodeCount := *configNodeGroups[0].DesiredSize
-		cluster, err = helper.ScaleNodeGroup(cluster, ctx.RancherAdminClient, initialNodeCount+1, true, true)
+		cluster, err = helper.ScaleNodeGroup(specCtx, cluster, ctx.RancherAdminClient, initialNodeCount+1, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("uninstalling the operator chart", func() {
-		helpers.UninstallOperatorCharts()
+		suite.UninstallOperatorCharts(specCtx)
 	})
 
 	By("making a change(adding a nodepool) to the cluster to re-install the operator and validating it is re-installed to the latest/upgraded version", func() {
 		currentNodeGroupNumber := len(*cluster.EKSConfig.NodeGroups)
 		var err error
-		cluster, err = helper.AddNodeGroup(cluster, 1, ctx.RancherAdminClient, false, false)
+		cluster, err = helper.AddNodeGroup(specCtx, cluster, 1, ctx.RancherAdminClient, false, false)
 		Expect(err).To(BeNil())
 
 		By("ensuring that the chart is re-installed to the latest/upgraded version", func() {
-			helpers.WaitUntilOperatorChartInstallation(upgradedChartVersion, "", 0)
+			suite.WaitUntilOperatorChartInstallation(specCtx, upgradedChartVersion, "", 0)
 		})
 
-		err = clusters.WaitClusterToBeUpgraded(ctx.RancherAdminClient, cluster.ID)
+		err = helpers.WaitClusterToBeUpdated(specCtx, ctx.RancherAdminClient, cluster.ID)
 		Expect(err).To(BeNil())
 		// Check if the desired config has been applied in Rancher
 		Eventually(func() int {
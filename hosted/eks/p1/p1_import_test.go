@@ -5,150 +5,136 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
 	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var _ = Describe("P1Import", func() {
 	var k8sVersion string
-	BeforeEach(func() {
+	BeforeEach(func(specCtx SpecContext) {
 		// assigning cluster nil value so that every new test has a fresh value of the variable
 		// this is to avoid using residual value of a cluster in a test that does not use it
 		cluster = nil
 
 		var err error
-		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
+		k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, false)
 		Expect(err).To(BeNil())
 		GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
 	})
 
-	AfterEach(func() {
-		if ctx.ClusterCleanup {
-			if cluster != nil && cluster.ID != "" {
-				GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
-				err := helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
-				Expect(err).To(BeNil())
-			}
-			err := helper.DeleteEKSClusterOnAWS(region, clusterName)
-			Expect(err).To(BeNil())
-		} else {
-			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-		}
-	})
-
 	Context("Upgrade Testing", func() {
 		var upgradeToVersion string
 
-		BeforeEach(func() {
-			if helpers.SkipUpgradeTests {
-				Skip(helpers.SkipUpgradeTestsLog)
-			}
+		BeforeEach(func(specCtx SpecContext) {
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 
 			var err error
-			k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, true)
+			k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, true)
 			Expect(err).To(BeNil())
 			GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
-			upgradeToVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
+			upgradeToVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, false)
 			Expect(err).To(BeNil())
 		})
 
 		When("a cluster is imported", func() {
 
-			BeforeEach(func() {
-				err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+			BeforeEach(func(specCtx SpecContext) {
+				err := helper.CreateEKSClusterOnAWS(specCtx, region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
 				Expect(err).To(BeNil())
-				cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
+				cluster, err = helper.ImportEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
 				Expect(err).To(BeNil())
-				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
 			})
 
-			It("Upgrade version of node group only", func() {
+			It("Upgrade version of node group only", func(specCtx SpecContext) {
 				testCaseID = 88
-				upgradeNodeKubernetesVersionGTCPCheck(cluster, ctx.RancherAdminClient, upgradeToVersion)
+				upgradeNodeKubernetesVersionGTCPCheck(specCtx, cluster, ctx.RancherAdminClient, upgradeToVersion)
 			})
 
 			// eks-operator/issues/752
-			It("should successfully update a cluster while it is still in updating state", func() {
+			It("should successfully update a cluster while it is still in updating state", func(specCtx SpecContext) {
 				testCaseID = 104
-				updateClusterInUpdatingState(cluster, ctx.RancherAdminClient, upgradeToVersion)
+				updateClusterInUpdatingState(specCtx, cluster, ctx.RancherAdminClient, upgradeToVersion)
 			})
 
-			It("Update k8s version of cluster and add node groups", func() {
+			It("Update k8s version of cluster and add node groups", func(specCtx SpecContext) {
 				testCaseID = 90
-				upgradeCPAndAddNgCheck(cluster, ctx.RancherAdminClient, upgradeToVersion)
+				upgradeCPAndAddNgCheck(specCtx, cluster, ctx.RancherAdminClient, upgradeToVersion)
 			})
 		})
 	})
 
-	It("should successfully Import cluster with ONLY control plane", func() {
+	It("should successfully Import cluster with ONLY control plane", func(specCtx SpecContext) {
 		testCaseID = 94
-		err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels(), "--without-nodegroup")
+		err := helper.CreateEKSClusterOnAWS(specCtx, region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels(), "--without-nodegroup")
 		Expect(err).To(BeNil())
-		cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
+		cluster, err = helper.ImportEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
 		Expect(err).To(BeNil())
-		Eventually(func() bool {
+		Eventually(func() *management.Cluster {
 			cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 			Expect(err).To(BeNil())
-			return cluster.Transitioning == "error" && cluster.TransitioningMessage == "Cluster must have at least one managed nodegroup or one self-managed node."
-		}, "5m", "2s").Should(BeTrue())
+			return cluster
+		}, "5m", "2s").Should(suite.HaveTransitionError(helper.ErrNoNodeGroup))
 		cluster.EKSConfig = cluster.EKSStatus.UpstreamSpec
-		cluster, err = helper.AddNodeGroup(cluster, 1, ctx.RancherAdminClient, false, false)
+		cluster, err = helper.AddNodeGroup(specCtx, cluster, 1, ctx.RancherAdminClient, false, false)
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 	})
 
-	It("successfully import EKS cluster with self-managed nodes", func() {
+	It("successfully import EKS cluster with self-managed nodes", func(specCtx SpecContext) {
 		testCaseID = 107
-		err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels(), "--managed=false")
+		err := helper.CreateEKSClusterOnAWS(specCtx, region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels(), "--managed=false")
 		Expect(err).To(BeNil())
-		cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
+		cluster, err = helper.ImportEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 	})
 
 	When("a cluster with multiple nodegroups is imported", func() {
-		BeforeEach(func() {
-			err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+		BeforeEach(func(specCtx SpecContext) {
+			err := helper.CreateEKSClusterOnAWS(specCtx, region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
 			Expect(err).To(BeNil())
 			for i := 0; i < 2; i++ {
-				err = helper.AddNodeGroupOnAWS(namegen.AppendRandomString("ng"), clusterName, region)
+				err = helper.AddNodeGroupOnAWS(specCtx, namegen.AppendRandomString("ng"), clusterName, region)
 				Expect(err).To(BeNil())
 			}
-			cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
+			cluster, err = helper.ImportEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
 
-		It("should successfully Import cluster with at least 2 nodegroups", func() {
+		It("should successfully Import cluster with at least 2 nodegroups", func(specCtx SpecContext) {
 			testCaseID = 105
-			helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+			suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 		})
 	})
 
 	When("a cluster is imported", func() {
 
-		var _ = BeforeEach(func() {
-			err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+		var _ = BeforeEach(func(specCtx SpecContext) {
+			err := helper.CreateEKSClusterOnAWS(specCtx, region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
 			Expect(err).To(BeNil())
-			cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
+			cluster, err = helper.ImportEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
 
-		It("Delete & re-import cluster", func() {
+		It("Delete & re-import cluster", func(specCtx SpecContext) {
 			testCaseID = 106
 
 			var err error
-			err = helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
+			err = helper.DeleteEKSHostCluster(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 
 			Eventually(func() string {
@@ -156,64 +142,64 @@ var _ = Describe("P1Import", func() {
 				return cluster.ID
 			}, "30s", "3s").Should(BeEmpty())
 
-			cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
+			cluster, err = helper.ImportEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
 
-		It("Update cluster logging types", func() {
+		It("Update cluster logging types", func(specCtx SpecContext) {
 			testCaseID = 77
-			updateLoggingCheck(cluster, ctx.RancherAdminClient)
+			updateLoggingCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("Update Tags and Labels", func() {
+		It("Update Tags and Labels", func(specCtx SpecContext) {
 			testCaseID = 81
-			updateTagsAndLabels(cluster, ctx.RancherAdminClient)
+			updateTagsAndLabels(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("Add a nodegroup in EKS -> Syncs to Rancher -> Update cluster, the nodegroup is intact", func() {
+		It("Add a nodegroup in EKS -> Syncs to Rancher -> Update cluster, the nodegroup is intact", func(specCtx SpecContext) {
 			testCaseID = 87
 			nodepoolcount := len(*cluster.EKSStatus.UpstreamSpec.NodeGroups)
-			err := helper.AddNodeGroupOnAWS(namegen.AppendRandomString("ng"), clusterName, region)
+			err := helper.AddNodeGroupOnAWS(specCtx, namegen.AppendRandomString("ng"), clusterName, region)
 			Expect(err).To(BeNil())
 			Eventually(func() bool {
 				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 				Expect(err).To(BeNil())
 				return len(*cluster.EKSStatus.UpstreamSpec.NodeGroups) == nodepoolcount+1
 			}, "10m", "7s").Should(BeTrue(), "Timed out while waiting for rancher to sync")
-			cluster, err = helper.UpdateLogging(cluster, ctx.RancherAdminClient, []string{"authenticator"}, true)
+			cluster, err = helper.UpdateLogging(specCtx, cluster, ctx.RancherAdminClient, []string{"authenticator"}, true)
 			Expect(err).To(BeNil())
 
 			// verify that the nodegroups are intact
 			Expect(*cluster.EKSStatus.UpstreamSpec.NodeGroups).To(HaveLen(nodepoolcount + 1))
 		})
 
-		It("Update the cloud creds", func() {
+		It("Update the cloud creds", func(specCtx SpecContext) {
 			testCaseID = 155
-			updateCloudCredentialsCheck(cluster, ctx.RancherAdminClient)
+			updateCloudCredentialsCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
 		Context("Reimporting/Editing a cluster with invalid config", func() {
-			It("Reimport a cluster to Rancher should fail", func() {
+			It("Reimport a cluster to Rancher should fail", func(specCtx SpecContext) {
 				testCaseID = 101
 
 				// We do not assign the cluster returned by import function to `cluster` since it will be nil and the cluster won't be deleted in AfterEach
-				_, err := helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
+				_, err := helper.ImportEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
 				Expect(err).To(HaveOccurred())
 				Expect(err).To(MatchError(ContainSubstring("cluster already exists for EKS cluster")))
 			})
 
-			It("Add node groups to the control-plane only cluster", func() {
+			It("Add node groups to the control-plane only cluster", func(specCtx SpecContext) {
 				testCaseID = 95
 
 				var err error
-				err = helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
+				err = helper.DeleteEKSHostCluster(specCtx, cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
-				err = helper.ModifyEKSNodegroupOnAWS(region, clusterName, "ranchernodes", "delete", "--wait")
+				err = helper.ModifyEKSNodegroupOnAWS(specCtx, region, clusterName, "ranchernodes", "delete", "--wait")
 				Expect(err).To(BeNil())
 
-				cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
+				cluster, err = helper.ImportEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
 				Expect(err).To(BeNil())
 				Eventually(func() bool {
 					cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
@@ -224,15 +210,15 @@ var _ = Describe("P1Import", func() {
 				cluster.EKSConfig = cluster.EKSStatus.UpstreamSpec
 
 				By("adding a NodeGroup", func() {
-					cluster, err = helper.AddNodeGroup(cluster, 1, ctx.RancherAdminClient, false, true)
+					cluster, err = helper.AddNodeGroup(specCtx, cluster, 1, ctx.RancherAdminClient, false, true)
 					Expect(err).To(BeNil())
 				})
 			})
 
-			It("Fail to update both Public/Private access as false and invalid values of the access", func() {
+			It("Fail to update both Public/Private access as false and invalid values of the access", func(specCtx SpecContext) {
 				testCaseID = 103 // also covers 102
-				invalidEndpointCheck(cluster, ctx.RancherAdminClient)
-				invalidAccessValuesCheck(cluster, ctx.RancherAdminClient)
+				invalidEndpointCheck(specCtx, cluster, ctx.RancherAdminClient)
+				invalidAccessValuesCheck(specCtx, cluster, ctx.RancherAdminClient)
 			})
 		})
 	})
@@ -2,13 +2,10 @@ package p1_test
 
 import (
 	"fmt"
-	"os"
-	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	"github.com/rancher/shepherd/extensions/clusters"
 	"github.com/rancher/shepherd/extensions/clusters/eks"
 	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 
@@ -16,36 +13,25 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var _ = Describe("P1Provisioning", func() {
 	var k8sVersion string
-	var _ = BeforeEach(func() {
+	var _ = BeforeEach(func(specCtx SpecContext) {
 		// assigning cluster nil value so that every new test has a fresh value of the variable
 		// this is to avoid using residual value of a cluster in a test that does not use it
 		cluster = nil
 
 		var err error
-		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
+		k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, false)
 		Expect(err).To(BeNil())
 		GinkgoLogr.Info(fmt.Sprintf("While provisioning, using kubernetes version %s for cluster %s", k8sVersion, clusterName))
 	})
 
-	AfterEach(func() {
-		if ctx.ClusterCleanup {
-			if cluster != nil && cluster.ID != "" {
-				GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
-				err := helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
-				Expect(err).To(BeNil())
-			}
-		} else {
-			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-		}
-	})
-
 	Context("Provisioning/Editing a cluster with invalid config", func() {
 
-		It("should error out to provision a cluster when nodegroups is nil", func() {
+		It("should error out to provision a cluster when nodegroups is nil", func(specCtx SpecContext) {
 			testCaseID = 141
 
 			updateFunc := func(clusterConfig *eks.ClusterConfig) {
@@ -53,28 +39,28 @@ var _ = Describe("P1Provisioning", func() {
 			}
 
 			var err error
-			cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, updateFunc)
+			cluster, err = helper.CreateEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, updateFunc)
 			Expect(err).To(BeNil())
 
-			Eventually(func() bool {
+			Eventually(func() *management.Cluster {
 				cluster, err := ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 				Expect(err).To(BeNil())
-				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "Cluster must have at least one managed nodegroup or one self-managed node")
-			}, "10m", "30s").Should(BeTrue())
+				return cluster
+			}, "10m", "30s").Should(suite.HaveTransitionError(helper.ErrNoNodeGroup))
 
 		})
 
-		It("should fail to create cluster when nodegroups is an empty array", func() {
+		It("should fail to create cluster when nodegroups is an empty array", func(specCtx SpecContext) {
 			createFunc := func(clusterConfig *eks.ClusterConfig) {
 				clusterConfig.NodeGroupsConfig = &[]eks.NodeGroupConfig{}
 			}
 			var err error
-			_, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, createFunc)
+			_, err = helper.CreateEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, createFunc)
 			Expect(err).NotTo(BeNil())
 			Expect(err.Error()).To(ContainSubstring("must have at least one nodegroup"))
 		})
 
-		It("should fail to provision a cluster with duplicate nodegroup names", func() {
+		It("should fail to provision a cluster with duplicate nodegroup names", func(specCtx SpecContext) {
 			testCaseID = 255
 
 			var err error
@@ -89,22 +75,20 @@ var _ = Describe("P1Provisioning", func() {
 				}
 				*clusterConfig.NodeGroupsConfig = updatedNodeGroupsList
 			}
-			cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, updateFunc)
+			cluster, err = helper.CreateEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, updateFunc)
 			Expect(err).To(BeNil())
 
-			Eventually(func() bool {
+			Eventually(func() *management.Cluster {
 				cluster, err := ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 				Expect(err).To(BeNil())
-				// checking for both the messages since different operator version shows different messages. To be removed once the message is updated.
-				// New Message: NodePool names must be unique within the [c-dnzzk] cluster to avoid duplication
-				return cluster.Transitioning == "error" && (strings.Contains(cluster.TransitioningMessage, "is not unique within the cluster") || strings.Contains(cluster.TransitioningMessage, "names must be unique"))
-			}, "1m", "3s").Should(BeTrue())
+				return cluster
+			}, "1m", "3s").Should(suite.HaveTransitionError(helper.ErrDuplicateNodeGroupName))
 		})
 
-		It("Fail to create cluster with different k8s versions on control plane and on nodegroup", func() {
+		It("Fail to create cluster with different k8s versions on control plane and on nodegroup", func(specCtx SpecContext) {
 			testCaseID = 127
 
-			k8sVersions, err := helper.ListEKSAllVersions(ctx.RancherAdminClient)
+			k8sVersions, err := helper.ListEKSAllVersions(specCtx, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 
 			cpK8sVersion := k8sVersions[1]
@@ -120,17 +104,17 @@ var _ = Describe("P1Provisioning", func() {
 			}
 
 			GinkgoLogr.Info(fmt.Sprintf("Kubernetes version %s for control plane and %s for nodegroup on cluster %s", cpK8sVersion, ngK8sVersion, clusterName))
-			cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, cpK8sVersion, region, updateFunc)
+			cluster, err = helper.CreateEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, cpK8sVersion, region, updateFunc)
 			Expect(err).To(BeNil())
 
-			Eventually(func() bool {
+			Eventually(func() *management.Cluster {
 				cluster, err := ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 				Expect(err).To(BeNil())
-				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "version must match cluster")
-			}, "1m", "3s").Should(BeTrue())
+				return cluster
+			}, "1m", "3s").Should(suite.HaveTransitionError(helper.ErrNodeGroupVersionMismatch))
 		})
 
-		It("Fail to create cluster with only Security groups", func() {
+		It("Fail to create cluster with only Security groups", func(specCtx SpecContext) {
 			testCaseID = 120
 
 			sg := []string{namegen.AppendRandomString("sg-"), namegen.AppendRandomString("sg-")}
@@ -138,42 +122,40 @@ var _ = Describe("P1Provisioning", func() {
 				clusterConfig.SecurityGroups = sg
 			}
 			var err error
-			cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, updateFunc)
+			cluster, err = helper.CreateEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, updateFunc)
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(MatchError(ContainSubstring("subnets must be provided if security groups are provided")))
 		})
 
-		It("Fail to update both Public/Private access as false and invalid values of the access", func() {
+		It("Fail to update both Public/Private access as false and invalid values of the access", func(specCtx SpecContext) {
 			testCaseID = 147 // also covers 146
 
 			var err error
-			cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
+			cluster, err = helper.CreateEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
-			invalidEndpointCheck(cluster, ctx.RancherAdminClient)
-			invalidAccessValuesCheck(cluster, ctx.RancherAdminClient)
+			invalidEndpointCheck(specCtx, cluster, ctx.RancherAdminClient)
+			invalidAccessValuesCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 	})
 
-	It("should successfully Provision EKS with secrets encryption (KMS)", func() {
+	It("should successfully Provision EKS with secrets encryption (KMS)", func(specCtx SpecContext) {
 		testCaseID = 149
 		createFunc := func(clusterConfig *eks.ClusterConfig) {
-			clusterConfig.KmsKey = pointer.String(os.Getenv("AWS_KMS_KEY"))
+			clusterConfig.KmsKey = pointer.String(helpers.Config.EKS.KMSKey)
 		}
 		var err error
-		cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, createFunc)
+		cluster, err = helper.CreateEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, createFunc)
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 
 	})
 
-	It("should successfully Provision EKS from Rancher with Enabled GPU feature", func() {
-		if helpers.SkipTest {
-			Skip("Skipping test for v2.8, v2.9 ...")
-		}
+	It("should successfully Provision EKS from Rancher with Enabled GPU feature", func(specCtx SpecContext) {
+		suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureEKSGPUNodeGroups)
 
 		testCaseID = 274
 		var gpuNodeName = "gpuenabled"
@@ -187,21 +169,21 @@ var _ = Describe("P1Provisioning", func() {
 			clusterConfig.NodeGroupsConfig = &nodeGroups
 		}
 		var err error
-		cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, createFunc)
+		cluster, err = helper.CreateEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, createFunc)
 		Expect(err).To(BeNil())
 
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
-		var amiID string
-		amiID, err = helper.GetFromEKS(region, clusterName, "nodegroup", ".[].ImageID", "--name", gpuNodeName)
+		suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
+		var gpuNG helper.EKSNodegroup
+		gpuNG, err = helper.GetEKSNodegroupOnAWS(specCtx, region, clusterName, gpuNodeName)
 		Expect(err).To(BeNil())
-		GinkgoLogr.Info(fmt.Sprintf("Used AMI for GPU enabled nodegroup in EKS cluster: %s", amiID))
-		Expect(amiID).To(Or(Equal("AL2_x86_64_GPU"), Equal("AL2023_x86_64_NVIDIA")))
+		GinkgoLogr.Info(fmt.Sprintf("Used AMI for GPU enabled nodegroup in EKS cluster: %s %s", gpuNG.AMIType, gpuNG.ReleaseVersion))
+		Expect(gpuNG.AMIType).To(Or(Equal("AL2_x86_64_GPU"), Equal("AL2023_x86_64_NVIDIA")))
 	})
 
-	XIt("Deploy a cluster with Public/Priv access then disable Public access", func() {
+	XIt("Deploy a cluster with Public/Priv access then disable Public access", func(specCtx SpecContext) {
 		// https://github.com/rancher/eks-operator/issues/752#issuecomment-2609144199
 		testCaseID = 151
 		createFunc := func(clusterConfig *eks.ClusterConfig) {
@@ -209,77 +191,75 @@ var _ = Describe("P1Provisioning", func() {
 			clusterConfig.PrivateAccess = pointer.Bool(true)
 		}
 		var err error
-		cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, createFunc)
+		cluster, err = helper.CreateEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, createFunc)
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 
-		cluster, err = helper.UpdateAccess(cluster, ctx.RancherAdminClient, false, true, true)
+		cluster, err = helper.UpdateAccess(specCtx, cluster, ctx.RancherAdminClient, false, true, true)
 		Expect(err).To(BeNil())
 
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 	})
 
 	Context("Upgrade testing", func() {
 		var upgradeToVersion string
 
-		BeforeEach(func() {
-			if helpers.SkipUpgradeTests {
-				Skip(helpers.SkipUpgradeTestsLog)
-			}
+		BeforeEach(func(specCtx SpecContext) {
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 
 			var err error
-			k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, true)
+			k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, true)
 			Expect(err).To(BeNil())
-			upgradeToVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
+			upgradeToVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, false)
 			Expect(err).To(BeNil())
 			GinkgoLogr.Info(fmt.Sprintf("While provisioning, using kubernetes version %s for cluster %s", k8sVersion, clusterName))
 		})
 
 		When("a cluster is created", func() {
 
-			BeforeEach(func() {
+			BeforeEach(func(specCtx SpecContext) {
 				var err error
-				cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
+				cluster, err = helper.CreateEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
 				Expect(err).To(BeNil())
-				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
 			})
 
-			It("Upgrade version of node group only", func() {
+			It("Upgrade version of node group only", func(specCtx SpecContext) {
 				testCaseID = 126
-				upgradeNodeKubernetesVersionGTCPCheck(cluster, ctx.RancherAdminClient, upgradeToVersion)
+				upgradeNodeKubernetesVersionGTCPCheck(specCtx, cluster, ctx.RancherAdminClient, upgradeToVersion)
 			})
 
-			It("Update k8s version of cluster and add node groups", func() {
+			It("Update k8s version of cluster and add node groups", func(specCtx SpecContext) {
 				testCaseID = 125
-				upgradeCPAndAddNgCheck(cluster, ctx.RancherAdminClient, upgradeToVersion)
+				upgradeCPAndAddNgCheck(specCtx, cluster, ctx.RancherAdminClient, upgradeToVersion)
 			})
 
 			// eks-operator/issues/752
-			XIt("should successfully update a cluster while it is still in updating state", func() {
+			XIt("should successfully update a cluster while it is still in updating state", func(specCtx SpecContext) {
 				testCaseID = 148
-				updateClusterInUpdatingState(cluster, ctx.RancherAdminClient, upgradeToVersion)
+				updateClusterInUpdatingState(specCtx, cluster, ctx.RancherAdminClient, upgradeToVersion)
 			})
 		})
 
 		When("a cluster is created with multiple nodegroups", func() {
-			BeforeEach(func() {
+			BeforeEach(func(specCtx SpecContext) {
 				var err error
 				createFunc := func(clusterConfig *eks.ClusterConfig) {
 					*clusterConfig, err = helper.AddNodeGroupToConfig(*clusterConfig, 4)
 					Expect(err).To(BeNil())
 				}
-				cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, createFunc)
+				cluster, err = helper.CreateEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, createFunc)
 				Expect(err).To(BeNil())
-				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
 			})
 
-			It("Update k8s version of node groups - sequential & simultaneous upgrade of multiple node groups", func() {
+			It("Update k8s version of node groups - sequential & simultaneous upgrade of multiple node groups", func(specCtx SpecContext) {
 				testCaseID = 153
 				var err error
-				cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeToVersion, ctx.RancherAdminClient, true)
+				cluster, err = helper.UpgradeClusterKubernetesVersion(specCtx, cluster, upgradeToVersion, ctx.RancherAdminClient, true)
 				Expect(err).To(BeNil())
 
 				// upgrade 2 nodegroups simultaneously
@@ -289,9 +269,9 @@ var _ = Describe("P1Provisioning", func() {
 						nodeGroups[i].Version = &upgradeToVersion
 					}
 				}
-				cluster, err = helper.UpdateCluster(cluster, ctx.RancherAdminClient, updateFunc)
+				cluster, err = helper.UpdateCluster(specCtx, cluster, ctx.RancherAdminClient, updateFunc)
 				Expect(err).To(BeNil())
-				err = clusters.WaitClusterToBeUpgraded(ctx.RancherAdminClient, cluster.ID)
+				err = helpers.WaitClusterToBeUpdated(specCtx, ctx.RancherAdminClient, cluster.ID)
 				Expect(err).To(BeNil())
 				Eventually(func() bool {
 					cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
@@ -306,7 +286,7 @@ var _ = Describe("P1Provisioning", func() {
 				}, "10m", "7s").Should(BeTrue())
 
 				// upgrade the remaining nodegroups
-				cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, upgradeToVersion, ctx.RancherAdminClient, true, true, false)
+				cluster, err = helper.UpgradeNodeKubernetesVersion(specCtx, cluster, upgradeToVersion, ctx.RancherAdminClient, true, true, false)
 				Expect(err).To(BeNil())
 			})
 		})
@@ -314,32 +294,32 @@ var _ = Describe("P1Provisioning", func() {
 
 	When("a cluster is created", func() {
 
-		BeforeEach(func() {
+		BeforeEach(func(specCtx SpecContext) {
 			var err error
-			cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
+			cluster, err = helper.CreateEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
 
-		It("Update cluster logging types", func() {
+		It("Update cluster logging types", func(specCtx SpecContext) {
 			testCaseID = 128
-			updateLoggingCheck(cluster, ctx.RancherAdminClient)
+			updateLoggingCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("Update Tags and Labels", func() {
+		It("Update Tags and Labels", func(specCtx SpecContext) {
 			testCaseID = 131
-			updateTagsAndLabels(cluster, ctx.RancherAdminClient)
+			updateTagsAndLabels(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("Update the cloud creds", func() {
+		It("Update the cloud creds", func(specCtx SpecContext) {
 			testCaseID = 109
-			updateCloudCredentialsCheck(cluster, ctx.RancherAdminClient)
+			updateCloudCredentialsCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should fail to Delete all Node groups", func() {
+		It("should fail to Delete all Node groups", func(specCtx SpecContext) {
 			testCaseID = 134
-			deleteAllNodeGroupsCheck(cluster, ctx.RancherAdminClient)
+			deleteAllNodeGroupsCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 	})
 })
@@ -15,10 +15,9 @@ limitations under the License.
 package p1_test
 
 import (
+	"context"
 	"fmt"
 	"maps"
-	"strconv"
-	"strings"
 	"testing"
 	"time"
 
@@ -36,6 +35,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var (
@@ -52,10 +52,10 @@ func TestP1(t *testing.T) {
 }
 
 var _ = SynchronizedBeforeSuite(func() []byte {
-	helpers.CommonSynchronizedBeforeSuite()
+	suite.CommonSynchronizedBeforeSuite()
 	return nil
 }, func() {
-	ctx = helpers.CommonBeforeSuite()
+	ctx = suite.CommonBeforeSuite()
 })
 
 var _ = BeforeEach(func() {
@@ -69,18 +69,19 @@ var _ = ReportBeforeEach(func(report SpecReport) {
 	testCaseID = -1
 })
 
-var _ = ReportAfterEach(func(report SpecReport) {
-	// Add result in Qase if asked
+var _ = ReportAfterEach(func(specCtx SpecContext, report SpecReport) {
+	// Add result in Qase and in the run report if asked
 	Qase(testCaseID, report)
+	suite.WriteRunReport(specCtx, report, testCaseID, ctx.RancherAdminClient)
 })
 
 // updateClusterInUpdatingState runs checks to ensure cluster in an updating state can be updated
-func updateClusterInUpdatingState(cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
+func updateClusterInUpdatingState(specCtx context.Context, cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
 	var (
 		exists bool
 		err    error
 	)
-	cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeToVersion, client, false)
+	cluster, err = helper.UpgradeClusterKubernetesVersion(specCtx, cluster, upgradeToVersion, client, false)
 	Expect(err).To(BeNil())
 	Expect(*cluster.EKSConfig.KubernetesVersion).To(Equal(upgradeToVersion))
 
@@ -88,11 +89,11 @@ func updateClusterInUpdatingState(cluster *management.Cluster, client *rancher.C
 	Expect(err).To(BeNil())
 
 	loggingTypes := []string{"api"}
-	cluster, err = helper.UpdateLogging(cluster, client, loggingTypes, false)
+	cluster, err = helper.UpdateLogging(specCtx, cluster, client, loggingTypes, false)
 	Expect(err).To(BeNil())
 	Expect(*cluster.EKSConfig.LoggingTypes).Should(HaveExactElements(loggingTypes))
 
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	err = helpers.WaitClusterToBeUpdated(specCtx, client, cluster.ID)
 	Expect(err).To(BeNil())
 
 	Eventually(func() bool {
@@ -107,12 +108,12 @@ func updateClusterInUpdatingState(cluster *management.Cluster, client *rancher.C
 	}, "15m", "30s").Should(BeTrue())
 }
 
-func syncK8sVersionUpgradeCheck(cluster *management.Cluster, client *rancher.Client, upgradeNodeGroup bool, k8sVersion, upgradeToVersion string) {
+func syncK8sVersionUpgradeCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client, upgradeNodeGroup bool, k8sVersion, upgradeToVersion string) {
 	var err error
 	GinkgoLogr.Info("Upgrading cluster to version:" + upgradeToVersion)
 
 	By("upgrading control plane", func() {
-		err = helper.UpgradeEKSClusterOnAWS(region, clusterName, upgradeToVersion)
+		err = helper.UpgradeEKSClusterOnAWS(specCtx, region, clusterName, upgradeToVersion)
 		Expect(err).To(BeNil())
 
 		Eventually(func() string {
@@ -134,7 +135,7 @@ func syncK8sVersionUpgradeCheck(cluster *management.Cluster, client *rancher.Cli
 		By("upgrading the nodegroup", func() {
 			GinkgoLogr.Info(fmt.Sprintf("Upgrading Nodegroup's EKS version to %s", upgradeToVersion))
 			for _, ng := range *cluster.EKSStatus.UpstreamSpec.NodeGroups {
-				err = helper.UpgradeEKSNodegroupOnAWS(region, clusterName, *ng.NodegroupName, upgradeToVersion)
+				err = helper.UpgradeEKSNodegroupOnAWS(specCtx, region, clusterName, *ng.NodegroupName, upgradeToVersion)
 				Expect(err).To(BeNil())
 			}
 
@@ -162,10 +163,10 @@ func syncK8sVersionUpgradeCheck(cluster *management.Cluster, client *rancher.Cli
 	}
 }
 
-func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client, k8sVersion, upgradeToVersion string) {
+func syncAWSToRancherCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client, k8sVersion, upgradeToVersion string) {
 	loggingTypes := []string{"api", "audit", "authenticator", "controllerManager", "scheduler"}
 	By("Enabling the LoggingTypes", func() {
-		err := helper.UpdateLoggingOnAWS(clusterName, region, loggingTypes, nil)
+		err := helper.UpdateLoggingOnAWS(specCtx, clusterName, region, loggingTypes, nil)
 		Expect(err).To(BeNil())
 
 		Eventually(func() bool {
@@ -187,7 +188,7 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 	})
 
 	By("Disabling the LoggingTypes", func() {
-		err := helper.UpdateLoggingOnAWS(clusterName, region, nil, []string{"all"})
+		err := helper.UpdateLoggingOnAWS(specCtx, clusterName, region, nil, []string{"all"})
 		Expect(err).To(BeNil())
 		Eventually(func() bool {
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -202,7 +203,7 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 
 	By("Updating public/private access and CIDRs", func() {
 		cidrs := []string{"0.0.0.0/0", helpers.GetRancherIP() + "/32"}
-		err := helper.UpdateVPCAccess(clusterName, region, true, true, cidrs)
+		err := helper.UpdateVPCAccess(specCtx, clusterName, region, true, true, cidrs)
 		Expect(err).To(BeNil())
 		Eventually(func() bool {
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -227,7 +228,7 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 	})
 
 	By("upgrading control plane and nodegroup", func() {
-		syncK8sVersionUpgradeCheck(cluster, client, true, k8sVersion, upgradeToVersion)
+		syncK8sVersionUpgradeCheck(specCtx, cluster, client, true, k8sVersion, upgradeToVersion)
 	})
 
 	By("scaling up the NodeGroup", func() {
@@ -237,7 +238,7 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 		if ng.DesiredSize != nil {
 			nodeCount = *ng.DesiredSize + 2
 		}
-		err := helper.ScaleNodeGroupOnAWS(*ng.NodegroupName, clusterName, region, nodeCount, nodeCount+2, nodeCount-1)
+		err := helper.ScaleNodeGroupOnAWS(specCtx, *ng.NodegroupName, clusterName, region, nodeCount, nodeCount+2, nodeCount-1)
 		Expect(err).To(BeNil())
 		Eventually(func() bool {
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -259,7 +260,7 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 		// Error: loading VPC spec for cluster "auto-eks-hp-ci-atiia": VPC configuration required for creating nodegroups on clusters not owned by eksctl: vpc.subnets, vpc.id, vpc.securityGroup
 		// If this is implemented for rancher-provisioned cluster; make sure to check for Config spec.
 		By("adding a NodeGroup", func() {
-			err := helper.AddNodeGroupOnAWS(nodeName, clusterName, region)
+			err := helper.AddNodeGroupOnAWS(specCtx, nodeName, clusterName, region)
 			Expect(err).To(BeNil())
 
 			Eventually(func() bool {
@@ -283,12 +284,12 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 			// adding an extra nodegroup to make sure there are at least 2 nodes in the cluster before deleting it for rancher-provisioned clusters
 			// remove this if and when By("adding a Nodegroup") is implemented for rancher-provisioned cluster
 			var err error
-			cluster, err = helper.AddNodeGroup(cluster, 1, client, true, true)
+			cluster, err = helper.AddNodeGroup(specCtx, cluster, 1, client, true, true)
 			Expect(err).To(BeNil())
 			configNodeGroups := *cluster.EKSConfig.NodeGroups
 			nodeName = *configNodeGroups[1].NodegroupName
 		}
-		err := helper.ModifyEKSNodegroupOnAWS(region, clusterName, nodeName, "delete", "--wait")
+		err := helper.ModifyEKSNodegroupOnAWS(specCtx, region, clusterName, nodeName, "delete", "--wait")
 		Expect(err).To(BeNil())
 		Eventually(func() bool {
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -324,7 +325,7 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 
 	tags := map[string]string{"foo": "bar", "updated": "via-cli"}
 	By("adding tags to EKS cluster", func() {
-		err := helper.AddClusterTagsOnAWS(clusterName, region, tags)
+		err := helper.AddClusterTagsOnAWS(specCtx, clusterName, region, tags)
 		Expect(err).To(BeNil())
 		Eventually(func() bool {
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -362,7 +363,7 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 		for key := range tags {
 			removeTags = append(removeTags, key)
 		}
-		err := helper.RemoveClusterTagsOnAWS(clusterName, region, removeTags)
+		err := helper.RemoveClusterTagsOnAWS(specCtx, clusterName, region, removeTags)
 		Expect(err).To(BeNil())
 		Eventually(func() bool {
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -387,7 +388,7 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 	const ngIndex = 0
 	By("add labels to Nodegroup", func() {
 		upstreamNodeGroups := *cluster.EKSStatus.UpstreamSpec.NodeGroups
-		err := helper.UpdateNodeGroupLabelsOnAWS(clusterName, *upstreamNodeGroups[ngIndex].NodegroupName, region, addLabels, nil)
+		err := helper.UpdateNodeGroupLabelsOnAWS(specCtx, clusterName, *upstreamNodeGroups[ngIndex].NodegroupName, region, addLabels, nil)
 		Expect(err).To(BeNil())
 		Eventually(func() bool {
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -430,7 +431,7 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 			removeLabels = append(removeLabels, key)
 		}
 		upstreamNodeGroups := *cluster.EKSStatus.UpstreamSpec.NodeGroups
-		err := helper.UpdateNodeGroupLabelsOnAWS(clusterName, *upstreamNodeGroups[ngIndex].NodegroupName, region, nil, removeLabels)
+		err := helper.UpdateNodeGroupLabelsOnAWS(specCtx, clusterName, *upstreamNodeGroups[ngIndex].NodegroupName, region, nil, removeLabels)
 		Expect(err).To(BeNil())
 		Eventually(func() bool {
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -462,9 +463,13 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 			Expect(*upstreamNodeGroups[ngIndex].Labels).ToNot(HaveKeyWithValue(key, value))
 		}
 	})
+
+	By("checking the cluster has not drifted from AWS", func() {
+		suite.ExpectNoDrift(specCtx, helper.Provider{}, client, cluster)
+	})
 }
 
-func syncRancherToAWSCheck(cluster *management.Cluster, client *rancher.Client, k8sVersion, upgradeToVersion string) {
+func syncRancherToAWSCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client, k8sVersion, upgradeToVersion string) {
 	var err error
 	loggingTypes := []string{"api", "audit", "authenticator", "controllerManager", "scheduler"}
 	currentNodeGroupNumber := len(*cluster.EKSConfig.NodeGroups)
@@ -472,11 +477,11 @@ func syncRancherToAWSCheck(cluster *management.Cluster, client *rancher.Client,
 	initialNodeCount := *configNodeGroups[0].DesiredSize
 
 	By("upgrading control plane", func() {
-		syncK8sVersionUpgradeCheck(cluster, client, false, k8sVersion, upgradeToVersion)
+		syncK8sVersionUpgradeCheck(specCtx, cluster, client, false, k8sVersion, upgradeToVersion)
 	})
 
 	By("scaling up the NodeGroup", func() {
-		cluster, err = helper.ScaleNodeGroup(cluster, client, initialNodeCount+1, true, true)
+		cluster, err = helper.ScaleNodeGroup(specCtx, cluster, client, initialNodeCount+1, true, true)
 		Expect(err).To(BeNil())
 
 		// Verify the existing details do NOT change in Rancher
@@ -486,22 +491,16 @@ func syncRancherToAWSCheck(cluster *management.Cluster, client *rancher.Client,
 		}
 
 		// Verify the new edits reflect in AWS and existing details do NOT change
-		var out string
-		out, err = helper.GetFromEKS(region, clusterName, "cluster", "'.[]|.Version'")
+		var spec helpers.ClusterSpec
+		spec, err = helper.Provider{}.ClusterSpecOnCloud(specCtx, cluster)
 		Expect(err).To(BeNil())
-		Expect(out).To(Equal(upgradeToVersion))
-
-		out, err = helper.GetFromEKS(region, clusterName, "nodegroup", "'.|length'")
-		Expect(err).To(BeNil())
-		Expect(strconv.Atoi(out)).To(Equal(currentNodeGroupNumber))
-
-		out, err = helper.GetFromEKS(region, clusterName, "nodegroup", "'.[]|.DesiredCapacity'")
-		Expect(err).To(BeNil())
-		Expect(strconv.ParseInt(out, 10, 64)).To(Equal(initialNodeCount + 1))
+		Expect(spec.KubernetesVersion).To(Equal(upgradeToVersion))
+		Expect(spec.NodePools).To(HaveLen(currentNodeGroupNumber))
+		Expect(spec.NodePools[0].NodeCount).To(Equal(initialNodeCount + 1))
 	})
 
 	By("adding a NodeGroup", func() {
-		cluster, err = helper.AddNodeGroup(cluster, 1, client, true, true)
+		cluster, err = helper.AddNodeGroup(specCtx, cluster, 1, client, true, true)
 		Expect(err).To(BeNil())
 
 		// Verify the existing details do NOT change in Rancher
@@ -509,18 +508,15 @@ func syncRancherToAWSCheck(cluster *management.Cluster, client *rancher.Client,
 		Expect(*cluster.EKSConfig.LoggingTypes).ShouldNot(HaveExactElements(loggingTypes))
 
 		// Verify the new edits reflect in AWS console and existing details do NOT change
-		var out string
-		out, err = helper.GetFromEKS(region, clusterName, "cluster", "'.[]|.Version'")
-		Expect(err).To(BeNil())
-		Expect(out).To(Equal(upgradeToVersion))
-
-		out, err = helper.GetFromEKS(region, clusterName, "nodegroup", "'.|length'")
+		var spec helpers.ClusterSpec
+		spec, err = helper.Provider{}.ClusterSpecOnCloud(specCtx, cluster)
 		Expect(err).To(BeNil())
-		Expect(strconv.Atoi(out)).To(Equal(currentNodeGroupNumber + 1))
+		Expect(spec.KubernetesVersion).To(Equal(upgradeToVersion))
+		Expect(spec.NodePools).To(HaveLen(currentNodeGroupNumber + 1))
 	})
 
 	By("Adding the LoggingTypes", func() {
-		cluster, err = helper.UpdateLogging(cluster, client, loggingTypes, true)
+		cluster, err = helper.UpdateLogging(specCtx, cluster, client, loggingTypes, true)
 		Expect(err).To(BeNil())
 
 		// Verify the existing details do NOT change in Rancher
@@ -528,63 +524,50 @@ func syncRancherToAWSCheck(cluster *management.Cluster, client *rancher.Client,
 		Expect(len(*cluster.EKSConfig.NodeGroups)).To(Equal(currentNodeGroupNumber + 1))
 
 		// Verify the new edits reflect in AWS console and existing details do NOT change
-		var out string
-		out, err = helper.GetFromEKS(region, clusterName, "nodegroup", "'.|length'")
-		Expect(err).To(BeNil())
-		Expect(strconv.Atoi(out)).To(Equal(currentNodeGroupNumber + 1))
-
-		out, err = helper.GetFromEKS(region, clusterName, "cluster", "'.[]|.Logging|.[]|.[]|.Types'")
+		var spec helpers.ClusterSpec
+		spec, err = helper.Provider{}.ClusterSpecOnCloud(specCtx, cluster)
 		Expect(err).To(BeNil())
-		Expect(out).ShouldNot(HaveExactElements(loggingTypes))
+		Expect(spec.NodePools).To(HaveLen(currentNodeGroupNumber + 1))
+		Expect(spec.Logging).To(ConsistOf(loggingTypes))
 	})
 
 }
 
 // upgradeNodeKubernetesVersionGTCP upgrades Nodegroup version greater than Controlplane's
-func upgradeNodeKubernetesVersionGTCPCheck(cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
+func upgradeNodeKubernetesVersionGTCPCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
 	GinkgoLogr.Info("Upgrading only Nodegroup's EKS version to: " + upgradeToVersion)
-	var err error
-	cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, upgradeToVersion, client, false, false, false)
+	timeline := helpers.StartClusterTimeline(specCtx, client, cluster)
+	_, err := helper.UpgradeNodeKubernetesVersion(specCtx, cluster, upgradeToVersion, client, false, false, false)
 	Expect(err).To(BeNil())
 
 	// wait until the error is visible on the cluster
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
-		Expect(err).To(BeNil())
-		// checking for both the messages since different operator version shows different messages. To be removed once the message is updated.
-		// New message:  versions for cluster [1.29] and nodegroup [1.30] not compatible: all nodegroup kubernetes versions must be equal to or one minor version lower than the cluster kubernetes version
-		return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "not compatible")
-	}, "1m", "3s").Should(BeTrue())
+	Expect(timeline.WaitUntilEntered(specCtx, "error", suite.TransitionErrorRegexp(specCtx, helper.ErrNodeGroupVersionIncompatible), time.Minute)).To(Succeed())
 }
 
 // invalidEndpointCheck updates PublicAccess Sources
-func invalidEndpointCheck(cluster *management.Cluster, client *rancher.Client) {
-	var err error
+func invalidEndpointCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	cidr := []string{namegen.AppendRandomString("invalid")}
-	cluster, _ = helper.UpdatePublicAccessSources(cluster, client, cidr, false)
+	timeline := helpers.StartClusterTimeline(specCtx, client, cluster)
+	_, _ = helper.UpdatePublicAccessSources(specCtx, cluster, client, cidr, false)
 
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
-		Expect(err).To(BeNil())
-		return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "The following CIDRs are invalid in publicAccessCidrs")
-	}, "2m", "3s").Should(BeTrue())
+	Expect(timeline.WaitUntilEntered(specCtx, "error", suite.TransitionErrorRegexp(specCtx, helper.ErrInvalidPublicAccessCIDRs), 2*time.Minute)).To(Succeed())
 }
 
 // invalidAccessCheck disbales both PublicAccess & PrivateAccess
-func invalidAccessValuesCheck(cluster *management.Cluster, client *rancher.Client) {
+func invalidAccessValuesCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	var err error
-	_, err = helper.UpdateAccess(cluster, client, false, false, false)
+	_, err = helper.UpdateAccess(specCtx, cluster, client, false, false, false)
 	Expect(err).To(MatchError(ContainSubstring("public access, private access, or both must be enabled")))
 }
 
-func upgradeCPAndAddNgCheck(cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
+func upgradeCPAndAddNgCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
 	var err error
 	originalLen := len(*cluster.EKSConfig.NodeGroups)
 	newNodeGroupName := pointer.String(namegen.AppendRandomString("ng"))
 	GinkgoLogr.Info("Upgrading control plane to version:" + upgradeToVersion)
 
 	By("upgrading the ControlPlane", func() {
-		cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeToVersion, ctx.RancherAdminClient, true)
+		cluster, err = helper.UpgradeClusterKubernetesVersion(specCtx, cluster, upgradeToVersion, ctx.RancherAdminClient, true)
 		Expect(err).To(BeNil())
 	})
 
@@ -600,14 +583,14 @@ func upgradeCPAndAddNgCheck(cluster *management.Cluster, client *rancher.Client,
 		cluster.EKSConfig.NodeGroups = &updatedNodeGroupsList
 	}
 
-	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+	cluster, err = helper.UpdateCluster(specCtx, cluster, client, updateFunc)
 	Expect(err).To(BeNil())
 	Expect(len(*cluster.EKSConfig.NodeGroups)).To(BeEquivalentTo(originalLen))
 	for _, ng := range *cluster.EKSConfig.NodeGroups {
 		Expect(ng.NodegroupName).To(Equal(newNodeGroupName))
 	}
 
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	err = helpers.WaitClusterToBeUpdated(specCtx, client, cluster.ID)
 	Expect(err).To(BeNil())
 
 	// wait until the update is visible on the cluster
@@ -625,7 +608,7 @@ func upgradeCPAndAddNgCheck(cluster *management.Cluster, client *rancher.Client,
 }
 
 // Automate Qase 81 and 131
-func updateTagsAndLabels(cluster *management.Cluster, client *rancher.Client) {
+func updateTagsAndLabels(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	var err error
 	tags := map[string]string{
 		"foo":        "bar",
@@ -643,12 +626,12 @@ func updateTagsAndLabels(cluster *management.Cluster, client *rancher.Client) {
 	maps.Copy(updatedTags, tags)
 
 	By("Adding cluster tags", func() {
-		cluster, err = helper.UpdateClusterTags(cluster, client, updatedTags, true)
+		cluster, err = helper.UpdateClusterTags(specCtx, cluster, client, updatedTags, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("Removing cluster tags", func() {
-		cluster, err = helper.UpdateClusterTags(cluster, client, originalClusterTags, true)
+		cluster, err = helper.UpdateClusterTags(specCtx, cluster, client, originalClusterTags, true)
 		for key, value := range tags {
 			Expect(*cluster.EKSConfig.Tags).ToNot(HaveKeyWithValue(key, value))
 		}
@@ -668,12 +651,12 @@ func updateTagsAndLabels(cluster *management.Cluster, client *rancher.Client) {
 	maps.Copy(updatedNGTags, tags)
 
 	By("Adding Nodegroup tags & labels", func() {
-		cluster, err = helper.UpdateNodegroupMetadata(cluster, client, updatedNGTags, updatedNGLabels, true)
+		cluster, err = helper.UpdateNodegroupMetadata(specCtx, cluster, client, updatedNGTags, updatedNGLabels, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("Removing Nodegroup tags & labels", func() {
-		cluster, err = helper.UpdateNodegroupMetadata(cluster, client, originalNGTags, originalNGLabels, true)
+		cluster, err = helper.UpdateNodegroupMetadata(specCtx, cluster, client, originalNGTags, originalNGLabels, true)
 		for _, ng := range *cluster.EKSConfig.NodeGroups {
 			for key, value := range tags {
 				Expect(*ng.Tags).ToNot(HaveKeyWithValue(key, value))
@@ -686,28 +669,28 @@ func updateTagsAndLabels(cluster *management.Cluster, client *rancher.Client) {
 }
 
 // Automates Qase: 128 and 77
-func updateLoggingCheck(cluster *management.Cluster, client *rancher.Client) {
+func updateLoggingCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	var err error
 	loggingTypes := []string{"api", "audit", "authenticator", "controllerManager", "scheduler"}
 	By("Adding the LoggingTypes", func() {
-		cluster, err = helper.UpdateLogging(cluster, client, loggingTypes, true)
+		cluster, err = helper.UpdateLogging(specCtx, cluster, client, loggingTypes, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("Removing the LoggingTypes", func() {
-		cluster, err = helper.UpdateLogging(cluster, client, []string{loggingTypes[0]}, true)
+		cluster, err = helper.UpdateLogging(specCtx, cluster, client, []string{loggingTypes[0]}, true)
 		Expect(err).To(BeNil())
 	})
 }
 
 // Automates Qase: 109 and 155
-func updateCloudCredentialsCheck(cluster *management.Cluster, client *rancher.Client) {
+func updateCloudCredentialsCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	newCCID, err := helpers.CreateCloudCredentials(client)
 	Expect(err).To(BeNil())
 	updateFunc := func(cluster *management.Cluster) {
 		cluster.EKSConfig.AmazonCredentialSecret = newCCID
 	}
-	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+	cluster, err = helper.UpdateCluster(specCtx, cluster, client, updateFunc)
 	Expect(err).To(BeNil())
 	Expect(cluster.EKSConfig.AmazonCredentialSecret).To(Equal(newCCID))
 	Eventually(func() bool {
@@ -716,19 +699,19 @@ func updateCloudCredentialsCheck(cluster *management.Cluster, client *rancher.Cl
 		return cluster.EKSStatus.UpstreamSpec.AmazonCredentialSecret == newCCID
 	}, "5m", "5s").Should(BeTrue(), "Failed while upstream cloud credentials update")
 
-	cluster, err = helper.ScaleNodeGroup(cluster, client, 3, true, true)
+	cluster, err = helper.ScaleNodeGroup(specCtx, cluster, client, 3, true, true)
 	Expect(err).To(BeNil())
 }
 
 // Automates Qase: 134 and
-func deleteAllNodeGroupsCheck(cluster *management.Cluster, client *rancher.Client) {
+func deleteAllNodeGroupsCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	updateFunc := func(cluster *management.Cluster) {
 		// setting this to nil will do nothing, so we set it to empty array
 		cluster.EKSConfig.NodeGroups = &[]management.NodeGroup{}
 	}
 
 	var err error
-	_, err = helper.UpdateCluster(cluster, client, updateFunc)
+	_, err = helper.UpdateCluster(specCtx, cluster, client, updateFunc)
 	Expect(err).ToNot(BeNil())
 	Expect(err.Error()).To(ContainSubstring("must have at least one nodegroup"))
 
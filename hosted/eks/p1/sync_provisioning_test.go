@@ -9,6 +9,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var _ = Describe("SyncProvisioning", func() {
@@ -17,51 +18,40 @@ var _ = Describe("SyncProvisioning", func() {
 		k8sVersion string
 	)
 
-	AfterEach(func() {
-		if ctx.ClusterCleanup && (cluster != nil && cluster.ID != "") {
-			err := helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
-			Expect(err).To(BeNil())
-		} else {
-			GinkgoLogr.Info(fmt.Sprintf("Skipping downstream cluster deletion: %s", clusterName))
-		}
-	})
-
 	When("a cluster is created for sync", func() {
 		var upgradeToVersion string
 
-		BeforeEach(func() {
-			if helpers.SkipUpgradeTests {
-				Skip(helpers.SkipUpgradeTestsLog)
-			}
+		BeforeEach(func(specCtx SpecContext) {
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 			var err error
-			k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, true)
+			k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, true)
 			Expect(err).To(BeNil())
-			upgradeToVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
+			upgradeToVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, false)
 			Expect(err).To(BeNil())
 			GinkgoLogr.Info(fmt.Sprintf("While provisioning, using kubernetes version %s for cluster %s", k8sVersion, clusterName))
 
-			cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
+			cluster, err = helper.CreateEKSHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
 
-		It("Upgrade k8s version of cluster from EKS and verify it is synced back to Rancher", func() {
+		It("Upgrade k8s version of cluster from EKS and verify it is synced back to Rancher", func(specCtx SpecContext) {
 			testCaseID = 159
 
 			By("upgrading the ControlPlane & NodeGroup", func() {
-				syncK8sVersionUpgradeCheck(cluster, ctx.RancherAdminClient, true, k8sVersion, upgradeToVersion)
+				syncK8sVersionUpgradeCheck(specCtx, cluster, ctx.RancherAdminClient, true, k8sVersion, upgradeToVersion)
 			})
 		})
 
-		It("Sync from AWS console to Rancher", func() {
+		It("Sync from AWS console to Rancher", func(specCtx SpecContext) {
 			testCaseID = 156
-			syncAWSToRancherCheck(cluster, ctx.RancherAdminClient, k8sVersion, upgradeToVersion)
+			syncAWSToRancherCheck(specCtx, cluster, ctx.RancherAdminClient, k8sVersion, upgradeToVersion)
 		})
 
-		It("Sync from Rancher to AWS console after a sync from AWS console to Rancher", func() {
+		It("Sync from Rancher to AWS console after a sync from AWS console to Rancher", func(specCtx SpecContext) {
 			testCaseID = 157
-			syncRancherToAWSCheck(cluster, ctx.RancherAdminClient, k8sVersion, upgradeToVersion)
+			syncRancherToAWSCheck(specCtx, cluster, ctx.RancherAdminClient, k8sVersion, upgradeToVersion)
 		})
 	})
 
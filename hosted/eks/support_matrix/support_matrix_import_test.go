@@ -25,6 +25,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var _ = Describe("SupportMatrixImport", func() {
@@ -37,33 +38,23 @@ var _ = Describe("SupportMatrixImport", func() {
 				clusterName string
 				cluster     *management.Cluster
 			)
-			BeforeEach(func() {
+			BeforeEach(func(specCtx SpecContext) {
 				clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
 				var err error
-				err = helper.CreateEKSClusterOnAWS(region, clusterName, version, "1", helpers.GetCommonMetadataLabels())
+				err = helper.CreateEKSClusterOnAWS(specCtx, region, clusterName, version, "1", helpers.GetCommonMetadataLabels())
 				Expect(err).To(BeNil())
-				cluster, err = helper.ImportEKSHostedCluster(ctx.StdUserClient, clusterName, ctx.CloudCredID, region)
+				cluster, err = helper.ImportEKSHostedCluster(specCtx, ctx.StdUserClient, clusterName, ctx.CloudCredID, region)
 				Expect(err).To(BeNil())
 				// Requires RancherAdminClient
-				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
 			})
-			AfterEach(func() {
-				if ctx.ClusterCleanup && cluster != nil {
-					err := helper.DeleteEKSHostCluster(cluster, ctx.StdUserClient)
-					Expect(err).To(BeNil())
-					err = helper.DeleteEKSClusterOnAWS(region, clusterName)
-					Expect(err).To(BeNil())
-				} else {
-					fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-				}
-			})
 
-			It("should successfully import the cluster", func() {
+			It("should successfully import the cluster", func(specCtx SpecContext) {
 				// Report to Qase
 				testCaseID = 70
 
-				helpers.ClusterIsReadyChecks(cluster, ctx.StdUserClient, clusterName)
+				suite.ClusterIsReadyChecks(specCtx, cluster, ctx.StdUserClient, clusterName)
 			})
 		})
 	}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var _ = Describe("SupportMatrixProvisioning", func() {
@@ -37,29 +38,21 @@ var _ = Describe("SupportMatrixProvisioning", func() {
 				clusterName string
 				cluster     *management.Cluster
 			)
-			BeforeEach(func() {
+			BeforeEach(func(specCtx SpecContext) {
 				clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
 				var err error
-				cluster, err = helper.CreateEKSHostedCluster(ctx.StdUserClient, clusterName, ctx.CloudCredID, version, region, nil)
+				cluster, err = helper.CreateEKSHostedCluster(specCtx, ctx.StdUserClient, clusterName, ctx.CloudCredID, version, region, nil)
 				Expect(err).To(BeNil())
 				// Requires RancherAdminClient
-				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
 			})
-			AfterEach(func() {
-				if ctx.ClusterCleanup && cluster != nil {
-					err := helper.DeleteEKSHostCluster(cluster, ctx.StdUserClient)
-					Expect(err).To(BeNil())
-				} else {
-					fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-				}
-			})
 
-			It("should successfully provision the cluster", func() {
+			It("should successfully provision the cluster", func(specCtx SpecContext) {
 				// Report to Qase
 				testCaseID = 69
 
-				helpers.ClusterIsReadyChecks(cluster, ctx.StdUserClient, clusterName)
+				suite.ClusterIsReadyChecks(specCtx, cluster, ctx.StdUserClient, clusterName)
 			})
 		})
 	}
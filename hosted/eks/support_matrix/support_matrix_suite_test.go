@@ -15,6 +15,7 @@ limitations under the License.
 package support_matrix_test
 
 import (
+	"context"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/rancher-sandbox/qase-ginkgo"
@@ -24,6 +25,7 @@ import (
 	"github.com/rancher/shepherd/extensions/clusters/kubernetesversions"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var (
@@ -35,15 +37,20 @@ var (
 
 func TestSupportMatrix(t *testing.T) {
 	RegisterFailHandler(Fail)
-	helpers.CommonSynchronizedBeforeSuite()
-	ctx = helpers.CommonBeforeSuite()
-	helpers.CreateStdUserClient(&ctx)
+	suite.CommonSynchronizedBeforeSuite()
+	ctx = suite.CommonBeforeSuite()
+	suite.CreateStdUserClient(&ctx)
 	var err error
 	allAvailableVersionList, err = kubernetesversions.ListEKSAllVersions(ctx.StdUserClient)
-	availableVersionList = helpers.FilterUIUnsupportedVersions(allAvailableVersionList, ctx.StdUserClient)
+	Expect(err).To(BeNil())
+	availableVersionList, err = helpers.FilterUIUnsupportedVersions(allAvailableVersionList, ctx.StdUserClient)
 	Expect(err).To(BeNil())
 	Expect(availableVersionList).ToNot(BeEmpty())
 	RunSpecs(t, "SupportMatrix Suite")
+	// the resources created before RunSpecs are not deferred by Ginkgo, hence they are cleaned up here
+	if err := ctx.Cleanups.RunAll(context.Background()); err != nil {
+		t.Error(ctx.Cleanups.Summary())
+	}
 }
 
 var _ = ReportBeforeEach(func(report SpecReport) {
@@ -51,7 +58,8 @@ var _ = ReportBeforeEach(func(report SpecReport) {
 	testCaseID = -1
 })
 
-var _ = ReportAfterEach(func(report SpecReport) {
-	// Add result in Qase if asked
+var _ = ReportAfterEach(func(specCtx SpecContext, report SpecReport) {
+	// Add result in Qase and in the run report if asked
 	Qase(testCaseID, report)
+	suite.WriteRunReport(specCtx, report, testCaseID, ctx.RancherAdminClient)
 })
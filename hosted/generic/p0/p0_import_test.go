@@ -0,0 +1,76 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package p0_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
+)
+
+var _ = Describe("P0Import", func() {
+	for _, testData := range []struct {
+		qaseIDs   map[string]int64
+		isUpgrade bool
+		testBody  func(specCtx context.Context, provider helpers.HostedProvider, cluster *management.Cluster, client *rancher.Client, clusterName string)
+		testTitle string
+	}{
+		{
+			qaseIDs:   map[string]int64{"aks": 213, "eks": 234, "gke": 9},
+			isUpgrade: false,
+			testBody:  suite.P0NodesChecks,
+			testTitle: "should successfully import the cluster & add, delete, scale nodepool",
+		},
+		{
+			qaseIDs:   map[string]int64{"aks": 232, "eks": 73, "gke": 10},
+			isUpgrade: true,
+			testBody:  suite.P0UpgradeK8sVersionChecks,
+			testTitle: "should be able to upgrade k8s version of the imported cluster",
+		},
+	} {
+		testData := testData
+		When("a cluster is imported", func() {
+			BeforeEach(func(specCtx SpecContext) {
+				if testData.isUpgrade {
+					suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
+				}
+
+				k8sVersion, err := provider.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, testData.isUpgrade)
+				Expect(err).NotTo(HaveOccurred())
+				GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
+
+				err = provider.CreateClusterOnCloud(specCtx, clusterName, k8sVersion, 1)
+				Expect(err).To(BeNil())
+
+				cluster, err = provider.ImportHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID)
+				Expect(err).To(BeNil())
+				cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
+				Expect(err).To(BeNil())
+			})
+
+			It(testData.testTitle, func(specCtx SpecContext) {
+				testCaseID = testData.qaseIDs[provider.Name()]
+				testData.testBody(specCtx, provider, cluster, ctx.RancherAdminClient, clusterName)
+			})
+		})
+	}
+})
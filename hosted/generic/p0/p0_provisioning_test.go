@@ -15,67 +15,58 @@ limitations under the License.
 package p0_test
 
 import (
+	"context"
 	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/rancher/shepherd/clients/rancher"
-
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
 
-	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var _ = Describe("P0Provisioning", func() {
 	for _, testData := range []struct {
-		qaseID    int64
+		qaseIDs   map[string]int64
 		isUpgrade bool
-		testBody  func(cluster *management.Cluster, client *rancher.Client, clusterName string)
+		testBody  func(specCtx context.Context, provider helpers.HostedProvider, cluster *management.Cluster, client *rancher.Client, clusterName string)
 		testTitle string
 	}{
 		{
-			qaseID:    172,
+			qaseIDs:   map[string]int64{"aks": 172, "eks": 71, "gke": 8},
 			isUpgrade: false,
-			testBody:  p0NodesChecks,
+			testBody:  suite.P0NodesChecks,
 			testTitle: "should successfully provision the cluster & add, delete, scale nodepool",
 		},
 		{
-			qaseID:    175,
+			qaseIDs:   map[string]int64{"aks": 175, "eks": 74, "gke": 11},
 			isUpgrade: true,
-			testBody:  p0upgradeK8sVersionCheck,
-			testTitle: "should be able to upgrade k8s version of the cluster",
+			testBody:  suite.P0UpgradeK8sVersionChecks,
+			testTitle: "should be able to upgrade k8s version of the provisioned cluster",
 		},
 	} {
 		testData := testData
 		When("a cluster is created", func() {
-			BeforeEach(func() {
-				if testData.isUpgrade && helpers.SkipUpgradeTests {
-					Skip("Skipping upgrade tests ...")
+			BeforeEach(func(specCtx SpecContext) {
+				if testData.isUpgrade {
+					suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 				}
-				k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, testData.isUpgrade)
+
+				k8sVersion, err := provider.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, testData.isUpgrade)
 				Expect(err).NotTo(HaveOccurred())
 				GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 
-				cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
+				cluster, err = provider.CreateHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion)
 				Expect(err).To(BeNil())
-				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
 			})
-			AfterEach(func() {
-				if ctx.ClusterCleanup {
-					if cluster != nil && cluster.ID != "" {
-						GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
-						err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
-						Expect(err).To(BeNil())
-					}
-				} else {
-					fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-				}
-			})
-			It(testData.testTitle, func() {
-				testCaseID = testData.qaseID
-				testData.testBody(cluster, ctx.RancherAdminClient, clusterName)
+
+			It(testData.testTitle, func(specCtx SpecContext) {
+				testCaseID = testData.qaseIDs[provider.Name()]
+				testData.testBody(specCtx, provider, cluster, ctx.RancherAdminClient, clusterName)
 			})
 		})
 	}
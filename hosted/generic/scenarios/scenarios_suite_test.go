@@ -0,0 +1,89 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenarios_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/rancher-sandbox/qase-ginkgo"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+
+	// The provider helper packages register their helpers.HostedProvider implementation
+	_ "github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
+	_ "github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
+	_ "github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
+)
+
+var (
+	ctx         helpers.RancherContext
+	provider    helpers.HostedProvider
+	scenarios   []helpers.Scenario
+	cluster     *management.Cluster
+	clusterName string
+	testCaseID  int64
+)
+
+// scenariosDir returns the directory of the scenario files; SCENARIOS_DIR can be used to run scenarios kept outside of the repository
+func scenariosDir() string {
+	if dir := helpers.Config.ScenariosDir; dir != "" {
+		return dir
+	}
+	return "testdata"
+}
+
+func TestScenarios(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	// The specs are generated from the scenarios, they must be loaded before the tree is built
+	var err error
+	provider, err = helpers.CurrentHostedProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	scenarios, err = helpers.LoadScenarios(scenariosDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	RunSpecs(t, "Scenarios Suite")
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	suite.CommonSynchronizedBeforeSuite()
+	return nil
+}, func() {
+	ctx = suite.CommonBeforeSuite()
+})
+
+var _ = BeforeEach(func() {
+	// Setting this to nil ensures we do not use the `cluster` variable value from another test running in parallel with this one.
+	cluster = nil
+	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+})
+
+var _ = ReportBeforeEach(func(report SpecReport) {
+	// Reset case ID
+	testCaseID = -1
+})
+
+var _ = ReportAfterEach(func(specCtx SpecContext, report SpecReport) {
+	// Add result in Qase and in the run report if asked
+	Qase(testCaseID, report)
+	suite.WriteRunReport(specCtx, report, testCaseID, ctx.RancherAdminClient)
+})
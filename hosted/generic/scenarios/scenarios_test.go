@@ -0,0 +1,70 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenarios_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
+)
+
+var _ = Describe("Scenarios", func() {
+	mode := "created"
+	if helpers.IsImport {
+		mode = "imported"
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		qaseID, ok := scenario.QaseIDFor(provider.Name(), helpers.IsImport)
+		if !ok {
+			continue
+		}
+
+		When("a cluster is "+mode, func() {
+			BeforeEach(func(specCtx SpecContext) {
+				if scenario.Upgrade {
+					suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
+				}
+
+				k8sVersion, err := provider.GetK8sVersion(specCtx, ctx.RancherAdminClient, ctx.CloudCredID, scenario.Upgrade)
+				Expect(err).NotTo(HaveOccurred())
+				GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
+
+				if helpers.IsImport {
+					err = provider.CreateClusterOnCloud(specCtx, clusterName, k8sVersion, 1)
+					Expect(err).To(BeNil())
+					cluster, err = provider.ImportHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID)
+				} else {
+					cluster, err = provider.CreateHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion)
+				}
+				Expect(err).To(BeNil())
+				cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
+				Expect(err).To(BeNil())
+			})
+
+			It(fmt.Sprintf("should run the scenario %s", scenario.Name), func(specCtx SpecContext) {
+				testCaseID = qaseID
+				suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
+				runner := suite.ScenarioRunner{Provider: provider, Client: ctx.RancherAdminClient}
+				cluster = runner.Run(specCtx, cluster, scenario)
+			})
+		})
+	}
+})
@@ -0,0 +1,141 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakecli"
+)
+
+var _ = Describe("GCloud CLI", func() {
+	const (
+		clusterName = "gke-cli"
+		zone        = "asia-south2-c"
+		project     = "gke-project"
+	)
+	var runner *fakecli.Runner
+
+	BeforeEach(func() {
+		runner = fakecli.NewRunner()
+		DeferCleanup(runner.Install())
+	})
+
+	AfterEach(func() {
+		Expect(runner.Verify()).To(Succeed())
+	})
+
+	It("CreateGKEClusterOnGCloud creates the cluster with the common metadata labels", func(ctx SpecContext) {
+		DeferCleanup(os.Setenv, "KUBECONFIG", os.Getenv("KUBECONFIG"))
+		DeferCleanup(func() {
+			_ = os.Remove(os.Getenv(helpers.DownstreamKubeconfig(clusterName)))
+			_ = os.Unsetenv(helpers.DownstreamKubeconfig(clusterName))
+		})
+
+		labels := k8slabels.SelectorFromSet(helpers.GetCommonMetadataLabels()).String()
+		runner.Expect("gcloud", "container", "clusters", "create", clusterName, "--project", project, "--zone", zone, "--cluster-version", "1.31.5-gke.1000", "--labels", labels, "--network", "default", "--release-channel", "None", "--machine-type", "n2-standard-2", "--disk-size", "100", "--num-nodes", "1", "--no-enable-master-authorized-networks", "--num-nodes", "2")
+
+		Expect(CreateGKEClusterOnGCloud(ctx, zone, clusterName, project, "1.31.5-gke.1000", "--num-nodes", "2")).To(Succeed())
+	})
+
+	DescribeTable("ClusterExistsOnGCloud",
+		func(ctx SpecContext, output string, expected bool) {
+			runner.Expect("gcloud", "container", "clusters", "list", "--filter", clusterName, "--project", project, "--zone", zone).Returns(output)
+
+			exists, err := ClusterExistsOnGCloud(ctx, clusterName, project, zone)
+			Expect(err).To(BeNil())
+			Expect(exists).To(Equal(expected))
+		},
+		Entry("returns true for a running cluster", "NAME     STATUS\ngke-cli  RUNNING", true),
+		Entry("returns true for a provisioning cluster", "NAME     STATUS\ngke-cli  PROVISIONING", true),
+		Entry("returns false for a stopping cluster", "NAME     STATUS\ngke-cli  STOPPING", false),
+		Entry("returns false if the cluster does not exist", "", false),
+	)
+
+	It("AddNodePoolOnGCloud adds a nodepool", func(ctx SpecContext) {
+		runner.Expect("gcloud", "container", "node-pools", "create", "np1", "--cluster", clusterName, "--project", project, "--zone", zone, "--num-nodes", "1", "--enable-autoscaling", "--max-nodes", "1", "--min-nodes", "0", "--image-type", "COS_CONTAINERD")
+
+		Expect(AddNodePoolOnGCloud(ctx, clusterName, zone, project, "np1", "--image-type", "COS_CONTAINERD")).To(Succeed())
+	})
+
+	It("DeleteNodePoolOnGCloud deletes a nodepool", func(ctx SpecContext) {
+		runner.Expect("gcloud", "container", "node-pools", "delete", "np1", "--cluster", clusterName, "--project", project, "--zone", zone, "--quiet")
+
+		Expect(DeleteNodePoolOnGCloud(ctx, zone, project, clusterName, "np1")).To(Succeed())
+	})
+
+	It("ResizeNodePoolOnGCloud resizes a nodepool", func(ctx SpecContext) {
+		runner.Expect("gcloud", "container", "clusters", "resize", clusterName, "--node-pool", "np1", "--num-nodes", "3", "--project", project, "--zone", zone, "--quiet")
+
+		Expect(ResizeNodePoolOnGCloud(ctx, zone, project, clusterName, "np1", 3)).To(Succeed())
+	})
+
+	It("UpgradeGKEClusterOnGCloud upgrades the control plane", func(ctx SpecContext) {
+		runner.Expect("gcloud", "container", "clusters", "upgrade", clusterName, "--cluster-version", "1.32.1", "--project", project, "--zone", zone, "--quiet", "--master")
+
+		Expect(UpgradeGKEClusterOnGCloud(ctx, zone, clusterName, project, "1.32.1", false, "")).To(Succeed())
+	})
+
+	It("UpgradeGKEClusterOnGCloud upgrades a nodepool", func(ctx SpecContext) {
+		runner.Expect("gcloud", "container", "clusters", "upgrade", clusterName, "--cluster-version", "1.32.1", "--project", project, "--zone", zone, "--quiet", "--node-pool", "np1", "--async")
+
+		Expect(UpgradeGKEClusterOnGCloud(ctx, zone, clusterName, project, "1.32.1", true, "np1", "--async")).To(Succeed())
+	})
+
+	It("UpgradeGKEClusterOnGCloud requires the nodepool name to upgrade a nodepool", func(ctx SpecContext) {
+		Expect(UpgradeGKEClusterOnGCloud(ctx, zone, clusterName, project, "1.32.1", true, "")).To(MatchError("node pool name must be provided"))
+		Expect(runner.Calls()).To(BeEmpty())
+	})
+
+	It("DeleteGKEClusterOnGCloud deletes the cluster and wraps the output on failure", func(ctx SpecContext) {
+		runner.Expect("gcloud", "container", "clusters", "delete", clusterName, "--zone", zone, "--quiet", "--project", project, "--async").Fails("NOT_FOUND", "exit status 1")
+
+		Expect(DeleteGKEClusterOnGCloud(ctx, zone, project, clusterName)).To(MatchError(ContainSubstring("Failed to delete cluster: NOT_FOUND")))
+	})
+
+	DescribeTable("EnableDisableServiceAccountOnGCloud",
+		func(ctx SpecContext, op string) {
+			runner.Expect("gcloud", "iam", "service-accounts", op, "sa@"+project+".iam.gserviceaccount.com", "--project", project)
+
+			Expect(EnableDisableServiceAccountOnGCloud(ctx, "sa", project, op)).To(Succeed())
+		},
+		Entry("enables the service account", "enable"),
+		Entry("disables the service account", "disable"),
+	)
+
+	It("EnableDisableServiceAccountOnGCloud rejects an unknown operation", func(ctx SpecContext) {
+		Expect(EnableDisableServiceAccountOnGCloud(ctx, "sa", project, "delete")).To(MatchError("unknown operation: delete"))
+		Expect(runner.Calls()).To(BeEmpty())
+	})
+	It("ListGKEClustersOnGCloud lists the clusters of all the locations", func(ctx SpecContext) {
+		runner.Expect("gcloud", "container", "clusters", "list", "--project", project, "--format", "json").Returns(`[
+			{"name": "gke-cli", "location": "asia-south2-c", "resourceLabels": {"owner": "hosted-providers-qa-ci-user", "janitor-ignore": "true"}, "createTime": "2024-05-01T10:00:00+00:00"},
+			{"name": "regional", "location": "asia-south2"}
+		]`)
+
+		clusters, err := ListGKEClustersOnGCloud(ctx, project)
+		Expect(err).To(BeNil())
+		Expect(clusters).To(HaveLen(2))
+		Expect(clusters[0].Labels).To(HaveKeyWithValue("janitor-ignore", "true"))
+		Expect(clusters[0].CreatedAt.UTC()).To(Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)))
+		Expect(clusters[1]).To(Equal(helpers.CloudCluster{Name: "regional", Location: "asia-south2"}))
+	})
+})
@@ -1,34 +1,31 @@
 package helper
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
-	"github.com/onsi/ginkgo/v2"
-	. "github.com/onsi/gomega"
-	"github.com/rancher-sandbox/ele-testhelpers/tools"
-
-	"github.com/rancher/shepherd/extensions/clusters"
 	"github.com/rancher/shepherd/extensions/clusters/gke"
 	k8slabels "k8s.io/apimachinery/pkg/labels"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 
 	"github.com/Masterminds/semver/v3"
-	"github.com/epinio/epinio/acceptance/helpers/proc"
 	"github.com/pkg/errors"
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
 	"github.com/rancher/shepherd/extensions/clusters/kubernetesversions"
 	"github.com/rancher/shepherd/pkg/config"
 	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	"google.golang.org/api/container/v1"
 	"k8s.io/utils/pointer"
 )
 
 // CreateGKEHostedCluster creates the GKE cluster
-func CreateGKEHostedCluster(client *rancher.Client, displayName, cloudCredentialID, k8sVersion, zone, region, project string, updateFunc func(clusterConfig *gke.ClusterConfig)) (*management.Cluster, error) {
+func CreateGKEHostedCluster(ctx context.Context, client *rancher.Client, displayName, cloudCredentialID, k8sVersion, zone, region, project string, updateFunc func(clusterConfig *gke.ClusterConfig)) (*management.Cluster, error) {
 	var gkeClusterConfig gke.ClusterConfig
 	config.LoadConfig(gke.GKEClusterConfigConfigurationFileKey, &gkeClusterConfig)
 
@@ -42,11 +39,16 @@ func CreateGKEHostedCluster(client *rancher.Client, displayName, cloudCredential
 		updateFunc(&gkeClusterConfig)
 	}
 
-	return gke.CreateGKEHostedCluster(client, displayName, cloudCredentialID, gkeClusterConfig, false, false, false, false, nil)
+	cluster, err := gke.CreateGKEHostedCluster(client, displayName, cloudCredentialID, gkeClusterConfig, false, false, false, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	helpers.RegisterHostedClusterCleanup(cluster, client, DeleteGKEHostCluster)
+	return cluster, nil
 }
 
 // ImportGKEHostedCluster imports the GKE cluster
-func ImportGKEHostedCluster(client *rancher.Client, displayName, cloudCredentialID, zone, project string) (*management.Cluster, error) {
+func ImportGKEHostedCluster(ctx context.Context, client *rancher.Client, displayName, cloudCredentialID, zone, project string) (*management.Cluster, error) {
 	cluster := &management.Cluster{
 		DockerRootDir: "/var/lib/docker",
 		GKEConfig: &management.GKEClusterConfigSpec{
@@ -63,17 +65,22 @@ func ImportGKEHostedCluster(client *rancher.Client, displayName, cloudCredential
 	if err != nil {
 		return nil, err
 	}
+	helpers.RegisterHostedClusterCleanup(clusterResp, client, DeleteGKEHostCluster)
 	return clusterResp, err
 }
 
 // DeleteGKEHostCluster deletes the GKE cluster
-func DeleteGKEHostCluster(cluster *management.Cluster, client *rancher.Client) error {
-	return client.Management.Cluster.Delete(cluster)
+func DeleteGKEHostCluster(ctx context.Context, cluster *management.Cluster, client *rancher.Client) error {
+	if err := client.Management.Cluster.Delete(cluster); err != nil {
+		return err
+	}
+	helpers.ForgetHostedClusterCleanup(cluster)
+	return nil
 }
 
 // UpgradeKubernetesVersion upgrades the k8s version to the value defined by upgradeToVersion; if upgradeNodePool is true, it also upgrades nodepool k8s version;
 // if wait is set to true, it waits until the update is complete; if checkClusterConfig is true, it validates the update
-func UpgradeKubernetesVersion(cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, upgradeNodePool, wait, checkClusterConfig bool) (*management.Cluster, error) {
+func UpgradeKubernetesVersion(ctx context.Context, cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, upgradeNodePool, wait, checkClusterConfig bool) (*management.Cluster, error) {
 	currentVersion := *cluster.GKEConfig.KubernetesVersion
 	upgradedCluster := new(management.Cluster)
 	upgradedCluster.Name = cluster.Name
@@ -92,58 +99,69 @@ func UpgradeKubernetesVersion(cluster *management.Cluster, upgradeToVersion stri
 		}
 	}
 
-	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Kubernetes version for cluster %s will be upgraded to %s", cluster.Name, upgradeToVersion))
+	helpers.Logger.Info(fmt.Sprintf("Kubernetes version for cluster %s will be upgraded to %s", cluster.Name, upgradeToVersion))
 
 	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "Failed to upgrade cluster")
 	}
 
+	// the nodepools keep their version unless they are upgraded too
+	nodePoolVersion := currentVersion
+	if upgradeNodePool {
+		nodePoolVersion = upgradeToVersion
+	}
 	if checkClusterConfig {
-		Expect(*cluster.GKEConfig.KubernetesVersion).To(Equal(upgradeToVersion))
-		for _, np := range *cluster.GKEConfig.NodePools {
-			if upgradeNodePool {
-				Expect(*np.Version).To(Equal(upgradeToVersion))
-			} else {
-				Expect(*np.Version).To(Equal(currentVersion))
-			}
+		if err = helpers.CheckField(cluster, "GKEConfig.KubernetesVersion", upgradeToVersion, *cluster.GKEConfig.KubernetesVersion); err != nil {
+			return cluster, err
+		}
+		if err = checkNodePoolVersions(cluster, "GKEConfig", *cluster.GKEConfig.NodePools, nodePoolVersion); err != nil {
+			return cluster, err
 		}
 	}
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
-		Expect(err).To(BeNil())
+		operation := helpers.OperationNodePoolUpgrade
+		if upgradeCP {
+			operation = helpers.OperationControlPlaneUpgrade
+		}
+		if err = helpers.WaitClusterToBeUpgraded(ctx, client, cluster.ID, operation); err != nil {
+			return cluster, errors.Wrap(err, "Failed to wait for the cluster upgrade")
+		}
 	}
 	if checkClusterConfig {
 		if upgradeCP {
-			Eventually(func() string {
-				ginkgo.GinkgoLogr.Info("Waiting for k8s upgrade to appear in GKEStatus.UpstreamSpec ...")
-				cluster, err = client.Management.Cluster.ByID(cluster.ID)
-				Expect(err).NotTo(HaveOccurred())
-				return *cluster.GKEStatus.UpstreamSpec.KubernetesVersion
-			}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(Equal(upgradeToVersion))
+			err = helpers.WaitFor(ctx, "the k8s upgrade to appear in GKEStatus.UpstreamSpec", 12*time.Minute, 10*time.Second, func() (bool, error) {
+				if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+					return false, err
+				}
+				return true, helpers.CheckField(cluster, "GKEStatus.UpstreamSpec.KubernetesVersion", upgradeToVersion, *cluster.GKEStatus.UpstreamSpec.KubernetesVersion)
+			})
+			if err != nil {
+				return cluster, err
+			}
 
 			if !upgradeNodePool {
-				for _, np := range *cluster.GKEConfig.NodePools {
-					Expect(*np.Version).To(BeEquivalentTo(currentVersion))
+				if err = checkNodePoolVersions(cluster, "GKEConfig", *cluster.GKEConfig.NodePools, currentVersion); err != nil {
+					return cluster, err
 				}
 			}
 		}
 
 		if upgradeNodePool {
-			Eventually(func() bool {
-				ginkgo.GinkgoLogr.Info("Waiting for the nodepool upgrade to appear in GKEStatus.UpstreamSpec ...")
-				cluster, err = client.Management.Cluster.ByID(cluster.ID)
-				Expect(err).To(BeNil())
-				for _, np := range *cluster.GKEStatus.UpstreamSpec.NodePools {
-					if *np.Version != upgradeToVersion {
-						return false
-					}
+			err = helpers.WaitFor(ctx, "the nodepool upgrade to appear in GKEStatus.UpstreamSpec", 12*time.Minute, 10*time.Second, func() (bool, error) {
+				if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+					return false, err
 				}
-				return true
-			}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeTrue())
+				return true, checkNodePoolVersions(cluster, "GKEStatus.UpstreamSpec", *cluster.GKEStatus.UpstreamSpec.NodePools, upgradeToVersion)
+			})
+			if err != nil {
+				return cluster, err
+			}
 		}
 
-		Expect(*cluster.GKEStatus.UpstreamSpec.KubernetesVersion).To(Equal(upgradeToVersion))
+		if err = helpers.CheckField(cluster, "GKEStatus.UpstreamSpec.KubernetesVersion", upgradeToVersion, *cluster.GKEStatus.UpstreamSpec.KubernetesVersion); err != nil {
+			return cluster, err
+		}
 	}
 	return cluster, nil
 }
@@ -151,7 +169,7 @@ func UpgradeKubernetesVersion(cluster *management.Cluster, upgradeToVersion stri
 // UpgradeNodeKubernetesVersion upgrades the k8s version of nodepool to the value defined by upgradeToVersion;
 // if wait is set to true, it will wait until the cluster finishes upgrading;
 // if checkClusterConfig is set to true, it will validate that nodepool has been upgraded successfully
-func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+func UpgradeNodeKubernetesVersion(ctx context.Context, cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	configNodePools := *upgradedCluster.GKEConfig.NodePools
 	for i := range configNodePools {
@@ -159,41 +177,52 @@ func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion
 	}
 	var err error
 	cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
-	Expect(err).To(BeNil())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to upgrade nodepools")
+	}
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
-		for _, np := range *cluster.GKEConfig.NodePools {
-			Expect(*np.Version).To(Equal(upgradeToVersion))
+		if err = checkNodePoolVersions(cluster, "GKEConfig", *cluster.GKEConfig.NodePools, upgradeToVersion); err != nil {
+			return cluster, err
 		}
 	}
 
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
-		Expect(err).To(BeNil())
+		if err = helpers.WaitClusterToBeUpgraded(ctx, client, cluster.ID, helpers.OperationNodePoolUpgrade); err != nil {
+			return cluster, errors.Wrap(err, "Failed to wait for the nodepools upgrade")
+		}
 	}
 
 	if checkClusterConfig {
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() bool {
-			ginkgo.GinkgoLogr.Info("Waiting for the nodepool upgrade to appear in GKEStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			for _, np := range *cluster.GKEStatus.UpstreamSpec.NodePools {
-				if *np.Version != upgradeToVersion {
-					return false
-				}
+		err = helpers.WaitFor(ctx, "the nodepool upgrade to appear in GKEStatus.UpstreamSpec", 12*time.Minute, 10*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
 			}
-			return true
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeTrue())
+			return true, checkNodePoolVersions(cluster, "GKEStatus.UpstreamSpec", *cluster.GKEStatus.UpstreamSpec.NodePools, upgradeToVersion)
+		})
+		if err != nil {
+			return cluster, err
+		}
 	}
 	return cluster, nil
 }
 
+// checkNodePoolVersions returns a helpers.ConfigMismatchError if the version of a nodepool of the given config is not the expected one
+func checkNodePoolVersions(cluster *management.Cluster, config string, nodePools []management.GKENodePoolConfig, version string) error {
+	for _, np := range nodePools {
+		if err := helpers.CheckField(cluster, fmt.Sprintf("%s.NodePools[%s].Version", config, *np.Name), version, *np.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // AddNodePool adds a nodepool to the list; it uses the nodepool template defined in CATTLE_TEST_CONFIG file
 // if wait is set to true, it waits until the update is complete; if checkClusterConfig is true, it validates the update
 // TODO(pvala): Enhance this method to accept a nodepool with different configuration
-func AddNodePool(cluster *management.Cluster, client *rancher.Client, increaseBy int, imageType string, wait, checkClusterConfig bool) (*management.Cluster, error) {
+func AddNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, increaseBy int, imageType string, wait, checkClusterConfig bool) (*management.Cluster, error) {
 	currentNodePoolNumber := len(*cluster.GKEConfig.NodePools)
 	upgradedCluster := new(management.Cluster)
 	upgradedCluster.Name = cluster.Name
@@ -224,33 +253,35 @@ func AddNodePool(cluster *management.Cluster, client *rancher.Client, increaseBy
 
 	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "Failed to add nodepools")
 	}
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
-		Expect(len(*cluster.GKEConfig.NodePools)).Should(BeNumerically("==", currentNodePoolNumber+increaseBy))
-		for i, np := range *cluster.GKEConfig.NodePools {
-			Expect(np.Name).To(Equal(updateNodePoolsList[i].Name))
+		if err = checkNodePoolNames(cluster, "GKEConfig", *cluster.GKEConfig.NodePools, updateNodePoolsList); err != nil {
+			return cluster, err
 		}
 	}
 
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
-		Expect(err).To(BeNil())
+		if err = helpers.WaitClusterToBeUpgraded(ctx, client, cluster.ID, helpers.OperationAddNodePool); err != nil {
+			return cluster, errors.Wrap(err, "Failed to wait for the nodepools addition")
+		}
 	}
 
 	if checkClusterConfig {
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() int {
-			ginkgo.GinkgoLogr.Info("Waiting for the total nodepool count to increase in GKEStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return len(*cluster.GKEStatus.UpstreamSpec.NodePools)
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeNumerically("==", currentNodePoolNumber+increaseBy))
-
-		for i, np := range *cluster.GKEStatus.UpstreamSpec.NodePools {
-			Expect(np.Name).To(Equal(updateNodePoolsList[i].Name))
+		err = helpers.WaitFor(ctx, "the total nodepool count to increase in GKEStatus.UpstreamSpec", 12*time.Minute, 10*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
+			}
+			return true, helpers.CheckField(cluster, "len(GKEStatus.UpstreamSpec.NodePools)", currentNodePoolNumber+increaseBy, len(*cluster.GKEStatus.UpstreamSpec.NodePools))
+		})
+		if err != nil {
+			return cluster, err
+		}
+		if err = checkNodePoolNames(cluster, "GKEStatus.UpstreamSpec", *cluster.GKEStatus.UpstreamSpec.NodePools, updateNodePoolsList); err != nil {
+			return cluster, err
 		}
 	}
 	return cluster, nil
@@ -259,7 +290,7 @@ func AddNodePool(cluster *management.Cluster, client *rancher.Client, increaseBy
 // DeleteNodePool deletes a nodepool from the list
 // if wait is set to true, it waits until the update is complete; if checkClusterConfig is true, it validates the update
 // TODO: Modify this method to delete a custom qty of nodepool, perhaps by adding an `decreaseBy int` arg
-func DeleteNodePool(cluster *management.Cluster, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+func DeleteNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
 	currentNodePoolNumber := len(*cluster.GKEConfig.NodePools)
 	upgradedCluster := new(management.Cluster)
 	upgradedCluster.Name = cluster.Name
@@ -270,40 +301,56 @@ func DeleteNodePool(cluster *management.Cluster, client *rancher.Client, wait, c
 
 	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "Failed to delete nodepool")
 	}
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
-		Expect(len(*cluster.GKEConfig.NodePools)).Should(BeNumerically("==", currentNodePoolNumber-1))
-		for i, np := range *cluster.GKEConfig.NodePools {
-			Expect(np.Name).To(Equal(updatedNodePoolsList[i].Name))
+		if err = checkNodePoolNames(cluster, "GKEConfig", *cluster.GKEConfig.NodePools, updatedNodePoolsList); err != nil {
+			return cluster, err
 		}
 	}
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
-		Expect(err).To(BeNil())
+		if err = helpers.WaitClusterToBeUpgraded(ctx, client, cluster.ID, helpers.OperationDeleteNodePool); err != nil {
+			return cluster, errors.Wrap(err, "Failed to wait for the nodepool deletion")
+		}
 	}
 	if checkClusterConfig {
 
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() int {
-			ginkgo.GinkgoLogr.Info("Waiting for the total nodepool count to decrease in GKEStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return len(*cluster.GKEStatus.UpstreamSpec.NodePools)
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeNumerically("==", currentNodePoolNumber-1))
-		for i, np := range *cluster.GKEStatus.UpstreamSpec.NodePools {
-			Expect(np.Name).To(Equal(updatedNodePoolsList[i].Name))
+		err = helpers.WaitFor(ctx, "the total nodepool count to decrease in GKEStatus.UpstreamSpec", 12*time.Minute, 10*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
+			}
+			return true, helpers.CheckField(cluster, "len(GKEStatus.UpstreamSpec.NodePools)", currentNodePoolNumber-1, len(*cluster.GKEStatus.UpstreamSpec.NodePools))
+		})
+		if err != nil {
+			return cluster, err
+		}
+		if err = checkNodePoolNames(cluster, "GKEStatus.UpstreamSpec", *cluster.GKEStatus.UpstreamSpec.NodePools, updatedNodePoolsList); err != nil {
+			return cluster, err
 		}
 	}
 
 	return cluster, nil
 }
 
+// checkNodePoolNames returns a helpers.ConfigMismatchError if the nodepools of the given config are not the expected ones, in the same order
+func checkNodePoolNames(cluster *management.Cluster, config string, nodePools, expectedNodePools []management.GKENodePoolConfig) error {
+	if err := helpers.CheckField(cluster, fmt.Sprintf("len(%s.NodePools)", config), len(expectedNodePools), len(nodePools)); err != nil {
+		return err
+	}
+	for i, np := range nodePools {
+		if err := helpers.CheckField(cluster, fmt.Sprintf("%s.NodePools[%d].Name", config, i), *expectedNodePools[i].Name, *np.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ScaleNodePool modifies the number of initialNodeCount of all the nodepools as defined by nodeCount
 // if wait is set to true, it waits until the update is complete; if checkClusterConfig is true, it validates the update
-func ScaleNodePool(cluster *management.Cluster, client *rancher.Client, nodeCount int64, wait, checkClusterConfig bool) (*management.Cluster, error) {
+func ScaleNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, nodeCount int64, wait, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := new(management.Cluster)
 	upgradedCluster.Name = cluster.Name
 	upgradedCluster.GKEConfig = cluster.GKEConfig
@@ -314,43 +361,50 @@ func ScaleNodePool(cluster *management.Cluster, client *rancher.Client, nodeCoun
 
 	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "Failed to scale nodepools")
 	}
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
-		configNodePools := *cluster.GKEConfig.NodePools
-		for i := range configNodePools {
-			Expect(*configNodePools[i].InitialNodeCount).To(BeNumerically("==", nodeCount))
+		if err = checkNodePoolCounts(cluster, "GKEConfig", *cluster.GKEConfig.NodePools, nodeCount); err != nil {
+			return cluster, err
 		}
 	}
 
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
-		Expect(err).To(BeNil())
+		if err = helpers.WaitClusterToBeUpgraded(ctx, client, cluster.ID, helpers.OperationScale); err != nil {
+			return cluster, errors.Wrap(err, "Failed to wait for the nodepools scaling")
+		}
 	}
 
 	if checkClusterConfig {
 		// check that the desired config is applied on Rancher
-		Eventually(func() bool {
-			ginkgo.GinkgoLogr.Info("Waiting for the node count change to appear in GKEStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			upstreamNodePools := *cluster.GKEStatus.UpstreamSpec.NodePools
-			for i := range upstreamNodePools {
-				if *upstreamNodePools[i].InitialNodeCount != nodeCount {
-					return false
-				}
+		err = helpers.WaitFor(ctx, "the node count change to appear in GKEStatus.UpstreamSpec", 12*time.Minute, 10*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
 			}
-			return true
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeTrue())
+			return true, checkNodePoolCounts(cluster, "GKEStatus.UpstreamSpec", *cluster.GKEStatus.UpstreamSpec.NodePools, nodeCount)
+		})
+		if err != nil {
+			return cluster, err
+		}
 	}
 
 	return cluster, nil
 }
 
+// checkNodePoolCounts returns a helpers.ConfigMismatchError if the initial node count of a nodepool of the given config is not the expected one
+func checkNodePoolCounts(cluster *management.Cluster, config string, nodePools []management.GKENodePoolConfig, nodeCount int64) error {
+	for _, np := range nodePools {
+		if err := helpers.CheckField(cluster, fmt.Sprintf("%s.NodePools[%s].InitialNodeCount", config, *np.Name), nodeCount, *np.InitialNodeCount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // UpdateMonitoringAndLoggingService updates the monitoring and loggingService of a GKE cluster
 // if wait is set to true, it waits until the update is complete; if checkClusterConfig is true, it validates the update
-func UpdateMonitoringAndLoggingService(cluster *management.Cluster, client *rancher.Client, monitoringService, loggingService string, wait, checkClusterConfig bool) (*management.Cluster, error) {
+func UpdateMonitoringAndLoggingService(ctx context.Context, cluster *management.Cluster, client *rancher.Client, monitoringService, loggingService string, wait, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := new(management.Cluster)
 	upgradedCluster.Name = cluster.Name
 	upgradedCluster.GKEConfig = cluster.GKEConfig
@@ -360,31 +414,44 @@ func UpdateMonitoringAndLoggingService(cluster *management.Cluster, client *ranc
 
 	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "Failed to update monitoring and logging services")
 	}
 	if checkClusterConfig {
-		Expect(*cluster.GKEConfig.MonitoringService).To(BeEquivalentTo(monitoringService))
-		Expect(*cluster.GKEConfig.LoggingService).To(BeEquivalentTo(loggingService))
+		if err = checkServices(cluster, "GKEConfig", cluster.GKEConfig, monitoringService, loggingService); err != nil {
+			return cluster, err
+		}
 	}
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
-		Expect(err).To(BeNil())
+		if err = helpers.WaitClusterToBeUpdated(ctx, client, cluster.ID); err != nil {
+			return cluster, errors.Wrap(err, "Failed to wait for the services update")
+		}
 	}
 	if checkClusterConfig {
-		Eventually(func() bool {
-			ginkgo.GinkgoLogr.Info("Waiting for the service change to appear in GKEStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return *cluster.GKEStatus.UpstreamSpec.MonitoringService == monitoringService && *cluster.GKEStatus.UpstreamSpec.LoggingService == loggingService
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeTrue())
+		err = helpers.WaitFor(ctx, "the service change to appear in GKEStatus.UpstreamSpec", 12*time.Minute, 10*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
+			}
+			return true, checkServices(cluster, "GKEStatus.UpstreamSpec", cluster.GKEStatus.UpstreamSpec, monitoringService, loggingService)
+		})
+		if err != nil {
+			return cluster, err
+		}
 	}
 	return cluster, nil
 }
 
+// checkServices returns a helpers.ConfigMismatchError if the monitoring or logging service of the given config is not the expected one
+func checkServices(cluster *management.Cluster, field string, config *management.GKEClusterConfigSpec, monitoringService, loggingService string) error {
+	if err := helpers.CheckField(cluster, field+".MonitoringService", monitoringService, *config.MonitoringService); err != nil {
+		return err
+	}
+	return helpers.CheckField(cluster, field+".LoggingService", loggingService, *config.LoggingService)
+}
+
 // UpdateAutoScaling updates the management.GKENodePoolAutoscaling for all the node pools of a GKE cluster
 // if wait is set to true, it waits until the update is complete; if checkClusterConfig is true, it validates the update
 // TODO: Facilitate passing minCount and maxCount values when autoscaling is enabled.
-func UpdateAutoScaling(cluster *management.Cluster, client *rancher.Client, enabled, wait, checkClusterConfig bool) (*management.Cluster, error) {
+func UpdateAutoScaling(ctx context.Context, cluster *management.Cluster, client *rancher.Client, enabled, wait, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := new(management.Cluster)
 	upgradedCluster.Name = cluster.Name
 	upgradedCluster.GKEConfig = cluster.GKEConfig
@@ -396,35 +463,44 @@ func UpdateAutoScaling(cluster *management.Cluster, client *rancher.Client, enab
 	}
 	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "Failed to update autoscaling")
 	}
 	if checkClusterConfig {
 		for _, np := range *cluster.GKEConfig.NodePools {
-			Expect(np.Autoscaling.Enabled).To(BeEquivalentTo(enabled))
+			if err = helpers.CheckField(cluster, fmt.Sprintf("GKEConfig.NodePools[%s].Autoscaling.Enabled", *np.Name), enabled, np.Autoscaling.Enabled); err != nil {
+				return cluster, err
+			}
 		}
 	}
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
-		Expect(err).To(BeNil())
+		if err = helpers.WaitClusterToBeUpdated(ctx, client, cluster.ID); err != nil {
+			return cluster, errors.Wrap(err, "Failed to wait for the autoscaling update")
+		}
 	}
 	if checkClusterConfig {
-		Eventually(func() bool {
-			ginkgo.GinkgoLogr.Info("Waiting for the autoscaling update to appear in GKEStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
+		err = helpers.WaitFor(ctx, "the autoscaling update to appear in GKEStatus.UpstreamSpec", 12*time.Minute, 10*time.Second, func() (bool, error) {
+			if cluster, err = client.Management.Cluster.ByID(cluster.ID); err != nil {
+				return false, err
+			}
 			for _, np := range *cluster.GKEStatus.UpstreamSpec.NodePools {
-				if np.Autoscaling != nil && np.Autoscaling.Enabled != enabled {
-					return false
+				if np.Autoscaling == nil {
+					continue
+				}
+				if err = helpers.CheckField(cluster, fmt.Sprintf("GKEStatus.UpstreamSpec.NodePools[%s].Autoscaling.Enabled", *np.Name), enabled, np.Autoscaling.Enabled); err != nil {
+					return false, err
 				}
 			}
-			return true
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeTrue())
+			return true, nil
+		})
+		if err != nil {
+			return cluster, err
+		}
 	}
 	return cluster, nil
 }
 
 // UpdateCluster is a generic function to update a cluster
-func UpdateCluster(cluster *management.Cluster, client *rancher.Client, updateFunc func(*management.Cluster)) (*management.Cluster, error) {
+func UpdateCluster(ctx context.Context, cluster *management.Cluster, client *rancher.Client, updateFunc func(*management.Cluster)) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	updateFunc(upgradedCluster)
 
@@ -432,7 +508,11 @@ func UpdateCluster(cluster *management.Cluster, client *rancher.Client, updateFu
 }
 
 // ListGKEAvailableVersions is a function to list and return only available GKE versions for a specific cluster.
-func ListGKEAvailableVersions(client *rancher.Client, clusterID string) ([]string, error) {
+func ListGKEAvailableVersions(ctx context.Context, client *rancher.Client, clusterID string) ([]string, error) {
+	// the shepherd calls below do not take a context, so a cancelled spec stops before them
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	// kubernetesversions.ListGKEAvailableVersions expects cluster.Version.GitVersion to be available, which it is not sometimes, so we fetch the cluster again to ensure it has all the available data
 	cluster, err := client.Management.Cluster.ByID(clusterID)
 	if err != nil {
@@ -442,12 +522,12 @@ func ListGKEAvailableVersions(client *rancher.Client, clusterID string) ([]strin
 	if err != nil {
 		return nil, err
 	}
-	return helpers.FilterUIUnsupportedVersions(availableVersions, client), nil
+	return helpers.FilterUIUnsupportedVersions(availableVersions, client)
 }
 
 // ListSingleVariantGKEAvailableVersions returns a list of single variants of minor versions
 // For e.g 1.27.5-gke.1700, 1.26.6-gke.2100, 1.25.8-gke.200
-func ListSingleVariantGKEAvailableVersions(client *rancher.Client, projectID, cloudCredentialID, zone, region string) (availableVersions []string, err error) {
+func ListSingleVariantGKEAvailableVersions(ctx context.Context, client *rancher.Client, projectID, cloudCredentialID, zone, region string) (availableVersions []string, err error) {
 	availableVersions, err = kubernetesversions.ListGKEAllVersions(client, projectID, cloudCredentialID, zone, region)
 	if err != nil {
 		return nil, err
@@ -461,12 +541,16 @@ func ListSingleVariantGKEAvailableVersions(client *rancher.Client, projectID, cl
 			oldMinor = currentMinor
 		}
 	}
-	return helpers.FilterUIUnsupportedVersions(singleVersionList, client), nil
+	singleVersionList, err = helpers.FilterCatalogueVersions(ctx, client, "gke", singleVersionList)
+	if err != nil {
+		return nil, err
+	}
+	return helpers.FilterUIUnsupportedVersions(singleVersionList, client)
 }
 
 // GetK8sVersionVariantGKE returns a variant of a given minor K8s version
-func GetK8sVersionVariantGKE(minorVersion string, client *rancher.Client, projectID, cloudCrendetialID, zone, region string) (string, error) {
-	versions, err := ListSingleVariantGKEAvailableVersions(client, projectID, cloudCrendetialID, zone, region)
+func GetK8sVersionVariantGKE(ctx context.Context, minorVersion string, client *rancher.Client, projectID, cloudCrendetialID, zone, region string) (string, error) {
+	versions, err := ListSingleVariantGKEAvailableVersions(ctx, client, projectID, cloudCrendetialID, zone, region)
 	if err != nil {
 		return "", err
 	}
@@ -482,7 +566,7 @@ func GetK8sVersionVariantGKE(minorVersion string, client *rancher.Client, projec
 // <==============================================================================GCLOUD CLI==============================>
 
 // Create Google GKE cluster using gcloud CLI
-func CreateGKEClusterOnGCloud(zone string, clusterName string, project string, k8sVersion string, extraArgs ...string) error {
+func CreateGKEClusterOnGCloud(ctx context.Context, zone string, clusterName string, project string, k8sVersion string, extraArgs ...string) error {
 
 	labels := helpers.GetCommonMetadataLabels()
 	labelsAsString := k8slabels.SelectorFromSet(labels).String()
@@ -492,30 +576,41 @@ func CreateGKEClusterOnGCloud(zone string, clusterName string, project string, k
 	currentKubeconfig := os.Getenv("KUBECONFIG")
 	defer os.Setenv("KUBECONFIG", currentKubeconfig)
 
-	helpers.SetTempKubeConfig(clusterName)
+	if err := helpers.SetTempKubeConfig(clusterName); err != nil {
+		return err
+	}
 
 	fmt.Println("Creating GKE cluster ...")
 	args := []string{"container", "clusters", "create", clusterName, "--project", project, "--zone", zone, "--cluster-version", k8sVersion, "--labels", labelsAsString, "--network", "default", "--release-channel", "None", "--machine-type", "n2-standard-2", "--disk-size", "100", "--num-nodes", "1", "--no-enable-master-authorized-networks"}
 	args = append(args, extraArgs...)
-	fmt.Printf("Running command: gcloud %v\n", args)
-	out, err := proc.RunW("gcloud", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return createGKEClusterWithSDK(ctx, zone, clusterName, project, k8sVersion, labels, extraArgs)
+	}, "gcloud", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to create cluster: "+out)
 	}
 
 	fmt.Println("Created GKE cluster: ", clusterName)
+	helpers.RegisterClusterCleanup(helpers.CleanupKey("gke-cluster", clusterName), fmt.Sprintf("GKE cluster %s", clusterName), func(ctx context.Context) error {
+		return DeleteGKEClusterOnGCloud(ctx, zone, project, clusterName)
+	})
 
 	return nil
 }
 
 // ClusterExistsOnGCloud gets a list of cluster based on the name filter and returns true if the cluster is in RUNNING or PROVISIONING state;
 // it returns false if the cluster does not exist or is in STOPPING state.
-func ClusterExistsOnGCloud(clusterName, project, zone string) (bool, error) {
+func ClusterExistsOnGCloud(ctx context.Context, clusterName, project, zone string) (bool, error) {
 	fmt.Println("Listing GKE cluster ...")
+	if helpers.UseCloudSDK() {
+		exists, err := clusterExistsWithSDK(ctx, clusterName, project, zone)
+		return exists, errors.Wrap(err, "Failed to list cluster")
+	}
+
 	args := []string{"container", "clusters", "list", "--filter", clusterName, "--project", project, "--zone", zone}
 
 	fmt.Printf("Running command: gcloud %v\n", args)
-	out, err := proc.RunW("gcloud", args...)
+	out, err := helpers.RunCLI(ctx, "gcloud", args...)
 	if err != nil {
 		return false, errors.Wrap(err, "Failed to list cluster: "+out)
 	}
@@ -525,8 +620,48 @@ func ClusterExistsOnGCloud(clusterName, project, zone string) (bool, error) {
 	return false, nil
 }
 
+// ListGKEClustersOnGCloud lists the GKE clusters of the project in all the locations via gcloud CLI
+func ListGKEClustersOnGCloud(ctx context.Context, project string) ([]helpers.CloudCluster, error) {
+	fmt.Println("Listing GKE clusters ...")
+	if helpers.UseCloudSDK() {
+		clusters, err := listGKEClustersWithSDK(ctx, project)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to list clusters")
+		}
+		return clusters, nil
+	}
+
+	args := []string{"container", "clusters", "list", "--project", project, "--format", "json"}
+
+	fmt.Printf("Running command: gcloud %v\n", args)
+	out, err := helpers.RunCLI(ctx, "gcloud", args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list clusters: "+out)
+	}
+	var listed []container.Cluster
+	if err = json.Unmarshal([]byte(out), &listed); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse the list of clusters")
+	}
+	var clusters []helpers.CloudCluster
+	for i := range listed {
+		clusters = append(clusters, gkeCloudCluster(&listed[i]))
+	}
+	return clusters, nil
+}
+
+// gkeCloudCluster converts a cluster listed by gcloud or the Google API, which use the same JSON format
+func gkeCloudCluster(cluster *container.Cluster) helpers.CloudCluster {
+	createdAt, _ := time.Parse(time.RFC3339, cluster.CreateTime)
+	return helpers.CloudCluster{
+		Name:      cluster.Name,
+		Location:  cluster.Location,
+		Labels:    cluster.ResourceLabels,
+		CreatedAt: createdAt,
+	}
+}
+
 // AddNodePoolOnGCloud adds a nodepool to the GKE cluster via gcloud CLI
-func AddNodePoolOnGCloud(clusterName, zone, project, npName string, extraArgs ...string) error {
+func AddNodePoolOnGCloud(ctx context.Context, clusterName, zone, project, npName string, extraArgs ...string) error {
 	if npName == "" {
 		npName = namegen.AppendRandomString("np")
 	}
@@ -535,8 +670,9 @@ func AddNodePoolOnGCloud(clusterName, zone, project, npName string, extraArgs ..
 	args := []string{"container", "node-pools", "create", npName, "--cluster", clusterName, "--project", project, "--zone", zone, "--num-nodes", "1", "--enable-autoscaling", "--max-nodes", "1", "--min-nodes", "0"}
 
 	args = append(args, extraArgs...)
-	fmt.Printf("Running command: gcloud %v\n", args)
-	out, err := proc.RunW("gcloud", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return addNodePoolWithSDK(ctx, clusterName, zone, project, npName, extraArgs)
+	}, "gcloud", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to add node pool: "+out)
 	}
@@ -545,11 +681,12 @@ func AddNodePoolOnGCloud(clusterName, zone, project, npName string, extraArgs ..
 }
 
 // DeleteNodePoolOnGCloud deletes a node pool to GKE using gcloud cli
-func DeleteNodePoolOnGCloud(zone, project, clusterName, poolName string) error {
+func DeleteNodePoolOnGCloud(ctx context.Context, zone, project, clusterName, poolName string) error {
 	fmt.Println("Deleting node pool on GKE cluster ...")
 	args := []string{"container", "node-pools", "delete", poolName, "--cluster", clusterName, "--project", project, "--zone", zone, "--quiet"}
-	fmt.Printf("Running command: gcloud %v\n", args)
-	out, err := proc.RunW("gcloud", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return deleteNodePoolWithSDK(ctx, zone, project, clusterName, poolName)
+	}, "gcloud", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to delete node pool: "+out)
 	}
@@ -557,8 +694,22 @@ func DeleteNodePoolOnGCloud(zone, project, clusterName, poolName string) error {
 	return nil
 }
 
+// ResizeNodePoolOnGCloud modifies the node count of a nodepool of the GKE cluster via gcloud CLI
+func ResizeNodePoolOnGCloud(ctx context.Context, zone, project, clusterName, poolName string, nodeCount int64) error {
+	fmt.Println("Resizing node pool on GKE cluster ...")
+	args := []string{"container", "clusters", "resize", clusterName, "--node-pool", poolName, "--num-nodes", fmt.Sprintf("%d", nodeCount), "--project", project, "--zone", zone, "--quiet"}
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return resizeNodePoolWithSDK(ctx, zone, project, clusterName, poolName, nodeCount)
+	}, "gcloud", args...)
+	if err != nil {
+		return errors.Wrap(err, "Failed to resize node pool: "+out)
+	}
+	fmt.Println("Node pool resized on GKE cluster: ", poolName)
+	return nil
+}
+
 // UpgradeGKEClusterOnGCloud upgrades the k8s version of a given GKE cluster; if upgradeNodePool is true, it only upgrades the nodepool version
-func UpgradeGKEClusterOnGCloud(zone, clusterName, project, k8sVersion string, upgradeNodePool bool, nodePoolName string, exrtaArgs ...string) error {
+func UpgradeGKEClusterOnGCloud(ctx context.Context, zone, clusterName, project, k8sVersion string, upgradeNodePool bool, nodePoolName string, exrtaArgs ...string) error {
 	args := []string{"container", "clusters", "upgrade", clusterName, "--cluster-version", k8sVersion, "--project", project, "--zone", zone, "--quiet"}
 
 	if upgradeNodePool {
@@ -577,8 +728,9 @@ func UpgradeGKEClusterOnGCloud(zone, clusterName, project, k8sVersion string, up
 
 	args = append(args, exrtaArgs...)
 
-	fmt.Printf("Running command: gcloud %v\n", args)
-	out, err := proc.RunW("gcloud", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return upgradeGKEClusterWithSDK(ctx, zone, clusterName, project, k8sVersion, upgradeNodePool, nodePoolName, exrtaArgs)
+	}, "gcloud", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to upgrade cluster: "+out)
 	}
@@ -592,7 +744,7 @@ func UpgradeGKEClusterOnGCloud(zone, clusterName, project, k8sVersion string, up
 }
 
 // Complete cleanup steps for Google GKE
-func DeleteGKEClusterOnGCloud(zone, project, clusterName string) error {
+func DeleteGKEClusterOnGCloud(ctx context.Context, zone, project, clusterName string) error {
 	currentKubeconfig := os.Getenv("KUBECONFIG")
 	downstreamKubeconfig := os.Getenv(helpers.DownstreamKubeconfig(clusterName))
 	defer func() {
@@ -603,26 +755,29 @@ func DeleteGKEClusterOnGCloud(zone, project, clusterName string) error {
 
 	fmt.Println("Deleting GKE cluster ...")
 	args := []string{"container", "clusters", "delete", clusterName, "--zone", zone, "--quiet", "--project", project, "--async"}
-	fmt.Printf("Running command: gcloud %v\n", args)
-	out, err := proc.RunW("gcloud", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return deleteGKEClusterWithSDK(ctx, zone, project, clusterName)
+	}, "gcloud", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to delete cluster: "+out)
 	}
 
 	fmt.Println("Deleted GKE cluster: ", clusterName)
+	helpers.ForgetCleanup(helpers.CleanupKey("gke-cluster", clusterName))
 
 	return nil
 }
 
 // EnableDisableServiceAccountOnGCloud can enable/disable a service account via gcloud cli
-func EnableDisableServiceAccountOnGCloud(clientID, project, op string) error {
+func EnableDisableServiceAccountOnGCloud(ctx context.Context, clientID, project, op string) error {
 	if !(op == "enable" || op == "disable") {
 		return fmt.Errorf("unknown operation: %s", op)
 	}
 	fmt.Printf("%s service account on GKE cluster...\n", op)
 	var args = []string{"iam", "service-accounts", op, fmt.Sprintf("%s@%s.iam.gserviceaccount.com", clientID, project), "--project", project}
-	fmt.Printf("Running command: gcloud %v\n", args)
-	out, err := proc.RunW("gcloud", args...)
+	out, err := helpers.RunCloud(ctx, func(ctx context.Context) error {
+		return enableDisableServiceAccountWithSDK(ctx, fmt.Sprintf("%s@%s.iam.gserviceaccount.com", clientID, project), project, op)
+	}, "gcloud", args...)
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("Failed to %s service-account: %s", op, out))
 	}
@@ -637,12 +792,12 @@ func EnableDisableServiceAccountOnGCloud(clientID, project, op string) error {
 // GetK8sVersion returns the k8s version to be used by the test;
 // this value can either be a variant of envvar DOWNSTREAM_K8S_MINOR_VERSION or the highest available version
 // or second-highest minor version in case of upgrade scenarios
-func GetK8sVersion(client *rancher.Client, projectID, cloudCredentialID, zone, region string, forUpgrade bool) (string, error) {
+func GetK8sVersion(ctx context.Context, client *rancher.Client, projectID, cloudCredentialID, zone, region string, forUpgrade bool) (string, error) {
 	if k8sMinorVersion := helpers.DownstreamK8sMinorVersion; k8sMinorVersion != "" {
-		return GetK8sVersionVariantGKE(k8sMinorVersion, client, projectID, cloudCredentialID, zone, region)
+		return GetK8sVersionVariantGKE(ctx, k8sMinorVersion, client, projectID, cloudCredentialID, zone, region)
 	}
 
-	allVariants, err := ListSingleVariantGKEAvailableVersions(client, projectID, cloudCredentialID, zone, region)
+	allVariants, err := ListSingleVariantGKEAvailableVersions(ctx, client, projectID, cloudCredentialID, zone, region)
 	if err != nil {
 		return "", err
 	}
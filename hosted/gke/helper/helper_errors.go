@@ -0,0 +1,23 @@
+package helper
+
+import (
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// The known errors of the gke-operator and of GKE, as set in cluster.TransitioningMessage; see suite.HaveTransitionError
+var (
+	ErrInvalidNodePoolName = helpers.RegisterOperatorError("gke", "invalid nodepool name",
+		helpers.ErrorMessage{Pattern: `Invalid value for field "node_pool\.name"`})
+	ErrZeroInitialNodeCount = helpers.RegisterOperatorError("gke", "zero initial node count",
+		helpers.ErrorMessage{Pattern: `Cluster\.initial_node_count must be greater than zero`})
+	ErrClusterExists = helpers.RegisterOperatorError("gke", "cluster exists",
+		helpers.ErrorMessage{Pattern: `a cluster in GKE exists with the same name`})
+	ErrWindowsImageUpgrade = helpers.RegisterOperatorError("gke", "windows image family upgrade",
+		helpers.ErrorMessage{Pattern: `Node pools cannot be upgraded between Windows and non-Windows image families`})
+	ErrInvalidCredentials = helpers.RegisterOperatorError("gke", "invalid credentials",
+		helpers.ErrorMessage{Pattern: `cannot fetch token|unexpected end of JSON input`})
+	// ErrKubernetesVersionDowngrade is set when the version in GKEConfig is older than the upstream version, for e.g. once the cluster is upgraded on GCloud
+	ErrKubernetesVersionDowngrade = helpers.RegisterOperatorError("gke", "kubernetes version downgrade",
+		helpers.ErrorMessage{Pattern: `downgrades of minor versions are not supported in GKE, consider updating spec version to match upstream version`},
+		helpers.ErrorMessage{Pattern: `specified version is not newer than the current version`})
+)
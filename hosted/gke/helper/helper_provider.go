@@ -0,0 +1,178 @@
+package helper
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+func init() {
+	helpers.RegisterHostedProvider(Provider{})
+}
+
+// Provider implements helpers.HostedProvider for GKE; it always uses a zonal cluster,
+// the zone and project are obtained via helpers.GetGKEZone and helpers.GetGKEProjectID
+type Provider struct{}
+
+func (Provider) Name() string {
+	return "gke"
+}
+
+func (Provider) GetK8sVersion(ctx context.Context, client *rancher.Client, cloudCredentialID string, forUpgrade bool) (string, error) {
+	return GetK8sVersion(ctx, client, helpers.GetGKEProjectID(), cloudCredentialID, helpers.GetGKEZone(), "", forUpgrade)
+}
+
+func (Provider) ListAvailableVersions(ctx context.Context, client *rancher.Client, cluster *management.Cluster) ([]string, error) {
+	return ListGKEAvailableVersions(ctx, client, cluster.ID)
+}
+
+func (Provider) CreateHostedCluster(ctx context.Context, client *rancher.Client, clusterName, cloudCredentialID, k8sVersion string) (*management.Cluster, error) {
+	return CreateGKEHostedCluster(ctx, client, clusterName, cloudCredentialID, k8sVersion, helpers.GetGKEZone(), "", helpers.GetGKEProjectID(), nil)
+}
+
+func (Provider) ImportHostedCluster(ctx context.Context, client *rancher.Client, clusterName, cloudCredentialID string) (*management.Cluster, error) {
+	return ImportGKEHostedCluster(ctx, client, clusterName, cloudCredentialID, helpers.GetGKEZone(), helpers.GetGKEProjectID())
+}
+
+func (Provider) DeleteHostedCluster(ctx context.Context, cluster *management.Cluster, client *rancher.Client) error {
+	return DeleteGKEHostCluster(ctx, cluster, client)
+}
+
+func (Provider) NodePoolCount(cluster *management.Cluster) int {
+	return len(*cluster.GKEConfig.NodePools)
+}
+
+func (Provider) NodeCount(cluster *management.Cluster) int64 {
+	return *(*cluster.GKEConfig.NodePools)[0].InitialNodeCount
+}
+
+func (Provider) ScaleNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, nodeCount int64, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	return ScaleNodePool(ctx, cluster, client, nodeCount, wait, checkClusterConfig)
+}
+
+func (Provider) AddNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, increaseBy int, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	return AddNodePool(ctx, cluster, client, increaseBy, "", wait, checkClusterConfig)
+}
+
+func (Provider) DeleteNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	return DeleteNodePool(ctx, cluster, client, wait, checkClusterConfig)
+}
+
+// UpgradeControlPlane waits for the upgrade to complete since GKE does not allow updating the nodepools while the control plane is upgrading
+func (Provider) UpgradeControlPlane(ctx context.Context, cluster *management.Cluster, client *rancher.Client, upgradeToVersion string, checkClusterConfig bool) (*management.Cluster, error) {
+	return UpgradeKubernetesVersion(ctx, cluster, upgradeToVersion, client, false, true, checkClusterConfig)
+}
+
+func (Provider) UpgradeNodePools(ctx context.Context, cluster *management.Cluster, client *rancher.Client, upgradeToVersion string, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	return UpgradeNodeKubernetesVersion(ctx, cluster, upgradeToVersion, client, wait, checkClusterConfig)
+}
+
+func (Provider) CreateClusterOnCloud(ctx context.Context, clusterName, k8sVersion string, nodeCount int64) error {
+	var extraArgs []string
+	if nodeCount != 1 {
+		extraArgs = append(extraArgs, "--num-nodes", strconv.FormatInt(nodeCount, 10))
+	}
+	return CreateGKEClusterOnGCloud(ctx, helpers.GetGKEZone(), clusterName, helpers.GetGKEProjectID(), k8sVersion, extraArgs...)
+}
+
+func (Provider) DeleteClusterOnCloud(ctx context.Context, clusterName string) error {
+	return DeleteGKEClusterOnGCloud(ctx, helpers.GetGKEZone(), helpers.GetGKEProjectID(), clusterName)
+}
+
+func (Provider) ListClustersOnCloud(ctx context.Context) ([]helpers.CloudCluster, error) {
+	return ListGKEClustersOnGCloud(ctx, helpers.GetGKEProjectID())
+}
+
+func (Provider) DeleteListedClusterOnCloud(ctx context.Context, cluster helpers.CloudCluster) error {
+	return DeleteGKEClusterOnGCloud(ctx, cluster.Location, helpers.GetGKEProjectID(), cluster.Name)
+}
+
+func (Provider) UpdateClusterTags(ctx context.Context, cluster *management.Cluster, client *rancher.Client, tags map[string]string) (*management.Cluster, error) {
+	return UpdateCluster(ctx, cluster, client, func(upgradedCluster *management.Cluster) {
+		upgradedCluster.GKEConfig.Labels = &tags
+	})
+}
+
+func (Provider) ConfigSpec(cluster *management.Cluster) helpers.ClusterSpec {
+	return clusterSpec(cluster.GKEConfig)
+}
+
+func (Provider) UpstreamSpec(cluster *management.Cluster) helpers.ClusterSpec {
+	if cluster.GKEStatus == nil {
+		return helpers.ClusterSpec{}
+	}
+	return clusterSpec(cluster.GKEStatus.UpstreamSpec)
+}
+
+func (Provider) ScaleNodePoolOnCloud(ctx context.Context, cluster *management.Cluster, nodeCount int64) error {
+	npName := *(*cluster.GKEConfig.NodePools)[0].Name
+	return ResizeNodePoolOnGCloud(ctx, location(cluster.GKEConfig), cluster.GKEConfig.ProjectID, cluster.GKEConfig.ClusterName, npName, nodeCount)
+}
+
+func (Provider) AddNodePoolOnCloud(ctx context.Context, cluster *management.Cluster, poolName string) error {
+	return AddNodePoolOnGCloud(ctx, cluster.GKEConfig.ClusterName, location(cluster.GKEConfig), cluster.GKEConfig.ProjectID, poolName)
+}
+
+func (Provider) UpgradeControlPlaneOnCloud(ctx context.Context, cluster *management.Cluster, upgradeToVersion string) error {
+	return UpgradeGKEClusterOnGCloud(ctx, location(cluster.GKEConfig), cluster.GKEConfig.ClusterName, cluster.GKEConfig.ProjectID, upgradeToVersion, false, "")
+}
+
+func (Provider) ClusterSpecOnCloud(ctx context.Context, cluster *management.Cluster) (helpers.ClusterSpec, error) {
+	gkeCluster, err := GetGKEClusterOnGCloud(ctx, location(cluster.GKEConfig), cluster.GKEConfig.ProjectID, cluster.GKEConfig.ClusterName)
+	if err != nil {
+		return helpers.ClusterSpec{}, err
+	}
+	return gcloudClusterSpec(gkeCluster), nil
+}
+
+// location returns the zone of a zonal cluster and the region of a regional one
+func location(config *management.GKEClusterConfigSpec) string {
+	if config.Zone != "" {
+		return config.Zone
+	}
+	return config.Region
+}
+
+// clusterSpec converts a GKE cluster config or upstream spec to a helpers.ClusterSpec
+func clusterSpec(config *management.GKEClusterConfigSpec) helpers.ClusterSpec {
+	var spec helpers.ClusterSpec
+	if config == nil {
+		return spec
+	}
+	spec.KubernetesVersion = pointer.StringDeref(config.KubernetesVersion, "")
+	if config.Labels != nil {
+		spec.Tags = *config.Labels
+	}
+	if config.LoggingService != nil {
+		spec.Logging = []string{*config.LoggingService}
+	}
+	if config.PrivateClusterConfig != nil {
+		spec.PrivateAccess = pointer.Bool(config.PrivateClusterConfig.EnablePrivateEndpoint)
+	}
+	if config.NodePools != nil {
+		for _, np := range *config.NodePools {
+			nodePool := helpers.NodePoolSpec{
+				Name:              pointer.StringDeref(np.Name, ""),
+				NodeCount:         pointer.Int64Deref(np.InitialNodeCount, 0),
+				KubernetesVersion: pointer.StringDeref(np.Version, ""),
+			}
+			if np.Config != nil {
+				nodePool.Labels = np.Config.Labels
+			}
+			if np.Autoscaling != nil {
+				nodePool.Autoscaling = pointer.Bool(np.Autoscaling.Enabled)
+				if np.Autoscaling.Enabled {
+					nodePool.MinCount = pointer.Int64(np.Autoscaling.MinNodeCount)
+					nodePool.MaxCount = pointer.Int64(np.Autoscaling.MaxNodeCount)
+				}
+			}
+			spec.NodePools = append(spec.NodePools, nodePool)
+		}
+	}
+	return spec
+}
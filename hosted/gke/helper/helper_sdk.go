@@ -0,0 +1,282 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/container/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iam/v1"
+	"google.golang.org/api/option"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// <==============================================================================GCLOUD SDK==============================>
+// The functions below implement the gcloud CLI helpers when helpers.UseCloudSDK() is true;
+// they are called by the *OnGCloud functions and should not be used directly.
+
+var (
+	// googleClientOptions returns the options passed to every Google API client; unit tests use it to point the clients to a fake server
+	googleClientOptions = func() []option.ClientOption {
+		return []option.ClientOption{option.WithCredentialsJSON([]byte(helpers.Config.GKE.Credentials))}
+	}
+	// googlePollInterval is the interval between two checks of a GKE operation
+	googlePollInterval = 15 * time.Second
+)
+
+func newGKEService(ctx context.Context) (*container.Service, error) {
+	service, err := container.NewService(ctx, googleClientOptions()...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create GKE client")
+	}
+	return service, nil
+}
+
+func gkeLocationName(project, zone string) string {
+	return fmt.Sprintf("projects/%s/locations/%s", project, zone)
+}
+
+func gkeClusterName(project, zone, clusterName string) string {
+	return fmt.Sprintf("%s/clusters/%s", gkeLocationName(project, zone), clusterName)
+}
+
+// waitForGKEOperation waits until the operation is done and returns its error, if any
+func waitForGKEOperation(ctx context.Context, service *container.Service, project, zone string, operation *container.Operation) error {
+	name := fmt.Sprintf("%s/operations/%s", gkeLocationName(project, zone), operation.Name)
+	for deadline := time.Now().Add(helpers.Timeout); time.Now().Before(deadline); {
+		if operation.Status == "DONE" {
+			if operation.Error != nil {
+				return fmt.Errorf("operation %s failed: %s", operation.Name, operation.Error.Message)
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for operation %s: %w", operation.Name, context.Cause(ctx))
+		case <-time.After(googlePollInterval):
+		}
+		var err error
+		if operation, err = service.Projects.Locations.Operations.Get(name).Context(ctx).Do(); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("timed out waiting for operation %s", operation.Name)
+}
+
+// GetGKEClusterOnGCloud returns the GKE cluster as reported by the Google API; it always uses the Google SDK
+func GetGKEClusterOnGCloud(ctx context.Context, zone, project, clusterName string) (*container.Cluster, error) {
+	service, err := newGKEService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cluster, err := service.Projects.Locations.Clusters.Get(gkeClusterName(project, zone, clusterName)).Context(ctx).Do()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get cluster")
+	}
+	return cluster, nil
+}
+
+// gcloudClusterSpec converts a GKE cluster as reported by the Google API to a helpers.ClusterSpec
+func gcloudClusterSpec(cluster *container.Cluster) helpers.ClusterSpec {
+	spec := helpers.ClusterSpec{
+		KubernetesVersion: cluster.CurrentMasterVersion,
+		Tags:              cluster.ResourceLabels,
+		Logging:           []string{cluster.LoggingService},
+		PrivateAccess:     pointer.Bool(false),
+	}
+	if spec.Tags == nil {
+		spec.Tags = map[string]string{}
+	}
+	if cluster.PrivateClusterConfig != nil {
+		spec.PrivateAccess = pointer.Bool(cluster.PrivateClusterConfig.EnablePrivateEndpoint)
+	}
+	for _, np := range cluster.NodePools {
+		nodePool := helpers.NodePoolSpec{
+			Name:              np.Name,
+			NodeCount:         np.InitialNodeCount,
+			KubernetesVersion: np.Version,
+			Labels:            map[string]string{},
+			Autoscaling:       pointer.Bool(false),
+		}
+		if np.Config != nil && np.Config.Labels != nil {
+			nodePool.Labels = np.Config.Labels
+		}
+		if np.Autoscaling != nil && np.Autoscaling.Enabled {
+			nodePool.Autoscaling = pointer.Bool(true)
+			nodePool.MinCount = pointer.Int64(np.Autoscaling.MinNodeCount)
+			nodePool.MaxCount = pointer.Int64(np.Autoscaling.MaxNodeCount)
+		}
+		spec.NodePools = append(spec.NodePools, nodePool)
+	}
+	return spec
+}
+
+func createGKEClusterWithSDK(ctx context.Context, zone, clusterName, project, k8sVersion string, labels map[string]string, extraArgs []string) error {
+	args, err := helpers.ParseSDKArgs(extraArgs, []string{"--num-nodes"}, nil)
+	if err != nil {
+		return err
+	}
+	var nodes int64 = 1
+	if value, ok := args["--num-nodes"]; ok {
+		if nodes, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return err
+		}
+	}
+	service, err := newGKEService(ctx)
+	if err != nil {
+		return err
+	}
+	operation, err := service.Projects.Locations.Clusters.Create(gkeLocationName(project, zone), &container.CreateClusterRequest{
+		Cluster: &container.Cluster{
+			Name:                           clusterName,
+			InitialClusterVersion:          k8sVersion,
+			ResourceLabels:                 labels,
+			Network:                        "default",
+			ReleaseChannel:                 &container.ReleaseChannel{Channel: "UNSPECIFIED"},
+			MasterAuthorizedNetworksConfig: &container.MasterAuthorizedNetworksConfig{Enabled: false},
+			NodePools: []*container.NodePool{{
+				Name:             "default-pool",
+				InitialNodeCount: nodes,
+				Config:           &container.NodeConfig{MachineType: "n2-standard-2", DiskSizeGb: 100},
+			}},
+		},
+	}).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	return waitForGKEOperation(ctx, service, project, zone, operation)
+}
+
+// clusterExistsWithSDK returns true if the cluster is in RUNNING or PROVISIONING state, as ClusterExistsOnGCloud does
+func clusterExistsWithSDK(ctx context.Context, clusterName, project, zone string) (bool, error) {
+	service, err := newGKEService(ctx)
+	if err != nil {
+		return false, err
+	}
+	cluster, err := service.Projects.Locations.Clusters.Get(gkeClusterName(project, zone, clusterName)).Context(ctx).Do()
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return cluster.Status == "RUNNING" || cluster.Status == "PROVISIONING", nil
+}
+
+func listGKEClustersWithSDK(ctx context.Context, project string) ([]helpers.CloudCluster, error) {
+	service, err := newGKEService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the location "-" matches all the zones and regions
+	response, err := service.Projects.Locations.Clusters.List(gkeLocationName(project, "-")).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	var clusters []helpers.CloudCluster
+	for _, cluster := range response.Clusters {
+		clusters = append(clusters, gkeCloudCluster(cluster))
+	}
+	return clusters, nil
+}
+
+func addNodePoolWithSDK(ctx context.Context, clusterName, zone, project, npName string, extraArgs []string) error {
+	args, err := helpers.ParseSDKArgs(extraArgs, []string{"--image-type"}, nil)
+	if err != nil {
+		return err
+	}
+	service, err := newGKEService(ctx)
+	if err != nil {
+		return err
+	}
+	operation, err := service.Projects.Locations.Clusters.NodePools.Create(gkeClusterName(project, zone, clusterName), &container.CreateNodePoolRequest{
+		NodePool: &container.NodePool{
+			Name:             npName,
+			InitialNodeCount: 1,
+			Autoscaling:      &container.NodePoolAutoscaling{Enabled: true, MaxNodeCount: 1, MinNodeCount: 0, ForceSendFields: []string{"MinNodeCount"}},
+			Config:           &container.NodeConfig{ImageType: args["--image-type"]},
+		},
+	}).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	return waitForGKEOperation(ctx, service, project, zone, operation)
+}
+
+func deleteNodePoolWithSDK(ctx context.Context, zone, project, clusterName, poolName string) error {
+	service, err := newGKEService(ctx)
+	if err != nil {
+		return err
+	}
+	operation, err := service.Projects.Locations.Clusters.NodePools.Delete(gkeClusterName(project, zone, clusterName) + "/nodePools/" + poolName).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	return waitForGKEOperation(ctx, service, project, zone, operation)
+}
+
+func resizeNodePoolWithSDK(ctx context.Context, zone, project, clusterName, poolName string, nodeCount int64) error {
+	service, err := newGKEService(ctx)
+	if err != nil {
+		return err
+	}
+	operation, err := service.Projects.Locations.Clusters.NodePools.SetSize(gkeClusterName(project, zone, clusterName)+"/nodePools/"+poolName, &container.SetNodePoolSizeRequest{NodeCount: nodeCount, ForceSendFields: []string{"NodeCount"}}).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	return waitForGKEOperation(ctx, service, project, zone, operation)
+}
+
+func upgradeGKEClusterWithSDK(ctx context.Context, zone, clusterName, project, k8sVersion string, upgradeNodePool bool, nodePoolName string, extraArgs []string) error {
+	args, err := helpers.ParseSDKArgs(extraArgs, nil, []string{"--async"})
+	if err != nil {
+		return err
+	}
+	service, err := newGKEService(ctx)
+	if err != nil {
+		return err
+	}
+	var operation *container.Operation
+	if upgradeNodePool {
+		operation, err = service.Projects.Locations.Clusters.NodePools.Update(gkeClusterName(project, zone, clusterName)+"/nodePools/"+nodePoolName, &container.UpdateNodePoolRequest{NodeVersion: k8sVersion}).Context(ctx).Do()
+	} else {
+		operation, err = service.Projects.Locations.Clusters.UpdateMaster(gkeClusterName(project, zone, clusterName), &container.UpdateMasterRequest{MasterVersion: k8sVersion}).Context(ctx).Do()
+	}
+	if err != nil || args["--async"] != "" {
+		return err
+	}
+	return waitForGKEOperation(ctx, service, project, zone, operation)
+}
+
+// deleteGKEClusterWithSDK does not wait for the deletion to complete, as `gcloud container clusters delete --async`
+func deleteGKEClusterWithSDK(ctx context.Context, zone, project, clusterName string) error {
+	service, err := newGKEService(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = service.Projects.Locations.Clusters.Delete(gkeClusterName(project, zone, clusterName)).Context(ctx).Do()
+	return err
+}
+
+func enableDisableServiceAccountWithSDK(ctx context.Context, email, project, op string) error {
+	service, err := iam.NewService(ctx, googleClientOptions()...)
+	if err != nil {
+		return errors.Wrap(err, "Failed to create IAM client")
+	}
+	name := fmt.Sprintf("projects/%s/serviceAccounts/%s", project, email)
+	if op == "enable" {
+		_, err = service.Projects.ServiceAccounts.Enable(name, &iam.EnableServiceAccountRequest{}).Context(ctx).Do()
+	} else {
+		_, err = service.Projects.ServiceAccounts.Disable(name, &iam.DisableServiceAccountRequest{}).Context(ctx).Do()
+	}
+	return err
+}
+
+// <==============================================================================GCLOUD SDK (end)==============================>
@@ -0,0 +1,166 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/container/v1"
+	"google.golang.org/api/option"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("GCloud SDK", func() {
+	const (
+		clusterName = "gke-sdk"
+		zone        = "asia-south2-c"
+		project     = "gke-project"
+		location    = "/v1/projects/" + project + "/locations/" + zone
+	)
+	var (
+		requests      []string
+		bodies        map[string]map[string]any
+		clusterStatus string
+		operationDone bool
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		bodies = map[string]map[string]any{}
+		clusterStatus = "RUNNING"
+		operationDone = false
+
+		mux := http.NewServeMux()
+		handle := func(pattern string, response func() (int, any)) {
+			mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+					body := map[string]any{}
+					Expect(json.Unmarshal(data, &body)).To(Succeed())
+					bodies[r.Method+" "+r.URL.Path] = body
+				}
+				code, body := response()
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(code)
+				Expect(json.NewEncoder(w).Encode(body)).To(Succeed())
+			})
+		}
+		handle("GET "+location+"/clusters/{name}", func() (int, any) {
+			if clusterStatus == "" {
+				return http.StatusNotFound, map[string]any{"error": map[string]any{"code": 404, "message": "cluster not found"}}
+			}
+			return http.StatusOK, map[string]any{"name": clusterName, "status": clusterStatus}
+		})
+		handle("POST "+location+"/clusters", func() (int, any) {
+			return http.StatusOK, map[string]any{"name": "operation-1", "status": "RUNNING"}
+		})
+		handle("GET "+location+"/operations/operation-1", func() (int, any) {
+			if !operationDone {
+				operationDone = true
+				return http.StatusOK, map[string]any{"name": "operation-1", "status": "RUNNING"}
+			}
+			return http.StatusOK, map[string]any{"name": "operation-1", "status": "DONE"}
+		})
+		handle("POST /v1/projects/"+project+"/serviceAccounts/{email}", func() (int, any) {
+			return http.StatusOK, map[string]any{}
+		})
+		srv := httptest.NewServer(mux)
+		DeferCleanup(srv.Close)
+
+		DeferCleanup(func(options func() []option.ClientOption, interval time.Duration) {
+			googleClientOptions, googlePollInterval = options, interval
+		}, googleClientOptions, googlePollInterval)
+		googleClientOptions = func() []option.ClientOption {
+			return []option.ClientOption{option.WithEndpoint(srv.URL), option.WithoutAuthentication()}
+		}
+		googlePollInterval = 10 * time.Millisecond
+
+		DeferCleanup(func(backend string) { helpers.CloudBackend = backend }, helpers.CloudBackend)
+		helpers.CloudBackend = helpers.SDKBackend
+	})
+
+	It("CreateGKEClusterOnGCloud creates the cluster and waits for the operation", func(ctx SpecContext) {
+		DeferCleanup(os.Setenv, "KUBECONFIG", os.Getenv("KUBECONFIG"))
+		DeferCleanup(func() {
+			_ = os.Remove(os.Getenv(helpers.DownstreamKubeconfig(clusterName)))
+			_ = os.Unsetenv(helpers.DownstreamKubeconfig(clusterName))
+		})
+
+		Expect(CreateGKEClusterOnGCloud(ctx, zone, clusterName, project, "1.31.5-gke.1000", "--num-nodes", "2")).To(Succeed())
+
+		Expect(requests).To(Equal([]string{
+			"POST " + location + "/clusters",
+			"GET " + location + "/operations/operation-1",
+			"GET " + location + "/operations/operation-1",
+		}))
+		cluster := bodies["POST "+location+"/clusters"]["cluster"]
+		Expect(cluster).To(HaveKeyWithValue("initialClusterVersion", "1.31.5-gke.1000"))
+		Expect(cluster).To(HaveKeyWithValue("resourceLabels", HaveLen(len(helpers.GetCommonMetadataLabels()))))
+		Expect(cluster).To(HaveKeyWithValue("nodePools", ConsistOf(HaveKeyWithValue("initialNodeCount", 2.0))))
+	})
+
+	DescribeTable("ClusterExistsOnGCloud",
+		func(ctx SpecContext, status string, expected bool) {
+			clusterStatus = status
+
+			exists, err := ClusterExistsOnGCloud(ctx, clusterName, project, zone)
+			Expect(err).To(BeNil())
+			Expect(exists).To(Equal(expected))
+		},
+		Entry("returns true for a running cluster", "RUNNING", true),
+		Entry("returns true for a provisioning cluster", "PROVISIONING", true),
+		Entry("returns false for a stopping cluster", "STOPPING", false),
+		Entry("returns false if the cluster does not exist", "", false),
+	)
+
+	It("EnableDisableServiceAccountOnGCloud disables the service account", func(ctx SpecContext) {
+		Expect(EnableDisableServiceAccountOnGCloud(ctx, "sa", project, "disable")).To(Succeed())
+
+		Expect(requests).To(Equal([]string{"POST /v1/projects/" + project + "/serviceAccounts/sa@" + project + ".iam.gserviceaccount.com:disable"}))
+	})
+
+	It("rejects the extra arguments it cannot translate", func(ctx SpecContext) {
+		Expect(AddNodePoolOnGCloud(ctx, clusterName, zone, project, "np1", "--spot")).To(MatchError(ContainSubstring(`argument "--spot" is not supported by the sdk cloud backend`)))
+		Expect(requests).To(BeEmpty())
+	})
+	It("gcloudClusterSpec normalizes the cluster reported by the API", func() {
+		spec := gcloudClusterSpec(&container.Cluster{
+			CurrentMasterVersion: "1.31.5-gke.1000",
+			LoggingService:       "logging.googleapis.com/kubernetes",
+			NodePools: []*container.NodePool{
+				{Name: "np1", InitialNodeCount: 2, Version: "1.31.5-gke.1000", Autoscaling: &container.NodePoolAutoscaling{Enabled: true, MinNodeCount: 1, MaxNodeCount: 3}},
+				{Name: "np2", InitialNodeCount: 1, Config: &container.NodeConfig{Labels: map[string]string{"team": "qa"}}},
+			},
+		})
+
+		Expect(spec.KubernetesVersion).To(Equal("1.31.5-gke.1000"))
+		Expect(spec.Tags).To(BeEmpty())
+		Expect(spec.Logging).To(Equal([]string{"logging.googleapis.com/kubernetes"}))
+		Expect(*spec.PrivateAccess).To(BeFalse())
+		Expect(spec.NodePools).To(Equal([]helpers.NodePoolSpec{
+			{Name: "np1", NodeCount: 2, KubernetesVersion: "1.31.5-gke.1000", Labels: map[string]string{}, Autoscaling: pointer.Bool(true), MinCount: pointer.Int64(1), MaxCount: pointer.Int64(3)},
+			{Name: "np2", NodeCount: 1, Labels: map[string]string{"team": "qa"}, Autoscaling: pointer.Bool(false)},
+		}))
+	})
+})
@@ -1,8 +1,6 @@
 package k8s_chart_support_test
 
 import (
-	"fmt"
-
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
@@ -16,30 +14,18 @@ var _ = Describe("K8sChartSupportImport", func() {
 		cluster *management.Cluster
 	)
 
-	BeforeEach(func() {
-		err := helper.CreateGKEClusterOnGCloud(zone, clusterName, project, k8sVersion)
+	BeforeEach(func(specCtx SpecContext) {
+		err := helper.CreateGKEClusterOnGCloud(specCtx, zone, clusterName, project, k8sVersion)
 		Expect(err).To(BeNil())
 
-		cluster, err = helper.ImportGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, zone, project)
+		cluster, err = helper.ImportGKEHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, zone, project)
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 	})
 
-	AfterEach(func() {
-		if ctx.ClusterCleanup && cluster != nil {
-			err := helper.DeleteGKEHostCluster(cluster, ctx.RancherAdminClient)
-			Expect(err).To(BeNil())
-			err = helper.DeleteGKEClusterOnGCloud(zone, project, clusterName)
-			Expect(err).To(BeNil())
-
-		} else {
-			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-		}
-	})
-
-	It("should successfully test k8s chart support import", func() {
+	It("should successfully test k8s chart support import", func(specCtx SpecContext) {
 		testCaseID = 65 // Report to Qase
-		commonChartSupport(ctx.RancherAdminClient, cluster)
+		commonChartSupport(specCtx, ctx.RancherAdminClient, cluster)
 	})
 })
@@ -1,6 +1,7 @@
 package k8s_chart_support_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -14,10 +15,10 @@ import (
 
 	. "github.com/rancher-sandbox/qase-ginkgo"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	"github.com/rancher/shepherd/extensions/clusters"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var (
@@ -34,93 +35,99 @@ func TestK8sChartSupport(t *testing.T) {
 }
 
 var _ = SynchronizedBeforeSuite(func() []byte {
-	helpers.CommonSynchronizedBeforeSuite()
+	suite.CommonSynchronizedBeforeSuite()
 	return nil
-}, func() {
+}, func(specCtx SpecContext) {
 	Expect(helpers.Kubeconfig).ToNot(BeEmpty())
 
 	By("Adding the necessary chart repos", func() {
-		helpers.AddRancherCharts()
+		suite.AddRancherCharts(specCtx)
 	})
 
-	ctx = helpers.CommonBeforeSuite()
+	ctx = suite.CommonBeforeSuite()
 })
 
-var _ = BeforeEach(func() {
+var _ = BeforeEach(func(specCtx SpecContext) {
+	// deferred rather than run in AfterEach so that it runs after the teardown of the downstream cluster; see helpers.CleanupRegistry
+	DeferCleanup(func(specCtx SpecContext) {
+		By("Uninstalling the existing operator charts", func() {
+			suite.UninstallOperatorCharts(specCtx)
+		})
+	})
+
 	var err error
 	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
 
-	k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", false)
+	k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", false)
 	Expect(err).To(BeNil())
 
 	GinkgoLogr.Info(fmt.Sprintf("Using GKE version %s for cluster %s", k8sVersion, clusterName))
 })
 
-var _ = AfterEach(func() {
-	By("Uninstalling the existing operator charts", func() {
-		helpers.UninstallOperatorCharts()
-	})
-})
-
 var _ = ReportBeforeEach(func(report SpecReport) {
 	// Reset case ID
 	testCaseID = -1
 })
 
-var _ = ReportAfterEach(func(report SpecReport) {
-	// Add result in Qase if asked
+var _ = ReportAfterEach(func(specCtx SpecContext, report SpecReport) {
+	// Add result in Qase and in the run report if asked
 	Qase(testCaseID, report)
+	suite.WriteRunReport(specCtx, report, testCaseID, ctx.RancherAdminClient)
 })
 
 // commonChartSupport runs the common checks required for testing chart support
-func commonChartSupport(client *rancher.Client, cluster *management.Cluster) {
+func commonChartSupport(specCtx context.Context, client *rancher.Client, cluster *management.Cluster) {
 	var originalChartVersion string
 	By("checking the chart version", func() {
-		originalChartVersion = helpers.GetCurrentOperatorChartVersion()
+		var err error
+		originalChartVersion, err = helpers.GetCurrentOperatorChartVersion(specCtx)
+		Expect(err).To(BeNil())
 		Expect(originalChartVersion).ToNot(BeEmpty())
 		GinkgoLogr.Info("Original chart version: " + originalChartVersion)
 	})
 
 	var downgradedVersion string
 	By("obtaining a version to downgrade", func() {
-		downgradedVersion = helpers.GetDowngradeOperatorChartVersion(originalChartVersion)
+		var err error
+		downgradedVersion, err = helpers.GetDowngradeOperatorChartVersion(specCtx, originalChartVersion)
+		Expect(err).To(BeNil())
 		Expect(downgradedVersion).ToNot(BeEmpty())
 		GinkgoLogr.Info("Downgrading to version: " + downgradedVersion)
 	})
 
 	By("downgrading the chart version", func() {
-		helpers.DowngradeProviderChart(downgradedVersion)
+		suite.DowngradeProviderChart(specCtx, downgradedVersion)
 	})
 
 	By("making a change to the cluster to validate functionality after chart downgrade", func() {
 		configNodePools := *cluster.GKEConfig.NodePools
 		initialNodeCount := *configNodePools[0].InitialNodeCount
 		var err error
-		cluster, err = helper.ScaleNodePool(cluster, client, initialNodeCount+1, true, true)
+		cluster, err = helper.ScaleNodePool(specCtx, cluster, client, initialNodeCount+1, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("uninstalling the operator chart", func() {
-		helpers.UninstallOperatorCharts()
+		suite.UninstallOperatorCharts(specCtx)
 	})
 
 	By("making a change(adding a nodepool) to the cluster to re-install the operator and validating it is re-installed to the latest/original version", func() {
 		currentNodePoolNumber := len(*cluster.GKEConfig.NodePools)
 		var err error
-		cluster, err = helper.AddNodePool(cluster, client, 1, "", false, false)
+		cluster, err = helper.AddNodePool(specCtx, cluster, client, 1, "", false, false)
 		Expect(err).To(BeNil())
 
 		Expect(len(*cluster.GKEConfig.NodePools)).To(BeNumerically("==", currentNodePoolNumber+1))
 
 		By("ensuring that the chart is re-installed to the latest/original version", func() {
-			helpers.WaitUntilOperatorChartInstallation(originalChartVersion, "", 0)
+			suite.WaitUntilOperatorChartInstallation(specCtx, originalChartVersion, "", 0)
 		})
 
 		By("ensuring that rancher is up", func() {
-			helpers.CheckRancherDeployments(kubectl.New())
+			suite.CheckRancherDeployments(specCtx, kubectl.New())
 		})
 
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpdated(specCtx, client, cluster.ID)
 		Expect(err).To(BeNil())
 		Eventually(func() int {
 			GinkgoLogr.Info("Waiting for the total nodepool count to increase in GKEStatus.UpstreamSpec ...")
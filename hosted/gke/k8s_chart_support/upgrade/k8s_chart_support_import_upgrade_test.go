@@ -16,32 +16,21 @@ var _ = Describe("K8sChartSupportUpgradeImport", func() {
 		cluster *management.Cluster
 	)
 
-	BeforeEach(func() {
-		err := helper.CreateGKEClusterOnGCloud(zone, clusterName, project, k8sVersion)
+	BeforeEach(func(specCtx SpecContext) {
+		err := helper.CreateGKEClusterOnGCloud(specCtx, zone, clusterName, project, k8sVersion)
 		Expect(err).To(BeNil())
 
-		cluster, err = helper.ImportGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, zone, project)
+		cluster, err = helper.ImportGKEHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, zone, project)
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 	})
 
-	AfterEach(func() {
-		if ctx.ClusterCleanup && cluster != nil {
-			err := helper.DeleteGKEHostCluster(cluster, ctx.RancherAdminClient)
-			Expect(err).To(BeNil())
-			err = helper.DeleteGKEClusterOnGCloud(zone, project, clusterName)
-			Expect(err).To(BeNil())
-		} else {
-			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-		}
-	})
-
-	It("should successfully test k8s chart support import in an upgrade scenario", func() {
+	It("should successfully test k8s chart support import in an upgrade scenario", func(specCtx SpecContext) {
 		GinkgoLogr.Info(fmt.Sprintf("Testing K8s %s chart support for import on Rancher upgraded from %s to %s", helpers.K8sUpgradedMinorVersion, helpers.RancherFullVersion, helpers.RancherUpgradeFullVersion))
 
 		testCaseID = 64 // Report to Qase
-		commonChartSupportUpgrade(&ctx, cluster, clusterName, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
+		commonChartSupportUpgrade(specCtx, &ctx, cluster, clusterName, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
 	})
 
 })
@@ -1,6 +1,7 @@
 package k8s_chart_support_upgrade_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -13,7 +14,6 @@ import (
 	. "github.com/rancher-sandbox/qase-ginkgo"
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	"github.com/rancher/shepherd/extensions/clusters"
 	nodestat "github.com/rancher/shepherd/extensions/nodes"
 	"github.com/rancher/shepherd/extensions/workloads/pods"
 	"github.com/rancher/shepherd/pkg/config"
@@ -21,6 +21,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var (
@@ -37,7 +38,21 @@ func TestK8sChartSupportUpgrade(t *testing.T) {
 	RunSpecs(t, "K8sChartSupportUpgrade Suite")
 }
 
-var _ = BeforeEach(func() {
+var _ = BeforeEach(func(specCtx SpecContext) {
+	// deferred rather than run in AfterEach so that it runs after the teardown of the downstream cluster; see helpers.CleanupRegistry
+	DeferCleanup(func(specCtx SpecContext) {
+		// The test must restore the env to its original state, so we install rancher back to its original version and uninstall the operator charts
+		By(fmt.Sprintf("Installing Rancher back to its original version %s", helpers.RancherFullVersion), func() {
+			rancherChannel, rancherVersion, rancherHeadVersion := suite.GetRancherVersions(helpers.RancherFullVersion)
+			suite.InstallRancherManager(specCtx, k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
+			suite.CheckRancherDeployments(specCtx, k)
+		})
+
+		By("Uninstalling the existing operator charts", func() {
+			suite.UninstallOperatorCharts(specCtx)
+		})
+	})
+
 	// For upgrade tests, the rancher version should not be an unreleased version (for e.g. 2.9-head)
 	Expect(helpers.RancherFullVersion).To(SatisfyAll(Not(BeEmpty()), Not(ContainSubstring("devel"))))
 
@@ -46,17 +61,17 @@ var _ = BeforeEach(func() {
 	Expect(helpers.Kubeconfig).ToNot(BeEmpty())
 
 	By("Adding the necessary chart repos", func() {
-		helpers.AddRancherCharts()
+		suite.AddRancherCharts(specCtx)
 	})
 
 	By(fmt.Sprintf("Installing Rancher Manager %s", helpers.RancherFullVersion), func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
-		helpers.CheckRancherDeployments(k)
+		rancherChannel, rancherVersion, rancherHeadVersion := suite.GetRancherVersions(helpers.RancherFullVersion)
+		suite.InstallRancherManager(specCtx, k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
+		suite.CheckRancherDeployments(specCtx, k)
 	})
 
-	helpers.CommonSynchronizedBeforeSuite()
-	ctx = helpers.CommonBeforeSuite()
+	suite.CommonSynchronizedBeforeSuite()
+	ctx = suite.CommonBeforeSuite()
 
 	By("creating and using a more permanent token", func() {
 		token, err := ctx.RancherAdminClient.Management.Token.Create(&management.Token{})
@@ -75,49 +90,39 @@ var _ = BeforeEach(func() {
 
 	var err error
 	// For k8s chart support upgrade we want to begin with the default k8s version; we will upgrade rancher and then upgrade k8s to the default available there.
-	k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", false)
+	k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", false)
 	Expect(err).To(BeNil())
 	GinkgoLogr.Info(fmt.Sprintf("Using GKE version %s for cluster %s", k8sVersion, clusterName))
 })
 
-var _ = AfterEach(func() {
-	// The test must restore the env to its original state, so we install rancher back to its original version and uninstall the operator charts
-	By(fmt.Sprintf("Installing Rancher back to its original version %s", helpers.RancherFullVersion), func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
-		helpers.CheckRancherDeployments(k)
-	})
-
-	By("Uninstalling the existing operator charts", func() {
-		helpers.UninstallOperatorCharts()
-	})
-})
-
 var _ = ReportBeforeEach(func(report SpecReport) {
 	// Reset case ID
 	testCaseID = -1
 })
 
-var _ = ReportAfterEach(func(report SpecReport) {
-	// Add result in Qase if asked
+var _ = ReportAfterEach(func(specCtx SpecContext, report SpecReport) {
+	// Add result in Qase and in the run report if asked
 	Qase(testCaseID, report)
+	suite.WriteRunReport(specCtx, report, testCaseID, ctx.RancherAdminClient)
 })
 
 // commonChartSupportUpgrade runs the common checks required for testing chart support
-func commonChartSupportUpgrade(ctx *helpers.RancherContext, cluster *management.Cluster, clusterName, rancherUpgradedVersion, k8sUpgradedVersion string) {
-	helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+func commonChartSupportUpgrade(specCtx context.Context, ctx *helpers.RancherContext, cluster *management.Cluster, clusterName, rancherUpgradedVersion, k8sUpgradedVersion string) {
+	suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 
 	var originalChartVersion string
 	By("checking the chart version", func() {
-		originalChartVersion = helpers.GetCurrentOperatorChartVersion()
+		var err error
+		originalChartVersion, err = helpers.GetCurrentOperatorChartVersion(specCtx)
+		Expect(err).To(BeNil())
 		Expect(originalChartVersion).ToNot(BeEmpty())
 		GinkgoLogr.Info("Original chart version: " + originalChartVersion)
 	})
 
 	By("upgrading rancher", func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(rancherUpgradedVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
-		helpers.CheckRancherDeployments(k)
+		rancherChannel, rancherVersion, rancherHeadVersion := suite.GetRancherVersions(rancherUpgradedVersion)
+		suite.InstallRancherManager(specCtx, k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
+		suite.CheckRancherDeployments(specCtx, k)
 
 		By("ensuring operator pods are also up", func() {
 			Eventually(func() error {
@@ -148,8 +153,10 @@ func commonChartSupportUpgrade(ctx *helpers.RancherContext, cluster *management.
 	var upgradedChartVersion string
 
 	By("checking the chart version and validating it is > the old version", func() {
-		helpers.WaitUntilOperatorChartInstallation(originalChartVersion, "==", 1)
-		upgradedChartVersion = helpers.GetCurrentOperatorChartVersion()
+		suite.WaitUntilOperatorChartInstallation(specCtx, originalChartVersion, "==", 1)
+		var err error
+		upgradedChartVersion, err = helpers.GetCurrentOperatorChartVersion(specCtx)
+		Expect(err).To(BeNil())
 		GinkgoLogr.Info("Upgraded chart version: " + upgradedChartVersion)
 
 	})
@@ -158,7 +165,7 @@ func commonChartSupportUpgrade(ctx *helpers.RancherContext, cluster *management.
 		var err error
 		cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 		Expect(err).To(BeNil())
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		suite.ClusterIsReadyChecks(specCtx, cluster, ctx.RancherAdminClient, clusterName)
 
 		// since no changes have been made to the cluster so far, we need reinstantiate GKEConfig after fetching the cluster
 		if helpers.IsImport {
@@ -167,12 +174,13 @@ func commonChartSupportUpgrade(ctx *helpers.RancherContext, cluster *management.
 	})
 
 	By(fmt.Sprintf("fetching a list of available k8s versions and ensuring v%s is present in the list and upgrading the cluster to it", k8sUpgradedVersion), func() {
-		versions, err := helper.ListGKEAvailableVersions(ctx.RancherAdminClient, cluster.ID)
+		versions, err := helper.ListGKEAvailableVersions(specCtx, ctx.RancherAdminClient, cluster.ID)
 		Expect(err).To(BeNil())
 		Expect(versions).ToNot(BeEmpty())
 		GinkgoLogr.Info(fmt.Sprintf("Available GKE versions: %v", versions))
 
-		highestSupportedVersionByUI := helpers.HighestK8sMinorVersionSupportedByUI(ctx.RancherAdminClient)
+		highestSupportedVersionByUI, err := helpers.HighestK8sMinorVersionSupportedByUI(ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
 		var latestVersion string
 		for _, v := range versions {
 			if strings.Contains(v, highestSupportedVersionByUI) {
@@ -182,44 +190,46 @@ func commonChartSupportUpgrade(ctx *helpers.RancherContext, cluster *management.
 		Expect(latestVersion).To(ContainSubstring(k8sUpgradedVersion))
 		Expect(helpers.VersionCompare(latestVersion, cluster.Version.GitVersion)).To(BeNumerically("==", 1))
 
-		cluster, err = helper.UpgradeKubernetesVersion(cluster, latestVersion, ctx.RancherAdminClient, true, true, true)
+		cluster, err = helper.UpgradeKubernetesVersion(specCtx, cluster, latestVersion, ctx.RancherAdminClient, true, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	var downgradeVersion string
 	By("fetching a value to downgrade to", func() {
-		downgradeVersion = helpers.GetDowngradeOperatorChartVersion(upgradedChartVersion)
+		var err error
+		downgradeVersion, err = helpers.GetDowngradeOperatorChartVersion(specCtx, upgradedChartVersion)
+		Expect(err).To(BeNil())
 	})
 
 	By("downgrading the chart version", func() {
-		helpers.DowngradeProviderChart(downgradeVersion)
+		suite.DowngradeProviderChart(specCtx, downgradeVersion)
 	})
 
 	By("making a change to the cluster (scaling nodepool up) to validate functionality after chart downgrade", func() {
 		configNodePools := *cluster.GKEConfig.NodePools
 		initialNodeCount := *configNodePools[0].InitialNodeCount
 		var err error
-		cluster, err = helper.ScaleNodePool(cluster, ctx.RancherAdminClient, initialNodeCount+1, true, true)
+		cluster, err = helper.ScaleNodePool(specCtx, cluster, ctx.RancherAdminClient, initialNodeCount+1, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("uninstalling the operator chart", func() {
-		helpers.UninstallOperatorCharts()
+		suite.UninstallOperatorCharts(specCtx)
 	})
 
 	By("making a change(adding a nodepool) to the cluster to re-install the operator and validating it is re-installed to the latest/upgraded version", func() {
 		currentNodePoolNumber := len(*cluster.GKEConfig.NodePools)
 		var err error
-		cluster, err = helper.AddNodePool(cluster, ctx.RancherAdminClient, 1, "", false, false)
+		cluster, err = helper.AddNodePool(specCtx, cluster, ctx.RancherAdminClient, 1, "", false, false)
 		Expect(err).To(BeNil())
 
 		Expect(len(*cluster.GKEConfig.NodePools)).To(BeNumerically("==", currentNodePoolNumber+1))
 
 		By("ensuring that the chart is re-installed to the latest/upgraded version", func() {
-			helpers.WaitUntilOperatorChartInstallation(upgradedChartVersion, "", 0)
+			suite.WaitUntilOperatorChartInstallation(specCtx, upgradedChartVersion, "", 0)
 		})
 
-		err = clusters.WaitClusterToBeUpgraded(ctx.RancherAdminClient, cluster.ID)
+		err = helpers.WaitClusterToBeUpdated(specCtx, ctx.RancherAdminClient, cluster.ID)
 		Expect(err).To(BeNil())
 
 		Eventually(func() int {
@@ -15,8 +15,8 @@ limitations under the License.
 package p0_test
 
 import (
+	"context"
 	"fmt"
-	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -27,84 +27,54 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var _ = Describe("P0Provisioning", func() {
 	for _, testData := range []struct {
 		qaseID    int64
 		isUpgrade bool
-		testBody  func(cluster *management.Cluster, client *rancher.Client, clusterName string)
+		testBody  func(specCtx context.Context, provider helpers.HostedProvider, cluster *management.Cluster, client *rancher.Client, clusterName string)
 		testTitle string
 	}{
-		{
-			qaseID:    8,
-			isUpgrade: false,
-			testBody:  p0NodesChecks,
-			testTitle: "should successfully provision the zonal cluster & add, delete, scale nodepool",
-		},
-		{
-			qaseID:    11,
-			isUpgrade: true,
-			testBody:  p0upgradeK8sVersionChecks,
-			testTitle: "should be able to upgrade k8s version of the zonal provisioned cluster",
-		},
 		{
 			qaseID:    300,
 			isUpgrade: false,
-			testBody:  p0NodesChecks,
+			testBody:  suite.P0NodesChecks,
 			testTitle: "should successfully provision the regional cluster & add, delete, scale nodepool",
 		},
 		{
 			qaseID:    301,
 			isUpgrade: true,
-			testBody:  p0upgradeK8sVersionChecks,
+			testBody:  suite.P0UpgradeK8sVersionChecks,
 			testTitle: "should be able to upgrade k8s version of the regional provisioned cluster",
 		},
 	} {
 		testData := testData
-		When("a cluster is created", func() {
-			BeforeEach(func() {
-				if testData.isUpgrade && helpers.SkipUpgradeTests {
-					Skip(helpers.SkipUpgradeTestsLog)
-				}
-
-				if strings.Contains(testData.testTitle, "regional") {
-					zone = ""
-					updateFunc = func(clusterConfig *gke.ClusterConfig) {
-						clusterConfig.Locations = append(clusterConfig.Locations, helpers.GetGKEZone())
-					}
-				} else {
-					region = ""
+		When("a regional cluster is created", func() {
+			BeforeEach(func(specCtx SpecContext) {
+				if testData.isUpgrade {
+					suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 				}
 
-				k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, project, ctx.CloudCredID, zone, region, testData.isUpgrade)
+				region := helpers.GetGKERegion()
+				k8sVersion, err := helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, helpers.GetGKEProjectID(), ctx.CloudCredID, "", region, testData.isUpgrade)
 				Expect(err).NotTo(HaveOccurred())
 				GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 
-				cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, region, project, updateFunc)
+				updateFunc := func(clusterConfig *gke.ClusterConfig) {
+					clusterConfig.Locations = append(clusterConfig.Locations, helpers.GetGKEZone())
+				}
+				cluster, err = helper.CreateGKEHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, "", region, helpers.GetGKEProjectID(), updateFunc)
 				Expect(err).To(BeNil())
-				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
 			})
-			AfterEach(func() {
-				if ctx.ClusterCleanup {
-					if cluster != nil && cluster.ID != "" {
-						GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
-						err := helper.DeleteGKEHostCluster(cluster, ctx.RancherAdminClient)
-						Expect(err).To(BeNil())
-					}
-				} else {
-					fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-				}
-			})
 
-			It(testData.testTitle, func() {
+			It(testData.testTitle, func(specCtx SpecContext) {
 				testCaseID = testData.qaseID
-				testData.testBody(cluster, ctx.RancherAdminClient, clusterName)
+				testData.testBody(specCtx, helper.Provider{}, cluster, ctx.RancherAdminClient, clusterName)
 			})
-
 		})
-
 	}
-
 })
@@ -15,53 +15,42 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var _ = Describe("P1Provisioning", func() {
-	var _ = BeforeEach(func() {
+	var _ = BeforeEach(func(specCtx SpecContext) {
 		// assigning cluster nil value so that every new test has a fresh value of the variable
 		// this is to avoid using residual value of a cluster in a test that does not use it
 		cluster = nil
 
 		var err error
-		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", false)
+		k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", false)
 		Expect(err).To(BeNil())
 		GinkgoLogr.Info(fmt.Sprintf("While provisioning, using kubernetes version %s for cluster %s", k8sVersion, clusterName))
 	})
 
-	AfterEach(func() {
-		if ctx.ClusterCleanup {
-			if cluster != nil && cluster.ID != "" {
-				GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
-				err := helper.DeleteGKEHostCluster(cluster, ctx.RancherAdminClient)
-				Expect(err).To(BeNil())
-			}
-		} else {
-			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-		}
-	})
-
 	Context("Provisioning a cluster with invalid config", func() {
 
-		It("should fail to provision a cluster when creating cluster with invalid name", func() {
+		It("should fail to provision a cluster when creating cluster with invalid name", func(specCtx SpecContext) {
 			testCaseID = 36
 			var err error
-			cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, "@!invalid-gke-name-@#", ctx.CloudCredID, k8sVersion, zone, "", project, nil)
+			cluster, err = helper.CreateGKEHostedCluster(specCtx, ctx.RancherAdminClient, "@!invalid-gke-name-@#", ctx.CloudCredID, k8sVersion, zone, "", project, nil)
 			Expect(err).ToNot(BeNil())
 			Expect(err.Error()).To(ContainSubstring("InvalidFormat"))
 		})
 
-		It("User should not be able to add cluster with invalid GKE creds in Rancher", func() {
+		It("User should not be able to add cluster with invalid GKE creds in Rancher", func(specCtx SpecContext) {
 			testCaseID = 2
-			invalidCredCheck(cluster, ctx.RancherAdminClient)
+			invalidCredCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("User should not be able to add a cluster using an expired GKE creds", func() {
+		It("User should not be able to add a cluster using an expired GKE creds", func(specCtx SpecContext) {
 			testCaseID = 6
-			expiredCredCheck(cluster, ctx.RancherAdminClient)
+			expiredCredCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should fail to provision a cluster with invalid nodepool name", func() {
+		It("should fail to provision a cluster with invalid nodepool name", func(specCtx SpecContext) {
 			testCaseID = 37
 
 			updateFunc := func(clusterConfig *gke.ClusterConfig) {
@@ -71,18 +60,18 @@ var _ = Describe("P1Provisioning", func() {
 			}
 
 			var err error
-			cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, updateFunc)
+			cluster, err = helper.CreateGKEHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, updateFunc)
 			Expect(err).To(BeNil())
 
-			Eventually(func() bool {
+			Eventually(func() *management.Cluster {
 				clusterState, err := ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 				Expect(err).To(BeNil())
-				return clusterState.Transitioning == "error" && strings.Contains(clusterState.TransitioningMessage, "Invalid value for field \"node_pool.name\"")
-			}, "60s", "2s").Should(BeTrue())
+				return clusterState
+			}, "60s", "2s").Should(suite.HaveTransitionError(helper.ErrInvalidNodePoolName))
 
 		})
 
-		It("should fail to provision a cluster nodepools is nil", func() {
+		It("should fail to provision a cluster nodepools is nil", func(specCtx SpecContext) {
 			testCaseID = 27
 
 			updateFunc := func(clusterConfig *gke.ClusterConfig) {
@@ -90,48 +79,48 @@ var _ = Describe("P1Provisioning", func() {
 			}
 
 			var err error
-			cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, updateFunc)
+			cluster, err = helper.CreateGKEHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, updateFunc)
 			Expect(err).To(BeNil())
 
-			Eventually(func() bool {
+			Eventually(func() *management.Cluster {
 				clusterState, err := ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 				Expect(err).To(BeNil())
-				return clusterState.Transitioning == "error" && strings.Contains(clusterState.TransitioningMessage, "Cluster.initial_node_count must be greater than zero")
-			}, "60s", "2s").Should(BeTrue())
+				return clusterState
+			}, "60s", "2s").Should(suite.HaveTransitionError(helper.ErrZeroInitialNodeCount))
 
 		})
 
-		It("should fail to provision a cluster when nodepools is an empty array", func() {
+		It("should fail to provision a cluster when nodepools is an empty array", func(specCtx SpecContext) {
 			updateFunc := func(clusterConfig *gke.ClusterConfig) {
 				clusterConfig.NodePools = []gke.NodePool{}
 			}
 
 			var err error
-			_, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, updateFunc)
+			_, err = helper.CreateGKEHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, updateFunc)
 			Expect(err).ToNot(BeNil())
 			Expect(err.Error()).To(ContainSubstring("must have at least one node pool"))
 		})
 	})
 
-	It("deleting a cluster while it is in creation state should delete it from rancher and cloud console", func() {
+	It("deleting a cluster while it is in creation state should delete it from rancher and cloud console", func(specCtx SpecContext) {
 		testCaseID = 25
 		var err error
-		cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, nil)
+		cluster, err = helper.CreateGKEHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, nil)
 		Expect(err).To(BeNil())
 
 		// Wait for the cluster to appear on cloud console before deleting it
 		Eventually(func() bool {
-			exists, err := helper.ClusterExistsOnGCloud(clusterName, project, zone)
+			exists, err := helper.ClusterExistsOnGCloud(specCtx, clusterName, project, zone)
 			Expect(err).To(BeNil())
 			return exists
 		}, "1m", "5s").Should(BeTrue())
 
-		err = helper.DeleteGKEHostCluster(cluster, ctx.RancherAdminClient)
+		err = helper.DeleteGKEHostCluster(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 
 		// Wait until the cluster finishes provisioning and then begins deletion process
 		Eventually(func() bool {
-			exists, err := helper.ClusterExistsOnGCloud(clusterName, project, zone)
+			exists, err := helper.ClusterExistsOnGCloud(specCtx, clusterName, project, zone)
 			Expect(err).To(BeNil())
 			return exists
 		}, "10m", "10s").Should(BeFalse())
@@ -142,13 +131,11 @@ var _ = Describe("P1Provisioning", func() {
 		Expect(err.Error()).To(ContainSubstring("not found"))
 	})
 
-	It("should be able to create a cluster with CP K8s version v-XX-1 and NP K8s version v-XX should use v-XX-1 for both CP and NP", func() {
-		if helpers.SkipUpgradeTests {
-			Skip(helpers.SkipUpgradeTestsLog)
-		}
+	It("should be able to create a cluster with CP K8s version v-XX-1 and NP K8s version v-XX should use v-XX-1 for both CP and NP", func(specCtx SpecContext) {
+		suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 		testCaseID = 33
 
-		k8sVersions, err := helper.ListSingleVariantGKEAvailableVersions(ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "")
+		k8sVersions, err := helper.ListSingleVariantGKEAvailableVersions(specCtx, ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "")
 		Expect(err).To(BeNil())
 		Expect(len(k8sVersions)).To(BeNumerically(">=", 2))
 		npK8sVersion := k8sVersions[0]
@@ -160,7 +147,7 @@ var _ = Describe("P1Provisioning", func() {
 			}
 		}
 
-		cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, cpK8sVersion, zone, "", project, updateFunc)
+		cluster, err = helper.CreateGKEHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, cpK8sVersion, zone, "", project, updateFunc)
 		Expect(err).To(BeNil())
 
 		Expect(*cluster.GKEConfig.KubernetesVersion).To(Equal(cpK8sVersion))
@@ -168,7 +155,7 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(*np.Version).To(Equal(cpK8sVersion))
 		}
 
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 
 		Eventually(func() bool {
@@ -190,76 +177,74 @@ var _ = Describe("P1Provisioning", func() {
 
 	When("a cluster is created", func() {
 
-		BeforeEach(func() {
+		BeforeEach(func(specCtx SpecContext) {
 			var err error
-			cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, nil)
+			cluster, err = helper.CreateGKEHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, nil)
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
 
-		It("recreating a cluster while it is being deleted should recreate the cluster", func() {
+		It("recreating a cluster while it is being deleted should recreate the cluster", func(specCtx SpecContext) {
 			testCaseID = 26
 
-			err := helper.DeleteGKEHostCluster(cluster, ctx.RancherAdminClient)
+			err := helper.DeleteGKEHostCluster(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 
 			// Wait until the cluster begins deletion process before recreating
 			Eventually(func() bool {
-				exists, err := helper.ClusterExistsOnGCloud(clusterName, project, zone)
+				exists, err := helper.ClusterExistsOnGCloud(specCtx, clusterName, project, zone)
 				Expect(err).To(BeNil())
 				return exists
 			}, "1m", "5s").Should(BeFalse())
 
-			cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, nil)
+			cluster, err = helper.CreateGKEHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, nil)
 			Expect(err).To(BeNil())
 
 			// wait until the error is visible on the provisioned cluster
-			Eventually(func() bool {
+			Eventually(func() *management.Cluster {
 				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 				Expect(err).To(BeNil())
-				return cluster.State == "provisioning" && cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "a cluster in GKE exists with the same name")
-			}, "30s", "2s").Should(BeTrue())
+				return cluster
+			}, "30s", "2s").Should(And(HaveField("State", "provisioning"), suite.HaveTransitionError(helper.ErrClusterExists)))
 
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
 
-		It("should be able to update mutable parameter loggingService and monitoringService", func() {
+		It("should be able to update mutable parameter loggingService and monitoringService", func(specCtx SpecContext) {
 			testCaseID = 28
 			By("disabling the services", func() {
-				updateLoggingAndMonitoringServiceCheck(cluster, ctx.RancherAdminClient, "none", "none")
+				updateLoggingAndMonitoringServiceCheck(specCtx, cluster, ctx.RancherAdminClient, "none", "none")
 			})
 			By("enabling the services", func() {
-				updateLoggingAndMonitoringServiceCheck(cluster, ctx.RancherAdminClient, "monitoring.googleapis.com/kubernetes", "logging.googleapis.com/kubernetes")
+				updateLoggingAndMonitoringServiceCheck(specCtx, cluster, ctx.RancherAdminClient, "monitoring.googleapis.com/kubernetes", "logging.googleapis.com/kubernetes")
 			})
 		})
 
-		It("should be able to update autoscaling", func() {
+		It("should be able to update autoscaling", func(specCtx SpecContext) {
 			testCaseID = 29
 			By("enabling autoscaling", func() {
-				updateAutoScaling(cluster, ctx.RancherAdminClient, true)
+				updateAutoScaling(specCtx, cluster, ctx.RancherAdminClient, true)
 			})
 			By("disabling autoscaling", func() {
-				updateAutoScaling(cluster, ctx.RancherAdminClient, false)
+				updateAutoScaling(specCtx, cluster, ctx.RancherAdminClient, false)
 			})
 		})
 
-		It("should successfully add a windows nodepool", func() {
+		It("should successfully add a windows nodepool", func(specCtx SpecContext) {
 			testCaseID = 30
 			var err error
-			if helpers.SkipUpgradeTests {
-				Skip(helpers.SkipUpgradeTestsLog)
-			}
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 
-			_, err = helper.AddNodePool(cluster, ctx.RancherAdminClient, 1, "WINDOWS_LTSC_CONTAINERD", true, true)
+			_, err = helper.AddNodePool(specCtx, cluster, ctx.RancherAdminClient, 1, "WINDOWS_LTSC_CONTAINERD", true, true)
 			Expect(err).To(BeNil())
 		})
 
-		It("updating a cluster to all windows nodepool should fail", func() {
+		It("updating a cluster to all windows nodepool should fail", func(specCtx SpecContext) {
 			testCaseID = 263
 
-			_, err := helper.UpdateCluster(cluster, ctx.RancherAdminClient, func(upgradedCluster *management.Cluster) {
+			_, err := helper.UpdateCluster(specCtx, cluster, ctx.RancherAdminClient, func(upgradedCluster *management.Cluster) {
 				updateNodePoolsList := *cluster.GKEConfig.NodePools
 				for i := 0; i < len(updateNodePoolsList); i++ {
 					updateNodePoolsList[i].Config.ImageType = "WINDOWS_LTSC_CONTAINERD"
@@ -271,19 +256,19 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(err.Error()).To(ContainSubstring("at least 1 Linux node pool is required"))
 		})
 
-		It("should be able to update combination mutable parameter", func() {
+		It("should be able to update combination mutable parameter", func(specCtx SpecContext) {
 			testCaseID = 31
-			combinationMutableParameterUpdate(cluster, ctx.RancherAdminClient)
+			combinationMutableParameterUpdate(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should successfully update with new cloud credentials", func() {
+		It("should successfully update with new cloud credentials", func(specCtx SpecContext) {
 			testCaseID = 5
-			updateCloudCredentialsCheck(cluster, ctx.RancherAdminClient)
+			updateCloudCredentialsCheck(specCtx, cluster, ctx.RancherAdminClient)
 		})
 	})
 
 	When("creating a cluster with at least 2 nodepools", func() {
-		BeforeEach(func() {
+		BeforeEach(func(specCtx SpecContext) {
 			var err error
 			updateFunc := func(clusterConfig *gke.ClusterConfig) {
 				var updateNodePoolsList []gke.NodePool
@@ -303,62 +288,60 @@ var _ = Describe("P1Provisioning", func() {
 				}
 				clusterConfig.NodePools = updateNodePoolsList
 			}
-			cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, updateFunc)
+			cluster, err = helper.CreateGKEHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, updateFunc)
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
 
-		It("for a given NodePool with a non-windows imageType, updating it to a windows imageType should fail", func() {
+		It("for a given NodePool with a non-windows imageType, updating it to a windows imageType should fail", func(specCtx SpecContext) {
 			testCaseID = 34
 			var err error
-			cluster, err = helper.UpdateCluster(cluster, ctx.RancherAdminClient, func(upgradedCluster *management.Cluster) {
+			cluster, err = helper.UpdateCluster(specCtx, cluster, ctx.RancherAdminClient, func(upgradedCluster *management.Cluster) {
 				updateNodePoolsList := *cluster.GKEConfig.NodePools
 				updateNodePoolsList[0].Config.ImageType = "WINDOWS_LTSC_CONTAINERD"
 
 				upgradedCluster.GKEConfig.NodePools = &updateNodePoolsList
 			})
 
-			Eventually(func() bool {
+			Eventually(func() *management.Cluster {
 				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 				Expect(err).To(BeNil())
-				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "Node pools cannot be upgraded between Windows and non-Windows image families")
-			}, "30s", "2s").Should(BeTrue())
+				return cluster
+			}, "30s", "2s").Should(suite.HaveTransitionError(helper.ErrWindowsImageUpgrade))
 		})
 	})
 
 	When("a cluster is created for upgrade scenarios", func() {
 
-		BeforeEach(func() {
-			if helpers.SkipUpgradeTests {
-				Skip(helpers.SkipUpgradeTestsLog)
-			}
+		BeforeEach(func(specCtx SpecContext) {
+			suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 
 			var err error
-			k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", true)
+			k8sVersion, err = helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", true)
 			Expect(err).To(BeNil())
 			GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
 
-			cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, nil)
+			cluster, err = helper.CreateGKEHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, nil)
 			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
 
-		It("should successfully update a cluster while it is still in updating state", func() {
+		It("should successfully update a cluster while it is still in updating state", func(specCtx SpecContext) {
 			testCaseID = 35
-			updateClusterInUpdatingState(cluster, ctx.RancherAdminClient)
+			updateClusterInUpdatingState(specCtx, cluster, ctx.RancherAdminClient)
 		})
 
-		It("should successfully upgrade CP & NP version simultaneously", func() {
-			upgradeK8sVersionChecks(cluster, ctx.RancherAdminClient)
+		It("should successfully upgrade CP & NP version simultaneously", func(specCtx SpecContext) {
+			upgradeK8sVersionChecks(specCtx, cluster, ctx.RancherAdminClient)
 		})
 	})
 
 	When("a private cluster is created", func() {
 		var err error
 
-		BeforeEach(func() {
+		BeforeEach(func(specCtx SpecContext) {
 			updateFunc := func(clusterConfig *gke.ClusterConfig) {
 				network := pointer.String("hosted-providers-ci-private")
 				clusterConfig.Network = network
@@ -380,30 +363,30 @@ var _ = Describe("P1Provisioning", func() {
 				}
 			}
 
-			cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, updateFunc)
+			cluster, err = helper.CreateGKEHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, updateFunc)
 			Expect(err).To(BeNil())
 
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 
 			Expect(cluster.GKEConfig.PrivateClusterConfig.EnablePrivateNodes).To(BeTrue())
 			Expect(cluster.GKEStatus.UpstreamSpec.PrivateClusterConfig.EnablePrivateNodes).To(BeTrue())
 		})
 
-		It("should successfully create with public endpoint", func() {
+		It("should successfully create with public endpoint", func(specCtx SpecContext) {
 			testCaseID = 22
 
-			cluster, err = helper.AddNodePool(cluster, ctx.RancherAdminClient, 1, "", true, true)
+			cluster, err = helper.AddNodePool(specCtx, cluster, ctx.RancherAdminClient, 1, "", true, true)
 			Expect(err).To(BeNil())
 		})
 
-		It("should successfully create with public endpoint and MasterAuthorizedNetworks", func() {
+		It("should successfully create with public endpoint and MasterAuthorizedNetworks", func(specCtx SpecContext) {
 			testCaseID = 24
 
 			Expect(cluster.GKEConfig.MasterAuthorizedNetworksConfig.Enabled).To(BeTrue())
 			Expect(cluster.GKEStatus.UpstreamSpec.MasterAuthorizedNetworksConfig.Enabled).To(BeTrue())
 
-			cluster, err = helper.ScaleNodePool(cluster, ctx.RancherAdminClient, 1, false, true)
+			cluster, err = helper.ScaleNodePool(specCtx, cluster, ctx.RancherAdminClient, 1, false, true)
 			Expect(err).To(BeNil())
 
 		})
@@ -15,9 +15,8 @@ limitations under the License.
 package p1_test
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"strings"
 	"testing"
 	"time"
 
@@ -35,6 +34,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var (
@@ -52,10 +52,10 @@ func TestP1(t *testing.T) {
 }
 
 var _ = SynchronizedBeforeSuite(func() []byte {
-	helpers.CommonSynchronizedBeforeSuite()
+	suite.CommonSynchronizedBeforeSuite()
 	return nil
 }, func() {
-	ctx = helpers.CommonBeforeSuite()
+	ctx = suite.CommonBeforeSuite()
 })
 
 var _ = BeforeEach(func() {
@@ -69,20 +69,21 @@ var _ = ReportBeforeEach(func(report SpecReport) {
 	testCaseID = -1
 })
 
-var _ = ReportAfterEach(func(report SpecReport) {
-	// Add result in Qase if asked
+var _ = ReportAfterEach(func(specCtx SpecContext, report SpecReport) {
+	// Add result in Qase and in the run report if asked
 	Qase(testCaseID, report)
+	suite.WriteRunReport(specCtx, report, testCaseID, ctx.RancherAdminClient)
 })
 
 // updateLoggingAndMonitoringServiceCheck tests updating `loggingService` and `monitoringService`
-func updateLoggingAndMonitoringServiceCheck(cluster *management.Cluster, client *rancher.Client, updateMonitoringValue, updateLoggingValue string) {
+func updateLoggingAndMonitoringServiceCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client, updateMonitoringValue, updateLoggingValue string) {
 	var err error
-	_, err = helper.UpdateMonitoringAndLoggingService(cluster, client, updateMonitoringValue, updateLoggingValue, true, true)
+	_, err = helper.UpdateMonitoringAndLoggingService(specCtx, cluster, client, updateMonitoringValue, updateLoggingValue, true, true)
 	Expect(err).To(BeNil())
 }
 
 // updateAutoScaling tests updating `autoscaling` for GKE node pools
-func updateAutoScaling(cluster *management.Cluster, client *rancher.Client, autoscale bool) {
+func updateAutoScaling(specCtx context.Context, cluster *management.Cluster, client *rancher.Client, autoscale bool) {
 	for _, np := range *cluster.GKEConfig.NodePools {
 		if np.Autoscaling != nil {
 			Expect(np.Autoscaling.Enabled).ToNot(BeEquivalentTo(autoscale))
@@ -90,12 +91,12 @@ func updateAutoScaling(cluster *management.Cluster, client *rancher.Client, auto
 	}
 
 	var err error
-	cluster, err = helper.UpdateAutoScaling(cluster, client, autoscale, true, true)
+	cluster, err = helper.UpdateAutoScaling(specCtx, cluster, client, autoscale, true, true)
 	Expect(err).To(BeNil())
 }
 
-func syncK8sVersionUpgradeCheck(cluster *management.Cluster, client *rancher.Client) {
-	availableVersions, err := helper.ListGKEAvailableVersions(client, cluster.ID)
+func syncK8sVersionUpgradeCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
+	availableVersions, err := helper.ListGKEAvailableVersions(specCtx, client, cluster.ID)
 	Expect(err).To(BeNil())
 	upgradeToVersion := availableVersions[0]
 	GinkgoLogr.Info("Upgrading to version " + upgradeToVersion)
@@ -103,10 +104,10 @@ func syncK8sVersionUpgradeCheck(cluster *management.Cluster, client *rancher.Cli
 	By("upgrading control plane", func() {
 		currentVersion := cluster.GKEConfig.KubernetesVersion
 
-		err = helper.UpgradeGKEClusterOnGCloud(zone, clusterName, project, upgradeToVersion, false, "")
+		err = helper.UpgradeGKEClusterOnGCloud(specCtx, zone, clusterName, project, upgradeToVersion, false, "")
 		Expect(err).To(BeNil())
 		// The cluster errors out and becomes unavailable at some point due to the upgrade , so we wait until the cluster is ready
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, client)
+		cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, client)
 		Expect(err).To(BeNil())
 		Eventually(func() string {
 			GinkgoLogr.Info("Waiting for k8s upgrade to appear in GKEStatus.UpstreamSpec ...")
@@ -123,9 +124,9 @@ func syncK8sVersionUpgradeCheck(cluster *management.Cluster, client *rancher.Cli
 		if !helpers.IsImport {
 			// For imported clusters, GKEConfig always has null values; so we check GKEConfig only when testing provisioned clusters
 			// Refer: github.com/rancher/gke-operator/issues/702
-			Expect(strings.Contains(cluster.TransitioningMessage, "downgrades of minor versions are not supported in GKE, consider updating spec version to match upstream version") || strings.Contains(cluster.TransitioningMessage, "specified version is not newer than the current version")).To(BeTrue())
+			Expect(cluster).To(suite.HaveTransitionError(helper.ErrKubernetesVersionDowngrade))
 			// Updating controlplane version via Rancher
-			cluster, err = helper.UpgradeKubernetesVersion(cluster, upgradeToVersion, client, false, true, false)
+			cluster, err = helper.UpgradeKubernetesVersion(specCtx, cluster, upgradeToVersion, client, false, true, false)
 			Expect(err).To(BeNil())
 			Expect(*cluster.GKEConfig.KubernetesVersion).To(Equal(upgradeToVersion))
 			for _, np := range *cluster.GKEConfig.NodePools {
@@ -137,7 +138,7 @@ func syncK8sVersionUpgradeCheck(cluster *management.Cluster, client *rancher.Cli
 	By("upgrading the node pool", func() {
 		for _, np := range *cluster.GKEStatus.UpstreamSpec.NodePools {
 			// The cluster errors out and becomes unavailable at some point due to the upgrade , so we wait until the cluster is ready
-			_ = helper.UpgradeGKEClusterOnGCloud(zone, clusterName, project, upgradeToVersion, true, *np.Name)
+			_ = helper.UpgradeGKEClusterOnGCloud(specCtx, zone, clusterName, project, upgradeToVersion, true, *np.Name)
 		}
 
 		Eventually(func() bool {
@@ -162,13 +163,13 @@ func syncK8sVersionUpgradeCheck(cluster *management.Cluster, client *rancher.Cli
 	})
 }
 
-func syncNodepoolsCheck(cluster *management.Cluster, client *rancher.Client) {
+func syncNodepoolsCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 
 	var poolName = namegen.AppendRandomString("new-np")
 	currentNodeCount := len(*cluster.GKEConfig.NodePools)
 
 	By("adding a nodepool", func() {
-		err := helper.AddNodePoolOnGCloud(clusterName, zone, project, poolName)
+		err := helper.AddNodePoolOnGCloud(specCtx, clusterName, zone, project, poolName)
 		Expect(err).To(BeNil())
 
 		// The cluster does not go into updating state, so we simply wait until the number of nodepools increases
@@ -207,7 +208,7 @@ func syncNodepoolsCheck(cluster *management.Cluster, client *rancher.Client) {
 	})
 
 	By("deleting the nodepool", func() {
-		err := helper.DeleteNodePoolOnGCloud(zone, project, clusterName, poolName)
+		err := helper.DeleteNodePoolOnGCloud(specCtx, zone, project, clusterName, poolName)
 		Expect(err).To(BeNil())
 
 		// The cluster does not go into updating state, so we simply wait until the number of nodepools decreases
@@ -242,31 +243,33 @@ func syncNodepoolsCheck(cluster *management.Cluster, client *rancher.Client) {
 			}()).To(BeFalse(), "GKEConfig.NodePools decrease check failed")
 		}
 	})
+
+	By("checking the cluster has not drifted from GCloud", func() {
+		suite.ExpectNoDrift(specCtx, helper.Provider{}, client, cluster)
+	})
 }
 
 // updateClusterInUpdatingState runs checks to ensure cluster in an updating state can be updated
-func updateClusterInUpdatingState(cluster *management.Cluster, client *rancher.Client) {
-	if helpers.SkipUpgradeTests {
-		Skip(helpers.SkipUpgradeTestsLog)
-	}
-	availableVersions, err := helper.ListGKEAvailableVersions(client, cluster.ID)
+func updateClusterInUpdatingState(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
+	suite.SkipUnlessSupported(specCtx, client, helpers.FeatureK8sUpgrade)
+	availableVersions, err := helper.ListGKEAvailableVersions(specCtx, client, cluster.ID)
 	Expect(err).To(BeNil())
 	upgradeK8sVersion := availableVersions[0]
 
 	currentNodePoolCount := len(*cluster.GKEConfig.NodePools)
-	cluster, err = helper.UpgradeKubernetesVersion(cluster, upgradeK8sVersion, client, false, false, false)
+	cluster, err = helper.UpgradeKubernetesVersion(specCtx, cluster, upgradeK8sVersion, client, false, false, false)
 	Expect(err).To(BeNil())
 	Expect(*cluster.GKEConfig.KubernetesVersion).To(Equal(upgradeK8sVersion))
 
 	err = clusters.WaitClusterToBeInUpgrade(client, cluster.ID)
 	Expect(err).To(BeNil())
 
-	cluster, err = helper.AddNodePool(cluster, client, 1, "", false, false)
+	cluster, err = helper.AddNodePool(specCtx, cluster, client, 1, "", false, false)
 	Expect(err).To(BeNil())
 
 	Expect(len(*cluster.GKEConfig.NodePools)).Should(BeNumerically("==", currentNodePoolCount+1))
 
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	err = helpers.WaitClusterToBeUpdated(specCtx, client, cluster.ID)
 	Expect(err).To(BeNil())
 
 	Eventually(func() bool {
@@ -278,14 +281,14 @@ func updateClusterInUpdatingState(cluster *management.Cluster, client *rancher.C
 }
 
 // combinationMutableParameterUpdate runs checks to test if mutable parameters can be updated in combination
-func combinationMutableParameterUpdate(cluster *management.Cluster, client *rancher.Client) {
+func combinationMutableParameterUpdate(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	const (
 		disableService = "none"
 		maxCount       = int64(5)
 		minCount       = int64(2)
 	)
 	var err error
-	cluster, err = helper.UpdateCluster(cluster, client, func(upgradedCluster *management.Cluster) {
+	cluster, err = helper.UpdateCluster(specCtx, cluster, client, func(upgradedCluster *management.Cluster) {
 		updatedNp := *upgradedCluster.GKEConfig.NodePools
 		for i := range updatedNp {
 			np := updatedNp[i]
@@ -320,7 +323,7 @@ func combinationMutableParameterUpdate(cluster *management.Cluster, client *ranc
 		Expect(np.Autoscaling.MinNodeCount).Should(Equal(minCount))
 	}
 
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	err = helpers.WaitClusterToBeUpdated(specCtx, client, cluster.ID)
 	Expect(err).To(BeNil())
 
 	Eventually(func() bool {
@@ -341,13 +344,13 @@ func combinationMutableParameterUpdate(cluster *management.Cluster, client *ranc
 	}, "5m", "5s").Should(BeTrue())
 }
 
-func updateCloudCredentialsCheck(cluster *management.Cluster, client *rancher.Client) {
+func updateCloudCredentialsCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	newCCID, err := helpers.CreateCloudCredentials(client)
 	Expect(err).To(BeNil())
 	updateFunc := func(cluster *management.Cluster) {
 		cluster.GKEConfig.GoogleCredentialSecret = newCCID
 	}
-	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+	cluster, err = helper.UpdateCluster(specCtx, cluster, client, updateFunc)
 	Expect(err).To(BeNil())
 	Expect(cluster.GKEConfig.GoogleCredentialSecret).To(Equal(newCCID))
 	Eventually(func() bool {
@@ -356,25 +359,25 @@ func updateCloudCredentialsCheck(cluster *management.Cluster, client *rancher.Cl
 		return cluster.GKEStatus.UpstreamSpec.GoogleCredentialSecret == newCCID
 	}, "5m", "5s").Should(BeTrue(), "Failed while upstream cloud credentials update")
 
-	cluster, err = helper.AddNodePool(cluster, client, 1, "", false, false)
+	cluster, err = helper.AddNodePool(specCtx, cluster, client, 1, "", false, false)
 	Expect(err).To(BeNil())
 }
 
-func upgradeK8sVersionChecks(cluster *management.Cluster, client *rancher.Client) {
-	versions, err := helper.ListGKEAvailableVersions(client, cluster.ID)
+func upgradeK8sVersionChecks(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
+	versions, err := helper.ListGKEAvailableVersions(specCtx, client, cluster.ID)
 	Expect(err).To(BeNil())
 	Expect(versions).ToNot(BeEmpty())
 	upgradeToVersion := versions[0]
 	GinkgoLogr.Info(fmt.Sprintf("Upgrading cluster to GKE version %s", upgradeToVersion))
 
 	By("upgrading the ControlPlane & Nodepools", func() {
-		cluster, err = helper.UpgradeKubernetesVersion(cluster, upgradeToVersion, client, true, true, true)
+		cluster, err = helper.UpgradeKubernetesVersion(specCtx, cluster, upgradeToVersion, client, true, true, true)
 		Expect(err).To(BeNil())
 	})
 }
 
 // Automates Qase 2 and 306
-func invalidCredCheck(cluster *management.Cluster, client *rancher.Client) {
+func invalidCredCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	By("creating invalid creds")
 	cloudCredentialConfig := cloudcredentials.CloudCredential{GoogleCredentialConfig: &cloudcredentials.GoogleCredentialConfig{AuthEncodedJSON: "{\"invalid-key\":\"invalid-value\"}"}}
 	cloudCredential, err := google.CreateGoogleCloudCredentials(client, cloudCredentialConfig)
@@ -383,11 +386,11 @@ func invalidCredCheck(cluster *management.Cluster, client *rancher.Client) {
 
 	if helpers.IsImport {
 		By("importing the cluster")
-		cluster, err = helper.ImportGKEHostedCluster(client, clusterName, cloudCredentialID, zone, project)
+		cluster, err = helper.ImportGKEHostedCluster(specCtx, client, clusterName, cloudCredentialID, zone, project)
 		Expect(err).To(BeNil())
 	} else {
 		By("provisioning the cluster")
-		cluster, err = helper.CreateGKEHostedCluster(client, clusterName, cloudCredentialID, k8sVersion, zone, "", project, nil)
+		cluster, err = helper.CreateGKEHostedCluster(specCtx, client, clusterName, cloudCredentialID, k8sVersion, zone, "", project, nil)
 		Expect(err).To(BeNil())
 	}
 
@@ -399,36 +402,36 @@ func invalidCredCheck(cluster *management.Cluster, client *rancher.Client) {
 }
 
 // Automates Qase 6 and 305
-func expiredCredCheck(cluster *management.Cluster, client *rancher.Client) {
+func expiredCredCheck(specCtx context.Context, cluster *management.Cluster, client *rancher.Client) {
 	By("adding the creds")
-	cloudCredentialConfig := cloudcredentials.CloudCredential{GoogleCredentialConfig: &cloudcredentials.GoogleCredentialConfig{AuthEncodedJSON: os.Getenv("SECONDARY_GCP_CREDENTIALS")}}
+	cloudCredentialConfig := cloudcredentials.CloudCredential{GoogleCredentialConfig: &cloudcredentials.GoogleCredentialConfig{AuthEncodedJSON: helpers.Config.GKE.SecondaryCredentials}}
 	cloudCredential, err := google.CreateGoogleCloudCredentials(client, cloudCredentialConfig)
 	Expect(err).To(BeNil())
 	cloudCredentialID := fmt.Sprintf("%s:%s", cloudCredential.Namespace, cloudCredential.Name)
 
 	By("disabling the service account")
 	const clientID = "hosted-providers-ci-creds-test"
-	err = helper.EnableDisableServiceAccountOnGCloud(clientID, project, "disable")
+	err = helper.EnableDisableServiceAccountOnGCloud(specCtx, clientID, project, "disable")
 	Expect(err).To(BeNil())
 	defer func() {
 		By("cleanup: enabling the service account")
-		err = helper.EnableDisableServiceAccountOnGCloud(clientID, project, "enable")
+		err = helper.EnableDisableServiceAccountOnGCloud(specCtx, clientID, project, "enable")
 		Expect(err).To(BeNil())
 	}()
 
 	if helpers.IsImport {
 		By("importing the cluster")
-		cluster, err = helper.ImportGKEHostedCluster(client, clusterName, cloudCredentialID, zone, project)
+		cluster, err = helper.ImportGKEHostedCluster(specCtx, client, clusterName, cloudCredentialID, zone, project)
 		Expect(err).To(BeNil())
 	} else {
 		By("provisioning the cluster")
-		cluster, err = helper.CreateGKEHostedCluster(client, clusterName, cloudCredentialID, k8sVersion, zone, "", project, nil)
+		cluster, err = helper.CreateGKEHostedCluster(specCtx, client, clusterName, cloudCredentialID, k8sVersion, zone, "", project, nil)
 		Expect(err).To(BeNil())
 	}
 
-	Eventually(func() bool {
+	Eventually(func() *management.Cluster {
 		cluster, err = client.Management.Cluster.ByID(cluster.ID)
 		Expect(err).To(BeNil())
-		return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "cannot fetch token") || strings.Contains(cluster.TransitioningMessage, "unexpected end of JSON input")
-	}, "2m", "3s").Should(BeTrue())
+		return cluster
+	}, "2m", "3s").Should(suite.HaveTransitionError(helper.ErrInvalidCredentials))
 }
@@ -1,6 +1,7 @@
 package p1_test
 
 import (
+	"context"
 	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -10,13 +11,14 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var _ = Describe("SyncProvisioning", func() {
 	for _, testData := range []struct {
 		qaseID    int64
 		isUpgrade bool
-		testBody  func(cluster *management.Cluster, client *rancher.Client)
+		testBody  func(specCtx context.Context, cluster *management.Cluster, client *rancher.Client)
 		testTitle string
 	}{
 		{
@@ -34,32 +36,23 @@ var _ = Describe("SyncProvisioning", func() {
 	} {
 		testData := testData
 		When("a cluster is created", func() {
-			BeforeEach(func() {
-				if testData.isUpgrade && helpers.SkipUpgradeTests {
-					Skip(helpers.SkipUpgradeTestsLog)
+			BeforeEach(func(specCtx SpecContext) {
+				if testData.isUpgrade {
+					suite.SkipUnlessSupported(specCtx, ctx.RancherAdminClient, helpers.FeatureK8sUpgrade)
 				}
-				k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", testData.isUpgrade)
+				k8sVersion, err := helper.GetK8sVersion(specCtx, ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", testData.isUpgrade)
 				Expect(err).NotTo(HaveOccurred())
 				GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 
-				cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, nil)
+				cluster, err = helper.CreateGKEHostedCluster(specCtx, ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, nil)
 				Expect(err).To(BeNil())
-				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
 			})
 
-			AfterEach(func() {
-				if ctx.ClusterCleanup && cluster != nil {
-					err := helper.DeleteGKEHostCluster(cluster, ctx.RancherAdminClient)
-					Expect(err).To(BeNil())
-				} else {
-					fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-				}
-			})
-
-			It(testData.testTitle, func() {
+			It(testData.testTitle, func(specCtx SpecContext) {
 				testCaseID = testData.qaseID
-				testData.testBody(cluster, ctx.RancherAdminClient)
+				testData.testBody(specCtx, cluster, ctx.RancherAdminClient)
 			})
 		})
 	}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var _ = Describe("SupportMatrixImport", func() {
@@ -37,31 +38,21 @@ var _ = Describe("SupportMatrixImport", func() {
 				clusterName string
 				cluster     *management.Cluster
 			)
-			BeforeEach(func() {
+			BeforeEach(func(specCtx SpecContext) {
 				clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
 				var err error
-				err = helper.CreateGKEClusterOnGCloud(zone, clusterName, project, version)
+				err = helper.CreateGKEClusterOnGCloud(specCtx, zone, clusterName, project, version)
 				Expect(err).To(BeNil())
-				cluster, err = helper.ImportGKEHostedCluster(ctx.StdUserClient, clusterName, ctx.CloudCredID, zone, project)
+				cluster, err = helper.ImportGKEHostedCluster(specCtx, ctx.StdUserClient, clusterName, ctx.CloudCredID, zone, project)
 				Expect(err).To(BeNil())
 				// Requires RancherAdminClient
-				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				cluster, err = helpers.WaitUntilClusterIsReady(specCtx, cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
 			})
-			AfterEach(func() {
-				if ctx.ClusterCleanup && cluster != nil {
-					err := helper.DeleteGKEHostCluster(cluster, ctx.StdUserClient)
-					Expect(err).To(BeNil())
-					err = helper.DeleteGKEClusterOnGCloud(zone, project, clusterName)
-					Expect(err).To(BeNil())
-				} else {
-					fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-				}
-			})
-			It("should successfully import the cluster", func() {
+			It("should successfully import the cluster", func(specCtx SpecContext) {
 				// Report to Qase
 				testCaseID = 13
-				helpers.ClusterIsReadyChecks(cluster, ctx.StdUserClient, clusterName)
+				suite.ClusterIsReadyChecks(specCtx, cluster, ctx.StdUserClient, clusterName)
 			})
 		})
 	}
@@ -0,0 +1,72 @@
+package fakecli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// Recorder is a helpers.CommandRunner that runs the commands with another runner and records them along with their output;
+// the recording can be saved and later replayed with Runner.Load.
+type Recorder struct {
+	runner helpers.CommandRunner
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewRecorder returns a Recorder running the commands with the given runner, usually helpers.ProcRunner
+func NewRecorder(runner helpers.CommandRunner) *Recorder {
+	return &Recorder{runner: runner}
+}
+
+// Install sets the recorder as the helpers.CommandRunner; it returns a function that restores the previous runner
+func (r *Recorder) Install() (restore func()) {
+	return helpers.SetCommandRunner(r)
+}
+
+func (r *Recorder) Run(ctx context.Context, name string, args ...string) (string, error) {
+	out, err := r.runner.Run(ctx, name, args...)
+	r.record(Call{Name: name, Args: args, Output: out}, err)
+	return out, err
+}
+
+// RunWithInput implements helpers.InputRunner if the recorded runner does
+func (r *Recorder) RunWithInput(ctx context.Context, input []byte, name string, args ...string) (string, error) {
+	runner, ok := r.runner.(helpers.InputRunner)
+	if !ok {
+		return "", fmt.Errorf("fakecli: the recorded runner cannot write to the standard input of %s", name)
+	}
+	out, err := runner.RunWithInput(ctx, input, name, args...)
+	r.record(Call{Name: name, Args: args, Input: string(input), Output: out}, err)
+	return out, err
+}
+
+func (r *Recorder) record(call Call, err error) {
+	if err != nil {
+		call.Error = err.Error()
+	}
+	r.mu.Lock()
+	r.calls = append(r.calls, call)
+	r.mu.Unlock()
+}
+
+// Calls returns the commands recorded so far
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// Save writes the recorded commands to path as JSON
+func (r *Recorder) Save(path string) error {
+	data, err := json.MarshalIndent(r.Calls(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,169 @@
+// Package fakecli provides a helpers.CommandRunner that replays expected commands with canned output,
+// and a recorder that captures the commands run by a real runner so that they can be replayed later.
+package fakecli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// Call is a command expected by a Runner along with the output it returns
+type Call struct {
+	Name string   `json:"name"`
+	Args []string `json:"args"`
+	// Input is the standard input of the command, for e.g. the manifest of kubectl apply -f -
+	Input  string `json:"input,omitempty"`
+	Output string `json:"output"`
+	// Error is the error message returned by the command; empty if the command succeeds
+	Error string `json:"error,omitempty"`
+}
+
+// Argv returns the command as it would be typed on a shell, for e.g. in error messages
+func (c *Call) Argv() string {
+	return strings.TrimSpace(c.Name + " " + strings.Join(c.Args, " "))
+}
+
+// WithInput sets the standard input expected by the call
+func (c *Call) WithInput(input string) *Call {
+	c.Input = input
+	return c
+}
+
+// Returns sets the output of the call
+func (c *Call) Returns(output string) *Call {
+	c.Output = output
+	return c
+}
+
+// Fails makes the call return an error along with the given output
+func (c *Call) Fails(output, message string) *Call {
+	c.Output = output
+	c.Error = message
+	return c
+}
+
+// Runner is a helpers.CommandRunner that expects the commands to be run in the exact order and with the exact arguments they were added;
+// an unexpected command does not run anything and returns an error describing the mismatch.
+type Runner struct {
+	mu       sync.Mutex
+	expected []*Call
+	next     int
+	calls    []Call
+	failures []string
+}
+
+// NewRunner returns a Runner without any expected command
+func NewRunner() *Runner {
+	return &Runner{}
+}
+
+// Install sets the runner as the helpers.CommandRunner; it returns a function that restores the previous runner
+func (r *Runner) Install() (restore func()) {
+	return helpers.SetCommandRunner(r)
+}
+
+// Expect adds a command expected to be run with exactly the given arguments; by default it succeeds without any output
+func (r *Runner) Expect(name string, args ...string) *Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	call := &Call{Name: name, Args: args}
+	r.expected = append(r.expected, call)
+	return call
+}
+
+func (r *Runner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	return r.RunWithInput(ctx, nil, name, args...)
+}
+
+// RunWithInput implements helpers.InputRunner; the input must be the one of the expected command
+func (r *Runner) RunWithInput(ctx context.Context, input []byte, name string, args ...string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	actual := Call{Name: name, Args: args, Input: string(input)}
+	r.calls = append(r.calls, actual)
+
+	if r.next >= len(r.expected) {
+		failure := fmt.Sprintf("unexpected command: %s", actual.Argv())
+		r.failures = append(r.failures, failure)
+		return "", fmt.Errorf("fakecli: %s", failure)
+	}
+
+	expected := r.expected[r.next]
+	r.next++
+	if expected.Name != name || !equalArgs(expected.Args, args) {
+		failure := fmt.Sprintf("command #%d mismatch:\n\texpected: %q\n\tactual:   %q", r.next, append([]string{expected.Name}, expected.Args...), append([]string{name}, args...))
+		r.failures = append(r.failures, failure)
+		return "", fmt.Errorf("fakecli: %s", failure)
+	}
+	if expected.Input != actual.Input {
+		failure := fmt.Sprintf("command #%d input mismatch:\n\texpected: %q\n\tactual:   %q", r.next, expected.Input, actual.Input)
+		r.failures = append(r.failures, failure)
+		return "", fmt.Errorf("fakecli: %s", failure)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if expected.Error != "" {
+		return expected.Output, fmt.Errorf("%s", expected.Error)
+	}
+	return expected.Output, nil
+}
+
+// Calls returns the commands run so far, whether they were expected or not
+func (r *Runner) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// Verify returns an error if a command did not match the expectation or if some expected commands were not run
+func (r *Runner) Verify() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	failures := append([]string(nil), r.failures...)
+	for _, call := range r.expected[r.next:] {
+		failures = append(failures, fmt.Sprintf("expected command was not run: %s", call.Argv()))
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("fakecli: %s", strings.Join(failures, "\n"))
+	}
+	return nil
+}
+
+// Load adds the calls saved by a Recorder to the expected commands
+func (r *Runner) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var calls []*Call
+	if err = json.Unmarshal(data, &calls); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expected = append(r.expected, calls...)
+	return nil
+}
+
+func equalArgs(expected, actual []string) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,307 @@
+package fakerancher
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+)
+
+// ClusterState is a state of a management.cattle.io cluster as reported by Rancher
+type ClusterState struct {
+	// Name is the value of cluster.State, e.g. provisioning, updating, active
+	Name string
+	// Message is the value of cluster.TransitioningMessage
+	Message string
+	// Error marks the state as failed; cluster.Transitioning is set to "error"
+	Error bool
+}
+
+var (
+	StateProvisioning = ClusterState{Name: "provisioning"}
+	StateUpdating     = ClusterState{Name: "updating"}
+	StateActive       = ClusterState{Name: "active"}
+)
+
+// StateError returns an errored updating state with the given transitioning message
+func StateError(message string) ClusterState {
+	return ClusterState{Name: "updating", Message: message, Error: true}
+}
+
+type clusterRecord struct {
+	cluster *management.Cluster
+	state   ClusterState
+	// generation is increased every time a new transition starts so that older transitions stop
+	generation int
+}
+
+// conditions returns the cluster conditions matching the state so that the wrangler summary computes the same state
+func (c ClusterState) conditions() []management.ClusterCondition {
+	switch {
+	case c.Error:
+		return []management.ClusterCondition{
+			{Type: "Ready", Status: "True"},
+			{Type: "Updated", Status: "False", Message: c.Message},
+		}
+	case c.Name == StateActive.Name:
+		return []management.ClusterCondition{
+			{Type: "Provisioned", Status: "True"},
+			{Type: "Updated", Status: "True"},
+			{Type: "Ready", Status: "True"},
+		}
+	case c.Name == StateProvisioning.Name:
+		return []management.ClusterCondition{
+			{Type: "Provisioned", Status: "Unknown", Message: c.Message},
+		}
+	default:
+		return []management.ClusterCondition{
+			{Type: "Ready", Status: "True"},
+			{Type: "Updated", Status: "Unknown", Message: c.Message},
+		}
+	}
+}
+
+func (c ClusterState) transitioning() string {
+	switch {
+	case c.Error:
+		return "error"
+	case c.Name == StateActive.Name:
+		return "no"
+	default:
+		return "yes"
+	}
+}
+
+// AddCluster stores a cluster as if it had been created via the API and returns its ID; the cluster is put in the given state without any transition
+func (s *Server) AddCluster(cluster *management.Cluster, state ClusterState) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := s.newClusterRecord(cluster)
+	s.setState(record, state)
+	return record.cluster.ID
+}
+
+// Cluster returns a copy of the stored cluster
+func (s *Server) Cluster(id string) (*management.Cluster, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.clusters[id]
+	if !ok {
+		return nil, false
+	}
+	return copyCluster(record.cluster), true
+}
+
+// UpdateCluster modifies the stored cluster as the operator would, e.g. to simulate a change done on the cloud, and notifies the watchers
+func (s *Server) UpdateCluster(id string, updateFunc func(cluster *management.Cluster)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.clusters[id]
+	if !ok {
+		return fmt.Errorf("cluster %s not found", id)
+	}
+	updateFunc(record.cluster)
+	s.notify(record, "MODIFIED")
+	return nil
+}
+
+// SetClusterState moves the cluster to the given state immediately and stops any ongoing transition
+func (s *Server) SetClusterState(id string, state ClusterState) error {
+	return s.ScriptClusterStates(id, state)
+}
+
+// ScriptClusterStates moves the cluster through the given states, spending TransitionInterval in each of them except the last one;
+// it stops any ongoing transition, including the ones started by CreateTransitions and UpdateTransitions
+func (s *Server) ScriptClusterStates(id string, states ...ClusterState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.clusters[id]
+	if !ok {
+		return fmt.Errorf("cluster %s not found", id)
+	}
+	s.startTransition(record, states)
+	return nil
+}
+
+func (s *Server) newClusterRecord(cluster *management.Cluster) *clusterRecord {
+	cluster = copyCluster(cluster)
+	if cluster.ID == "" {
+		cluster.ID = namegen.AppendRandomString("c")
+	}
+	cluster.Type = management.ClusterType
+	cluster.Links = map[string]string{"self": s.URL + "/v3/clusters/" + cluster.ID}
+	cluster.Created = time.Now().UTC().Format(time.RFC3339)
+	record := &clusterRecord{cluster: cluster}
+	s.clusters[cluster.ID] = record
+	return record
+}
+
+// startTransition must be called with the lock held
+func (s *Server) startTransition(record *clusterRecord, states []ClusterState) {
+	if len(states) == 0 {
+		return
+	}
+	record.generation++
+	generation := record.generation
+	s.setState(record, states[0])
+	go func() {
+		for _, state := range states[1:] {
+			time.Sleep(s.TransitionInterval)
+			s.mu.Lock()
+			if record.generation != generation || s.clusters[record.cluster.ID] != record {
+				s.mu.Unlock()
+				return
+			}
+			s.setState(record, state)
+			s.mu.Unlock()
+		}
+	}()
+}
+
+// setState must be called with the lock held
+func (s *Server) setState(record *clusterRecord, state ClusterState) {
+	record.state = state
+	cluster := record.cluster
+	cluster.State = state.Name
+	cluster.Transitioning = state.transitioning()
+	cluster.TransitioningMessage = state.Message
+	cluster.Conditions = state.conditions()
+	if state.Name == StateActive.Name && !state.Error {
+		syncUpstreamSpec(cluster)
+	}
+	s.notify(record, "MODIFIED")
+}
+
+// syncUpstreamSpec mimics the hosted operators which report the applied config in the status once the cluster is active
+func syncUpstreamSpec(cluster *management.Cluster) {
+	var kubernetesVersion *string
+	if cluster.AKSConfig != nil {
+		if cluster.AKSStatus == nil {
+			cluster.AKSStatus = &management.AKSStatus{}
+		}
+		cluster.AKSStatus.UpstreamSpec = nil
+		deepCopy(cluster.AKSConfig, &cluster.AKSStatus.UpstreamSpec)
+		kubernetesVersion = cluster.AKSConfig.KubernetesVersion
+	}
+	if cluster.EKSConfig != nil {
+		if cluster.EKSStatus == nil {
+			cluster.EKSStatus = &management.EKSStatus{}
+		}
+		cluster.EKSStatus.UpstreamSpec = nil
+		deepCopy(cluster.EKSConfig, &cluster.EKSStatus.UpstreamSpec)
+		kubernetesVersion = cluster.EKSConfig.KubernetesVersion
+	}
+	if cluster.GKEConfig != nil {
+		if cluster.GKEStatus == nil {
+			cluster.GKEStatus = &management.GKEStatus{}
+		}
+		cluster.GKEStatus.UpstreamSpec = nil
+		deepCopy(cluster.GKEConfig, &cluster.GKEStatus.UpstreamSpec)
+		kubernetesVersion = cluster.GKEConfig.KubernetesVersion
+	}
+	if kubernetesVersion != nil && *kubernetesVersion != "" {
+		cluster.Version = &management.Info{GitVersion: "v" + strings.TrimPrefix(*kubernetesVersion, "v")}
+	}
+}
+
+func (s *Server) handleClusters(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		name := r.URL.Query().Get("name")
+		var data []*management.Cluster
+		for _, record := range s.clusters {
+			if name == "" || record.cluster.Name == name {
+				data = append(data, record.cluster)
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"type": "collection", "resourceType": management.ClusterType, "data": data})
+	case http.MethodPost:
+		cluster := new(management.Cluster)
+		if err := json.NewDecoder(r.Body).Decode(cluster); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for _, record := range s.clusters {
+			if record.cluster.Name == cluster.Name {
+				writeError(w, http.StatusConflict, fmt.Sprintf("cluster name %s is already in use", cluster.Name))
+				return
+			}
+		}
+		cluster.ID = ""
+		record := s.newClusterRecord(cluster)
+		s.notify(record, "ADDED")
+		s.startTransition(record, s.CreateTransitions)
+		writeJSON(w, http.StatusCreated, record.cluster)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, r.Method)
+	}
+}
+
+func (s *Server) handleCluster(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/v3/clusters/")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.clusters[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("clusters.management.cattle.io %q not found", id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, record.cluster)
+	case http.MethodPut:
+		// only the provided top-level fields are replaced, as norman does; nested maps are replaced rather than merged
+		var fields map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		stored := map[string]any{}
+		deepCopy(record.cluster, &stored)
+		for key, value := range fields {
+			stored[key] = value
+		}
+		updated := new(management.Cluster)
+		deepCopy(stored, updated)
+		updated.Resource = record.cluster.Resource
+		// status fields are owned by the server
+		updated.State, updated.Transitioning, updated.TransitioningMessage = record.cluster.State, record.cluster.Transitioning, record.cluster.TransitioningMessage
+		updated.Conditions = record.cluster.Conditions
+		updated.AKSStatus, updated.EKSStatus, updated.GKEStatus = record.cluster.AKSStatus, record.cluster.EKSStatus, record.cluster.GKEStatus
+		record.cluster = updated
+		s.notify(record, "MODIFIED")
+		s.startTransition(record, s.UpdateTransitions)
+		writeJSON(w, http.StatusOK, record.cluster)
+	case http.MethodDelete:
+		delete(s.clusters, id)
+		s.notify(record, "DELETED")
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, r.Method)
+	}
+}
+
+func copyCluster(cluster *management.Cluster) *management.Cluster {
+	copied := new(management.Cluster)
+	deepCopy(cluster, copied)
+	return copied
+}
+
+func deepCopy(in, out any) {
+	data, err := json.Marshal(in)
+	if err != nil {
+		panic(err)
+	}
+	if err = json.Unmarshal(data, out); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,44 @@
+package fakerancher
+
+import (
+	"net/http"
+)
+
+// schema describes a resource type served by the fake; the collection link is derived from the plural name
+type schema struct {
+	id                string
+	pluralName        string
+	collectionMethods []string
+	resourceMethods   []string
+}
+
+var (
+	managementSchemas = []schema{
+		{id: "cluster", pluralName: "clusters", collectionMethods: []string{"GET", "POST"}, resourceMethods: []string{"GET", "PUT", "DELETE"}},
+		{id: "setting", pluralName: "settings", collectionMethods: []string{"GET"}, resourceMethods: []string{"GET", "PUT"}},
+		{id: "token", pluralName: "tokens", collectionMethods: []string{"GET"}, resourceMethods: []string{"GET"}},
+	}
+	steveSchemas = []schema{
+		{id: "secret", pluralName: "secrets", collectionMethods: []string{"GET", "POST"}, resourceMethods: []string{"GET", "PUT", "DELETE"}},
+	}
+)
+
+func (s *Server) handleSchemas(version string, schemas []schema) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var data []map[string]any
+		for _, sc := range schemas {
+			data = append(data, map[string]any{
+				"id":                sc.id,
+				"type":              "schema",
+				"pluralName":        sc.pluralName,
+				"collectionMethods": sc.collectionMethods,
+				"resourceMethods":   sc.resourceMethods,
+				"links": map[string]string{
+					"self":       s.URL + "/" + version + "/schemas/" + sc.id,
+					"collection": s.URL + "/" + version + "/" + sc.pluralName,
+				},
+			})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"type": "collection", "resourceType": "schema", "data": data})
+	}
+}
@@ -0,0 +1,66 @@
+package fakerancher
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+)
+
+// Secrets returns the secrets created via Steve, such as cloud credentials, keyed by namespace/name
+func (s *Server) Secrets() map[string]map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	secrets := map[string]map[string]any{}
+	for id, secret := range s.secrets {
+		secrets[id] = secret
+	}
+	return secrets
+}
+
+func (s *Server) handleSecrets(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/secrets"), "/")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		var secret map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&secret); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		metadata, _ := secret["metadata"].(map[string]any)
+		if metadata == nil {
+			metadata = map[string]any{}
+		}
+		if name, _ := metadata["name"].(string); name == "" {
+			generateName, _ := metadata["generateName"].(string)
+			metadata["name"] = namegen.AppendRandomString(generateName)
+		}
+		namespace, _ := metadata["namespace"].(string)
+		id = fmt.Sprintf("%s/%s", namespace, metadata["name"])
+		metadata["state"] = map[string]any{"name": "active", "transitioning": false, "error": false}
+		metadata["resourceVersion"] = s.nextResourceVersion()
+		secret["metadata"] = metadata
+		secret["id"] = id
+		secret["type"] = "secret"
+		secret["links"] = map[string]string{"self": s.URL + "/v1/secrets/" + id}
+		s.secrets[id] = secret
+		writeJSON(w, http.StatusCreated, secret)
+	case id != "" && r.Method == http.MethodGet:
+		secret, ok := s.secrets[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("secrets %q not found", id))
+			return
+		}
+		writeJSON(w, http.StatusOK, secret)
+	case id != "" && r.Method == http.MethodDelete:
+		delete(s.secrets, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, r.Method)
+	}
+}
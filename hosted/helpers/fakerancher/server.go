@@ -0,0 +1,235 @@
+// Package fakerancher provides an in-process fake of the Rancher v3 management, Steve and
+// management.cattle.io watch endpoints used by the shepherd rancher.Client,
+// so that the helpers can be exercised without a live Rancher server.
+package fakerancher
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/pkg/config"
+	"github.com/rancher/shepherd/pkg/session"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// TokenID is the ID of the admin token accepted by the fake server
+	TokenID = "token-fake"
+	// Token is the admin bearer token accepted by the fake server
+	Token = TokenID + ":fakesecret"
+	// UserID is the ID of the user owning Token
+	UserID = "user-fake"
+)
+
+// DefaultSettings are the settings available on a new Server
+var DefaultSettings = map[string]string{
+	"server-version":                  "v2.11.0",
+	"ui-k8s-supported-versions-range": ">=v1.30.x <=v1.32.x",
+	"rke-metadata-config":             `{"refresh-interval-minutes":"1440","url":"https://releases.rancher.com/kontainer-driver-metadata/release-v2.11/data.json"}`,
+}
+
+// Server is a fake Rancher server; the zero value is not usable, use NewServer instead.
+type Server struct {
+	*httptest.Server
+
+	// CreateTransitions are the states a cluster goes through after being created
+	CreateTransitions []ClusterState
+	// UpdateTransitions are the states a cluster goes through after being updated
+	UpdateTransitions []ClusterState
+	// TransitionInterval is the time spent in each state of a transition
+	TransitionInterval time.Duration
+
+	mu              sync.Mutex
+	settings        map[string]string
+	clusters        map[string]*clusterRecord
+	secrets         map[string]map[string]any
+	watchers        map[string][]chan watchEvent
+	resourceVersion int
+	requests        []string
+}
+
+// NewServer starts a new fake Rancher server listening on a local TLS port
+func NewServer() *Server {
+	s := &Server{
+		CreateTransitions:  []ClusterState{StateProvisioning, StateActive},
+		UpdateTransitions:  []ClusterState{StateUpdating, StateActive},
+		TransitionInterval: 50 * time.Millisecond,
+		settings:           map[string]string{},
+		clusters:           map[string]*clusterRecord{},
+		secrets:            map[string]map[string]any{},
+		watchers:           map[string][]chan watchEvent{},
+	}
+	for key, value := range DefaultSettings {
+		s.settings[key] = value
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3", s.handleRoot("v3"))
+	mux.HandleFunc("/v3/schemas", s.handleSchemas("v3", managementSchemas))
+	mux.HandleFunc("/v3/settings/", s.handleSetting)
+	mux.HandleFunc("/rancherversion", s.handleRancherVersion)
+	mux.HandleFunc("/v3/tokens/", s.handleToken)
+	mux.HandleFunc("/v3/clusters", s.handleClusters)
+	mux.HandleFunc("/v3/clusters/", s.handleCluster)
+	mux.HandleFunc("/v1", s.handleRoot("v1"))
+	mux.HandleFunc("/v1/schemas", s.handleSchemas("v1", steveSchemas))
+	mux.HandleFunc("/v1/secrets", s.handleSecrets)
+	mux.HandleFunc("/v1/secrets/", s.handleSecrets)
+	mux.HandleFunc("/apis/management.cattle.io/v3/clusters", s.handleClusterWatch)
+
+	s.Server = httptest.NewTLSServer(s.authenticate(mux))
+	return s
+}
+
+// Host returns the host:port of the server as expected by rancher.Config
+func (s *Server) Host() string {
+	return strings.TrimPrefix(s.URL, "https://")
+}
+
+// WriteConfig writes a cattle config file pointing to the server and sets CATTLE_TEST_CONFIG to it;
+// extra contains additional top level keys such as aksClusterConfig or azureCredentials.
+// It returns the path of the file, which should be removed by the caller.
+func (s *Server) WriteConfig(extra map[string]any) (string, error) {
+	content := map[string]any{}
+	for key, value := range extra {
+		content[key] = value
+	}
+	content[rancher.ConfigurationFileKey] = rancher.Config{
+		Host:       s.Host(),
+		AdminToken: Token,
+		Insecure:   pointer.Bool(true),
+		Cleanup:    pointer.Bool(false),
+	}
+	file, err := os.CreateTemp("", "fakerancher-*.yaml")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	if err = json.NewEncoder(file).Encode(content); err != nil {
+		return "", err
+	}
+	return file.Name(), os.Setenv(config.ConfigEnvironmentKey, file.Name())
+}
+
+// NewClient returns a rancher.Client authenticated against the server;
+// WriteConfig must have been called before since the client reads its configuration from CATTLE_TEST_CONFIG.
+func (s *Server) NewClient() (*rancher.Client, error) {
+	return rancher.NewClient(Token, session.NewSession())
+}
+
+// SetSetting creates or updates a management.cattle.io setting
+func (s *Server) SetSetting(name, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settings[name] = value
+}
+
+// Requests returns the "METHOD path" of every request received by the server so far
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+
+		if r.Header.Get("Authorization") != "Bearer "+Token {
+			writeError(w, http.StatusUnauthorized, "must authenticate")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleRoot(version string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-API-Schemas", s.URL+"/"+version+"/schemas")
+		writeJSON(w, http.StatusOK, map[string]any{"type": "apiRoot"})
+	}
+}
+
+func (s *Server) handleSetting(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/v3/settings/")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var setting management.Setting
+		if err := json.NewDecoder(r.Body).Decode(&setting); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.settings[name] = setting.Value
+	default:
+		writeError(w, http.StatusMethodNotAllowed, r.Method)
+		return
+	}
+
+	value, ok := s.settings[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("settings.management.cattle.io %q not found", name))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":    name,
+		"type":  "setting",
+		"name":  name,
+		"value": value,
+		"links": map[string]string{"self": s.URL + r.URL.Path},
+	})
+}
+
+// handleRancherVersion reports the server-version setting like the /rancherversion endpoint of Rancher
+func (s *Server) handleRancherVersion(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]string{"Version": s.settings["server-version"], "GitCommit": "fake", "RancherPrime": "false"})
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/v3/tokens/")
+	if id != TokenID {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("token %q not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":     id,
+		"type":   "token",
+		"userId": UserID,
+		"links":  map[string]string{"self": s.URL + r.URL.Path},
+	})
+}
+
+func (s *Server) nextResourceVersion() string {
+	s.resourceVersion++
+	return fmt.Sprintf("%d", s.resourceVersion)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{
+		"type":    "error",
+		"status":  status,
+		"code":    http.StatusText(status),
+		"message": message,
+	})
+}
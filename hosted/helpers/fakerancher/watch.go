@@ -0,0 +1,102 @@
+package fakerancher
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// watchEvent is a kubernetes watch event as sent by the management.cattle.io watch endpoint
+type watchEvent struct {
+	Type   string         `json:"type"`
+	Object map[string]any `json:"object"`
+}
+
+// notify must be called with the lock held
+func (s *Server) notify(record *clusterRecord, eventType string) {
+	event := watchEvent{Type: eventType, Object: s.clusterObject(record.cluster)}
+	for _, ch := range s.watchers[record.cluster.ID] {
+		// watchers are buffered; a watcher that can not keep up misses the event instead of blocking the server
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// clusterObject converts the norman cluster into the management.cattle.io/v3 Cluster object sent to the watchers;
+// only the fields used by the watch functions are populated
+func (s *Server) clusterObject(cluster *management.Cluster) map[string]any {
+	return map[string]any{
+		"apiVersion": "management.cattle.io/v3",
+		"kind":       "Cluster",
+		"metadata": map[string]any{
+			"name":            cluster.ID,
+			"resourceVersion": s.nextResourceVersion(),
+		},
+		"spec": map[string]any{
+			"displayName": cluster.Name,
+		},
+		"status": map[string]any{
+			"conditions": cluster.Conditions,
+		},
+	}
+}
+
+func (s *Server) handleClusterWatch(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("watch") != "true" {
+		writeError(w, http.StatusMethodNotAllowed, "only watch is supported")
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Query().Get("fieldSelector"), "metadata.name=")
+
+	s.mu.Lock()
+	record, ok := s.clusters[id]
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "clusters.management.cattle.io \""+id+"\" not found")
+		return
+	}
+	ch := make(chan watchEvent, 100)
+	ch <- watchEvent{Type: "ADDED", Object: s.clusterObject(record.cluster)}
+	s.watchers[id] = append(s.watchers[id], ch)
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		watchers := s.watchers[id]
+		for i := range watchers {
+			if watchers[i] == ch {
+				s.watchers[id] = append(watchers[:i], watchers[i+1:]...)
+				break
+			}
+		}
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	encoder := json.NewEncoder(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-ch:
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			if event.Type == "DELETED" {
+				return
+			}
+		}
+	}
+}
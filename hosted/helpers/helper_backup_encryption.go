@@ -0,0 +1,143 @@
+package helpers
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/shepherd/extensions/defaults/stevetypes"
+	"sigs.k8s.io/yaml"
+)
+
+// encryptionConfigKey is the key of the EncryptionConfiguration in the secret, as expected by the rancher-backup operator
+const encryptionConfigKey = "encryption-provider-config.yaml"
+
+// EncryptionConfig is the EncryptionConfiguration with which the rancher-backup operator encrypts the secrets of a backup,
+// for e.g. the cloud credentials; it is stored in the SecretName secret of BackupNamespace.
+type EncryptionConfig struct {
+	SecretName string
+	// Config is the apiserver.config.k8s.io/v1 EncryptionConfiguration
+	Config []byte
+}
+
+var (
+	encryptionConfigsMu sync.Mutex
+	// encryptionConfigs are kept by secret name since the restore runs on a new k3s, on which the secret must be created again
+	encryptionConfigs = map[string]*EncryptionConfig{}
+)
+
+// NewEncryptionConfig returns an EncryptionConfiguration with a random aescbc key; the secret is created by suite.ExecuteBackup,
+// suite.ExecuteScheduledBackup and suite.ExecuteRestore once the operator is installed, when the resource refers to it by EncryptionConfigSecretName
+func NewEncryptionConfig(secretName string) (*EncryptionConfig, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.Wrap(err, "Failed to generate the encryption key")
+	}
+	config, err := yaml.Marshal(map[string]any{
+		"apiVersion": "apiserver.config.k8s.io/v1",
+		"kind":       "EncryptionConfiguration",
+		"resources": []any{map[string]any{
+			"resources": []string{"secrets"},
+			"providers": []any{map[string]any{
+				"aescbc": map[string]any{"keys": []any{map[string]string{"name": "key1", "secret": base64.StdEncoding.EncodeToString(key)}}},
+			}},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encryption := &EncryptionConfig{SecretName: secretName, Config: config}
+	encryptionConfigsMu.Lock()
+	defer encryptionConfigsMu.Unlock()
+	encryptionConfigs[secretName] = encryption
+	return encryption, nil
+}
+
+// Manifest returns the YAML manifest of the secret
+func (e *EncryptionConfig) Manifest() ([]byte, error) {
+	return yaml.Marshal(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   backupMetadata{Name: e.SecretName, Namespace: BackupNamespace},
+		"data":       map[string][]byte{encryptionConfigKey: e.Config},
+	})
+}
+
+// ApplyEncryptionConfig creates the secret of the EncryptionConfig created by NewEncryptionConfig; it does nothing if secretName is empty
+func ApplyEncryptionConfig(ctx context.Context, secretName string) error {
+	if secretName == "" {
+		return nil
+	}
+	encryptionConfigsMu.Lock()
+	encryption, ok := encryptionConfigs[secretName]
+	encryptionConfigsMu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown encryption config secret %s, it must be created with NewEncryptionConfig", secretName)
+	}
+	manifest, err := encryption.Manifest()
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(ApplyManifest(ctx, manifest), "Failed to create the encryption config secret %s", secretName)
+}
+
+// CloudCredentialData returns the base64-encoded values of the cloud credential, as stored in a backup when it is not encrypted
+func CloudCredentialData(client *rancher.Client, cloudCredentialID string) ([]string, error) {
+	secret, err := client.Steve.SteveType(stevetypes.Secret).ByID(strings.Replace(cloudCredentialID, ":", "/", 1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get cloud credential %s", cloudCredentialID)
+	}
+	data, _ := secret.JSONResp["data"].(map[string]any)
+	var values []string
+	for _, value := range data {
+		if value, ok := value.(string); ok && value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("cloud credential %s has no data", cloudCredentialID)
+	}
+	return values, nil
+}
+
+// BackupFileContains returns true if the backup file contains one of the values, for e.g. those of CloudCredentialData;
+// the file is decompressed if it is gzipped, as the unencrypted backups are
+func BackupFileContains(file string, values ...string) (bool, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	var content io.Reader = reader
+	if header, err := reader.Peek(2); err == nil && bytes.Equal(header, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return false, err
+		}
+		defer gz.Close()
+		content = gz
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to read backup file %s", file)
+	}
+	for _, value := range values {
+		if bytes.Contains(data, []byte(value)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
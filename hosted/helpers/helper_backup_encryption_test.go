@@ -0,0 +1,97 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("BackupEncryption", func() {
+	It("renders the secret of a random EncryptionConfiguration", func() {
+		encryption, err := helpers.NewEncryptionConfig("hp-backup-encryption")
+		Expect(err).To(BeNil())
+		other, err := helpers.NewEncryptionConfig("hp-other-encryption")
+		Expect(err).To(BeNil())
+		Expect(encryption.Config).NotTo(Equal(other.Config))
+
+		manifest, err := encryption.Manifest()
+		Expect(err).To(BeNil())
+		var secret struct {
+			Kind     string            `json:"kind"`
+			Metadata map[string]string `json:"metadata"`
+			Data     map[string][]byte `json:"data"`
+		}
+		Expect(yaml.Unmarshal(manifest, &secret)).To(Succeed())
+		Expect(secret.Kind).To(Equal("Secret"))
+		Expect(secret.Metadata).To(Equal(map[string]string{"name": "hp-backup-encryption", "namespace": "cattle-resources-system"}))
+		Expect(secret.Data).To(HaveKeyWithValue("encryption-provider-config.yaml", encryption.Config))
+		Expect(string(encryption.Config)).To(And(ContainSubstring("kind: EncryptionConfiguration"), ContainSubstring("aescbc:"), ContainSubstring("- secrets")))
+	})
+
+	It("finds the values of a secret in a gzipped backup", func() {
+		dir := GinkgoT().TempDir()
+		backupFile := filepath.Join(dir, "hp-backup-1234-2024-11-05T10-00-00Z.tar.gz")
+		writeBackupFile(backupFile, "secrets.#v1/cattle-global-data/cc-abcde.json", `{"data":{"accessKey":"QUtJQUVYQU1QTEU="}}`)
+		Expect(helpers.BackupFileContains(backupFile, "c2VjcmV0", "QUtJQUVYQU1QTEU=")).To(BeTrue())
+		Expect(helpers.BackupFileContains(backupFile, "c2VjcmV0")).To(BeFalse())
+
+		encryptedFile := filepath.Join(dir, "hp-backup-1234-2024-11-05T10-00-00Z.tar.gz.enc")
+		Expect(os.WriteFile(encryptedFile, []byte("k8s:enc:aescbc:v1:key1:..."), 0o600)).To(Succeed())
+		Expect(helpers.BackupFileContains(encryptedFile, "QUtJQUVYQU1QTEU=")).To(BeFalse())
+
+		_, err := helpers.BackupFileContains(filepath.Join(dir, "missing.tar.gz"), "QUtJQUVYQU1QTEU=")
+		Expect(err).To(MatchError(os.ErrNotExist))
+	})
+
+	It("returns the values of the cloud credential", func() {
+		originalProvider := helpers.Provider
+		DeferCleanup(func() {
+			helpers.Provider = originalProvider
+		})
+		helpers.Provider = "eks"
+		cloudCredID, err := helpers.CreateCloudCredentials(client)
+		Expect(err).To(BeNil())
+
+		values, err := helpers.CloudCredentialData(client, cloudCredID)
+		Expect(err).To(BeNil())
+		Expect(values).NotTo(BeEmpty())
+
+		_, err = helpers.CloudCredentialData(client, "cattle-global-data:cc-missing")
+		Expect(err).To(MatchError(ContainSubstring("Failed to get cloud credential cattle-global-data:cc-missing")))
+	})
+})
+
+// writeBackupFile writes a gzipped tarball holding a single file, as the rancher-backup operator does for each resource
+func writeBackupFile(path, name, content string) {
+	f, err := os.Create(path)
+	Expect(err).To(BeNil())
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content))})).To(Succeed())
+	_, err = tw.Write([]byte(content))
+	Expect(err).To(BeNil())
+	Expect(tw.Close()).To(Succeed())
+	Expect(gz.Close()).To(Succeed())
+}
@@ -0,0 +1,194 @@
+package helpers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// backupAPIVersion is the API version of the resources of the rancher-backup operator
+	backupAPIVersion = "resources.cattle.io/v1"
+	// DefaultResourceSetName is the ResourceSet installed by the rancher-backup chart, covering the Rancher resources
+	DefaultResourceSetName = "rancher-resource-set"
+	// descriptionAnnotation is the annotation shown as description by the Rancher UI
+	descriptionAnnotation = "field.cattle.io/description"
+)
+
+// Backup is a Backup resource of the rancher-backup operator, rendered by Manifest and applied by suite.ExecuteBackup
+type Backup struct {
+	Name        string
+	Description string
+	// ResourceSetName is the ResourceSet selecting the backed up resources
+	ResourceSetName string
+	// RetentionCount is the number of backups kept when Schedule is set
+	RetentionCount int
+	// Schedule is the cron schedule of a recurring backup; the backup is taken once if empty
+	Schedule string
+	// EncryptionConfigSecretName is the secret in BackupNamespace holding the EncryptionConfiguration of the backed up secrets
+	EncryptionConfigSecretName string
+	// StorageLocation overrides the storage set at the installation of the operator
+	StorageLocation *S3Storage
+}
+
+// NewBackup returns a one-time backup of the Rancher resources, as taken by the backup-restore suites
+func NewBackup(name string) *Backup {
+	return &Backup{
+		Name:            name,
+		Description:     "Backup HP/Rancher resources",
+		ResourceSetName: DefaultResourceSetName,
+		RetentionCount:  1,
+	}
+}
+
+// Restore is a Restore resource of the rancher-backup operator, rendered by Manifest and applied by suite.ExecuteRestore
+type Restore struct {
+	Name        string
+	Description string
+	// BackupFilename is the file of the backup, as returned by suite.ExecuteBackup
+	BackupFilename string
+	// Prune deletes the resources of the ResourceSet which are not in the backup
+	Prune                bool
+	DeleteTimeoutSeconds int
+	// EncryptionConfigSecretName must be the secret used by the backup if it was encrypted
+	EncryptionConfigSecretName string
+	// StorageLocation overrides the storage set at the installation of the operator
+	StorageLocation *S3Storage
+}
+
+// NewRestore returns a restore of the backup file without pruning, as done by the backup-restore suites
+func NewRestore(name, backupFilename string) *Restore {
+	return &Restore{
+		Name:                 name,
+		Description:          "Restore HP/Rancher resources",
+		BackupFilename:       backupFilename,
+		DeleteTimeoutSeconds: 10,
+	}
+}
+
+// backupResource is the layout of the Backup and Restore resources
+type backupResource struct {
+	APIVersion string         `json:"apiVersion"`
+	Kind       string         `json:"kind"`
+	Metadata   backupMetadata `json:"metadata"`
+	Spec       any            `json:"spec"`
+}
+
+type backupMetadata struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type backupSpec struct {
+	StorageLocation            *storageLocation `json:"storageLocation,omitempty"`
+	ResourceSetName            string           `json:"resourceSetName"`
+	EncryptionConfigSecretName string           `json:"encryptionConfigSecretName,omitempty"`
+	Schedule                   string           `json:"schedule,omitempty"`
+	RetentionCount             int              `json:"retentionCount,omitempty"`
+}
+
+type restoreSpec struct {
+	BackupFilename             string           `json:"backupFilename"`
+	DeleteTimeoutSeconds       int              `json:"deleteTimeoutSeconds,omitempty"`
+	Prune                      bool             `json:"prune"`
+	EncryptionConfigSecretName string           `json:"encryptionConfigSecretName,omitempty"`
+	StorageLocation            *storageLocation `json:"storageLocation,omitempty"`
+}
+
+type storageLocation struct {
+	S3 s3ObjectStore `json:"s3"`
+}
+
+type s3ObjectStore struct {
+	CredentialSecretName      string `json:"credentialSecretName,omitempty"`
+	CredentialSecretNamespace string `json:"credentialSecretNamespace,omitempty"`
+	BucketName                string `json:"bucketName"`
+	Region                    string `json:"region,omitempty"`
+	Folder                    string `json:"folder,omitempty"`
+	Endpoint                  string `json:"endpoint"`
+	EndpointCA                string `json:"endpointCA,omitempty"`
+	InsecureTLSSkipVerify     bool   `json:"insecureTLSSkipVerify,omitempty"`
+}
+
+// newStorageLocation returns the storage location of the bucket, whose credentials are in the secret created by suite.InstallBackupOperator
+func newStorageLocation(s *S3Storage) *storageLocation {
+	if s == nil {
+		return nil
+	}
+	location := &storageLocation{S3: s3ObjectStore{
+		CredentialSecretName:      backupS3CredentialSecret,
+		CredentialSecretNamespace: BackupNamespace,
+		BucketName:                s.Bucket,
+		Region:                    s.Region,
+		Folder:                    s.Folder,
+		Endpoint:                  s.Endpoint,
+		InsecureTLSSkipVerify:     s.InsecureTLSSkipVerify,
+	}}
+	if len(s.EndpointCA) > 0 {
+		location.S3.EndpointCA = base64.StdEncoding.EncodeToString(s.EndpointCA)
+	}
+	return location
+}
+
+func newBackupMetadata(name, description string) backupMetadata {
+	metadata := backupMetadata{Name: name}
+	if description != "" {
+		metadata.Annotations = map[string]string{descriptionAnnotation: description}
+	}
+	return metadata
+}
+
+// Manifest returns the YAML manifest of the backup
+func (b *Backup) Manifest() ([]byte, error) {
+	if b.Name == "" || b.ResourceSetName == "" {
+		return nil, fmt.Errorf("backup %q must have a name and a resource set", b.Name)
+	}
+	if b.RetentionCount < 0 {
+		return nil, fmt.Errorf("backup %s has a negative retention count %d", b.Name, b.RetentionCount)
+	}
+	return yaml.Marshal(backupResource{
+		APIVersion: backupAPIVersion,
+		Kind:       "Backup",
+		Metadata:   newBackupMetadata(b.Name, b.Description),
+		Spec: backupSpec{
+			StorageLocation:            newStorageLocation(b.StorageLocation),
+			ResourceSetName:            b.ResourceSetName,
+			EncryptionConfigSecretName: b.EncryptionConfigSecretName,
+			Schedule:                   b.Schedule,
+			RetentionCount:             b.RetentionCount,
+		},
+	})
+}
+
+// Manifest returns the YAML manifest of the restore
+func (r *Restore) Manifest() ([]byte, error) {
+	if r.Name == "" || r.BackupFilename == "" {
+		return nil, fmt.Errorf("restore %q must have a name and a backup filename", r.Name)
+	}
+	return yaml.Marshal(backupResource{
+		APIVersion: backupAPIVersion,
+		Kind:       "Restore",
+		Metadata:   newBackupMetadata(r.Name, r.Description),
+		Spec: restoreSpec{
+			BackupFilename:             r.BackupFilename,
+			DeleteTimeoutSeconds:       r.DeleteTimeoutSeconds,
+			Prune:                      r.Prune,
+			EncryptionConfigSecretName: r.EncryptionConfigSecretName,
+			StorageLocation:            newStorageLocation(r.StorageLocation),
+		},
+	})
+}
+
+// ApplyManifest applies the manifest to the upstream cluster from memory, via the standard input of kubectl
+func ApplyManifest(ctx context.Context, manifest []byte) error {
+	args := []string{"apply", "--filename", "-"}
+	if Kubeconfig != "" {
+		args = append([]string{"--kubeconfig", Kubeconfig}, args...)
+	}
+	out, err := RunCLIWithInput(ctx, manifest, "kubectl", args...)
+	return errors.Wrapf(err, "Failed to apply the manifest: %s", out)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakecli"
+)
+
+var _ = Describe("BackupManifests", func() {
+	It("renders the backup and the restore of the suites", func() {
+		manifest, err := helpers.NewBackup("hp-backup").Manifest()
+		Expect(err).To(BeNil())
+		Expect(manifest).To(MatchYAML(`
+apiVersion: resources.cattle.io/v1
+kind: Backup
+metadata:
+  name: hp-backup
+  annotations:
+    field.cattle.io/description: Backup HP/Rancher resources
+spec:
+  resourceSetName: rancher-resource-set
+  retentionCount: 1
+`))
+
+		manifest, err = helpers.NewRestore("hp-restore", "hp-backup-1234.tar.gz").Manifest()
+		Expect(err).To(BeNil())
+		Expect(manifest).To(MatchYAML(`
+apiVersion: resources.cattle.io/v1
+kind: Restore
+metadata:
+  name: hp-restore
+  annotations:
+    field.cattle.io/description: Restore HP/Rancher resources
+spec:
+  backupFilename: hp-backup-1234.tar.gz
+  deleteTimeoutSeconds: 10
+  prune: false
+`))
+	})
+
+	It("renders the optional fields", func() {
+		storage := &helpers.S3Storage{Bucket: "hp-backups", Endpoint: "10.1.2.3:32768", Folder: "run-1", EndpointCA: []byte("CA")}
+		backup := helpers.NewBackup("hp-scheduled")
+		backup.Description = ""
+		backup.Schedule = "@every 1h"
+		backup.RetentionCount = 3
+		backup.EncryptionConfigSecretName = "hp-encryption"
+		backup.StorageLocation = storage
+		manifest, err := backup.Manifest()
+		Expect(err).To(BeNil())
+		Expect(manifest).To(MatchYAML(`
+apiVersion: resources.cattle.io/v1
+kind: Backup
+metadata:
+  name: hp-scheduled
+spec:
+  resourceSetName: rancher-resource-set
+  schedule: '@every 1h'
+  retentionCount: 3
+  encryptionConfigSecretName: hp-encryption
+  storageLocation:
+    s3:
+      credentialSecretName: hp-backup-s3-credentials
+      credentialSecretNamespace: cattle-resources-system
+      bucketName: hp-backups
+      folder: run-1
+      endpoint: 10.1.2.3:32768
+      endpointCA: Q0E=
+`))
+
+		restore := helpers.NewRestore("hp-restore", "")
+		_, err = restore.Manifest()
+		Expect(err).To(MatchError(ContainSubstring("must have a name and a backup filename")))
+	})
+
+	It("applies the manifest from memory", func(ctx SpecContext) {
+		manifest, err := helpers.NewRestore("hp-restore", "hp-backup-1234.tar.gz").Manifest()
+		Expect(err).To(BeNil())
+		runner := fakecli.NewRunner()
+		runner.Expect("kubectl", "apply", "--filename", "-").WithInput(string(manifest)).Returns("restore.resources.cattle.io/hp-restore created")
+		DeferCleanup(runner.Install())
+
+		Expect(helpers.ApplyManifest(ctx, manifest)).To(Succeed())
+		Expect(runner.Verify()).To(Succeed())
+	})
+})
@@ -0,0 +1,314 @@
+package helpers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+)
+
+const (
+	// BackupStorageLocal stores the backups in the local-path volume of the k3s node, from which they are copied to the working directory
+	BackupStorageLocal = "local"
+	// BackupStorageS3 stores the backups in an S3-compatible bucket, which works with a remote or multi-node Rancher
+	BackupStorageS3 = "s3"
+
+	// BackupNamespace is the namespace of the rancher-backup operator
+	BackupNamespace = "cattle-resources-system"
+	// backupS3CredentialSecret is the secret holding the accessKey and secretKey of the S3Storage in BackupNamespace
+	backupS3CredentialSecret = "hp-backup-s3-credentials"
+
+	// MinIOImage is the image of the MinIO container started by StartMinIO; it is pinned so that a MinIO or mc release cannot change the runs
+	MinIOImage = "quay.io/minio/minio:RELEASE.2025-04-22T22-12-26Z"
+	minIOPort  = "9000/tcp"
+)
+
+// S3Storage is the S3-compatible bucket in which the rancher-backup operator stores the backups
+type S3Storage struct {
+	Bucket    string
+	Endpoint  string
+	Region    string
+	Folder    string
+	AccessKey string
+	SecretKey string
+	// EndpointCA is the PEM CA of the endpoint, for e.g. the self-signed certificate of a MinIO
+	EndpointCA            []byte
+	InsecureTLSSkipVerify bool
+	// container is the MinIO container started by StartMinIO, through which the bucket can be listed
+	container string
+}
+
+// ChartValues returns the --set flags of the rancher-backup chart making the bucket its default storage location
+func (s *S3Storage) ChartValues() []string {
+	values := []string{
+		"s3.enabled=true",
+		"s3.credentialSecretName=" + backupS3CredentialSecret,
+		"s3.credentialSecretNamespace=" + BackupNamespace,
+		"s3.bucketName=" + s.Bucket,
+		"s3.endpoint=" + s.Endpoint,
+		"s3.insecureTLSSkipVerify=" + strconv.FormatBool(s.InsecureTLSSkipVerify),
+	}
+	if s.Region != "" {
+		values = append(values, "s3.region="+s.Region)
+	}
+	if s.Folder != "" {
+		values = append(values, "s3.folder="+s.Folder)
+	}
+	if len(s.EndpointCA) > 0 {
+		values = append(values, "s3.endpointCA="+base64.StdEncoding.EncodeToString(s.EndpointCA))
+	}
+	var flags []string
+	for _, value := range values {
+		flags = append(flags, "--set", value)
+	}
+	return flags
+}
+
+// CreateCredentialSecret creates the secret referenced by the chart values; it is recreated since the restore runs on a new k3s
+func (s *S3Storage) CreateCredentialSecret(ctx context.Context) error {
+	if _, err := kubectlCLI(ctx, "delete", "secret", backupS3CredentialSecret, "--namespace", BackupNamespace, "--ignore-not-found"); err != nil {
+		return errors.Wrap(err, "Failed to delete the S3 credential secret")
+	}
+	if out, err := kubectlCLI(ctx, "create", "namespace", BackupNamespace); err != nil && !strings.Contains(out, "AlreadyExists") {
+		return errors.Wrapf(err, "Failed to create namespace %s: %s", BackupNamespace, out)
+	}
+	out, err := kubectlCLI(ctx, "create", "secret", "generic", backupS3CredentialSecret, "--namespace", BackupNamespace,
+		"--from-literal", "accessKey="+s.AccessKey, "--from-literal", "secretKey="+s.SecretKey)
+	return errors.Wrapf(err, "Failed to create the S3 credential secret: %s", out)
+}
+
+// ListFiles returns the files of the folder of the bucket starting with prefix, sorted by name;
+// only the bucket of the MinIO started by StartMinIO can be listed
+func (s *S3Storage) ListFiles(ctx context.Context, prefix string) ([]string, error) {
+	if s.container == "" {
+		return nil, fmt.Errorf("listing bucket %s is only supported with the MinIO started by StartMinIO", s.Bucket)
+	}
+	out, err := RunCLI(ctx, "docker", "exec", s.container, "mc", "ls", "--insecure", s.objectPath(""))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to list bucket %s: %s", s.Bucket, out)
+	}
+	// mc ls prints the date, size and storage class before the name, for e.g. [2024-11-05 10:00:00 UTC] 12KiB STANDARD hp-backup-...tar.gz
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && strings.HasPrefix(fields[len(fields)-1], prefix) {
+			files = append(files, fields[len(fields)-1])
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// DownloadFile copies the file of the folder of the bucket to dest on the test host, for e.g. to check the content of a backup;
+// only the bucket of the MinIO started by StartMinIO can be read
+func (s *S3Storage) DownloadFile(ctx context.Context, name, dest string) error {
+	if s.container == "" {
+		return fmt.Errorf("reading bucket %s is only supported with the MinIO started by StartMinIO", s.Bucket)
+	}
+	// the objects are not stored as plain files by MinIO, hence they are copied out of the bucket first
+	tmp := "/tmp/" + name
+	if out, err := RunCLI(ctx, "docker", "exec", s.container, "mc", "cp", "--insecure", s.objectPath(name), tmp); err != nil {
+		return errors.Wrapf(err, "Failed to read %s from bucket %s: %s", name, s.Bucket, out)
+	}
+	defer func() {
+		_, _ = RunCLI(ctx, "docker", "exec", s.container, "rm", "-f", tmp)
+	}()
+	out, err := RunCLI(ctx, "docker", "cp", s.container+":"+tmp, dest)
+	return errors.Wrapf(err, "Failed to copy %s from MinIO %s: %s", name, s.container, out)
+}
+
+// objectPath returns the path of the file of the folder of the bucket for the mc client of the MinIO container
+func (s *S3Storage) objectPath(name string) string {
+	return strings.TrimSuffix("local/"+s.Bucket+"/"+s.Folder, "/") + "/" + name
+}
+
+// kubectlCLI runs kubectl against the upstream cluster via the CommandRunner
+func kubectlCLI(ctx context.Context, args ...string) (string, error) {
+	if Kubeconfig != "" {
+		args = append([]string{"--kubeconfig", Kubeconfig}, args...)
+	}
+	return RunCLI(ctx, "kubectl", args...)
+}
+
+var (
+	s3StorageMu sync.Mutex
+	// s3Storage is the storage of the run, kept so that the restore reads the bucket written by the backup
+	s3Storage *S3Storage
+)
+
+// GetS3Storage returns the storage configured by the BACKUP_S3_* variables; if BACKUP_S3_ENDPOINT is unset, a MinIO container is started
+// on the test host by StartMinIO and kept until the end of the spec, so that the backup and the restore of a spec use the same bucket.
+func GetS3Storage(ctx context.Context) (*S3Storage, error) {
+	s3StorageMu.Lock()
+	defer s3StorageMu.Unlock()
+	if s3Storage != nil {
+		return s3Storage, nil
+	}
+
+	backup := Config.Backup
+	if backup.S3Endpoint == "" {
+		storage, err := StartMinIO(ctx, "", backup.S3Bucket)
+		if err != nil {
+			return nil, err
+		}
+		storage.Folder = backup.S3Folder
+		s3Storage = storage
+		return s3Storage, nil
+	}
+
+	storage := &S3Storage{
+		Bucket:                backup.S3Bucket,
+		Endpoint:              backup.S3Endpoint,
+		Region:                backup.S3Region,
+		Folder:                backup.S3Folder,
+		AccessKey:             backup.S3AccessKey,
+		SecretKey:             backup.S3SecretKey,
+		InsecureTLSSkipVerify: backup.S3InsecureTLSSkipVerify,
+	}
+	if backup.S3EndpointCA != "" {
+		ca, err := os.ReadFile(backup.S3EndpointCA)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to read BACKUP_S3_ENDPOINT_CA")
+		}
+		storage.EndpointCA = ca
+	}
+	s3Storage = storage
+	return s3Storage, nil
+}
+
+// StartMinIO starts a MinIO container on the test host, serving TLS with a self-signed certificate for host, and creates the bucket;
+// host is the address at which Rancher reaches the test host, it defaults to KDM_SERVER_HOST or else to the address of its default route.
+// The container is removed by Cleanups.
+func StartMinIO(ctx context.Context, host, bucket string) (*S3Storage, error) {
+	if host == "" {
+		var err error
+		if host, err = testHostAddress(); err != nil {
+			return nil, err
+		}
+	}
+	certsDir, err := os.MkdirTemp("", "hp-minio-certs-")
+	if err != nil {
+		return nil, err
+	}
+	ca, err := writeSelfSignedCertificate(certsDir, host)
+	if err != nil {
+		_ = os.RemoveAll(certsDir)
+		return nil, errors.Wrap(err, "Failed to create the certificate of MinIO")
+	}
+
+	name := "hp-minio-" + namegen.RandStringLower(5)
+	storage := &S3Storage{
+		Bucket:     bucket,
+		AccessKey:  namegen.RandStringLower(20),
+		SecretKey:  namegen.RandStringLower(40),
+		EndpointCA: ca,
+		container:  name,
+	}
+	out, err := RunCLI(ctx, "docker", "run", "--detach", "--name", name, "--publish", minIOPort,
+		"--volume", certsDir+":/certs:ro",
+		"--env", "MINIO_ROOT_USER="+storage.AccessKey, "--env", "MINIO_ROOT_PASSWORD="+storage.SecretKey,
+		MinIOImage, "server", "/data", "--certs-dir", "/certs")
+	if err != nil {
+		_ = os.RemoveAll(certsDir)
+		return nil, errors.Wrapf(err, "Failed to start MinIO: %s", out)
+	}
+	RegisterCleanup(CleanupKey("minio", name), "MinIO container "+name, func(ctx context.Context) error {
+		s3StorageMu.Lock()
+		s3Storage = nil
+		s3StorageMu.Unlock()
+		defer os.RemoveAll(certsDir)
+		out, err := RunCLI(ctx, "docker", "rm", "--force", "--volumes", name)
+		return errors.Wrapf(err, "Failed to remove MinIO container %s: %s", name, out)
+	})
+
+	out, err = RunCLI(ctx, "docker", "port", name, minIOPort)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the port of MinIO: %s", out)
+	}
+	// docker lists one line per address family, for e.g. 0.0.0.0:32768
+	_, port, err := net.SplitHostPort(strings.TrimSpace(strings.SplitN(out, "\n", 2)[0]))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse the port of MinIO %q", out)
+	}
+	storage.Endpoint = net.JoinHostPort(host, port)
+
+	// the certificate is self-signed, hence --insecure
+	err = WaitFor(ctx, "MinIO to be ready", 2*time.Minute, 2*time.Second, func() (bool, error) {
+		out, err := RunCLI(ctx, "docker", "exec", name, "mc", "alias", "set", "local", "https://127.0.0.1:9000", storage.AccessKey, storage.SecretKey, "--insecure")
+		if err != nil {
+			return false, fmt.Errorf("%w: %s", err, out)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out, err = RunCLI(ctx, "docker", "exec", name, "mc", "mb", "--ignore-existing", "local/"+bucket, "--insecure"); err != nil {
+		return nil, errors.Wrapf(err, "Failed to create bucket %s: %s", bucket, out)
+	}
+	Logger.Info(fmt.Sprintf("Started MinIO %s at %s with bucket %s", name, storage.Endpoint, bucket))
+	return storage, nil
+}
+
+// writeSelfSignedCertificate writes the public.crt and private.key of a self-signed certificate for host to dir, as expected by MinIO;
+// it returns the PEM certificate, which is its own CA
+func writeSelfSignedCertificate(dir, host string) ([]byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: host, Organization: []string{"hosted-providers-e2e"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(7 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:              []string{"localhost"},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		template.IPAddresses = append(template.IPAddresses, ip)
+	} else {
+		template.DNSNames = append(template.DNSNames, host)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err = os.WriteFile(filepath.Join(dir, "public.crt"), cert, 0o644); err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(filepath.Join(dir, "private.key"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKey}), 0o600); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
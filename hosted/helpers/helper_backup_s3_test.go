@@ -0,0 +1,106 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("StartMinIO", func() {
+	It("starts MinIO with a certificate for the test host and creates the bucket", func(ctx SpecContext) {
+		var commands []string
+		DeferCleanup(helpers.SetCommandRunner(helpers.CommandRunnerFunc(func(_ context.Context, name string, args ...string) (string, error) {
+			commands = append(commands, name+" "+strings.Join(args, " "))
+			if len(args) > 0 && args[0] == "port" {
+				return "0.0.0.0:32768\n[::]:32768\n", nil
+			}
+			return "", nil
+		})))
+
+		storage, err := helpers.StartMinIO(ctx, "10.1.2.3", "hp-backups")
+		Expect(err).To(BeNil())
+		Expect(storage.Endpoint).To(Equal("10.1.2.3:32768"))
+		Expect(commands).To(HaveLen(4))
+		Expect(commands[0]).To(And(HavePrefix("docker run --detach --name hp-minio"), ContainSubstring("MINIO_ROOT_PASSWORD="+storage.SecretKey),
+			HaveSuffix(helpers.MinIOImage+" server /data --certs-dir /certs")))
+		Expect(commands[3]).To(MatchRegexp(`^docker exec hp-minio\S+ mc mb --ignore-existing local/hp-backups --insecure$`))
+
+		block, _ := pem.Decode(storage.EndpointCA)
+		Expect(block).NotTo(BeNil())
+		cert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).To(BeNil())
+		Expect(cert.VerifyHostname("10.1.2.3")).To(Succeed())
+
+		Expect(storage.ChartValues()).To(ContainElements("s3.enabled=true", "s3.bucketName=hp-backups", "s3.endpoint=10.1.2.3:32768",
+			"s3.credentialSecretNamespace=cattle-resources-system", ContainSubstring("s3.endpointCA=")))
+		Expect(storage.ChartValues()).NotTo(ContainElement(HavePrefix("s3.folder=")))
+	})
+
+	It("lists the files of the bucket of MinIO", func(ctx SpecContext) {
+		var listed string
+		DeferCleanup(helpers.SetCommandRunner(helpers.CommandRunnerFunc(func(_ context.Context, name string, args ...string) (string, error) {
+			switch {
+			case len(args) > 0 && args[0] == "port":
+				return "0.0.0.0:32768\n", nil
+			case len(args) > 3 && args[3] == "ls":
+				listed = strings.Join(args, " ")
+				return "[2024-11-05 10:02:00 UTC] 12KiB STANDARD hp-backup-1234-2024-11-05T10-02-00Z.tar.gz\n" +
+					"[2024-11-05 10:00:00 UTC] 12KiB STANDARD hp-backup-1234-2024-11-05T10-00-00Z.tar.gz\n" +
+					"[2024-11-05 09:00:00 UTC] 12KiB STANDARD hp-other-5678-2024-11-05T09-00-00Z.tar.gz\n", nil
+			}
+			return "", nil
+		})))
+
+		storage, err := helpers.StartMinIO(ctx, "10.1.2.3", "hp-backups")
+		Expect(err).To(BeNil())
+		storage.Folder = "run-1"
+		Expect(storage.ListFiles(ctx, "hp-backup-")).To(Equal([]string{"hp-backup-1234-2024-11-05T10-00-00Z.tar.gz", "hp-backup-1234-2024-11-05T10-02-00Z.tar.gz"}))
+		Expect(listed).To(MatchRegexp(`^exec hp-minio\S+ mc ls --insecure local/hp-backups/run-1/$`))
+
+		_, err = (&helpers.S3Storage{Bucket: "remote"}).ListFiles(ctx, "hp-backup-")
+		Expect(err).To(MatchError(ContainSubstring("only supported with the MinIO started by StartMinIO")))
+	})
+
+	It("downloads a file of the bucket of MinIO", func(ctx SpecContext) {
+		var commands []string
+		DeferCleanup(helpers.SetCommandRunner(helpers.CommandRunnerFunc(func(_ context.Context, name string, args ...string) (string, error) {
+			if len(args) > 0 && args[0] == "port" {
+				return "0.0.0.0:32768\n", nil
+			}
+			commands = append(commands, name+" "+strings.Join(args, " "))
+			return "", nil
+		})))
+
+		storage, err := helpers.StartMinIO(ctx, "10.1.2.3", "hp-backups")
+		Expect(err).To(BeNil())
+		commands = nil
+		Expect(storage.DownloadFile(ctx, "hp-backup-1234.tar.gz", "/work/hp-backup-1234.tar.gz")).To(Succeed())
+		Expect(commands).To(HaveLen(3))
+		Expect(commands[0]).To(MatchRegexp(`^docker exec hp-minio\S+ mc cp --insecure local/hp-backups/hp-backup-1234.tar.gz /tmp/hp-backup-1234.tar.gz$`))
+		Expect(commands[1]).To(MatchRegexp(`^docker cp hp-minio\S+:/tmp/hp-backup-1234.tar.gz /work/hp-backup-1234.tar.gz$`))
+		Expect(commands[2]).To(MatchRegexp(`^docker exec hp-minio\S+ rm -f /tmp/hp-backup-1234.tar.gz$`))
+
+		err = (&helpers.S3Storage{Bucket: "remote"}).DownloadFile(ctx, "hp-backup-1234.tar.gz", "/work/hp-backup-1234.tar.gz")
+		Expect(err).To(MatchError(ContainSubstring("only supported with the MinIO started by StartMinIO")))
+	})
+})
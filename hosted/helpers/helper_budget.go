@@ -0,0 +1,164 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/pkg/config"
+	"github.com/rancher/wrangler/pkg/summary"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// LifecycleOperation is a cluster lifecycle operation whose duration is checked against the budgets of the cattle config
+type LifecycleOperation string
+
+const (
+	OperationProvision           LifecycleOperation = "provision"
+	OperationImport              LifecycleOperation = "import"
+	OperationScale               LifecycleOperation = "scale"
+	OperationAddNodePool         LifecycleOperation = "addNodePool"
+	OperationDeleteNodePool      LifecycleOperation = "deleteNodePool"
+	OperationControlPlaneUpgrade LifecycleOperation = "controlPlaneUpgrade"
+	OperationNodePoolUpgrade     LifecycleOperation = "nodePoolUpgrade"
+	OperationDelete              LifecycleOperation = "delete"
+
+	// LifecycleBudgetsConfigKey is the key of the LifecycleBudgets in the cattle config
+	LifecycleBudgetsConfigKey = "lifecycleBudgets"
+	// defaultBudgetTolerance is the percentage by which an operation may exceed its baseline when none is configured
+	defaultBudgetTolerance = 20
+	// LifecycleOperationReportEntry is the name of the report entries holding an OperationTiming
+	LifecycleOperationReportEntry = "LifecycleOperation"
+)
+
+// LifecycleBudgets holds the expected duration of the lifecycle operations per provider, for e.g.
+//
+//	lifecycleBudgets:
+//	  enforce: false
+//	  tolerance: 20
+//	  baselines:
+//	    aks:
+//	      provision: 10m
+//	      nodePoolUpgrade: 15m
+type LifecycleBudgets struct {
+	// Enforce fails the spec when an operation exceeds its budget; a warning is logged otherwise
+	Enforce bool `json:"enforce" yaml:"enforce"`
+	// Tolerance is the percentage by which an operation may exceed its baseline before it is reported; defaults to 20
+	Tolerance *int `json:"tolerance" yaml:"tolerance"`
+	// Baselines is the expected duration of each operation per provider
+	Baselines map[string]map[LifecycleOperation]metav1.Duration `json:"baselines" yaml:"baselines"`
+}
+
+// OperationTiming is the duration of a lifecycle operation and its budget, if any
+type OperationTiming struct {
+	Operation LifecycleOperation `json:"operation"`
+	Duration  time.Duration      `json:"duration"`
+	Budget    time.Duration      `json:"budget,omitempty"`
+	Exceeded  bool               `json:"exceeded,omitempty"`
+}
+
+var (
+	lifecycleBudgets     LifecycleBudgets
+	lifecycleBudgetsOnce sync.Once
+)
+
+// GetLifecycleBudgets returns the LifecycleBudgets of the cattle config; it is loaded only once
+func GetLifecycleBudgets() LifecycleBudgets {
+	lifecycleBudgetsOnce.Do(func() {
+		config.LoadConfig(LifecycleBudgetsConfigKey, &lifecycleBudgets)
+	})
+	return lifecycleBudgets
+}
+
+// Budget returns the maximum duration allowed for the operation on the provider, i.e. the baseline plus the tolerance;
+// it returns 0 if there is no baseline for the operation.
+func (b LifecycleBudgets) Budget(provider string, operation LifecycleOperation) time.Duration {
+	baseline, ok := b.Baselines[provider][operation]
+	if !ok || baseline.Duration <= 0 {
+		return 0
+	}
+	tolerance := defaultBudgetTolerance
+	if b.Tolerance != nil {
+		tolerance = *b.Tolerance
+	}
+	return baseline.Duration + baseline.Duration*time.Duration(tolerance)/100
+}
+
+// CheckLifecycleOperation compares the duration of the operation to its budget for the current provider and attaches the result to the spec report;
+// if the budget is exceeded, it fails the spec when the budgets are enforced, or logs a warning otherwise.
+func CheckLifecycleOperation(operation LifecycleOperation, duration time.Duration) {
+	budgets := GetLifecycleBudgets()
+	timing := OperationTiming{Operation: operation, Duration: duration.Round(time.Second), Budget: budgets.Budget(Provider, operation)}
+	timing.Exceeded = timing.Budget > 0 && duration > timing.Budget
+	Logger.Info(fmt.Sprintf("Lifecycle operation %s took %s (budget: %s)", operation, timing.Duration, timing.Budget))
+
+	// the timings can only be attached to a report while a spec is running
+	hooks := GetSpecHooks()
+	if !hooks.Running() {
+		return
+	}
+	hooks.AddReportEntry(LifecycleOperationReportEntry, timing, ReportEntryVisibilityNever)
+	if !timing.Exceeded {
+		return
+	}
+	message := fmt.Sprintf("%s lifecycle operation %s took %s, exceeding its budget of %s", Provider, operation, timing.Duration, timing.Budget)
+	if budgets.Enforce {
+		hooks.Fail(message, 1)
+	}
+	hooks.AddReportEntry("WARNING: "+message, nil, ReportEntryVisibilityAlways)
+}
+
+// TimeLifecycleOperation runs f and checks its duration against the budget of the operation; see CheckLifecycleOperation
+func TimeLifecycleOperation(operation LifecycleOperation, f func()) {
+	start := time.Now()
+	f()
+	CheckLifecycleOperation(operation, time.Since(start))
+}
+
+// WaitClusterToBeUpgraded waits for the cluster update as WaitClusterToBeUpdated and checks the duration against the budget of the operation
+func WaitClusterToBeUpgraded(ctx context.Context, client *rancher.Client, clusterID string, operation LifecycleOperation) error {
+	start := time.Now()
+	if err := WaitClusterToBeUpdated(ctx, client, clusterID); err != nil {
+		return err
+	}
+	CheckLifecycleOperation(operation, time.Since(start))
+	return nil
+}
+
+// WaitClusterToBeUpdated waits until the cluster starts and then finishes updating, as shepherdclusters.WaitClusterToBeUpgraded;
+// the watches are stopped once ctx is cancelled
+func WaitClusterToBeUpdated(ctx context.Context, client *rancher.Client, clusterID string) error {
+	// as shepherd, the error states are ignored since the operators report transient errors during the updates
+	inUpgrade := func(cluster summary.Summary) bool {
+		return cluster.Transitioning && !cluster.Error && (cluster.State == "updating" || cluster.State == "upgrading")
+	}
+	for _, done := range []func(summary.Summary) bool{inUpgrade, summary.Summary.IsReady} {
+		watchInterface, err := client.GetManagementWatchInterface(management.ClusterType, clusterWatchOptions(ctx, clusterID))
+		if err != nil {
+			return err
+		}
+		err = watchWait(ctx, watchInterface, func(event watch.Event) (bool, error) {
+			return done(summary.Summarize(event.Object.(*unstructured.Unstructured))), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// provisioningOperation returns the operation of a cluster that becomes ready for the first time, i.e. provision or import
+func provisioningOperation(cluster *management.Cluster) LifecycleOperation {
+	switch {
+	case cluster.AKSConfig != nil && cluster.AKSConfig.Imported,
+		cluster.EKSConfig != nil && cluster.EKSConfig.Imported,
+		cluster.GKEConfig != nil && cluster.GKEConfig.Imported:
+		return OperationImport
+	}
+	return OperationProvision
+}
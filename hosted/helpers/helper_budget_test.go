@@ -0,0 +1,109 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakerancher"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
+)
+
+var _ = Describe("LifecycleBudgets", func() {
+	budgets := helpers.LifecycleBudgets{
+		Baselines: map[string]map[helpers.LifecycleOperation]metav1.Duration{
+			"aks": {helpers.OperationProvision: {Duration: 10 * time.Minute}},
+		},
+	}
+
+	DescribeTable("Budget",
+		func(tolerance *int, provider string, operation helpers.LifecycleOperation, expected time.Duration) {
+			budgets.Tolerance = tolerance
+			Expect(budgets.Budget(provider, operation)).To(Equal(expected))
+		},
+		Entry("adds the default tolerance to the baseline", nil, "aks", helpers.OperationProvision, 12*time.Minute),
+		Entry("adds the configured tolerance to the baseline", pointer.Int(50), "aks", helpers.OperationProvision, 15*time.Minute),
+		Entry("returns 0 without baseline for the operation", nil, "aks", helpers.OperationDelete, time.Duration(0)),
+		Entry("returns 0 without baseline for the provider", nil, "eks", helpers.OperationProvision, time.Duration(0)),
+	)
+
+	It("is loaded from the cattle config", func() {
+		Expect(helpers.GetLifecycleBudgets().Budget("gke", helpers.OperationProvision)).To(Equal(time.Millisecond))
+	})
+})
+
+var _ = Describe("CheckLifecycleOperation", func() {
+	var originalProvider string
+
+	BeforeEach(func() {
+		originalProvider = helpers.Provider
+		DeferCleanup(func() {
+			helpers.Provider = originalProvider
+		})
+		helpers.Provider = "gke"
+	})
+
+	operationTimings := func() []helpers.OperationTiming {
+		return suite.BuildSpecResult(CurrentSpecReport(), 0).Operations
+	}
+	warnings := func() []string {
+		var names []string
+		for _, entry := range CurrentSpecReport().ReportEntries {
+			if strings.HasPrefix(entry.Name, "WARNING:") {
+				names = append(names, entry.Name)
+			}
+		}
+		return names
+	}
+
+	It("reports the timing of an operation without budget", func() {
+		helpers.CheckLifecycleOperation(helpers.OperationScale, 90*time.Second)
+
+		Expect(operationTimings()).To(Equal([]helpers.OperationTiming{{Operation: helpers.OperationScale, Duration: 90 * time.Second}}))
+		Expect(warnings()).To(BeEmpty())
+	})
+
+	It("warns when an operation exceeds its budget", func() {
+		helpers.CheckLifecycleOperation(helpers.OperationProvision, 2*time.Second)
+
+		Expect(operationTimings()).To(Equal([]helpers.OperationTiming{{Operation: helpers.OperationProvision, Duration: 2 * time.Second, Budget: time.Millisecond, Exceeded: true}}))
+		Expect(warnings()).To(ConsistOf("WARNING: gke lifecycle operation provision took 2s, exceeding its budget of 1ms"))
+	})
+
+	It("times the provisioning of a cluster", func(ctx SpecContext) {
+		id := server.AddCluster(&management.Cluster{
+			Name:      "fake-gke",
+			GKEConfig: &management.GKEClusterConfigSpec{ClusterName: "fake-gke"},
+		}, fakerancher.StateProvisioning)
+		Expect(server.ScriptClusterStates(id, fakerancher.StateProvisioning, fakerancher.StateActive)).To(Succeed())
+
+		cluster, err := client.Management.Cluster.ByID(id)
+		Expect(err).To(BeNil())
+		_, err = helpers.WaitUntilClusterIsReady(ctx, cluster, client)
+		Expect(err).To(BeNil())
+
+		Expect(operationTimings()).To(ConsistOf(HaveField("Operation", helpers.OperationProvision)))
+		Expect(operationTimings()[0].Exceeded).To(BeTrue())
+		Expect(warnings()).To(HaveLen(1))
+	})
+})
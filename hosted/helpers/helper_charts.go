@@ -1,140 +1,94 @@
 package helpers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os/exec"
 	"strings"
-	"time"
 
 	"github.com/blang/semver"
-	"github.com/onsi/ginkgo/v2"
-	. "github.com/onsi/gomega"
-	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
-	"github.com/rancher-sandbox/ele-testhelpers/tools"
-	"github.com/rancher/shepherd/clients/rancher/catalog"
+	"github.com/pkg/errors"
 )
 
-// AddRancherCharts adds the repo from which rancher operator charts can be installed
-func AddRancherCharts() {
-	err := kubectl.RunHelmBinaryWithCustomErr("repo", "add", catalog.RancherChartRepo, "https://charts.rancher.io")
-	Expect(err).To(BeNil())
-}
-
-// GetCurrentOperatorChartVersion returns the current version of a Provider chart.
-func GetCurrentOperatorChartVersion() string {
-	charts := ListOperatorChart()
-	if len(charts) == 0 {
-		return ""
+// GetCurrentOperatorChartVersion returns the current version of a Provider chart; it returns an empty version if the chart is not installed
+func GetCurrentOperatorChartVersion(ctx context.Context) (string, error) {
+	charts, err := ListOperatorChart(ctx)
+	if err != nil || len(charts) == 0 {
+		return "", err
 	}
-	return charts[0].DerivedVersion
+	return charts[0].DerivedVersion, nil
 }
 
-// GetDowngradeOperatorChartVersion returns a version to downgrade to from a given chart version.
-func GetDowngradeOperatorChartVersion(currentChartVersion string) string {
-	var chartName string
-	if charts := ListOperatorChart(); len(charts) > 0 {
-		chartName = charts[0].Name
-	} else {
-		ginkgo.GinkgoLogr.Info("Could not find downgrade chart; chart is not installed")
-		return ""
+// GetDowngradeOperatorChartVersion returns a version to downgrade to from a given chart version; it returns an empty version if there is none.
+func GetDowngradeOperatorChartVersion(ctx context.Context, currentChartVersion string) (string, error) {
+	charts, err := ListOperatorChart(ctx)
+	if err != nil {
+		return "", err
 	}
-	chartVersions := ListChartVersions(chartName)
-	for _, chartVersion := range chartVersions {
-		if VersionCompare(chartVersion.DerivedVersion, currentChartVersion) == -1 {
-			return chartVersion.DerivedVersion
-		}
-	}
-	return ""
-}
-
-func DowngradeProviderChart(downgradeChartVersion string) {
-	currentChartVersion := GetCurrentOperatorChartVersion()
-	Expect(currentChartVersion).ToNot(BeEmpty())
-	UpdateOperatorChartsVersion(downgradeChartVersion)
-	Expect(VersionCompare(downgradeChartVersion, currentChartVersion)).To(Equal(-1))
-}
-
-// WaitUntilOperatorChartInstallation waits until the current operator chart version compares to the input chartVersion using the comparator.
-// comparator values can be >,<,<=,>=,==,!=
-// compareTo value can be 0 if current == input; -1 if current < input; 1 if current > input; defaults to 0
-// compareTo is helpful in case either of the chart version is unknown;
-// for e.g. after a rancher upgrade, if only the old chart version is known then we can wait until the current chart version is greater than it
-func WaitUntilOperatorChartInstallation(chartVersion, comparator string, compareTo int) {
-	if comparator == "" {
-		comparator = "=="
+	if len(charts) == 0 {
+		return "", fmt.Errorf("could not find downgrade chart; %s operator chart is not installed", Provider)
 	}
-
-	if !(compareTo >= -1 && compareTo <= 1) {
-		compareTo = 0
+	chartVersions, err := ListChartVersions(ctx, charts[0].Name)
+	if err != nil {
+		return "", err
 	}
-	Eventually(func() int {
-		currentChartVersion := GetCurrentOperatorChartVersion()
-		ginkgo.GinkgoLogr.Info(fmt.Sprintf("CurrentChartVersion: %s; comparing with %s to %s %d", currentChartVersion, chartVersion, comparator, compareTo))
-		if currentChartVersion == "" {
-			return 10
-		}
-		return VersionCompare(currentChartVersion, chartVersion)
-	}, tools.SetTimeout(4*time.Minute), 3*time.Second).Should(BeNumerically(comparator, compareTo))
-
-}
-
-// UpdateOperatorChartsVersion updates the operator charts to a given chart version and validates that the current version is same as provided
-func UpdateOperatorChartsVersion(updateChartVersion string) {
-	for _, chart := range ListOperatorChart() {
-		err := kubectl.RunHelmBinaryWithCustomErr("upgrade", "--install", chart.Name, fmt.Sprintf("%s/%s", catalog.RancherChartRepo, chart.Name), "--namespace", CattleSystemNS, "--version", updateChartVersion, "--wait")
+	for _, chartVersion := range chartVersions {
+		comparison, err := VersionCompare(chartVersion.DerivedVersion, currentChartVersion)
 		if err != nil {
-			Expect(err).To(BeNil(), "UpdateOperatorChartsVersion Failed")
+			return "", err
 		}
-	}
-	Expect(VersionCompare(GetCurrentOperatorChartVersion(), updateChartVersion)).To(Equal(0))
-}
-
-// UninstallOperatorCharts uninstalls the operator charts
-func UninstallOperatorCharts() {
-	for _, chart := range ListOperatorChart() {
-		args := []string{"uninstall", chart.Name, "--namespace", CattleSystemNS}
-		err := kubectl.RunHelmBinaryWithCustomErr(args...)
-		if err != nil {
-			Expect(err).To(BeNil(), "Failed to uninstall chart %s", chart.Name)
+		if comparison == -1 {
+			return chartVersion.DerivedVersion, nil
 		}
 	}
+	return "", nil
 }
 
 // ListOperatorChart lists the installed provider charts for a provider in cattle-system; it fetches the provider value using Provider
-func ListOperatorChart() (operatorCharts []HelmChart) {
-	cmd := exec.Command("helm", "list", "--namespace", CattleSystemNS, "-o", "json", "--filter", fmt.Sprintf("%s-operator", Provider))
+func ListOperatorChart(ctx context.Context) (operatorCharts []HelmChart, err error) {
+	cmd := exec.CommandContext(ctx, "helm", "list", "--namespace", CattleSystemNS, "-o", "json", "--filter", fmt.Sprintf("%s-operator", Provider))
 	output, err := cmd.Output()
-	Expect(err).To(BeNil(), "Failed to list chart %s", Provider)
-	ginkgo.GinkgoLogr.Info(string(output))
-	err = json.Unmarshal(output, &operatorCharts)
-	Expect(err).To(BeNil(), "Failed to unmarshal chart %s", Provider)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list chart "+Provider)
+	}
+	Logger.Info(string(output))
+	if err = json.Unmarshal(output, &operatorCharts); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal chart "+Provider)
+	}
 	for i := range operatorCharts {
 		operatorCharts[i].DerivedVersion = strings.TrimPrefix(operatorCharts[i].Chart, fmt.Sprintf("%s-", operatorCharts[i].Name))
 	}
-	return
+	return operatorCharts, nil
 }
 
 // ListChartVersions lists all the available the chart version for a given chart name
-func ListChartVersions(chartName string) (charts []HelmChart) {
-	cmd := exec.Command("helm", "search", "repo", chartName, "--versions", "-ojson", "--devel")
+func ListChartVersions(ctx context.Context, chartName string) (charts []HelmChart, err error) {
+	cmd := exec.CommandContext(ctx, "helm", "search", "repo", chartName, "--versions", "-ojson", "--devel")
 	output, err := cmd.Output()
-	Expect(err).To(BeNil())
-	ginkgo.GinkgoLogr.Info(string(output))
-	err = json.Unmarshal(output, &charts)
-	Expect(err).To(BeNil())
-	return
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list the versions of chart "+chartName)
+	}
+	Logger.Info(string(output))
+	if err = json.Unmarshal(output, &charts); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal the versions of chart "+chartName)
+	}
+	return charts, nil
 }
 
 // VersionCompare compares Versions v to o:
 // -1 == v is less than o
 // 0 == v is equal to o
 // 1 == v is greater than o
-func VersionCompare(v, o string) int {
-	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Version %s vs %s", v, o))
+func VersionCompare(v, o string) (int, error) {
+	Logger.Info(fmt.Sprintf("Version %s vs %s", v, o))
 	latestVer, err := semver.ParseTolerant(v)
-	Expect(err).To(BeNil())
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to parse version "+v)
+	}
 	oldVer, err := semver.ParseTolerant(o)
-	Expect(err).To(BeNil())
-	return latestVer.Compare(oldVer)
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to parse version "+o)
+	}
+	return latestVer.Compare(oldVer), nil
 }
@@ -0,0 +1,211 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/pkg/clientbase"
+)
+
+// Cleanups is the CleanupRegistry of the current process; RancherContext.Cleanups points to it
+var Cleanups = NewCleanupRegistry()
+
+// CleanupRegistry holds the teardown of every resource created by the helpers, so that it is deleted even if the spec fails halfway or the run is interrupted.
+// A teardown registered within a spec or a BeforeSuite is deferred via SpecHooks.DeferCleanup, i.e. ginkgo.DeferCleanup, which Ginkgo runs in the reverse order of registration
+// when the spec or the suite ends, including on SIGINT and timeout; the other teardowns are run by RunAll.
+type CleanupRegistry struct {
+	mu       sync.Mutex
+	cleanups []*cleanup
+	failures []CleanupFailure
+}
+
+// CleanupFailure is a teardown that returned an error
+type CleanupFailure struct {
+	Description string
+	Err         error
+}
+
+type cleanup struct {
+	key         string
+	description string
+	teardown    func(ctx context.Context) error
+	done        bool
+}
+
+// NewCleanupRegistry returns an empty CleanupRegistry
+func NewCleanupRegistry() *CleanupRegistry {
+	return &CleanupRegistry{}
+}
+
+// Register adds the teardown of a resource; key identifies the resource so that Forget can drop the teardown once the test deleted the resource itself.
+// The teardown is given the context of the cleanup node, which is not cancelled when the spec is interrupted.
+func (r *CleanupRegistry) Register(key, description string, teardown func(ctx context.Context) error) {
+	c := &cleanup{key: key, description: description, teardown: teardown}
+	// the lock also serializes the calls to DeferCleanup, for e.g. when the resources are created by several goroutines of a spec
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cleanups = append(r.cleanups, c)
+
+	if hooks := GetSpecHooks(); hooks.CanDeferCleanup() {
+		hooks.DeferCleanup(func(ctx context.Context) error {
+			return r.run(ctx, c)
+		}, 2)
+	}
+}
+
+// Forget drops the pending teardowns of the resource, for e.g. once the resource has been deleted by the test
+func (r *CleanupRegistry) Forget(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.cleanups {
+		if c.key == key {
+			c.done = true
+		}
+	}
+}
+
+// RunAll runs the pending teardowns in the reverse order of registration; it returns the errors of the teardowns that failed
+func (r *CleanupRegistry) RunAll(ctx context.Context) error {
+	r.mu.Lock()
+	cleanups := make([]*cleanup, len(r.cleanups))
+	copy(cleanups, r.cleanups)
+	r.mu.Unlock()
+
+	var errs []error
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		if err := r.run(ctx, cleanups[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Failures returns the teardowns that failed so far
+func (r *CleanupRegistry) Failures() []CleanupFailure {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]CleanupFailure(nil), r.failures...)
+}
+
+// Summary describes the teardowns that failed so far; it is empty if none failed
+func (r *CleanupRegistry) Summary() string {
+	failures := r.Failures()
+	if len(failures) == 0 {
+		return ""
+	}
+	lines := []string{fmt.Sprintf("Failed to clean up %d resource(s), they must be deleted manually:", len(failures))}
+	for _, failure := range failures {
+		lines = append(lines, fmt.Sprintf("- %s: %v", failure.Description, failure.Err))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// LogSummary logs the Summary if any teardown failed
+func (r *CleanupRegistry) LogSummary() {
+	if summary := r.Summary(); summary != "" {
+		Logger.Info(summary)
+	}
+}
+
+// HandleSignals runs the pending teardowns and exits on SIGINT or SIGTERM; it is meant for the programs that do not run within Ginkgo,
+// which already runs the deferred teardowns when it is interrupted. The teardowns are run with a new context since the one of the
+// interrupted operations is usually cancelled by the same signal. It returns a function that stops handling the signals.
+func (r *CleanupRegistry) HandleSignals() (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-signals:
+			fmt.Printf("Received %s, cleaning up ...\n", sig)
+			_ = r.RunAll(context.Background())
+			if summary := r.Summary(); summary != "" {
+				fmt.Println(summary)
+			}
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// run runs the teardown once and records its failure
+func (r *CleanupRegistry) run(ctx context.Context, c *cleanup) error {
+	r.mu.Lock()
+	if c.done {
+		r.mu.Unlock()
+		return nil
+	}
+	c.done = true
+	r.mu.Unlock()
+
+	fmt.Printf("Cleaning up %s ...\n", c.description)
+	err := c.teardown(ctx)
+	if err == nil {
+		return nil
+	}
+	r.mu.Lock()
+	r.failures = append(r.failures, CleanupFailure{Description: c.description, Err: err})
+	r.mu.Unlock()
+	return fmt.Errorf("failed to clean up %s: %w", c.description, err)
+}
+
+// RegisterCleanup registers the teardown of a resource in Cleanups; see CleanupRegistry.Register
+func RegisterCleanup(key, description string, teardown func(ctx context.Context) error) {
+	Cleanups.Register(key, description, teardown)
+}
+
+// RegisterClusterCleanup registers the teardown of a downstream cluster, or of a resource it depends on, only if DOWNSTREAM_CLUSTER_CLEANUP is true
+func RegisterClusterCleanup(key, description string, teardown func(ctx context.Context) error) {
+	if !clusterCleanup {
+		fmt.Printf("Skipping the cleanup of %s since DOWNSTREAM_CLUSTER_CLEANUP is not true\n", description)
+		return
+	}
+	Cleanups.Register(key, description, teardown)
+}
+
+// ForgetCleanup drops the pending teardowns of the resource from Cleanups; see CleanupRegistry.Forget
+func ForgetCleanup(key string) {
+	Cleanups.Forget(key)
+}
+
+// CleanupKey returns the key of a resource in the CleanupRegistry, for e.g. CleanupKey("aks-resource-group", name)
+func CleanupKey(kind, name string) string {
+	return kind + "/" + name
+}
+
+// RegisterHostedClusterCleanup registers the deletion of the cluster from Rancher via deleteFunc, which must call ForgetHostedClusterCleanup;
+// the deletion is only awaited if it has a lifecycle budget, so that the specs do not get slower otherwise.
+// If the spec failed, the diagnostics of the cluster are collected before its deletion; see suite.WriteRunReport.
+func RegisterHostedClusterCleanup(cluster *management.Cluster, client *rancher.Client, deleteFunc func(context.Context, *management.Cluster, *rancher.Client) error) {
+	RegisterClusterCleanup(CleanupKey("cluster", cluster.ID), fmt.Sprintf("cluster %s (%s) from Rancher", cluster.Name, cluster.ID), func(ctx context.Context) error {
+		collectDiagnosticsOnFailure(ctx, client, cluster)
+		err := deleteFunc(ctx, cluster, client)
+		if clientbase.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if GetLifecycleBudgets().Budget(Provider, OperationDelete) > 0 {
+			return WaitUntilClusterIsDeleted(ctx, cluster, client)
+		}
+		return nil
+	})
+}
+
+// ForgetHostedClusterCleanup drops the teardown registered by RegisterHostedClusterCleanup once the cluster has been deleted from Rancher
+func ForgetHostedClusterCleanup(cluster *management.Cluster) {
+	ForgetCleanup(CleanupKey("cluster", cluster.ID))
+}
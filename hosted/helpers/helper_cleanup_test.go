@@ -0,0 +1,109 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// outsideGinkgo are the SpecHooks of a program not run by Ginkgo, for e.g. hpctl
+type outsideGinkgo struct {
+	helpers.SpecHooks
+}
+
+func (outsideGinkgo) CanDeferCleanup() bool {
+	return false
+}
+
+var _ = Describe("CleanupRegistry", func() {
+	var (
+		registry *helpers.CleanupRegistry
+		ran      []string
+	)
+
+	teardown := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			ran = append(ran, name)
+			return err
+		}
+	}
+
+	BeforeEach(func() {
+		registry = helpers.NewCleanupRegistry()
+		ran = nil
+	})
+
+	It("runs the teardowns in the reverse order of registration and only once", func(ctx SpecContext) {
+		registry.Register("cloud-cluster/a", "cloud cluster a", teardown("cloud cluster", nil))
+		registry.Register("cluster/a", "cluster a", teardown("cluster", nil))
+		registry.Register("kubeconfig/a", "kubeconfig a", teardown("kubeconfig", nil))
+
+		Expect(registry.RunAll(ctx)).To(Succeed())
+		Expect(registry.RunAll(ctx)).To(Succeed())
+		Expect(ran).To(Equal([]string{"kubeconfig", "cluster", "cloud cluster"}))
+		Expect(registry.Summary()).To(BeEmpty())
+	})
+
+	It("does not run the teardowns of the forgotten resources", func(ctx SpecContext) {
+		registry.Register("cluster/a", "cluster a", teardown("cluster a", nil))
+		registry.Register("cluster/b", "cluster b", teardown("cluster b", nil))
+		registry.Forget("cluster/a")
+
+		Expect(registry.RunAll(ctx)).To(Succeed())
+		Expect(ran).To(Equal([]string{"cluster b"}))
+	})
+
+	It("runs the other teardowns and summarizes the failures", func(ctx SpecContext) {
+		registry.Register("cluster/a", "cluster a", teardown("cluster a", errors.New("timeout")))
+		registry.Register("cluster/b", "cluster b", teardown("cluster b", nil))
+
+		err := registry.RunAll(ctx)
+		Expect(err).To(MatchError(ContainSubstring("failed to clean up cluster a: timeout")))
+		Expect(ran).To(Equal([]string{"cluster b", "cluster a"}))
+		Expect(registry.Failures()).To(HaveLen(1))
+		Expect(registry.Summary()).To(Equal("Failed to clean up 1 resource(s), they must be deleted manually:\n- cluster a: timeout"))
+	})
+
+	It("leaves the teardowns to RunAll outside Ginkgo", func(ctx SpecContext) {
+		DeferCleanup(helpers.SetSpecHooks(outsideGinkgo{}))
+		registry.Register("cluster/a", "cluster a", teardown("cluster a", nil))
+		Expect(ran).To(BeEmpty())
+
+		Expect(registry.RunAll(ctx)).To(Succeed())
+		Expect(ran).To(Equal([]string{"cluster a"}))
+	})
+
+	Context("when the teardowns are registered within a spec", Ordered, func() {
+		var deferred []string
+
+		It("defers them", func() {
+			helpers.NewCleanupRegistry().Register("cluster/a", "cluster a", func(context.Context) error {
+				deferred = append(deferred, "cluster a")
+				return nil
+			})
+			Expect(deferred).To(BeEmpty())
+		})
+
+		It("runs them once the spec has ended", func() {
+			Expect(deferred).To(Equal([]string{"cluster a"}))
+		})
+	})
+})
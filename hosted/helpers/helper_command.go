@@ -0,0 +1,163 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// CommandRunner runs an external command such as az, eksctl, aws or gcloud and returns its combined stdout and stderr;
+// the command must be stopped once ctx is cancelled
+type CommandRunner interface {
+	Run(ctx context.Context, name string, args ...string) (string, error)
+}
+
+// CommandRunnerFunc allows using an ordinary function as a CommandRunner
+type CommandRunnerFunc func(ctx context.Context, name string, args ...string) (string, error)
+
+func (f CommandRunnerFunc) Run(ctx context.Context, name string, args ...string) (string, error) {
+	return f(ctx, name, args...)
+}
+
+// InputRunner is a CommandRunner that can also write to the standard input of the command, for e.g. for kubectl apply -f -
+type InputRunner interface {
+	CommandRunner
+	RunWithInput(ctx context.Context, input []byte, name string, args ...string) (string, error)
+}
+
+// ProcRunner runs the commands on the local machine; it is the default CommandRunner and implements InputRunner
+var ProcRunner CommandRunner = procRunner{}
+
+type procRunner struct{}
+
+func (procRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	return runCommand(ctx, name, args...)
+}
+
+func (procRunner) RunWithInput(ctx context.Context, input []byte, name string, args ...string) (string, error) {
+	return runCommandWithInput(ctx, input, name, args...)
+}
+
+// commandWaitDelay is the time given to a command to exit after being interrupted, before it is killed
+const commandWaitDelay = 30 * time.Second
+
+// runCommand runs the command and returns its combined output; once ctx is cancelled, the command is interrupted
+// so that the CLIs can stop their pending operation, and killed if it does not exit within commandWaitDelay
+func runCommand(ctx context.Context, name string, args ...string) (string, error) {
+	return runCommandWithInput(ctx, nil, name, args...)
+}
+
+// runCommandWithInput is runCommand writing input, if any, to the standard input of the command
+func runCommandWithInput(ctx context.Context, input []byte, name string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGINT)
+	}
+	cmd.WaitDelay = commandWaitDelay
+	var b bytes.Buffer
+	cmd.Stdout = &b
+	cmd.Stderr = &b
+	err := cmd.Run()
+	if ctxErr := ctx.Err(); ctxErr != nil && err != nil {
+		return b.String(), fmt.Errorf("%s interrupted: %w", name, ctxErr)
+	}
+	return b.String(), err
+}
+
+var (
+	commandRunnerMu sync.RWMutex
+	commandRunner   = ProcRunner
+)
+
+// SetCommandRunner replaces the CommandRunner used by the cloud CLI helpers, for e.g. with a fakecli.Runner in unit tests;
+// it returns a function that restores the previous runner.
+func SetCommandRunner(runner CommandRunner) (restore func()) {
+	commandRunnerMu.Lock()
+	defer commandRunnerMu.Unlock()
+	previous := commandRunner
+	commandRunner = runner
+	return func() {
+		commandRunnerMu.Lock()
+		defer commandRunnerMu.Unlock()
+		commandRunner = previous
+	}
+}
+
+// GetCommandRunner returns the CommandRunner currently used by the cloud CLI helpers
+func GetCommandRunner() CommandRunner {
+	commandRunnerMu.RLock()
+	defer commandRunnerMu.RUnlock()
+	return commandRunner
+}
+
+// RunCLI runs the command with the current CommandRunner; all the *OnAzure, *OnAWS and *OnGCloud helpers must use it instead of calling proc directly
+func RunCLI(ctx context.Context, name string, args ...string) (string, error) {
+	return GetCommandRunner().Run(ctx, name, args...)
+}
+
+// RunCLIWithInput runs the command with the current CommandRunner, writing input to its standard input;
+// it fails if the runner does not implement InputRunner
+func RunCLIWithInput(ctx context.Context, input []byte, name string, args ...string) (string, error) {
+	runner, ok := GetCommandRunner().(InputRunner)
+	if !ok {
+		return "", fmt.Errorf("the command runner cannot write to the standard input of %s", name)
+	}
+	return runner.RunWithInput(ctx, input, name, args...)
+}
+
+const (
+	// CLIBackend runs the az, eksctl, aws and gcloud CLIs via the CommandRunner; it is the default
+	CLIBackend = "cli"
+	// SDKBackend calls the Azure, AWS and Google APIs directly via their Go SDKs
+	SDKBackend = "sdk"
+)
+
+// UseCloudSDK returns true if the cloud helpers must use the Go SDKs instead of the CLIs, i.e. if CLOUD_BACKEND is set to "sdk"
+func UseCloudSDK() bool {
+	return strings.EqualFold(CloudBackend, SDKBackend)
+}
+
+// ParseSDKArgs converts the extra CLI arguments passed to a cloud helper into a map usable by the sdk backend;
+// valueFlags take a value (for e.g. --mode User) and boolFlags do not (for e.g. --wait, which is stored as "true").
+// Any other argument is rejected since the sdk backend has no equivalent for it.
+func ParseSDKArgs(args []string, valueFlags, boolFlags []string) (map[string]string, error) {
+	parsed := map[string]string{}
+	for i := 0; i < len(args); i++ {
+		flag, value, hasValue := strings.Cut(args[i], "=")
+		switch {
+		case slices.Contains(boolFlags, flag) && !hasValue:
+			parsed[flag] = "true"
+		case slices.Contains(valueFlags, flag):
+			if !hasValue {
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("flag %s requires a value", flag)
+				}
+				i++
+				value = args[i]
+			}
+			parsed[flag] = value
+		default:
+			return nil, fmt.Errorf("argument %q is not supported by the %s cloud backend", args[i], SDKBackend)
+		}
+	}
+	return parsed, nil
+}
+
+// RunCloud runs the cloud CLI command with the current CommandRunner, or sdkFunc instead if UseCloudSDK is true;
+// the output is always empty when sdkFunc is used since the sdk backend returns typed values instead.
+func RunCloud(ctx context.Context, sdkFunc func(ctx context.Context) error, name string, args ...string) (string, error) {
+	if UseCloudSDK() {
+		return "", sdkFunc(ctx)
+	}
+	fmt.Printf("Running command: %s %v\n", name, args)
+	return RunCLI(ctx, name, args...)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakecli"
+)
+
+var _ = Describe("CommandRunner", func() {
+	It("replays the commands recorded from another runner", func(ctx SpecContext) {
+		recorder := fakecli.NewRecorder(helpers.CommandRunnerFunc(func(_ context.Context, name string, args ...string) (string, error) {
+			if name == "gcloud" {
+				return "ERROR: permission denied", fmt.Errorf("exit status 1")
+			}
+			return "ok", nil
+		}))
+		restore := recorder.Install()
+		out, err := helpers.RunCLI(ctx, "az", "group", "list")
+		Expect(err).To(BeNil())
+		Expect(out).To(Equal("ok"))
+		_, err = helpers.RunCLI(ctx, "gcloud", "container", "clusters", "list")
+		Expect(err).ToNot(BeNil())
+		restore()
+
+		recording := filepath.Join(GinkgoT().TempDir(), "recording.json")
+		Expect(recorder.Save(recording)).To(Succeed())
+
+		runner := fakecli.NewRunner()
+		Expect(runner.Load(recording)).To(Succeed())
+		DeferCleanup(runner.Install())
+
+		out, err = helpers.RunCLI(ctx, "az", "group", "list")
+		Expect(err).To(BeNil())
+		Expect(out).To(Equal("ok"))
+		out, err = helpers.RunCLI(ctx, "gcloud", "container", "clusters", "list")
+		Expect(err).To(MatchError("exit status 1"))
+		Expect(out).To(Equal("ERROR: permission denied"))
+		Expect(runner.Verify()).To(Succeed())
+	})
+
+	It("reports mismatching and missing commands", func(ctx SpecContext) {
+		runner := fakecli.NewRunner()
+		runner.Expect("eksctl", "get", "cluster", "--region=us-west-2")
+		runner.Expect("eksctl", "delete", "cluster")
+		DeferCleanup(runner.Install())
+
+		_, err := helpers.RunCLI(ctx, "eksctl", "get", "cluster", "--region", "us-west-2")
+		Expect(err).To(MatchError(ContainSubstring("command #1 mismatch")))
+
+		err = runner.Verify()
+		Expect(err).To(MatchError(ContainSubstring("command #1 mismatch")))
+		Expect(err).To(MatchError(ContainSubstring("expected command was not run: eksctl delete cluster")))
+		Expect(runner.Calls()).To(HaveLen(1))
+	})
+
+	It("writes the input to the standard input of the command", func(ctx SpecContext) {
+		out, err := helpers.ProcRunner.(helpers.InputRunner).RunWithInput(ctx, []byte("kind: Backup\n"), "cat")
+		Expect(err).To(BeNil())
+		Expect(out).To(Equal("kind: Backup\n"))
+
+		DeferCleanup(helpers.SetCommandRunner(helpers.CommandRunnerFunc(func(context.Context, string, ...string) (string, error) {
+			return "", nil
+		})))
+		_, err = helpers.RunCLIWithInput(ctx, []byte("kind: Backup\n"), "kubectl", "apply", "--filename", "-")
+		Expect(err).To(MatchError(ContainSubstring("cannot write to the standard input of kubectl")))
+	})
+
+	It("interrupts the local command once the context is cancelled", func(ctx SpecContext) {
+		timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := helpers.ProcRunner.Run(timeoutCtx, "sleep", "30")
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(err).To(MatchError(ContainSubstring("sleep interrupted")))
+		Expect(time.Since(start)).To(BeNumerically("<", 10*time.Second))
+	})
+})
+
+var _ = Describe("ParseSDKArgs", func() {
+	DescribeTable("parses the supported flags",
+		func(args []string, expected map[string]string) {
+			parsed, err := helpers.ParseSDKArgs(args, []string{"--mode", "--name"}, []string{"--wait"})
+			Expect(err).To(BeNil())
+			Expect(parsed).To(Equal(expected))
+		},
+		Entry("with no argument", nil, map[string]string{}),
+		Entry("with a separate value", []string{"--mode", "User"}, map[string]string{"--mode": "User"}),
+		Entry("with an inline value", []string{"--name=ng1", "--wait"}, map[string]string{"--name": "ng1", "--wait": "true"}),
+	)
+
+	DescribeTable("rejects the other arguments",
+		func(args []string, message string) {
+			_, err := helpers.ParseSDKArgs(args, []string{"--mode"}, []string{"--wait"})
+			Expect(err).To(MatchError(message))
+		},
+		Entry("with an unknown flag", []string{"--spot"}, `argument "--spot" is not supported by the sdk cloud backend`),
+		Entry("with a value for a bool flag", []string{"--wait=false"}, `argument "--wait=false" is not supported by the sdk cloud backend`),
+		Entry("with a missing value", []string{"--mode"}, "flag --mode requires a value"),
+	)
+})
@@ -1,17 +1,14 @@
 package helpers
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
 	"strings"
 	"time"
 
-	"github.com/onsi/ginkgo/v2"
-	. "github.com/onsi/gomega"
-	"github.com/rancher-sandbox/ele-testhelpers/tools"
-	"github.com/rancher/rancher/tests/v2/actions/clusters"
-	"github.com/rancher/rancher/tests/v2/actions/pipeline"
+	"github.com/pkg/errors"
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
 	v1 "github.com/rancher/shepherd/clients/rancher/v1"
@@ -20,135 +17,39 @@ import (
 	"github.com/rancher/shepherd/extensions/cloudcredentials/azure"
 	"github.com/rancher/shepherd/extensions/cloudcredentials/google"
 	shepherdclusters "github.com/rancher/shepherd/extensions/clusters"
-	"github.com/rancher/shepherd/extensions/defaults"
-	nodestat "github.com/rancher/shepherd/extensions/nodes"
-	"github.com/rancher/shepherd/extensions/users"
-	password "github.com/rancher/shepherd/extensions/users/passwordgenerator"
-	"github.com/rancher/shepherd/extensions/workloads/pods"
+	"github.com/rancher/shepherd/extensions/defaults/stevetypes"
+	"github.com/rancher/shepherd/pkg/clientbase"
 	"github.com/rancher/shepherd/pkg/config"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
-	"github.com/rancher/shepherd/pkg/session"
-	"github.com/rancher/shepherd/pkg/wait"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/utils/pointer"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
-func CommonSynchronizedBeforeSuite() {
-	ginkgo.GinkgoLogr.Info("Using Common SynchronizedBeforeSuite ...")
-
-	rancherConfig := new(rancher.Config)
-
-	// Attempt at manually loading and updating the rancher config to avoid `nil map entry assignment`
-	config.LoadConfig(rancher.ConfigurationFileKey, rancherConfig)
-
-	rancherConfig.Host = RancherHostname
-	time.Sleep(2 * time.Second)
-
-	token, err := pipeline.CreateAdminToken(RancherPassword, rancherConfig)
-	Expect(err).To(BeNil())
-	rancherConfig.AdminToken = token
-
-	config.UpdateConfig(rancher.ConfigurationFileKey, rancherConfig)
-
-	switch Provider {
-	case "aks":
-		credentialConfig := new(cloudcredentials.AzureCredentialConfig)
-		config.LoadAndUpdateConfig("azureCredentials", credentialConfig, func() {
-			credentialConfig.ClientID = os.Getenv("AKS_CLIENT_ID")
-			credentialConfig.SubscriptionID = os.Getenv("AKS_SUBSCRIPTION_ID")
-			credentialConfig.ClientSecret = os.Getenv("AKS_CLIENT_SECRET")
-		})
-	case "eks":
-		credentialConfig := new(cloudcredentials.AmazonEC2CredentialConfig)
-		config.LoadAndUpdateConfig("awsCredentials", credentialConfig, func() {
-			credentialConfig.AccessKey = os.Getenv("AWS_ACCESS_KEY_ID")
-			credentialConfig.SecretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
-			credentialConfig.DefaultRegion = GetEKSRegion()
-		})
-
-	case "gke":
-		credentialConfig := new(cloudcredentials.GoogleCredentialConfig)
-		config.LoadAndUpdateConfig("googleCredentials", credentialConfig, func() {
-			credentialConfig.AuthEncodedJSON = os.Getenv("GCP_CREDENTIALS")
-		})
-	}
-
-}
-
-func CommonBeforeSuite() RancherContext {
-	ginkgo.GinkgoLogr.Info("Using Common BeforeSuite ...")
-
-	rancherConfig := new(rancher.Config)
-	config.LoadConfig(rancher.ConfigurationFileKey, rancherConfig)
-
-	testSession := session.NewSession()
-	rancherAdminClient, err := rancher.NewClient(rancherConfig.AdminToken, testSession)
-	Expect(err).To(BeNil())
-
-	// This is done by testhelpers already
-	//setting := new(management.Setting)
-	//resp, err := rancherAdminClient.Management.Setting.ByID("server-url")
-	//Expect(err).To(BeNil())
-
-	//setting.Source = "env"
-	//setting.Value = fmt.Sprintf("https://%s", RancherHostname)
-	//_, err = rancherAdminClient.Management.Setting.Update(resp, setting)
-	//Expect(err).To(BeNil())
-
-	cloudCredID, err := CreateCloudCredentials(rancherAdminClient)
-	Expect(err).To(BeNil())
-
-	return RancherContext{
-		RancherAdminClient: rancherAdminClient,
-		Session:            testSession,
-		ClusterCleanup:     clusterCleanup,
-		CloudCredID:        cloudCredID,
-	}
-}
-
-func CreateStdUserClient(ctx *RancherContext) {
-	ginkgo.GinkgoLogr.Info("Creating Std User client ...")
-
-	var stduser = namegen.AppendRandomString("stduser-")
-	var stduserpassword = password.GenerateUserPassword("testpass-")
-	newuser := &management.User{
-		Username: stduser,
-		Password: stduserpassword,
-		Name:     stduser,
-		Enabled:  pointer.Bool(true),
-	}
-
-	stdUser, err := users.CreateUserWithRole(ctx.RancherAdminClient, newuser, "user")
-	Expect(err).To(BeNil())
-
-	stdUser.Password = newuser.Password
-	stdUserClient, err := ctx.RancherAdminClient.AsUser(stdUser)
-	Expect(err).To(BeNil())
-
-	cloudCredID, err := CreateCloudCredentials(stdUserClient)
-	Expect(err).To(BeNil())
-
-	ctx.StdUserClient = stdUserClient
-	ctx.CloudCredID = cloudCredID
-}
-
 // WaitUntilClusterIsReady waits until the cluster is in a Ready state,
 // fetch the cluster again once it's ready so that it has everything up to date and then return it.
 // For e.g. once the cluster has been updated, it contains information such as Version.GitVersion which it does not have before it's ready
 // If the cluster is imported; it also updates the ProviderConfig with ProviderStatus.UpstreamSpec data
-func WaitUntilClusterIsReady(cluster *management.Cluster, client *rancher.Client) (*management.Cluster, error) {
-	opts := metav1.ListOptions{FieldSelector: "metadata.name=" + cluster.ID, TimeoutSeconds: &defaults.WatchTimeoutSeconds}
-	watchInterface, err := client.GetManagementWatchInterface(management.ClusterType, opts)
+// The watch is stopped once ctx is cancelled.
+func WaitUntilClusterIsReady(ctx context.Context, cluster *management.Cluster, client *rancher.Client) (*management.Cluster, error) {
+	watchInterface, err := client.GetManagementWatchInterface(management.ClusterType, clusterWatchOptions(ctx, cluster.ID))
 	if err != nil {
 		return cluster, err
 	}
 
+	// a cluster without version has never been ready, its provisioning or import is timed from its creation
+	firstReady := cluster.Version == nil
+	start, parseErr := time.Parse(time.RFC3339, cluster.Created)
+	if parseErr != nil {
+		start = time.Now()
+	}
+
 	watchFunc := shepherdclusters.IsHostedProvisioningClusterReady
 
-	err = wait.WatchWait(watchInterface, watchFunc)
+	err = watchWait(ctx, watchInterface, watchFunc)
 	if err != nil {
 		return cluster, err
 	}
+	if firstReady {
+		CheckLifecycleOperation(provisioningOperation(cluster), time.Since(start))
+	}
 	var updatedCluster *management.Cluster
 	updatedCluster, err = client.Management.Cluster.ByID(cluster.ID)
 	if err != nil {
@@ -156,52 +57,49 @@ func WaitUntilClusterIsReady(cluster *management.Cluster, client *rancher.Client
 		return updatedCluster, err
 	}
 
+	return SyncImportedClusterConfig(updatedCluster), nil
+
+}
+
+// SyncImportedClusterConfig updates the ProviderConfig with ProviderStatus.UpstreamSpec data if the cluster is imported
+func SyncImportedClusterConfig(cluster *management.Cluster) *management.Cluster {
 	// Workaround to null values in ProviderConfig for an imported cluster
 	// Ref: https://github.com/rancher/aks-operator/issues/251 (won't fix)
 	if IsImport {
 		switch Provider {
 		case "aks":
-			updatedCluster.AKSConfig = updatedCluster.AKSStatus.UpstreamSpec
+			cluster.AKSConfig = cluster.AKSStatus.UpstreamSpec
 		case "gke":
-			updatedCluster.GKEConfig = updatedCluster.GKEStatus.UpstreamSpec
+			cluster.GKEConfig = cluster.GKEStatus.UpstreamSpec
 		case "eks":
-			updatedCluster.EKSConfig = updatedCluster.EKSStatus.UpstreamSpec
+			cluster.EKSConfig = cluster.EKSStatus.UpstreamSpec
 		}
 	}
-	return updatedCluster, nil
-
+	return cluster
 }
 
-// ClusterIsReadyChecks runs the basic checks on a cluster such as cluster name, service account, nodes and pods check
-func ClusterIsReadyChecks(cluster *management.Cluster, client *rancher.Client, clusterName string) {
-
-	ginkgo.By("checking cluster name is same", func() {
-		Expect(cluster.Name).To(BeEquivalentTo(clusterName))
-	})
-
-	ginkgo.By("checking service account token secret", func() {
-		success, err := clusters.CheckServiceAccountTokenSecret(client, clusterName)
-		Expect(err).To(BeNil())
-		Expect(success).To(BeTrue())
-	})
-
-	ginkgo.By("checking all management nodes are ready", func() {
-		err := nodestat.AllManagementNodeReady(client, cluster.ID, Timeout)
-		Expect(err).To(BeNil())
-	})
-
-	ginkgo.By("checking all pods are ready", func() {
-		Eventually(func() []error {
-			return pods.StatusPods(client, cluster.ID)
-		}, tools.SetTimeout(Timeout), 30*time.Second).Should(BeEmpty(), "All pods are not running")
+// WaitUntilClusterIsDeleted waits until the cluster has been removed from Rancher and checks the duration against the budget of OperationDelete
+func WaitUntilClusterIsDeleted(ctx context.Context, cluster *management.Cluster, client *rancher.Client) error {
+	start := time.Now()
+	watchInterface, err := client.GetManagementWatchInterface(management.ClusterType, clusterWatchOptions(ctx, cluster.ID))
+	if err != nil {
+		return err
+	}
+	err = watchWait(ctx, watchInterface, func(event watch.Event) (bool, error) {
+		return event.Type == watch.Deleted, nil
 	})
+	if err != nil {
+		return err
+	}
+	CheckLifecycleOperation(OperationDelete, time.Since(start))
+	return nil
 }
 
 // GetGKEZone fetches the value of GKE zone;
 // it first obtains the value from env var GKE_ZONE, if the value is empty, it fetches the information from config file(cattle_config-import.yaml/cattle_config-provisioning.yaml)
 // if none of the sources can provide a value, it returns the default value
 func GetGKEZone() string {
-	zone := os.Getenv("GKE_ZONE")
+	zone := Config.GKE.Zone
 	if zone == "" {
 		gkeConfig := new(management.GKEClusterConfigSpec)
 		config.LoadConfig("gkeClusterConfig", gkeConfig)
@@ -219,7 +117,7 @@ func GetGKEZone() string {
 // it first obtains the value from env var GKE_REGION, if the value is empty, it fetches the information from config file(cattle_config-provisioning.yaml)
 // if none of the sources can provide a value, it returns the default value
 func GetGKERegion() string {
-	region := os.Getenv("GKE_REGION")
+	region := Config.GKE.Region
 	if region == "" {
 		gkeConfig := new(management.GKEClusterConfigSpec)
 		config.LoadConfig("gkeClusterConfig", gkeConfig)
@@ -237,7 +135,7 @@ func GetGKERegion() string {
 // it first obtains the value from env var AKS_REGION, if the value is empty, it fetches the information from config file(cattle_config-import.yaml/cattle_config-provisioning.yaml)
 // if none of the sources can provide a value, it returns the default value
 func GetAKSLocation() string {
-	region := os.Getenv("AKS_REGION")
+	region := Config.AKS.Region
 	if region == "" {
 		aksClusterConfig := new(management.AKSClusterConfigSpec)
 		config.LoadConfig("aksClusterConfig", aksClusterConfig)
@@ -253,7 +151,7 @@ func GetAKSLocation() string {
 // it first obtains the value from env var EKS_REGION, if the value is empty, it fetches the information from config file(cattle_config-import.yaml/cattle_config-provisioning.yaml)
 // if none of the sources can provide a value, it returns the default value
 func GetEKSRegion() string {
-	region := os.Getenv("EKS_REGION")
+	region := Config.EKS.Region
 	if region == "" {
 		eksClusterConfig := new(management.EKSClusterConfigSpec)
 		config.LoadConfig("eksClusterConfig", eksClusterConfig)
@@ -267,76 +165,106 @@ func GetEKSRegion() string {
 
 // GetGKEProjectID returns the value of GKE project by fetching the value of env var GKE_PROJECT_ID
 func GetGKEProjectID() string {
-	return os.Getenv("GKE_PROJECT_ID")
+	return Config.GKE.ProjectID
+}
+
+// ClusterNamePrefixFor returns the prefix of the names of the clusters created by the tests for the given provider
+func ClusterNamePrefixFor(provider string) string {
+	if clusterCleanup {
+		return fmt.Sprintf("%s-hp-ci", provider)
+	}
+	return fmt.Sprintf("%s-%s-hp-ci", provider, testuser.Username)
 }
 
 // GetCommonMetadataLabels returns a list of common metadata labels/tabs
 func GetCommonMetadataLabels() map[string]string {
-	specReport := ginkgo.CurrentSpecReport()
+	specFile, specLine := GetSpecHooks().Location()
 	// filename indicates the filename and line number of the test
 	// we only use this information instead of the ginkgo.CurrentSpecReport().FullText() because of the 63 character limit
 	var filename string
 	// Because of the way Support Matrix suites are designed, filename is not loaded at first, so we need to ensure it is non-empty before sanitizing it
 	//E.g. line51_k8s_chart_support_provisioning_test
-	if specReport.FileName() != "" {
+	if specFile != "" {
 		// Sanitize the filename to fit the label requirements for all the hosted providers
-		fileSplit := strings.Split(specReport.FileName(), "/") // abstract the filename
+		fileSplit := strings.Split(specFile, "/") // abstract the filename
 		filename = fileSplit[len(fileSplit)-1]
 		filename = strings.TrimSuffix(filename, ".go") // `.` is not allowed
 		filename = strings.ToLower(filename)           // string must be in lowercase
-		filename = fmt.Sprintf("line%d_%s", specReport.LineNumber(), filename)
+		filename = fmt.Sprintf("line%d_%s", specLine, filename)
 	}
 
 	metadataLabels := map[string]string{
-		"owner":          "hosted-providers-qa-ci-" + testuser.Username,
+		OwnerLabel:       OwnerLabelPrefix + testuser.Username,
 		"testfilenumber": filename,
 	}
 
 	if !clusterCleanup {
-		metadataLabels["janitor-ignore"] = "true"
+		metadataLabels[JanitorIgnoreLabel] = "true"
 	} else if Provider == "eks" {
 		metadataLabels["aws-janitor/marked-for-deletion"] = "true"
 	}
 	return metadataLabels
 }
 
-func SetTempKubeConfig(clusterName string) {
+// SetTempKubeConfig points KUBECONFIG to the kubeconfig of the downstream cluster; if unset, it creates a temporary one whose removal is registered in Cleanups
+func SetTempKubeConfig(clusterName string) error {
 	downstreamKubeconfig := os.Getenv(DownstreamKubeconfig(clusterName))
 	if downstreamKubeconfig == "" {
 		tmpKubeConfig, err := os.CreateTemp("", clusterName)
-		Expect(err).To(BeNil())
+		if err != nil {
+			return errors.Wrap(err, "Failed to create the temporary kubeconfig")
+		}
+		_ = tmpKubeConfig.Close()
 		downstreamKubeconfig = tmpKubeConfig.Name()
 		_ = os.Setenv(DownstreamKubeconfig(clusterName), downstreamKubeconfig)
+		RegisterCleanup(CleanupKey("kubeconfig", downstreamKubeconfig), fmt.Sprintf("temporary kubeconfig %s", downstreamKubeconfig), func(context.Context) error {
+			_ = os.Unsetenv(DownstreamKubeconfig(clusterName))
+			if err := os.Remove(downstreamKubeconfig); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			return nil
+		})
 	}
-	_ = os.Setenv("KUBECONFIG", downstreamKubeconfig)
+	return os.Setenv("KUBECONFIG", downstreamKubeconfig)
 }
 
 // HighestK8sMinorVersionSupportedByUI returns the highest k8s version supported by UI
 // TODO(pvala): Use this by default when fetching a list of k8s version for all the downstream providers.
-func HighestK8sMinorVersionSupportedByUI(client *rancher.Client) (value string) {
-	uiValue, err := client.Management.Setting.ByID("ui-k8s-supported-versions-range")
-	Expect(err).To(BeNil())
+func HighestK8sMinorVersionSupportedByUI(client *rancher.Client) (string, error) {
+	uiValue, err := client.Management.Setting.ByID(uiSupportedVersionsSetting)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to get the k8s versions range supported by UI")
+	}
 	// example value: >=v1.31.x <=v1.33.x
 	values := strings.Split(uiValue.Value, " ")
-	value = values[len(values)-1]
-	Expect(value).ToNot(BeEmpty())
+	value := values[len(values)-1]
+	if value == "" {
+		return "", fmt.Errorf("k8s versions range supported by UI %q has no upper bound", uiValue.Value)
+	}
 	value = strings.TrimPrefix(value, "<=v")
 	value = strings.TrimSuffix(value, ".x")
-	return value
+	return value, nil
 }
 
 // FilterUIUnsupportedVersions filters all k8s versions that are not supported by the UI
-func FilterUIUnsupportedVersions(versions []string, client *rancher.Client) (filteredVersions []string) {
-	maxValue := HighestK8sMinorVersionSupportedByUI(client)
+func FilterUIUnsupportedVersions(versions []string, client *rancher.Client) (filteredVersions []string, err error) {
+	maxValue, err := HighestK8sMinorVersionSupportedByUI(client)
+	if err != nil {
+		return nil, err
+	}
 	for _, version := range versions {
+		comparison, err := VersionCompare(version, maxValue)
+		if err != nil {
+			return nil, err
+		}
 		// if the version is <= maxValue, then append it to the filtered list
-		if comparison := VersionCompare(version, maxValue); comparison < 1 {
+		if comparison < 1 {
 			filteredVersions = append(filteredVersions, version)
 		} else if strings.Contains(version, maxValue) {
 			filteredVersions = append(filteredVersions, version)
 		}
 	}
-	return
+	return filteredVersions, nil
 }
 
 // CheckMapElements checks if map1 keys are subset of map2
@@ -361,6 +289,7 @@ func DefaultK8sVersion(descVersions []string, forUpgrade bool) (string, error) {
 	return descVersions[1], nil
 }
 
+// CreateCloudCredentials creates the cloud credential of the current Provider and returns its ID
 func CreateCloudCredentials(client *rancher.Client) (string, error) {
 	var (
 		err                   error
@@ -372,16 +301,26 @@ func CreateCloudCredentials(client *rancher.Client) (string, error) {
 	case "aks":
 		cloudCredentialConfig = cloudcredentials.LoadCloudCredential("azure")
 		cloudCredential, err = azure.CreateAzureCloudCredentials(client, cloudCredentialConfig)
-		Expect(err).To(BeNil())
 	case "eks":
 		cloudCredentialConfig = cloudcredentials.LoadCloudCredential("aws")
 		cloudCredential, err = aws.CreateAWSCloudCredentials(client, cloudCredentialConfig)
-		Expect(err).To(BeNil())
 	case "gke":
 		cloudCredentialConfig = cloudcredentials.LoadCloudCredential("google")
 		cloudCredential, err = google.CreateGoogleCloudCredentials(client, cloudCredentialConfig)
-		Expect(err).To(BeNil())
+	default:
+		return "", fmt.Errorf("unsupported provider %q", Provider)
 	}
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to create "+Provider+" cloud credential")
+	}
+	// the clusters kept by DOWNSTREAM_CLUSTER_CLEANUP=false still use the cloud credential
+	RegisterClusterCleanup(CleanupKey("cloud-credential", cloudCredential.ID), fmt.Sprintf("cloud credential %s/%s", cloudCredential.Namespace, cloudCredential.Name), func(context.Context) error {
+		err := client.Steve.SteveType(stevetypes.Secret).Delete(cloudCredential)
+		if clientbase.IsNotFound(err) {
+			return nil
+		}
+		return err
+	})
 	return fmt.Sprintf("%s:%s", cloudCredential.Namespace, cloudCredential.Name), nil
 }
 
@@ -405,19 +344,6 @@ func ContainsString(slice []string, item string) bool {
 	return false
 }
 
-func GetRancherVersions(rancherFullVersion string) (string, string, string) {
-	var rancherChannel, rancherVersion, rancherHeadVersion string
-	// Extract Rancher Manager channel/version to install
-	s := strings.Split(rancherFullVersion, "/")
-	Expect(len(s)).To(BeNumerically(">=", 2), "RANCHER_VERSION must contain at least two strings separated by '/'")
-	rancherChannel = s[0]
-	rancherVersion = s[1] // This can be either a string like "2.9.3[-rc4]", "devel", or "latest"
-	if len(s) > 2 {
-		rancherHeadVersion = s[2]
-	}
-	return rancherChannel, rancherVersion, rancherHeadVersion
-}
-
 // GetRancherServerVersion returns the value of `server-version` Setting
 func GetRancherServerVersion(client *rancher.Client) (string, error) {
 	serverVersion, err := client.Management.Setting.ByID("server-version")
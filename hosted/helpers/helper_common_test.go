@@ -0,0 +1,131 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakerancher"
+)
+
+var _ = Describe("WaitUntilClusterIsReady", func() {
+	var originalProvider string
+	var originalIsImport bool
+
+	BeforeEach(func() {
+		originalProvider, originalIsImport = helpers.Provider, helpers.IsImport
+		DeferCleanup(func() {
+			helpers.Provider, helpers.IsImport = originalProvider, originalIsImport
+		})
+		helpers.Provider = "aks"
+	})
+
+	It("returns the up to date cluster once it is provisioned", func(ctx SpecContext) {
+		cluster, err := client.Management.Cluster.Create(&management.Cluster{
+			Name:      "fake-aks",
+			AKSConfig: &management.AKSClusterConfigSpec{ClusterName: "fake-aks", KubernetesVersion: pointer.String("1.31.2")},
+		})
+		Expect(err).To(BeNil())
+		Expect(cluster.State).To(Equal(fakerancher.StateProvisioning.Name))
+
+		cluster, err = helpers.WaitUntilClusterIsReady(ctx, cluster, client)
+		Expect(err).To(BeNil())
+		Expect(cluster.State).To(Equal(fakerancher.StateActive.Name))
+		Expect(cluster.Version.GitVersion).To(Equal("v1.31.2"))
+		Expect(client.Management.Cluster.Delete(cluster)).To(Succeed())
+	})
+
+	It("waits through a scripted transition", func(ctx SpecContext) {
+		id := server.AddCluster(&management.Cluster{Name: "fake-scripted"}, fakerancher.StateProvisioning)
+		Expect(server.ScriptClusterStates(id, fakerancher.StateProvisioning, fakerancher.StateProvisioning, fakerancher.StateActive)).To(Succeed())
+
+		cluster, err := client.Management.Cluster.ByID(id)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(ctx, cluster, client)
+		Expect(err).To(BeNil())
+		Expect(cluster.State).To(Equal(fakerancher.StateActive.Name))
+	})
+
+	It("replaces the config of an imported cluster with the upstream spec", func(ctx SpecContext) {
+		helpers.IsImport = true
+		id := server.AddCluster(&management.Cluster{
+			Name:      "fake-imported",
+			AKSConfig: &management.AKSClusterConfigSpec{ClusterName: "fake-imported", Imported: true, KubernetesVersion: pointer.String("1.30.5")},
+		}, fakerancher.StateActive)
+		// the operator fills in the remaining fields only in the upstream spec
+		Expect(server.UpdateCluster(id, func(cluster *management.Cluster) {
+			cluster.AKSStatus.UpstreamSpec.ResourceLocation = "centralindia"
+		})).To(Succeed())
+
+		cluster, err := client.Management.Cluster.ByID(id)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(ctx, cluster, client)
+		Expect(err).To(BeNil())
+		Expect(cluster.AKSConfig.ResourceLocation).To(Equal("centralindia"))
+	})
+})
+
+var _ = Describe("CreateCloudCredentials", func() {
+	var originalProvider string
+
+	BeforeEach(func() {
+		originalProvider = helpers.Provider
+		DeferCleanup(func() {
+			helpers.Provider = originalProvider
+		})
+	})
+
+	DescribeTable("creates the cloud credential of the provider",
+		func(provider, credentialKey string) {
+			helpers.Provider = provider
+			cloudCredID, err := helpers.CreateCloudCredentials(client)
+			Expect(err).To(BeNil())
+
+			namespace, name, found := strings.Cut(cloudCredID, ":")
+			Expect(found).To(BeTrue())
+			Expect(namespace).To(Equal("cattle-global-data"))
+			secret, ok := server.Secrets()[namespace+"/"+name]
+			Expect(ok).To(BeTrue())
+			Expect(secret["data"]).To(HaveKey(credentialKey))
+		},
+		Entry("aks", "aks", "azurecredentialConfig-clientId"),
+		Entry("eks", "eks", "amazonec2credentialConfig-accessKey"),
+		Entry("gke", "gke", "googlecredentialConfig-authEncodedJson"),
+	)
+})
+
+var _ = Describe("HighestK8sMinorVersionSupportedByUI", func() {
+	BeforeEach(func() {
+		DeferCleanup(server.SetSetting, "ui-k8s-supported-versions-range", fakerancher.DefaultSettings["ui-k8s-supported-versions-range"])
+	})
+
+	It("returns the upper bound of the UI supported range", func() {
+		Expect(helpers.HighestK8sMinorVersionSupportedByUI(client)).To(Equal("1.32"))
+
+		server.SetSetting("ui-k8s-supported-versions-range", ">=v1.31.x <=v1.33.x")
+		Expect(helpers.HighestK8sMinorVersionSupportedByUI(client)).To(Equal("1.33"))
+	})
+
+	It("filters the versions newer than the upper bound", func() {
+		versions := []string{"1.33.1", "1.32.4", "1.32.0-gke.100", "1.31.7"}
+		Expect(helpers.FilterUIUnsupportedVersions(versions, client)).To(Equal([]string{"1.32.4", "1.32.0-gke.100", "1.31.7"}))
+	})
+})
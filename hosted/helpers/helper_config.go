@@ -0,0 +1,363 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Mode tells whether the hosted clusters are provisioned by Rancher or created on the cloud provider and imported
+type Mode string
+
+const (
+	ModeProvisioning Mode = "provisioning"
+	ModeImport       Mode = "import"
+)
+
+// Suite selects the variables validated by RunConfig.Validate
+type Suite string
+
+const (
+	// SuiteSetup installs k3s, cert-manager and Rancher, i.e. the suite at the root of the repository
+	SuiteSetup Suite = "setup"
+	// SuiteHosted covers the suites running against the hosted clusters, for e.g. p0, p1, support matrix, k8s chart support and scenarios
+	SuiteHosted Suite = "hosted"
+	// SuiteBackupRestore requires the variables of SuiteHosted and of the reinstallation of Rancher
+	SuiteBackupRestore Suite = "backup-restore"
+)
+
+// Suites lists the suites accepted by RunConfig.Validate
+var Suites = []Suite{SuiteSetup, SuiteHosted, SuiteBackupRestore}
+
+// includes returns true if the variables required by the other suite are also required by s
+func (s Suite) includes(other Suite) bool {
+	return s == other || (s == SuiteBackupRestore && other == SuiteHosted)
+}
+
+// Config is the RunConfig loaded from the environment when the package is initialized
+var Config = LoadRunConfig(os.LookupEnv)
+
+// RunConfig is the configuration of a test run.
+// Each field is read from the env var of its `env` tag, or set to its `default` tag if the variable is empty;
+// `required` lists the suites that need it, and `secret` fields are redacted by Entries.
+// The fields of a provider section are only required and reported if PROVIDER selects it.
+type RunConfig struct {
+	Provider string `env:"PROVIDER" required:"hosted"`
+	// Mode is read from TEST_MODE; it is inferred from the name of the CATTLE_TEST_CONFIG file if unset
+	Mode             Mode   `env:"TEST_MODE"`
+	CattleTestConfig string `env:"CATTLE_TEST_CONFIG" required:"hosted"`
+	// DownstreamClusterCleanup deletes the clusters at the end of the specs; they are labelled to be ignored by the janitor otherwise
+	DownstreamClusterCleanup  bool   `env:"DOWNSTREAM_CLUSTER_CLEANUP"`
+	DownstreamK8sMinorVersion string `env:"DOWNSTREAM_K8S_MINOR_VERSION"`
+	K8sUpgradeMinorVersion    string `env:"K8S_UPGRADE_MINOR_VERSION"`
+	// K8sVersionCatalogue is the source of the k8s versions supported per provider and Rancher version; see LoadVersionCatalogue
+	K8sVersionCatalogue string `env:"K8S_VERSION_CATALOGUE"`
+	// KDMServerHost is the address at which Rancher reaches the test host to download the data of a KDMServer
+	KDMServerHost string `env:"KDM_SERVER_HOST"`
+	// CloudBackend selects how the *OnAzure, *OnAWS and *OnGCloud helpers reach the cloud; see UseCloudSDK
+	CloudBackend string `env:"CLOUD_BACKEND" default:"cli"`
+	// RunReportDir is the directory in which suite.WriteRunReport writes a JSON report per spec; no report is written if it is empty
+	RunReportDir string `env:"RUN_REPORT_DIR"`
+	ScenariosDir string `env:"SCENARIOS_DIR"`
+
+	Rancher RancherConfig
+	Install InstallConfig
+	Backup  BackupConfig
+	AKS     AKSConfig `provider:"aks"`
+	EKS     EKSConfig `provider:"eks"`
+	GKE     GKEConfig `provider:"gke"`
+
+	inferredMode bool
+	defaults     map[string]bool
+	invalid      []string
+}
+
+// RancherConfig is the Rancher server used by the tests
+type RancherConfig struct {
+	Hostname string `env:"RANCHER_HOSTNAME" required:"setup,hosted"`
+	Password string `env:"RANCHER_PASSWORD" required:"hosted" secret:"true"`
+	// Version is the channel/version of the chart, for e.g. latest/devel/2.11
+	Version        string `env:"RANCHER_VERSION" required:"setup,backup-restore"`
+	UpgradeVersion string `env:"RANCHER_UPGRADE_VERSION"`
+	// MigrationHostname is the hostname of the Rancher restored by the migration specs of the backup-restore suites; see MigrationHostname
+	MigrationHostname string `env:"RANCHER_MIGRATION_HOSTNAME"`
+	// MigrationKubeconfig is the kubeconfig of the upstream cluster on which the migration specs restore Rancher;
+	// the backup is restored on the upstream of Kubeconfig if empty
+	MigrationKubeconfig string `env:"RANCHER_MIGRATION_KUBECONFIG"`
+	Kubeconfig          string `env:"KUBECONFIG" required:"setup,backup-restore"`
+}
+
+// InstallConfig is the installation of k3s, Rancher and the operators by the setup and backup-restore suites
+type InstallConfig struct {
+	K3sVersion            string `env:"INSTALL_K3S_VERSION" required:"setup,backup-restore"`
+	Proxy                 string `env:"RANCHER_BEHIND_PROXY"`
+	ProxyHost             string `env:"PROXY_HOST" default:"172.17.0.1:3128"`
+	NightlyChart          string `env:"NIGHTLY_CHART"`
+	SkipRancherInstall    bool   `env:"SKIP_RANCHER_INSTALL"`
+	BackupOperatorVersion string `env:"BACKUP_OPERATOR_VERSION"`
+}
+
+// BackupConfig is the storage of the backups taken by the backup-restore suites
+type BackupConfig struct {
+	// Storage is local, the local-path volume of the k3s node the tests run on, or s3; see GetS3Storage.
+	// It defaults to local, which needs neither docker nor credentials
+	Storage string `env:"BACKUP_STORAGE" default:"local"`
+	// S3Endpoint is the host[:port] of the S3-compatible storage; with s3 storage, a local MinIO container is started if it is unset
+	S3Endpoint  string `env:"BACKUP_S3_ENDPOINT"`
+	S3Bucket    string `env:"BACKUP_S3_BUCKET" default:"hp-backups"`
+	S3Folder    string `env:"BACKUP_S3_FOLDER"`
+	S3Region    string `env:"BACKUP_S3_REGION"`
+	S3AccessKey string `env:"BACKUP_S3_ACCESS_KEY"`
+	S3SecretKey string `env:"BACKUP_S3_SECRET_KEY" secret:"true"`
+	// S3EndpointCA is the path of the PEM CA of the endpoint, for e.g. if its certificate is self-signed
+	S3EndpointCA            string `env:"BACKUP_S3_ENDPOINT_CA"`
+	S3InsecureTLSSkipVerify bool   `env:"BACKUP_S3_INSECURE_TLS_SKIP_VERIFY"`
+}
+
+// AKSConfig is the Azure service principal and location used on AKS
+type AKSConfig struct {
+	ClientID       string `env:"AKS_CLIENT_ID" required:"hosted"`
+	ClientSecret   string `env:"AKS_CLIENT_SECRET" required:"hosted" secret:"true"`
+	SubscriptionID string `env:"AKS_SUBSCRIPTION_ID" required:"hosted"`
+	// TenantID is only required by the sdk cloud backend
+	TenantID          string `env:"AKS_TENANT_ID"`
+	Region            string `env:"AKS_REGION"`
+	VNet              string `env:"AKS_VNET"`
+	VNetResourceGroup string `env:"AKS_VNET_RG"`
+}
+
+// EKSConfig is the AWS account and region used on EKS
+type EKSConfig struct {
+	AccessKeyID     string `env:"AWS_ACCESS_KEY_ID" required:"hosted"`
+	SecretAccessKey string `env:"AWS_SECRET_ACCESS_KEY" required:"hosted" secret:"true"`
+	Region          string `env:"EKS_REGION"`
+	KMSKey          string `env:"AWS_KMS_KEY"`
+	// ClusterRoleARN, NodeRoleARN and SubnetIDs are only required to create clusters with the sdk cloud backend
+	ClusterRoleARN string `env:"EKS_CLUSTER_ROLE_ARN"`
+	NodeRoleARN    string `env:"EKS_NODE_ROLE_ARN"`
+	SubnetIDs      string `env:"EKS_SUBNET_IDS"`
+}
+
+// GKEConfig is the Google project and location used on GKE
+type GKEConfig struct {
+	ProjectID   string `env:"GKE_PROJECT_ID" required:"hosted"`
+	Credentials string `env:"GCP_CREDENTIALS" required:"hosted" secret:"true"`
+	// SecondaryCredentials is the service account of another project, used by the p1 specs sharing a network
+	SecondaryCredentials string `env:"SECONDARY_GCP_CREDENTIALS" secret:"true"`
+	Zone                 string `env:"GKE_ZONE"`
+	Region               string `env:"GKE_REGION"`
+}
+
+// ConfigEntry is a variable of the RunConfig as reported by Entries
+type ConfigEntry struct {
+	Env   string
+	Value string
+	// Default is true if the variable is unset and Value is its default
+	Default  bool
+	Secret   bool
+	Required []Suite
+}
+
+// ConfigError lists every problem found by RunConfig.Validate
+type ConfigError struct {
+	Suite   Suite
+	Missing []string
+	Invalid []string
+}
+
+func (e *ConfigError) Error() string {
+	var problems []string
+	if len(e.Missing) > 0 {
+		problems = append(problems, "missing "+strings.Join(e.Missing, ", "))
+	}
+	problems = append(problems, e.Invalid...)
+	return fmt.Sprintf("invalid configuration for the %s suite: %s", e.Suite, strings.Join(problems, "; "))
+}
+
+// LoadRunConfig reads the RunConfig from lookupEnv, for e.g. os.LookupEnv;
+// the values that cannot be parsed are reported by Validate.
+func LoadRunConfig(lookupEnv func(string) (string, bool)) *RunConfig {
+	c := &RunConfig{defaults: map[string]bool{}}
+	c.walk(true, func(field reflect.StructField, value reflect.Value) {
+		env := field.Tag.Get("env")
+		raw, _ := lookupEnv(env)
+		if raw == "" {
+			raw = field.Tag.Get("default")
+			c.defaults[env] = raw != ""
+		}
+		switch value.Kind() {
+		case reflect.Bool:
+			if raw == "" {
+				return
+			}
+			parsed, err := strconv.ParseBool(raw)
+			if err != nil {
+				c.invalid = append(c.invalid, fmt.Sprintf("%s must be a boolean, got %q", env, raw))
+				return
+			}
+			value.SetBool(parsed)
+		default:
+			value.SetString(raw)
+		}
+	})
+
+	switch c.Mode {
+	case ModeProvisioning, ModeImport:
+	case "":
+		// the config files of the import suites are named cattle-config-import*.yaml
+		c.inferredMode = true
+		c.Mode = ModeProvisioning
+		if strings.Contains(c.CattleTestConfig, "import") {
+			c.Mode = ModeImport
+		}
+	default:
+		c.invalid = append(c.invalid, fmt.Sprintf("TEST_MODE must be %s or %s, got %q", ModeProvisioning, ModeImport, c.Mode))
+	}
+	return c
+}
+
+// IsImport returns true if the hosted clusters are imported
+func (c *RunConfig) IsImport() bool {
+	return c.Mode == ModeImport
+}
+
+// Validate checks that every variable required by the suite is set and that the values are valid; the returned *ConfigError lists all the problems at once
+func (c *RunConfig) Validate(suite Suite) error {
+	known := false
+	for _, s := range Suites {
+		known = known || s == suite
+	}
+	if !known {
+		return fmt.Errorf("unknown suite %q; valid suites: %v", suite, Suites)
+	}
+
+	configErr := &ConfigError{Suite: suite, Invalid: append([]string(nil), c.invalid...)}
+	for _, entry := range c.Entries() {
+		if entry.Value != "" {
+			continue
+		}
+		for _, required := range entry.Required {
+			if suite.includes(required) {
+				configErr.Missing = append(configErr.Missing, entry.Env)
+				break
+			}
+		}
+	}
+	if c.Provider != "" && !ContainsString([]string{"aks", "eks", "gke"}, c.Provider) {
+		configErr.Invalid = append(configErr.Invalid, fmt.Sprintf("PROVIDER must be aks, eks or gke, got %q", c.Provider))
+	}
+	if !ContainsString([]string{CLIBackend, SDKBackend}, strings.ToLower(c.CloudBackend)) {
+		configErr.Invalid = append(configErr.Invalid, fmt.Sprintf("CLOUD_BACKEND must be %s or %s, got %q", CLIBackend, SDKBackend, c.CloudBackend))
+	}
+	if !ContainsString([]string{BackupStorageLocal, BackupStorageS3}, c.Backup.Storage) {
+		configErr.Invalid = append(configErr.Invalid, fmt.Sprintf("BACKUP_STORAGE must be %s or %s, got %q", BackupStorageLocal, BackupStorageS3, c.Backup.Storage))
+	}
+	if suite.includes(SuiteBackupRestore) && c.Backup.Storage == BackupStorageS3 && c.Backup.S3Endpoint != "" {
+		configErr.Missing = appendIfEmpty(configErr.Missing, "BACKUP_S3_ACCESS_KEY", c.Backup.S3AccessKey)
+		configErr.Missing = appendIfEmpty(configErr.Missing, "BACKUP_S3_SECRET_KEY", c.Backup.S3SecretKey)
+	}
+	if c.Rancher.MigrationHostname != "" && strings.EqualFold(c.Rancher.MigrationHostname, c.Rancher.Hostname) {
+		configErr.Invalid = append(configErr.Invalid, "RANCHER_MIGRATION_HOSTNAME must differ from RANCHER_HOSTNAME")
+	}
+	if c.Rancher.MigrationKubeconfig != "" {
+		// the default migration hostname resolves to the host of the current upstream
+		configErr.Missing = appendIfEmpty(configErr.Missing, "RANCHER_MIGRATION_HOSTNAME", c.Rancher.MigrationHostname)
+		if c.Rancher.MigrationKubeconfig == c.Rancher.Kubeconfig {
+			configErr.Invalid = append(configErr.Invalid, "RANCHER_MIGRATION_KUBECONFIG must differ from KUBECONFIG")
+		}
+		if c.Backup.Storage != BackupStorageS3 {
+			configErr.Invalid = append(configErr.Invalid, "RANCHER_MIGRATION_KUBECONFIG requires BACKUP_STORAGE=s3 since the local backups cannot be restored on another upstream")
+		}
+	}
+	if suite.includes(SuiteHosted) && strings.EqualFold(c.CloudBackend, SDKBackend) {
+		switch c.Provider {
+		case "aks":
+			configErr.Missing = appendIfEmpty(configErr.Missing, "AKS_TENANT_ID", c.AKS.TenantID)
+		case "eks":
+			configErr.Missing = appendIfEmpty(configErr.Missing, "EKS_CLUSTER_ROLE_ARN", c.EKS.ClusterRoleARN)
+			configErr.Missing = appendIfEmpty(configErr.Missing, "EKS_NODE_ROLE_ARN", c.EKS.NodeRoleARN)
+			configErr.Missing = appendIfEmpty(configErr.Missing, "EKS_SUBNET_IDS", c.EKS.SubnetIDs)
+		}
+	}
+
+	if len(configErr.Missing) == 0 && len(configErr.Invalid) == 0 {
+		return nil
+	}
+	return configErr
+}
+
+// Entries returns the variables of the RunConfig with the secrets redacted, in the order of the struct fields;
+// the provider sections not selected by PROVIDER are skipped
+func (c *RunConfig) Entries() []ConfigEntry {
+	var entries []ConfigEntry
+	c.walk(false, func(field reflect.StructField, value reflect.Value) {
+		entry := ConfigEntry{
+			Env:     field.Tag.Get("env"),
+			Default: c.defaults[field.Tag.Get("env")],
+			Secret:  field.Tag.Get("secret") == "true",
+		}
+		switch value.Kind() {
+		case reflect.Bool:
+			entry.Value = strconv.FormatBool(value.Bool())
+		default:
+			entry.Value = value.String()
+		}
+		if entry.Secret && entry.Value != "" {
+			entry.Value = "<redacted>"
+		}
+		if required := field.Tag.Get("required"); required != "" {
+			for _, suite := range strings.Split(required, ",") {
+				entry.Required = append(entry.Required, Suite(suite))
+			}
+		}
+		entries = append(entries, entry)
+	})
+	return entries
+}
+
+// String formats the effective configuration, one variable per line, with the secrets redacted
+func (c *RunConfig) String() string {
+	var b strings.Builder
+	for _, entry := range c.Entries() {
+		value := entry.Value
+		switch {
+		case value == "":
+			value = "<unset>"
+		case entry.Env == "TEST_MODE" && c.inferredMode:
+			value += " (inferred from CATTLE_TEST_CONFIG)"
+		case entry.Default:
+			value += " (default)"
+		}
+		fmt.Fprintf(&b, "%s=%s\n", entry.Env, value)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// walk calls f for every field with an env tag; the provider sections not selected by PROVIDER are skipped unless allProviders is true
+func (c *RunConfig) walk(allProviders bool, f func(field reflect.StructField, value reflect.Value)) {
+	var walkStruct func(value reflect.Value)
+	walkStruct = func(value reflect.Value) {
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			switch {
+			case field.Type.Kind() == reflect.Struct:
+				if provider := field.Tag.Get("provider"); provider != "" && provider != c.Provider && !allProviders {
+					continue
+				}
+				walkStruct(value.Field(i))
+			case field.Tag.Get("env") != "":
+				f(field, value.Field(i))
+			}
+		}
+	}
+	walkStruct(reflect.ValueOf(c).Elem())
+}
+
+func appendIfEmpty(missing []string, env, value string) []string {
+	if value == "" {
+		return append(missing, env)
+	}
+	return missing
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("RunConfig", func() {
+	load := func(env map[string]string) *helpers.RunConfig {
+		return helpers.LoadRunConfig(func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		})
+	}
+	validEKS := map[string]string{
+		"PROVIDER":              "eks",
+		"CATTLE_TEST_CONFIG":    "cattle-config-provisioning.yaml",
+		"RANCHER_HOSTNAME":      "rancher.example.com",
+		"RANCHER_PASSWORD":      "admin123",
+		"AWS_ACCESS_KEY_ID":     "AKIA",
+		"AWS_SECRET_ACCESS_KEY": "aws-secret",
+	}
+
+	It("reads the variables and applies the defaults", func() {
+		config := load(validEKS)
+		Expect(config.Validate(helpers.SuiteHosted)).To(Succeed())
+		Expect(config.Provider).To(Equal("eks"))
+		Expect(config.EKS.SecretAccessKey).To(Equal("aws-secret"))
+		Expect(config.CloudBackend).To(Equal(helpers.CLIBackend))
+		Expect(config.Install.ProxyHost).To(Equal("172.17.0.1:3128"))
+		Expect(config.IsImport()).To(BeFalse())
+	})
+
+	It("infers the mode from the config file unless TEST_MODE is set", func() {
+		Expect(load(map[string]string{"CATTLE_TEST_CONFIG": "cattle-config-import.yaml"}).Mode).To(Equal(helpers.ModeImport))
+		Expect(load(map[string]string{"CATTLE_TEST_CONFIG": "cattle-config-import.yaml", "TEST_MODE": "provisioning"}).Mode).To(Equal(helpers.ModeProvisioning))
+	})
+
+	It("lists every missing and invalid variable of the suite", func() {
+		config := load(map[string]string{"PROVIDER": "gke", "DOWNSTREAM_CLUSTER_CLEANUP": "maybe", "TEST_MODE": "imported"})
+		err := config.Validate(helpers.SuiteBackupRestore)
+		var configErr *helpers.ConfigError
+		Expect(errors.As(err, &configErr)).To(BeTrue())
+		Expect(configErr.Missing).To(Equal([]string{
+			"CATTLE_TEST_CONFIG", "RANCHER_HOSTNAME", "RANCHER_PASSWORD", "RANCHER_VERSION", "KUBECONFIG", "INSTALL_K3S_VERSION", "GKE_PROJECT_ID", "GCP_CREDENTIALS",
+		}))
+		Expect(configErr.Invalid).To(ConsistOf(
+			`DOWNSTREAM_CLUSTER_CLEANUP must be a boolean, got "maybe"`,
+			`TEST_MODE must be provisioning or import, got "imported"`,
+		))
+		Expect(config.Validate(helpers.SuiteSetup)).To(MatchError(ContainSubstring("missing RANCHER_HOSTNAME, RANCHER_VERSION, KUBECONFIG, INSTALL_K3S_VERSION")))
+	})
+
+	It("requires the variables of the sdk cloud backend", func() {
+		env := map[string]string{"CLOUD_BACKEND": "sdk"}
+		for key, value := range validEKS {
+			env[key] = value
+		}
+		Expect(load(env).Validate(helpers.SuiteHosted)).To(MatchError(
+			"invalid configuration for the hosted suite: missing EKS_CLUSTER_ROLE_ARN, EKS_NODE_ROLE_ARN, EKS_SUBNET_IDS"))
+	})
+
+	It("requires the credentials of a remote S3 backup storage", func() {
+		env := map[string]string{"BACKUP_STORAGE": "s3", "BACKUP_S3_ENDPOINT": "s3.example.com", "BACKUP_S3_ACCESS_KEY": "AKIA"}
+		Expect(load(env).Validate(helpers.SuiteBackupRestore)).To(MatchError(ContainSubstring("INSTALL_K3S_VERSION, BACKUP_S3_SECRET_KEY")))
+		Expect(load(map[string]string{"BACKUP_STORAGE": "s3"}).Backup.S3Bucket).To(Equal("hp-backups"))
+		Expect(load(map[string]string{"BACKUP_STORAGE": "pv"}).Validate(helpers.SuiteSetup)).To(MatchError(ContainSubstring(`BACKUP_STORAGE must be local or s3, got "pv"`)))
+	})
+
+	It("rejects a migration hostname equal to the Rancher hostname", func() {
+		env := map[string]string{"RANCHER_HOSTNAME": "rancher.example.com", "RANCHER_MIGRATION_HOSTNAME": "Rancher.example.com"}
+		Expect(load(env).Validate(helpers.SuiteSetup)).To(MatchError(ContainSubstring("RANCHER_MIGRATION_HOSTNAME must differ from RANCHER_HOSTNAME")))
+	})
+
+	It("requires a migration hostname and the S3 storage to migrate to another upstream", func() {
+		env := map[string]string{"RANCHER_HOSTNAME": "rancher.example.com", "KUBECONFIG": "/etc/rancher/k3s/k3s.yaml", "RANCHER_MIGRATION_KUBECONFIG": "/etc/rancher/k3s/k3s.yaml"}
+		err := load(env).Validate(helpers.SuiteSetup)
+		Expect(err).To(MatchError(ContainSubstring("RANCHER_MIGRATION_HOSTNAME")))
+		Expect(err).To(MatchError(ContainSubstring("RANCHER_MIGRATION_KUBECONFIG must differ from KUBECONFIG")))
+		Expect(err).To(MatchError(ContainSubstring("RANCHER_MIGRATION_KUBECONFIG requires BACKUP_STORAGE=s3")))
+
+		env["RANCHER_MIGRATION_KUBECONFIG"] = "/root/target.yaml"
+		env["RANCHER_MIGRATION_HOSTNAME"] = "rancher-new.example.com"
+		env["BACKUP_STORAGE"] = "s3"
+		Expect(load(env).Validate(helpers.SuiteSetup)).NotTo(MatchError(ContainSubstring("RANCHER_MIGRATION")))
+	})
+
+	It("redacts the secrets and skips the other providers", func() {
+		output := load(validEKS).String()
+		Expect(output).To(ContainSubstring("AWS_SECRET_ACCESS_KEY=<redacted>"))
+		Expect(output).To(ContainSubstring("RANCHER_PASSWORD=<redacted>"))
+		Expect(output).To(ContainSubstring("CLOUD_BACKEND=cli (default)"))
+		Expect(output).To(ContainSubstring("TEST_MODE=provisioning (inferred from CATTLE_TEST_CONFIG)"))
+		Expect(output).ToNot(ContainSubstring("aws-secret"))
+		Expect(output).ToNot(ContainSubstring("admin123"))
+		Expect(output).ToNot(ContainSubstring("AKS_CLIENT_ID"))
+	})
+})
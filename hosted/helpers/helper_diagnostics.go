@@ -0,0 +1,186 @@
+package helpers
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/clusters"
+)
+
+const (
+	// CattleGlobalDataNS is the namespace of the *ClusterConfig objects of the hosted clusters
+	CattleGlobalDataNS = "cattle-global-data"
+	// diagnosticsLogLines is the number of lines of the logs of each pod collected in a DiagnosticsBundle
+	diagnosticsLogLines = "5000"
+)
+
+// DiagnosticsBundle is the state of Rancher, of the operator and of a cluster, collected when a spec fails
+type DiagnosticsBundle struct {
+	// ClusterName is the name of the cluster the bundle is about; it is empty if the spec failed before creating a cluster
+	ClusterName string
+	CollectedAt time.Time
+	// Files are the contents of the bundle keyed by their path in the tarball
+	Files map[string][]byte
+	// Errors are the parts that could not be collected; a bundle is collected on a best effort basis
+	Errors []string
+}
+
+func (b *DiagnosticsBundle) add(name string, content []byte, err error) {
+	if err != nil {
+		b.Errors = append(b.Errors, fmt.Sprintf("%s: %v", name, err))
+	}
+	if len(content) > 0 {
+		b.Files[name] = content
+	}
+}
+
+// CollectDiagnostics collects from Rancher and from the cloud provider:
+//   - cluster.json, the management.Cluster, including its conditions and Transitioning message
+//   - clusterconfig.yaml, the *ClusterConfig object of the operator in cattle-global-data
+//   - operator.log and rancher.log, the logs of the ke.cattle.io/operator=<provider> and Rancher pods
+//   - events-cluster.txt and events-cattle-system.txt, the Kubernetes events of the cluster objects and of the operator and Rancher pods
+//   - cloud.json, the cluster as described by the cloud provider API
+//
+// cluster can be nil, for e.g. if the spec failed before creating it; the parts that cannot be collected are listed in Errors.
+func CollectDiagnostics(ctx context.Context, client *rancher.Client, cluster *management.Cluster) *DiagnosticsBundle {
+	bundle := &DiagnosticsBundle{CollectedAt: time.Now(), Files: map[string][]byte{}}
+	kubectl := func(name string, args ...string) {
+		out, err := kubectlCLI(ctx, args...)
+		bundle.add(name, []byte(out), err)
+	}
+
+	kubectl("operator.log", "logs", "--namespace", CattleSystemNS, "--selector", "ke.cattle.io/operator="+Provider,
+		"--all-containers", "--prefix", "--tail", diagnosticsLogLines)
+	kubectl("rancher.log", "logs", "--namespace", CattleSystemNS, "--selector", "app=rancher",
+		"--all-containers", "--prefix", "--tail", diagnosticsLogLines)
+	kubectl("events-cattle-system.txt", "get", "events", "--namespace", CattleSystemNS, "--sort-by", ".lastTimestamp")
+	if cluster == nil {
+		return bundle
+	}
+
+	bundle.ClusterName = cluster.Name
+	if client != nil {
+		// the cluster passed by the spec can be outdated
+		if latest, err := client.Management.Cluster.ByID(cluster.ID); err == nil {
+			cluster = latest
+		} else {
+			bundle.add("cluster.json", nil, errors.Wrap(err, "Failed to get the latest cluster, using the one of the spec"))
+		}
+	}
+	clusterJSON, err := json.MarshalIndent(cluster, "", "  ")
+	bundle.add("cluster.json", clusterJSON, err)
+	kubectl("clusterconfig.yaml", "get", fmt.Sprintf("%sclusterconfigs.%s.cattle.io", Provider, Provider),
+		"--namespace", CattleGlobalDataNS, cluster.ID, "--output", "yaml")
+	kubectl("events-cluster.txt", "get", "events", "--all-namespaces", "--field-selector", "involvedObject.name="+cluster.ID,
+		"--sort-by", ".lastTimestamp")
+
+	provider, err := CurrentHostedProvider()
+	if err != nil {
+		bundle.add("cloud.json", nil, err)
+		return bundle
+	}
+	spec, err := provider.ClusterSpecOnCloud(ctx, cluster)
+	if err != nil {
+		bundle.add("cloud.json", nil, err)
+		return bundle
+	}
+	cloudJSON, err := json.MarshalIndent(spec, "", "  ")
+	bundle.add("cloud.json", cloudJSON, err)
+	return bundle
+}
+
+// WriteDiagnostics writes the bundles to a gzipped tarball, each in a directory named after its cluster, along with an errors.txt for the parts not collected
+func WriteDiagnostics(file string, bundles ...*DiagnosticsBundle) error {
+	out, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	for i, bundle := range bundles {
+		dir := bundle.ClusterName
+		if dir == "" {
+			dir = "rancher"
+		}
+		dir = fmt.Sprintf("%d-%s", i+1, dir)
+		files := map[string][]byte{}
+		for name, content := range bundle.Files {
+			files[name] = content
+		}
+		if len(bundle.Errors) > 0 {
+			files["errors.txt"] = []byte(strings.Join(bundle.Errors, "\n") + "\n")
+		}
+		names := make([]string, 0, len(files))
+		for name := range files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			header := &tar.Header{Name: path.Join(dir, name), Mode: 0o644, Size: int64(len(files[name])), ModTime: bundle.CollectedAt}
+			if err = tw.WriteHeader(header); err != nil {
+				return err
+			}
+			if _, err = tw.Write(files[name]); err != nil {
+				return err
+			}
+		}
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+var (
+	pendingDiagnosticsMu sync.Mutex
+	// pendingDiagnostics are the bundles collected by the cluster teardowns of the failed spec, written by suite.WriteRunReport
+	pendingDiagnostics []*DiagnosticsBundle
+)
+
+// collectDiagnosticsOnFailure collects the diagnostics of the cluster before its teardown deletes it, if the current spec failed;
+// the teardowns run before ReportAfterEach, by which time the cluster may be gone
+func collectDiagnosticsOnFailure(ctx context.Context, client *rancher.Client, cluster *management.Cluster) {
+	if RunReportDir == "" || !GetSpecHooks().Failed() {
+		return
+	}
+	bundle := CollectDiagnostics(ctx, client, cluster)
+	pendingDiagnosticsMu.Lock()
+	defer pendingDiagnosticsMu.Unlock()
+	pendingDiagnostics = append(pendingDiagnostics, bundle)
+}
+
+// TakeDiagnostics returns the bundles collected for the current spec; if none and the spec failed, it collects them for the cluster if it still exists
+func TakeDiagnostics(ctx context.Context, failed bool, client *rancher.Client, clusterName string) []*DiagnosticsBundle {
+	pendingDiagnosticsMu.Lock()
+	bundles := pendingDiagnostics
+	pendingDiagnostics = nil
+	pendingDiagnosticsMu.Unlock()
+	if !failed || len(bundles) > 0 {
+		return bundles
+	}
+
+	var cluster *management.Cluster
+	if client != nil && clusterName != "" {
+		if clusterID, err := clusters.GetClusterIDByName(client, clusterName); err == nil {
+			cluster, _ = client.Management.Cluster.ByID(clusterID)
+		}
+	}
+	return []*DiagnosticsBundle{CollectDiagnostics(ctx, client, cluster)}
+}
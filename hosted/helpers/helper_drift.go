@@ -0,0 +1,166 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// Drift is a field whose value differs between the cluster config, its upstream spec and the cloud; an empty value is not reported by the source
+type Drift struct {
+	Field    string
+	Config   string
+	Upstream string
+	Cloud    string
+}
+
+// DriftReport lists the drifts of a cluster, sorted by field
+type DriftReport struct {
+	ClusterName string
+	Drifts      []Drift
+}
+
+// String formats the report with one line per drift and a `-` for the values that are not reported
+func (r DriftReport) String() string {
+	if len(r.Drifts) == 0 {
+		return fmt.Sprintf("No drift for cluster %s", r.ClusterName)
+	}
+	notReported := func(value string) string {
+		if value == "" {
+			return "-"
+		}
+		return value
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Cluster %s has drifted:\n", r.ClusterName)
+	for _, drift := range r.Drifts {
+		fmt.Fprintf(&b, "- %s: config=%s upstream=%s cloud=%s\n", drift.Field, notReported(drift.Config), notReported(drift.Upstream), notReported(drift.Cloud))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Ignoring returns the report without the drifts of the given fields and of their subfields, for e.g. "tags" or "nodePools[np1]"
+func (r DriftReport) Ignoring(fields ...string) DriftReport {
+	report := DriftReport{ClusterName: r.ClusterName}
+	for _, drift := range r.Drifts {
+		ignored := false
+		for _, field := range fields {
+			if drift.Field == field || strings.HasPrefix(drift.Field, field+".") || strings.HasPrefix(drift.Field, field+"[") {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			report.Drifts = append(report.Drifts, drift)
+		}
+	}
+	return report
+}
+
+// CompareClusterSpecs reports the fields whose value differs between at least two of the sources that report them;
+// the config of an imported cluster for e.g. only reports the fields managed by Rancher
+func CompareClusterSpecs(config, upstream, cloud ClusterSpec) []Drift {
+	configFields, upstreamFields, cloudFields := config.fields(), upstream.fields(), cloud.fields()
+	names := map[string]bool{}
+	for _, fields := range []map[string]string{configFields, upstreamFields, cloudFields} {
+		for name := range fields {
+			names[name] = true
+		}
+	}
+
+	var drifts []Drift
+	for name := range names {
+		drift := Drift{Field: name, Config: configFields[name], Upstream: upstreamFields[name], Cloud: cloudFields[name]}
+		var reported []string
+		for _, value := range []string{drift.Config, drift.Upstream, drift.Cloud} {
+			if value != "" {
+				reported = append(reported, value)
+			}
+		}
+		for i := 1; i < len(reported); i++ {
+			if reported[i] != reported[0] {
+				drifts = append(drifts, drift)
+				break
+			}
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].Field < drifts[j].Field })
+	return drifts
+}
+
+// fields flattens the spec into field paths and formatted values, omitting the fields that are not reported
+func (s ClusterSpec) fields() map[string]string {
+	fields := map[string]string{}
+	setString := func(name, value string) {
+		if value != "" {
+			fields[name] = value
+		}
+	}
+	setBool := func(name string, value *bool) {
+		if value != nil {
+			fields[name] = strconv.FormatBool(*value)
+		}
+	}
+	setInt := func(name string, value *int64) {
+		if value != nil {
+			fields[name] = strconv.FormatInt(*value, 10)
+		}
+	}
+	setMap := func(name string, value map[string]string) {
+		if value != nil {
+			fields[name] = FormatMap(value)
+		}
+	}
+
+	setString("kubernetesVersion", s.KubernetesVersion)
+	setMap("tags", s.Tags)
+	if s.Logging != nil {
+		logging := append([]string{}, s.Logging...)
+		sort.Strings(logging)
+		fields["logging"] = "[" + strings.Join(logging, ", ") + "]"
+	}
+	setBool("privateAccess", s.PrivateAccess)
+	setBool("publicAccess", s.PublicAccess)
+	if s.NodePools != nil {
+		var names []string
+		for _, np := range s.NodePools {
+			names = append(names, np.Name)
+			prefix := fmt.Sprintf("nodePools[%s].", np.Name)
+			fields[prefix+"nodeCount"] = strconv.FormatInt(np.NodeCount, 10)
+			setString(prefix+"kubernetesVersion", np.KubernetesVersion)
+			setMap(prefix+"labels", np.Labels)
+			setBool(prefix+"autoscaling", np.Autoscaling)
+			setInt(prefix+"minCount", np.MinCount)
+			setInt(prefix+"maxCount", np.MaxCount)
+		}
+		sort.Strings(names)
+		fields["nodePools"] = "[" + strings.Join(names, ", ") + "]"
+	}
+	return fields
+}
+
+// FormatMap formats the map with sorted keys, for e.g. {a=1, b=2}
+func FormatMap(m map[string]string) string {
+	var entries []string
+	for key, value := range m {
+		entries = append(entries, key+"="+value)
+	}
+	sort.Strings(entries)
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+// DetectDrift compares the cluster config, its upstream spec and the cluster as reported by the cloud provider
+func DetectDrift(ctx context.Context, provider HostedProvider, cluster *management.Cluster) (DriftReport, error) {
+	cloud, err := provider.ClusterSpecOnCloud(ctx, cluster)
+	if err != nil {
+		return DriftReport{}, err
+	}
+	return DriftReport{
+		ClusterName: cluster.Name,
+		Drifts:      CompareClusterSpecs(provider.ConfigSpec(cluster), provider.UpstreamSpec(cluster), cloud),
+	}, nil
+}
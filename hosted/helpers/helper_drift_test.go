@@ -0,0 +1,106 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakerancher"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
+)
+
+// driftProvider reports a fixed cloud spec for the scenarioProvider clusters
+type driftProvider struct {
+	scenarioProvider
+	cloud helpers.ClusterSpec
+}
+
+func (p driftProvider) ClusterSpecOnCloud(_ context.Context, _ *management.Cluster) (helpers.ClusterSpec, error) {
+	return p.cloud, nil
+}
+
+var _ = Describe("Drift", func() {
+	var upstream helpers.ClusterSpec
+
+	BeforeEach(func() {
+		upstream = helpers.ClusterSpec{
+			KubernetesVersion: "1.31.4",
+			Tags:              map[string]string{"owner": "qa"},
+			Logging:           []string{"audit", "api"},
+			PrivateAccess:     pointer.Bool(false),
+			NodePools: []helpers.NodePoolSpec{
+				{Name: "np1", NodeCount: 2, KubernetesVersion: "1.31.4", Autoscaling: pointer.Bool(false)},
+			},
+		}
+	})
+
+	It("does not report the fields that are not reported by a source", func() {
+		config := helpers.ClusterSpec{KubernetesVersion: "1.31.4"}
+		cloud := upstream
+		cloud.Logging = []string{"api", "audit"}
+		cloud.PublicAccess = pointer.Bool(true)
+
+		Expect(helpers.CompareClusterSpecs(config, upstream, cloud)).To(BeEmpty())
+	})
+
+	It("reports the fields that differ between the config, the upstream spec and the cloud", func() {
+		config := helpers.ClusterSpec{KubernetesVersion: "1.32.0", Tags: map[string]string{}}
+		cloud := upstream
+		cloud.NodePools = []helpers.NodePoolSpec{
+			{Name: "np1", NodeCount: 3, KubernetesVersion: "1.31.4", Autoscaling: pointer.Bool(false)},
+			{Name: "np2", NodeCount: 1},
+		}
+
+		Expect(helpers.CompareClusterSpecs(config, upstream, cloud)).To(Equal([]helpers.Drift{
+			{Field: "kubernetesVersion", Config: "1.32.0", Upstream: "1.31.4", Cloud: "1.31.4"},
+			{Field: "nodePools", Upstream: "[np1]", Cloud: "[np1, np2]"},
+			{Field: "nodePools[np1].nodeCount", Upstream: "2", Cloud: "3"},
+			{Field: "tags", Config: "{}", Upstream: "{owner=qa}", Cloud: "{owner=qa}"},
+		}))
+	})
+
+	It("detects the drift of a cluster and ignores the given fields", func(ctx SpecContext) {
+		id := server.AddCluster(&management.Cluster{
+			Name: "fake-drift",
+			AKSConfig: &management.AKSClusterConfigSpec{
+				KubernetesVersion: pointer.String("1.31.4"),
+				NodePools:         &[]management.AKSNodePool{{Name: pointer.String("np1"), Count: pointer.Int64(2)}},
+				Tags:              map[string]string{"owner": "qa"},
+			},
+		}, fakerancher.StateActive)
+		cluster, err := client.Management.Cluster.ByID(id)
+		Expect(err).To(BeNil())
+
+		cloud := upstream
+		cloud.KubernetesVersion = "1.32.0"
+		cloud.Tags = map[string]string{"owner": "qa", "env": "ci"}
+		report, err := helpers.DetectDrift(ctx, driftProvider{cloud: cloud}, cluster)
+		Expect(err).To(BeNil())
+		Expect(report.String()).To(Equal(`Cluster fake-drift has drifted:
+- kubernetesVersion: config=1.31.4 upstream=1.31.4 cloud=1.32.0
+- tags: config={owner=qa} upstream={owner=qa} cloud={env=ci, owner=qa}`))
+
+		report = report.Ignoring("tags", "kubernetesVersion")
+		Expect(report.Drifts).To(BeEmpty())
+		Expect(report.String()).To(Equal("No drift for cluster fake-drift"))
+
+		suite.ExpectNoDrift(ctx, driftProvider{cloud: helpers.ClusterSpec{Tags: map[string]string{"owner": "qa"}}}, client, cluster)
+	})
+})
@@ -0,0 +1,215 @@
+package helpers
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+	"github.com/rancher/shepherd/clients/rancher"
+)
+
+// Component is the software whose version a FeatureGate checks
+type Component string
+
+const (
+	ComponentRancher  Component = "Rancher"
+	ComponentOperator Component = "operator"
+)
+
+// FeatureGate is a semver constraint on the version of Rancher or of the operator of the current Provider
+type FeatureGate struct {
+	Component  Component
+	Constraint string
+	constraint *semver.Constraints
+}
+
+// RequiresRancher returns a gate on the Rancher server version, for e.g. RequiresRancher(">=2.12");
+// it panics if the constraint is invalid since the gates are declared as package variables
+func RequiresRancher(constraint string) FeatureGate {
+	return newFeatureGate(ComponentRancher, constraint)
+}
+
+// RequiresOperator returns a gate on the app version of the operator chart of the current Provider, for e.g. RequiresOperator(">=1.12")
+func RequiresOperator(constraint string) FeatureGate {
+	return newFeatureGate(ComponentOperator, constraint)
+}
+
+func newFeatureGate(component Component, constraint string) FeatureGate {
+	parsed, err := semver.NewConstraint(constraint)
+	if err != nil {
+		panic(fmt.Sprintf("invalid %s version constraint %q: %v", component, constraint, err))
+	}
+	return FeatureGate{Component: component, Constraint: constraint, constraint: parsed}
+}
+
+func (g FeatureGate) String() string {
+	return fmt.Sprintf("%s %s", g.Component, g.Constraint)
+}
+
+// Feature is a capability that is only available from some versions of Rancher or of the operators
+type Feature struct {
+	Name  string
+	Gates []FeatureGate
+}
+
+var (
+	featuresMu sync.RWMutex
+	features   = map[string]Feature{}
+)
+
+// RegisterFeature declares a feature available if all its gates are satisfied; it panics if the name is already registered
+func RegisterFeature(name string, gates ...FeatureGate) Feature {
+	featuresMu.Lock()
+	defer featuresMu.Unlock()
+	if _, exists := features[name]; exists {
+		panic(fmt.Sprintf("feature %s is already registered", name))
+	}
+	features[name] = Feature{Name: name, Gates: gates}
+	return features[name]
+}
+
+// GetFeature returns a feature registered by RegisterFeature
+func GetFeature(name string) (Feature, bool) {
+	featuresMu.RLock()
+	defer featuresMu.RUnlock()
+	feature, ok := features[name]
+	return feature, ok
+}
+
+var (
+	// FeatureK8sUpgrade is the upgrade of the k8s version of a cluster; Rancher 2.8 only supports one minor version on most providers
+	FeatureK8sUpgrade = RegisterFeature("k8s upgrade", RequiresRancher(">=2.9"))
+	// FeatureAKSNodePoolValidation is the rejection of the availability zone edits and of the removal of the system nodepool
+	FeatureAKSNodePoolValidation = RegisterFeature("AKS nodepool validation", RequiresRancher(">=2.10"))
+	// FeatureEKSGPUNodeGroups is the provisioning of nodegroups with GPU instances
+	FeatureEKSGPUNodeGroups = RegisterFeature("EKS GPU nodegroups", RequiresRancher(">=2.10"))
+	// FeatureAKSPrivateCluster is the provisioning of a private AKS cluster, see https://github.com/rancher/rancher/issues/43772
+	FeatureAKSPrivateCluster = RegisterFeature("AKS private cluster", RequiresRancher(">=2.12"))
+)
+
+// ComponentVersions are the versions checked by the feature gates; an empty version is fetched by suite.SkipUnlessSupported when a gate needs it
+type ComponentVersions struct {
+	Rancher  string
+	Operator string
+}
+
+func (v ComponentVersions) of(component Component) string {
+	if component == ComponentOperator {
+		return v.Operator
+	}
+	return v.Rancher
+}
+
+// UnsupportedFeatureError is returned by Feature.Check when a gate is not satisfied
+type UnsupportedFeatureError struct {
+	Feature string
+	Gate    FeatureGate
+	Version string
+}
+
+func (e *UnsupportedFeatureError) Error() string {
+	return fmt.Sprintf("feature %q requires %s, running %s %s", e.Feature, e.Gate, e.Gate.Component, e.Version)
+}
+
+// Check returns an *UnsupportedFeatureError for the first gate not satisfied by the versions.
+// The versions are compared without their pre-release and build metadata, so that for e.g. v2.12.0-rc3 and v2.12-head satisfy ">=2.12".
+func (f Feature) Check(versions ComponentVersions) error {
+	for _, gate := range f.Gates {
+		version := versions.of(gate.Component)
+		parsed, err := ParseReleaseVersion(version)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to check feature %q", f.Name)
+		}
+		if !gate.constraint.Check(parsed) {
+			return &UnsupportedFeatureError{Feature: f.Name, Gate: gate, Version: version}
+		}
+	}
+	return nil
+}
+
+var releaseVersionRe = regexp.MustCompile(`^v?(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseReleaseVersion returns the major.minor.patch part of a Rancher or chart version, for e.g. 2.12.0 for v2.12.0-rc3 or v2.12-3f2a1b-head;
+// the patch defaults to 0
+func ParseReleaseVersion(version string) (*semver.Version, error) {
+	match := releaseVersionRe.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return nil, fmt.Errorf("invalid version %q", version)
+	}
+	patch := match[3]
+	if patch == "" {
+		patch = "0"
+	}
+	return semver.NewVersion(fmt.Sprintf("%s.%s.%s", match[1], match[2], patch))
+}
+
+// GetCurrentOperatorAppVersion returns the app version of the operator chart of the current Provider, for e.g. v1.12.0
+func GetCurrentOperatorAppVersion(ctx context.Context) (string, error) {
+	charts, err := ListOperatorChart(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(charts) == 0 {
+		return "", fmt.Errorf("%s operator chart is not installed", Provider)
+	}
+	if charts[0].AppVersion != "" {
+		return charts[0].AppVersion, nil
+	}
+	// the rancher charts are versioned <rancher chart version>+up<app version>
+	if _, appVersion, found := strings.Cut(charts[0].DerivedVersion, "+up"); found {
+		return appVersion, nil
+	}
+	return charts[0].DerivedVersion, nil
+}
+
+// GetRancherVersionInfo returns the version of the Rancher server as reported by its `server-version` setting and its /rancherversion endpoint;
+// only the version is returned if the endpoint cannot be reached
+func GetRancherVersionInfo(client *rancher.Client) (RancherVersionInfo, error) {
+	serverVersion, err := GetRancherServerVersion(client)
+	if err != nil {
+		return RancherVersionInfo{}, errors.Wrap(err, "Failed to get the server-version setting")
+	}
+
+	info, err := getRancherVersionEndpoint(client)
+	if err != nil {
+		Logger.Info(fmt.Sprintf("Using the server-version setting only: %v", err))
+	}
+	// the setting is the source of truth for the version; the endpoint only adds the commit and the prime flag
+	info.Version = serverVersion
+	_, err = ParseReleaseVersion(serverVersion)
+	info.Devel = err != nil || strings.Contains(serverVersion, "head") || strings.Contains(serverVersion, "dev")
+	return info, nil
+}
+
+// getRancherVersionEndpoint returns the version of the Rancher server as reported by its /rancherversion endpoint
+func getRancherVersionEndpoint(client *rancher.Client) (RancherVersionInfo, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if client.RancherConfig.Insecure != nil && *client.RancherConfig.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} // #nosec G402 -- the test servers use self-signed certificates
+	}
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://%s/rancherversion", client.RancherConfig.Host), nil)
+	if err != nil {
+		return RancherVersionInfo{}, err
+	}
+	request.Header.Set("Authorization", "Bearer "+client.RancherConfig.AdminToken)
+	response, err := (&http.Client{Transport: transport}).Do(request)
+	if err != nil {
+		return RancherVersionInfo{}, errors.Wrap(err, "Failed to get the Rancher version")
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return RancherVersionInfo{}, fmt.Errorf("failed to get the Rancher version: %s", response.Status)
+	}
+	var info RancherVersionInfo
+	if err = json.NewDecoder(response.Body).Decode(&info); err != nil {
+		return RancherVersionInfo{}, errors.Wrap(err, "Failed to decode the Rancher version")
+	}
+	return info, nil
+}
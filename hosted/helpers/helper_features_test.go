@@ -0,0 +1,86 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakerancher"
+)
+
+var _ = Describe("Feature", func() {
+	feature := helpers.Feature{Name: "fake", Gates: []helpers.FeatureGate{helpers.RequiresRancher(">=2.12"), helpers.RequiresOperator(">=1.9")}}
+
+	DescribeTable("checks the release part of the versions",
+		func(rancherVersion string, supported bool) {
+			err := feature.Check(helpers.ComponentVersions{Rancher: rancherVersion, Operator: "v1.9.3"})
+			if supported {
+				Expect(err).To(BeNil())
+				return
+			}
+			var unsupported *helpers.UnsupportedFeatureError
+			Expect(errors.As(err, &unsupported)).To(BeTrue())
+			Expect(err).To(MatchError(`feature "fake" requires Rancher >=2.12, running Rancher ` + rancherVersion))
+		},
+		Entry("with a release", "v2.12.1", true),
+		Entry("with a release candidate", "v2.12.0-rc3", true),
+		Entry("with a head build", "v2.12-3f2a1b-head", true),
+		Entry("with a later minor", "v2.13.0", true),
+		Entry("with an earlier minor", "v2.11.4", false),
+		Entry("with an earlier release candidate", "v2.11.5-rc1", false),
+	)
+
+	It("reports the operator gate", func() {
+		Expect(feature.Check(helpers.ComponentVersions{Rancher: "v2.12.0", Operator: "1.8.2"})).To(
+			MatchError(`feature "fake" requires operator >=1.9, running operator 1.8.2`))
+	})
+
+	It("fails on an invalid version", func() {
+		Expect(feature.Check(helpers.ComponentVersions{Rancher: "head", Operator: "v1.9.0"})).To(MatchError(ContainSubstring(`invalid version "head"`)))
+	})
+
+	It("registers the features once", func() {
+		registered, ok := helpers.GetFeature(helpers.FeatureAKSPrivateCluster.Name)
+		Expect(ok).To(BeTrue())
+		Expect(registered.Gates).To(HaveLen(1))
+		Expect(registered.Gates[0].String()).To(Equal("Rancher >=2.12"))
+		Expect(func() { helpers.RegisterFeature(helpers.FeatureAKSPrivateCluster.Name) }).To(Panic())
+	})
+})
+
+var _ = Describe("GetRancherVersionInfo", func() {
+	It("returns the server version along with the commit", func() {
+		server.SetSetting("server-version", "v2.12-3f2a1b-head")
+		DeferCleanup(server.SetSetting, "server-version", fakerancher.DefaultSettings["server-version"])
+
+		info, err := helpers.GetRancherVersionInfo(client)
+		Expect(err).To(BeNil())
+		Expect(info).To(Equal(helpers.RancherVersionInfo{Version: "v2.12-3f2a1b-head", GitCommit: "fake", RancherPrime: "false", Devel: true}))
+	})
+
+	It("falls back to the server version if /rancherversion fails", func() {
+		// only /rancherversion is sent to the configured host, the settings are fetched through the API client
+		DeferCleanup(func(host string) { client.RancherConfig.Host = host }, client.RancherConfig.Host)
+		client.RancherConfig.Host = "127.0.0.1:1"
+
+		info, err := helpers.GetRancherVersionInfo(client)
+		Expect(err).To(BeNil())
+		Expect(info).To(Equal(helpers.RancherVersionInfo{Version: fakerancher.DefaultSettings["server-version"]}))
+	})
+})
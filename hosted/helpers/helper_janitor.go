@@ -0,0 +1,80 @@
+package helpers
+
+import (
+	"strings"
+	"time"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+const (
+	// OwnerLabel is the label/tag set by GetCommonMetadataLabels on every cluster created by the tests
+	OwnerLabel = "owner"
+	// OwnerLabelPrefix is the prefix of the OwnerLabel value; it is followed by the name of the user running the tests
+	OwnerLabelPrefix = "hosted-providers-qa-ci-"
+	// JanitorIgnoreLabel is set by GetCommonMetadataLabels when DOWNSTREAM_CLUSTER_CLEANUP is false, i.e. the clusters must be kept
+	JanitorIgnoreLabel = "janitor-ignore"
+)
+
+// CloudCluster is a cluster as listed by HostedProvider.ListClustersOnCloud
+type CloudCluster struct {
+	Name string
+	// Location is the region or zone of the cluster
+	Location string
+	// ResourceGroup is the resource group of an AKS cluster; it is empty for the other providers
+	ResourceGroup string
+	Labels        map[string]string
+	// CreatedAt is zero if the cloud provider does not report it
+	CreatedAt time.Time
+}
+
+// JanitorFilter selects the clusters left behind by the tests, for e.g. when a suite crashed before its AfterEach
+type JanitorFilter struct {
+	// NamePrefix selects the clusters that are not labelled with OwnerLabel, for e.g. the clusters created via Rancher; see ClusterNamePrefixFor
+	NamePrefix string
+	// OlderThan skips the clusters created more recently, since they may still be used by a running test;
+	// the clusters without creation time are skipped unless OlderThan is 0
+	OlderThan time.Duration
+	// IncludeIgnored also selects the clusters labelled with JanitorIgnoreLabel
+	IncludeIgnored bool
+}
+
+// MatchCloudCluster returns true if the cluster was created by the tests and must be deleted
+func (f JanitorFilter) MatchCloudCluster(cluster CloudCluster, now time.Time) bool {
+	return f.match(cluster.Name, cluster.Labels, cluster.CreatedAt, now)
+}
+
+// MatchRancherCluster returns true if the cluster of the given provider was created by the tests and must be deleted
+func (f JanitorFilter) MatchRancherCluster(cluster *management.Cluster, provider string, now time.Time) bool {
+	var labels map[string]string
+	switch {
+	case provider == "aks" && cluster.AKSConfig != nil:
+		labels = cluster.AKSConfig.Tags
+	case provider == "eks" && cluster.EKSConfig != nil:
+		if cluster.EKSConfig.Tags != nil {
+			labels = *cluster.EKSConfig.Tags
+		}
+	case provider == "gke" && cluster.GKEConfig != nil:
+		if cluster.GKEConfig.Labels != nil {
+			labels = *cluster.GKEConfig.Labels
+		}
+	default:
+		return false
+	}
+	createdAt, _ := time.Parse(time.RFC3339, cluster.Created)
+	return f.match(cluster.Name, labels, createdAt, now)
+}
+
+func (f JanitorFilter) match(name string, labels map[string]string, createdAt, now time.Time) bool {
+	owned := strings.HasPrefix(labels[OwnerLabel], OwnerLabelPrefix)
+	if !owned && (f.NamePrefix == "" || !strings.HasPrefix(name, f.NamePrefix)) {
+		return false
+	}
+	if !f.IncludeIgnored && labels[JanitorIgnoreLabel] == "true" {
+		return false
+	}
+	if f.OlderThan == 0 {
+		return true
+	}
+	return !createdAt.IsZero() && now.Sub(createdAt) >= f.OlderThan
+}
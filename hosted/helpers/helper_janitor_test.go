@@ -0,0 +1,58 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("JanitorFilter", func() {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	filter := helpers.JanitorFilter{NamePrefix: "aks-hp-ci", OlderThan: 6 * time.Hour}
+	owned := map[string]string{helpers.OwnerLabel: helpers.OwnerLabelPrefix + "user"}
+	ignored := map[string]string{helpers.OwnerLabel: helpers.OwnerLabelPrefix + "user", helpers.JanitorIgnoreLabel: "true"}
+
+	DescribeTable("MatchCloudCluster",
+		func(filter helpers.JanitorFilter, cluster helpers.CloudCluster, expected bool) {
+			Expect(filter.MatchCloudCluster(cluster, now)).To(Equal(expected))
+		},
+		Entry("selects an old cluster labelled by the tests", filter, helpers.CloudCluster{Name: "any", Labels: owned, CreatedAt: now.Add(-7 * time.Hour)}, true),
+		Entry("selects an old cluster named after the prefix", filter, helpers.CloudCluster{Name: "aks-hp-ci-abcd", CreatedAt: now.Add(-7 * time.Hour)}, true),
+		Entry("skips a cluster of another owner", filter, helpers.CloudCluster{Name: "any", Labels: map[string]string{helpers.OwnerLabel: "someone"}, CreatedAt: now.Add(-7 * time.Hour)}, false),
+		Entry("skips a recent cluster", filter, helpers.CloudCluster{Name: "any", Labels: owned, CreatedAt: now.Add(-time.Hour)}, false),
+		Entry("skips a cluster without creation time", filter, helpers.CloudCluster{Name: "any", Labels: owned}, false),
+		Entry("selects a cluster without creation time if there is no minimum age", helpers.JanitorFilter{}, helpers.CloudCluster{Name: "any", Labels: owned}, true),
+		Entry("skips an ignored cluster", filter, helpers.CloudCluster{Name: "any", Labels: ignored, CreatedAt: now.Add(-7 * time.Hour)}, false),
+		Entry("selects an ignored cluster if asked", helpers.JanitorFilter{IncludeIgnored: true}, helpers.CloudCluster{Name: "any", Labels: ignored}, true),
+	)
+
+	DescribeTable("MatchRancherCluster",
+		func(cluster *management.Cluster, provider string, expected bool) {
+			cluster.Created = now.Add(-7 * time.Hour).Format(time.RFC3339)
+			Expect(filter.MatchRancherCluster(cluster, provider, now)).To(Equal(expected))
+		},
+		Entry("selects a cluster of the provider named after the prefix", &management.Cluster{Name: "aks-hp-ci-abcd", AKSConfig: &management.AKSClusterConfigSpec{}}, "aks", true),
+		Entry("selects a cluster of the provider labelled by the tests", &management.Cluster{Name: "any", EKSConfig: &management.EKSClusterConfigSpec{Tags: &owned}}, "eks", true),
+		Entry("skips an ignored cluster", &management.Cluster{Name: "any", GKEConfig: &management.GKEClusterConfigSpec{Labels: &ignored}}, "gke", false),
+		Entry("skips a cluster of another provider", &management.Cluster{Name: "aks-hp-ci-abcd", EKSConfig: &management.EKSClusterConfigSpec{}}, "aks", false),
+		Entry("skips the local cluster", &management.Cluster{Name: "local"}, "aks", false),
+	)
+})
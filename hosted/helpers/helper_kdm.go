@@ -0,0 +1,287 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rancher/shepherd/clients/rancher"
+)
+
+const (
+	// KDMDistroK3S and KDMDistroRKE2 are the sections of the KDM data listing the k3s and rke2 releases
+	KDMDistroK3S  = "k3s"
+	KDMDistroRKE2 = "rke2"
+	// kdmSetting is the Rancher setting holding the URL of the KDM data
+	kdmSetting = "rke-metadata-config"
+)
+
+// KDMRelease is a k3s or rke2 release of the KDM data, available on the Rancher versions between its channel server versions
+type KDMRelease struct {
+	Version                 string
+	MinChannelServerVersion string
+	MaxChannelServerVersion string
+}
+
+// KDMData is the content of a KDM data.json; the sections other than the k3s and rke2 releases are kept as is
+type KDMData map[string]any
+
+// ParseKDMData parses a KDM data.json, for e.g. as returned by FetchKDMData
+func ParseKDMData(data []byte) (KDMData, error) {
+	var kdm KDMData
+	if err := json.Unmarshal(data, &kdm); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse the KDM data")
+	}
+	return kdm, nil
+}
+
+func (d KDMData) releases(distro string) []any {
+	section, _ := d[distro].(map[string]any)
+	releases, _ := section["releases"].([]any)
+	return releases
+}
+
+func (d KDMData) setReleases(distro string, releases []any) {
+	section, ok := d[distro].(map[string]any)
+	if !ok {
+		section = map[string]any{}
+		d[distro] = section
+	}
+	section["releases"] = releases
+}
+
+// AddRelease adds a k3s or rke2 release, for e.g. to make a new k8s minor version available
+func (d KDMData) AddRelease(distro string, release KDMRelease) {
+	d.setReleases(distro, append(d.releases(distro), map[string]any{
+		"version":                 release.Version,
+		"minChannelServerVersion": release.MinChannelServerVersion,
+		"maxChannelServerVersion": release.MaxChannelServerVersion,
+	}))
+}
+
+// RemoveReleases removes the k3s or rke2 releases of a k8s minor version, for e.g. 1.30; it returns the number of releases removed
+func (d KDMData) RemoveReleases(distro, minorVersion string) (int, error) {
+	removed, err := ParseReleaseVersion(minorVersion)
+	if err != nil {
+		return 0, err
+	}
+	releases := d.releases(distro)
+	var kept []any
+	for _, release := range releases {
+		fields, _ := release.(map[string]any)
+		version, _ := fields["version"].(string)
+		if parsed, err := ParseReleaseVersion(version); err == nil && parsed.Major() == removed.Major() && parsed.Minor() == removed.Minor() {
+			continue
+		}
+		kept = append(kept, release)
+	}
+	d.setReleases(distro, kept)
+	return len(releases) - len(kept), nil
+}
+
+// FetchKDMData downloads the KDM data.json from the URL of the `rke-metadata-config` setting of Rancher
+func FetchKDMData(ctx context.Context, client *rancher.Client) ([]byte, error) {
+	setting, err := client.Management.Setting.ByID(kdmSetting)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the %s setting", kdmSetting)
+	}
+	var metadataConfig struct {
+		URL string `json:"url"`
+	}
+	if err = json.Unmarshal([]byte(setting.Value), &metadataConfig); err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse the %s setting", kdmSetting)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataConfig.URL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to build the KDM data request")
+	}
+	response, err := http.DefaultClient.Do(request) // #nosec G107 -- the URL is set by the Rancher admin
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to download the KDM data")
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download the KDM data from %s: %s", metadataConfig.URL, response.Status)
+	}
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read the KDM data")
+	}
+	return data, nil
+}
+
+// KDMServer serves a KDM data.json from the test host, so that the k8s versions known by Rancher can be changed within a run;
+// see UseKDMServer to point Rancher at it.
+type KDMServer struct {
+	// URL is the URL of the data.json as reachable by Rancher
+	URL string
+
+	server     *http.Server
+	mu         sync.Mutex
+	data       []byte
+	generation int
+	requests   int
+}
+
+// NewKDMServer serves the data on all the interfaces of the test host; host is the address at which Rancher reaches the test host,
+// it defaults to KDM_SERVER_HOST or else to the address of the default route of the test host
+func NewKDMServer(data KDMData, host string) (*KDMServer, error) {
+	if host == "" {
+		var err error
+		if host, err = testHostAddress(); err != nil {
+			return nil, err
+		}
+	}
+
+	k := &KDMServer{}
+	if err := k.Update(data); err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to start the KDM server")
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	k.URL = fmt.Sprintf("http://%s/data.json", net.JoinHostPort(host, strconv.Itoa(port)))
+	k.server = &http.Server{Handler: http.HandlerFunc(k.serve), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		_ = k.server.Serve(listener)
+	}()
+	Logger.Info(fmt.Sprintf("Serving the KDM data at %s", k.URL))
+	return k, nil
+}
+
+func (k *KDMServer) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, r.Method, http.StatusMethodNotAllowed)
+		return
+	}
+	k.mu.Lock()
+	data := k.data
+	k.requests++
+	k.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// Update replaces the served data; Rancher only downloads it again once refreshed by UseKDMServer or after its refresh interval
+func (k *KDMServer) Update(data KDMData) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "Failed to encode the KDM data")
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.data = encoded
+	k.generation++
+	return nil
+}
+
+// Requests returns the number of times the data has been downloaded
+func (k *KDMServer) Requests() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.requests
+}
+
+// WaitForRequests waits until the data has been downloaded at least count times, for e.g. Requests()+1 to wait for the refresh of Rancher
+func (k *KDMServer) WaitForRequests(ctx context.Context, count int, timeout time.Duration) error {
+	return WaitFor(ctx, "Rancher to download the KDM data", timeout, 2*time.Second, func() (bool, error) {
+		return k.Requests() >= count, nil
+	})
+}
+
+// Close stops the server
+func (k *KDMServer) Close() error {
+	return k.server.Close()
+}
+
+// settingURL is the URL set in rke-metadata-config; the generation changes the setting on every Update so that Rancher refreshes the data
+func (k *KDMServer) settingURL() string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return fmt.Sprintf("%s?generation=%d", k.URL, k.generation)
+}
+
+// UseKDMServer points the rke-metadata-config setting of Rancher at the server, which makes Rancher download the data again;
+// the original setting is restored and the server closed by Cleanups. It must be called again after KDMServer.Update for Rancher to refresh the data.
+func UseKDMServer(client *rancher.Client, kdm *KDMServer) error {
+	setting, err := client.Management.Setting.ByID(kdmSetting)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get the %s setting", kdmSetting)
+	}
+	metadataConfig := map[string]any{}
+	if setting.Value != "" {
+		if err = json.Unmarshal([]byte(setting.Value), &metadataConfig); err != nil {
+			return errors.Wrapf(err, "Failed to parse the %s setting", kdmSetting)
+		}
+	}
+	currentURL, _ := metadataConfig["url"].(string)
+	metadataConfig["url"] = kdm.settingURL()
+	value, err := json.Marshal(metadataConfig)
+	if err != nil {
+		return err
+	}
+
+	// the original setting has already been saved if Rancher points at the server
+	if parsed, err := url.Parse(currentURL); err == nil && currentURL != "" && parsed.Scheme+"://"+parsed.Host+parsed.Path == kdm.URL {
+		_, err = client.Management.Setting.Update(setting, map[string]any{"value": string(value)})
+		return errors.Wrapf(err, "Failed to update the %s setting", kdmSetting)
+	}
+	// registered before the setting so that Rancher stops using the server before it is closed
+	RegisterCleanup(CleanupKey("kdm-server", kdm.URL), fmt.Sprintf("KDM server %s", kdm.URL), func(_ context.Context) error {
+		return kdm.Close()
+	})
+	return OverrideSetting(client, kdmSetting, string(value))
+}
+
+// OverrideSetting updates a Rancher setting, for e.g. ui-k8s-supported-versions-range, and registers the restore of its original value in Cleanups
+func OverrideSetting(client *rancher.Client, name, value string) error {
+	setting, err := client.Management.Setting.ByID(name)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get the %s setting", name)
+	}
+	original := setting.Value
+	if _, err = client.Management.Setting.Update(setting, map[string]any{"value": value}); err != nil {
+		return errors.Wrapf(err, "Failed to update the %s setting", name)
+	}
+	RegisterCleanup(CleanupKey("setting", name), fmt.Sprintf("setting %s", name), func(_ context.Context) error {
+		setting, err := client.Management.Setting.ByID(name)
+		if err != nil {
+			return err
+		}
+		_, err = client.Management.Setting.Update(setting, map[string]any{"value": original})
+		return err
+	})
+	return nil
+}
+
+// testHostAddress returns the address at which Rancher reaches the test host: KDM_SERVER_HOST or else the address of its default route
+func testHostAddress() (string, error) {
+	if Config.KDMServerHost != "" {
+		return Config.KDMServerHost, nil
+	}
+	host, err := defaultRouteAddress()
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to get the address of the test host, set KDM_SERVER_HOST")
+	}
+	return host, nil
+}
+
+// defaultRouteAddress returns the local address used to reach the other hosts; dialing UDP does not send any packet
+func defaultRouteAddress() (string, error) {
+	conn, err := net.Dial("udp", "1.1.1.1:53")
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP.String(), nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakerancher"
+)
+
+var _ = Describe("KDMServer", Ordered, func() {
+	var (
+		kdmData helpers.KDMData
+		kdm     *helpers.KDMServer
+	)
+
+	BeforeEach(func() {
+		data, err := os.ReadFile("testdata/kdm-data.json")
+		Expect(err).To(BeNil())
+		kdmData, err = helpers.ParseKDMData(data)
+		Expect(err).To(BeNil())
+	})
+
+	It("edits the releases of the KDM data", func() {
+		kdmData.AddRelease(helpers.KDMDistroK3S, helpers.KDMRelease{Version: "v1.33.1+k3s1", MinChannelServerVersion: "v2.11.0-alpha1", MaxChannelServerVersion: "v2.11.99"})
+		Expect(kdmData.RemoveReleases(helpers.KDMDistroK3S, "1.31")).To(Equal(1))
+		Expect(kdmData.RemoveReleases(helpers.KDMDistroRKE2, "1.28")).To(Equal(0))
+
+		var edited struct {
+			K3S struct {
+				Releases []helpers.KDMRelease `json:"releases"`
+			} `json:"k3s"`
+		}
+		Expect(json.Unmarshal(mustMarshal(kdmData), &edited)).To(Succeed())
+		var versions []string
+		for _, release := range edited.K3S.Releases {
+			versions = append(versions, release.Version)
+		}
+		Expect(versions).To(Equal([]string{"v1.30.9+k3s1", "v1.32.1+k3s1", "v1.33.1+k3s1"}))
+	})
+
+	It("serves the KDM data to Rancher and refreshes it on update", func(ctx SpecContext) {
+		var err error
+		kdm, err = helpers.NewKDMServer(kdmData, "127.0.0.1")
+		Expect(err).To(BeNil())
+		Expect(helpers.UseKDMServer(client, kdm)).To(Succeed())
+		Expect(helpers.FetchKDMData(ctx, client)).To(MatchJSON(mustMarshal(kdmData)))
+
+		_, err = kdmData.RemoveReleases(helpers.KDMDistroK3S, "1.32")
+		Expect(err).To(BeNil())
+		Expect(kdm.Update(kdmData)).To(Succeed())
+		Expect(helpers.UseKDMServer(client, kdm)).To(Succeed())
+		setting, err := client.Management.Setting.ByID("rke-metadata-config")
+		Expect(err).To(BeNil())
+		Expect(setting.Value).To(ContainSubstring(`"refresh-interval-minutes":"1440"`))
+		Expect(setting.Value).To(ContainSubstring(kdm.URL + "?generation=2"))
+
+		Expect(helpers.FetchKDMData(ctx, client)).To(MatchJSON(mustMarshal(kdmData)))
+		Expect(kdm.WaitForRequests(ctx, 2, time.Second)).To(Succeed())
+	})
+
+	It("restores the original setting once the spec ends", func() {
+		setting, err := client.Management.Setting.ByID("rke-metadata-config")
+		Expect(err).To(BeNil())
+		Expect(setting.Value).To(Equal(fakerancher.DefaultSettings["rke-metadata-config"]))
+		_, err = http.Get(kdm.URL) // #nosec G107 -- local test server
+		Expect(err).To(HaveOccurred(), "the KDM server must be closed once the spec ends")
+	})
+
+	It("overrides the UI supported versions range", func() {
+		Expect(helpers.OverrideSetting(client, "ui-k8s-supported-versions-range", ">=v1.30.x <=v1.31.x")).To(Succeed())
+		Expect(helpers.FilterUIUnsupportedVersions([]string{"1.32.1", "1.31.5", "1.30.9"}, client)).To(Equal([]string{"1.31.5", "1.30.9"}))
+	})
+
+	It("restores the overridden setting once the spec ends", func() {
+		Expect(helpers.HighestK8sMinorVersionSupportedByUI(client)).To(Equal("1.32"))
+	})
+})
+
+func mustMarshal(v any) []byte {
+	data, err := json.Marshal(v)
+	Expect(err).To(BeNil())
+	return data
+}
@@ -0,0 +1,215 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/pkg/config"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ClusterAgentDeployment is the deployment of the agent of Rancher in the downstream clusters, in CattleSystemNS
+	ClusterAgentDeployment = "cattle-cluster-agent"
+	// serverURLSetting is the setting holding the URL at which the agents reach Rancher
+	serverURLSetting = "server-url"
+)
+
+// MigrationHostname returns the hostname of the Rancher migrated by the backup-restore suites: RANCHER_MIGRATION_HOSTNAME if set, or else
+// the migrated subdomain of RANCHER_HOSTNAME, which resolves to the same address with a wildcard DNS such as sslip.io
+func MigrationHostname() string {
+	if Config.Rancher.MigrationHostname != "" {
+		return Config.Rancher.MigrationHostname
+	}
+	return "migrated." + RancherHostname
+}
+
+// StopRancher scales the rancher deployment of the current upstream down to 0, for e.g. before its backup is restored on another upstream,
+// so that the downstream clusters are not managed by both; the deployment is scaled back up, and awaited, when Cleanups runs so that
+// the teardowns registered with the original Rancher still reach it
+func StopRancher(ctx context.Context) error {
+	kubeconfig := Kubeconfig
+	if kubeconfig == "" {
+		kubeconfig = os.Getenv("KUBECONFIG")
+	}
+	rancherDeployment := func(ctx context.Context, args ...string) (string, error) {
+		return RunCLI(ctx, "kubectl", append([]string{"--kubeconfig", kubeconfig, "--namespace", CattleSystemNS}, args...)...)
+	}
+
+	replicas, err := rancherDeployment(ctx, "get", "deployment", "rancher", "--output", "jsonpath={.spec.replicas}")
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get the rancher deployment: %s", replicas)
+	}
+	if out, err := rancherDeployment(ctx, "scale", "deployment", "rancher", "--replicas=0"); err != nil {
+		return errors.Wrapf(err, "Failed to stop the rancher deployment: %s", out)
+	}
+	RegisterCleanup(CleanupKey("rancher-deployment", kubeconfig), "rancher deployment of "+kubeconfig, func(ctx context.Context) error {
+		if out, err := rancherDeployment(ctx, "scale", "deployment", "rancher", "--replicas="+replicas); err != nil {
+			return errors.Wrapf(err, "Failed to restart the rancher deployment: %s", out)
+		}
+		out, err := rancherDeployment(ctx, "rollout", "status", "deployment", "rancher", "--timeout=10m")
+		return errors.Wrapf(err, "Failed to wait for the rancher deployment: %s", out)
+	})
+	return nil
+}
+
+// SwitchUpstream points the kubectl and helm commands to the upstream cluster of kubeconfig, for e.g. RANCHER_MIGRATION_KUBECONFIG;
+// both Kubeconfig and KUBECONFIG are switched back when Cleanups runs
+func SwitchUpstream(kubeconfig string) error {
+	original, originalEnv := Kubeconfig, os.Getenv("KUBECONFIG")
+	if err := os.Setenv("KUBECONFIG", kubeconfig); err != nil {
+		return err
+	}
+	Kubeconfig = kubeconfig
+	RegisterCleanup(CleanupKey("upstream", kubeconfig), "upstream "+kubeconfig, func(context.Context) error {
+		Kubeconfig = original
+		return os.Setenv("KUBECONFIG", originalEnv)
+	})
+	return nil
+}
+
+// SwitchRancherHost points the client to the Rancher restored on hostname, authenticated with the admin token restored by the backup;
+// the client is updated in place so that the teardowns registered with it, for e.g. the deletion of the clusters, reach the new Rancher.
+// Since the rancher client reads its configuration from CATTLE_TEST_CONFIG, the file is copied with the new host and CATTLE_TEST_CONFIG
+// points to the copy; both the client and CATTLE_TEST_CONFIG are switched back when Cleanups runs.
+func SwitchRancherHost(client *rancher.Client, hostname string) error {
+	original := os.Getenv(config.ConfigEnvironmentKey)
+	data, err := os.ReadFile(original)
+	if err != nil {
+		return errors.Wrap(err, "Failed to read CATTLE_TEST_CONFIG")
+	}
+	content := map[string]any{}
+	if err = yaml.Unmarshal(data, &content); err != nil {
+		return errors.Wrap(err, "Failed to parse CATTLE_TEST_CONFIG")
+	}
+	rancherConfig, ok := content[rancher.ConfigurationFileKey].(map[string]any)
+	if !ok {
+		return fmt.Errorf("CATTLE_TEST_CONFIG has no %s configuration", rancher.ConfigurationFileKey)
+	}
+	rancherConfig["host"] = hostname
+	if data, err = yaml.Marshal(content); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp("", "cattle-config-migrated-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err = file.Write(data); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	if err = os.Setenv(config.ConfigEnvironmentKey, file.Name()); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+
+	migrated, err := rancher.NewClient("", client.Session)
+	if err != nil {
+		_ = os.Setenv(config.ConfigEnvironmentKey, original)
+		_ = os.Remove(file.Name())
+		return errors.Wrapf(err, "Failed to create the client of the Rancher migrated to %s", hostname)
+	}
+	previous := *client
+	*client = *migrated
+	RegisterCleanup(CleanupKey("cattle-config", file.Name()), "migrated cattle config "+file.Name(), func(context.Context) error {
+		*client = previous
+		if err := os.Setenv(config.ConfigEnvironmentKey, original); err != nil {
+			return err
+		}
+		return os.Remove(file.Name())
+	})
+	return nil
+}
+
+// SetServerURL updates the server-url setting, for e.g. once Rancher has been migrated to a new hostname, so that the agents are redeployed with it
+func SetServerURL(client *rancher.Client, serverURL string) error {
+	setting, err := client.Management.Setting.ByID(serverURLSetting)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get the %s setting", serverURLSetting)
+	}
+	if setting.Value == serverURL {
+		return nil
+	}
+	_, err = client.Management.Setting.Update(setting, map[string]any{"value": serverURL})
+	return errors.Wrapf(err, "Failed to update the %s setting", serverURLSetting)
+}
+
+// WaitForClusterAgentServerURL waits until the cluster is connected to Rancher and its cluster agent has been redeployed with the serverURL;
+// the agent is read from the downstream cluster through the Rancher proxy, which only works once the agent is connected.
+func WaitForClusterAgentServerURL(ctx context.Context, client *rancher.Client, cluster *management.Cluster, serverURL string, timeout time.Duration) error {
+	description := fmt.Sprintf("the agent of cluster %s to connect to %s", cluster.Name, serverURL)
+	return WaitFor(ctx, description, timeout, 15*time.Second, func() (bool, error) {
+		latest, err := client.Management.Cluster.ByID(cluster.ID)
+		if err != nil {
+			return false, err
+		}
+		if !clusterConditionIsTrue(latest, "Connected") {
+			return false, fmt.Errorf("cluster %s is not connected: %s", cluster.Name, latest.TransitioningMessage)
+		}
+
+		downstream, err := client.Steve.ProxyDownstream(cluster.ID)
+		if err != nil {
+			return false, err
+		}
+		deployment, err := downstream.SteveType("apps.deployment").ByID(CattleSystemNS + "/" + ClusterAgentDeployment)
+		if err != nil {
+			return false, err
+		}
+		agentURL := agentServerURL(deployment.JSONResp)
+		if agentURL != serverURL {
+			return false, fmt.Errorf("the agent of cluster %s connects to %q", cluster.Name, agentURL)
+		}
+		return true, nil
+	})
+}
+
+func clusterConditionIsTrue(cluster *management.Cluster, conditionType string) bool {
+	for _, condition := range cluster.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == "True"
+		}
+	}
+	return false
+}
+
+// agentServerURL returns the CATTLE_SERVER environment variable of the containers of the cluster agent deployment
+func agentServerURL(deployment map[string]any) string {
+	spec, _ := deployment["spec"].(map[string]any)
+	template, _ := spec["template"].(map[string]any)
+	podSpec, _ := template["spec"].(map[string]any)
+	containers, _ := podSpec["containers"].([]any)
+	for _, container := range containers {
+		container, _ := container.(map[string]any)
+		env, _ := container["env"].([]any)
+		for _, variable := range env {
+			variable, _ := variable.(map[string]any)
+			if variable["name"] == "CATTLE_SERVER" {
+				value, _ := variable["value"].(string)
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// GetClusterConfigSpec returns the spec of the *ClusterConfig object of the operator of the cluster in CattleGlobalDataNS,
+// for e.g. to check that it is intact after a restore
+func GetClusterConfigSpec(ctx context.Context, cluster *management.Cluster) (map[string]any, error) {
+	out, err := kubectlCLI(ctx, "get", fmt.Sprintf("%sclusterconfigs.%s.cattle.io", Provider, Provider), "--namespace", CattleGlobalDataNS,
+		cluster.ID, "--output", "jsonpath={.spec}")
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the %sclusterconfig of cluster %s: %s", Provider, cluster.Name, out)
+	}
+	spec := map[string]any{}
+	if err = yaml.Unmarshal([]byte(out), &spec); err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse the %sclusterconfig of cluster %s", Provider, cluster.Name)
+	}
+	return spec, nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/pkg/config"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakecli"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakerancher"
+)
+
+var _ = Describe("Migration", Ordered, func() {
+	var (
+		migrated       *fakerancher.Server
+		migratedClient *rancher.Client
+	)
+
+	BeforeAll(func() {
+		migrated = fakerancher.NewServer()
+		DeferCleanup(migrated.Close)
+	})
+
+	It("switches the client to the migrated Rancher and updates its server-url", func() {
+		var err error
+		migratedClient, err = server.NewClient()
+		Expect(err).To(BeNil())
+		migrated.SetSetting("server-url", "https://"+server.Host())
+
+		Expect(helpers.SwitchRancherHost(migratedClient, migrated.Host())).To(Succeed())
+		Expect(migratedClient.RancherConfig.Host).To(Equal(migrated.Host()))
+		Expect(os.Getenv(config.ConfigEnvironmentKey)).NotTo(Equal(configPath))
+
+		Expect(helpers.SetServerURL(migratedClient, "https://"+migrated.Host())).To(Succeed())
+		setting, err := migratedClient.Management.Setting.ByID("server-url")
+		Expect(err).To(BeNil())
+		Expect(setting.Value).To(Equal("https://" + migrated.Host()))
+		Expect(migrated.Requests()).To(ContainElement("PUT /v3/settings/server-url"))
+	})
+
+	It("restores CATTLE_TEST_CONFIG and the client once the spec ends", func() {
+		Expect(os.Getenv(config.ConfigEnvironmentKey)).To(Equal(configPath))
+		Expect(migratedClient.RancherConfig.Host).To(Equal(server.Host()))
+	})
+
+	var (
+		runner   *fakecli.Runner
+		upstream string
+	)
+
+	It("stops the original Rancher and switches to the target upstream", func(ctx SpecContext) {
+		upstream = helpers.Kubeconfig
+		DeferCleanup(os.Setenv, "KUBECONFIG", os.Getenv("KUBECONFIG"))
+		Expect(os.Setenv("KUBECONFIG", "/tmp/source.yaml")).To(Succeed())
+		helpers.Kubeconfig = ""
+
+		runner = fakecli.NewRunner()
+		rancherDeployment := []string{"--kubeconfig", "/tmp/source.yaml", "--namespace", helpers.CattleSystemNS}
+		runner.Expect("kubectl", append(rancherDeployment, "get", "deployment", "rancher", "--output", "jsonpath={.spec.replicas}")...).Returns("3")
+		runner.Expect("kubectl", append(rancherDeployment, "scale", "deployment", "rancher", "--replicas=0")...)
+		runner.Expect("kubectl", append(rancherDeployment, "scale", "deployment", "rancher", "--replicas=3")...)
+		runner.Expect("kubectl", append(rancherDeployment, "rollout", "status", "deployment", "rancher", "--timeout=10m")...)
+		DeferCleanup(runner.Install())
+
+		Expect(helpers.StopRancher(ctx)).To(Succeed())
+		Expect(helpers.SwitchUpstream("/tmp/target.yaml")).To(Succeed())
+		Expect(helpers.Kubeconfig).To(Equal("/tmp/target.yaml"))
+		Expect(os.Getenv("KUBECONFIG")).To(Equal("/tmp/target.yaml"))
+	})
+
+	It("restarts the original Rancher and switches back to its upstream once the spec ends", func() {
+		Expect(runner.Verify()).To(Succeed())
+		Expect(helpers.Kubeconfig).To(BeEmpty())
+		helpers.Kubeconfig = upstream
+	})
+
+	It("defaults the migration hostname to a subdomain of the Rancher hostname", func() {
+		DeferCleanup(func(original string) {
+			helpers.Config.Rancher.MigrationHostname = original
+		}, helpers.Config.Rancher.MigrationHostname)
+		helpers.Config.Rancher.MigrationHostname = ""
+		Expect(helpers.MigrationHostname()).To(Equal("migrated." + helpers.RancherHostname))
+		helpers.Config.Rancher.MigrationHostname = "rancher-new.example.com"
+		Expect(helpers.MigrationHostname()).To(Equal("rancher-new.example.com"))
+	})
+
+	It("gets the spec of the cluster config", func(ctx SpecContext) {
+		DeferCleanup(func(original string) {
+			helpers.Provider = original
+		}, helpers.Provider)
+		helpers.Provider = "gke"
+		var command string
+		DeferCleanup(helpers.SetCommandRunner(helpers.CommandRunnerFunc(func(_ context.Context, name string, args ...string) (string, error) {
+			command = name + " " + strings.Join(args, " ")
+			return `{"clusterName":"hp-gke","nodePools":[{"name":"np-1","initialNodeCount":1}]}`, nil
+		})))
+
+		cluster := &management.Cluster{Name: "hp-gke"}
+		cluster.ID = "c-abcde"
+		spec, err := helpers.GetClusterConfigSpec(ctx, cluster)
+		Expect(err).To(BeNil())
+		Expect(spec).To(HaveKeyWithValue("clusterName", "hp-gke"))
+		Expect(spec["nodePools"]).To(HaveLen(1))
+		Expect(command).To(ContainSubstring("get gkeclusterconfigs.gke.cattle.io --namespace cattle-global-data c-abcde"))
+	})
+})
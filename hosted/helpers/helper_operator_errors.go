@@ -0,0 +1,164 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+)
+
+// UnknownOperatorError is the class of the errors that match no OperatorError of the catalogue of the provider
+const UnknownOperatorError = "unknown"
+
+// ErrorMessage is a message of an OperatorError, as a regular expression, on the operator versions satisfying the Operator constraint;
+// an empty Operator matches all the versions
+type ErrorMessage struct {
+	Operator string
+	Pattern  string
+	operator *semver.Constraints
+	pattern  *regexp.Regexp
+}
+
+// OperatorError is a known error of a hosted operator or of the Rancher webhook, as set in cluster.TransitioningMessage or returned by the API;
+// its message can change across the operator releases, see RegisterOperatorError
+type OperatorError struct {
+	Provider string
+	Name     string
+	Messages []ErrorMessage
+}
+
+var (
+	operatorErrorsMu sync.RWMutex
+	operatorErrors   = map[string][]OperatorError{}
+)
+
+// operatorVersion is the app version of the installed operator against which the errors are matched, resolved once by CurrentOperatorVersion
+var (
+	operatorVersionMu sync.Mutex
+	operatorVersion   string
+)
+
+// CurrentOperatorVersion returns the app version of the installed operator, as returned by GetCurrentOperatorAppVersion;
+// it is fetched once and cached until the operator charts change, see SetOperatorVersion
+func CurrentOperatorVersion(ctx context.Context) (string, error) {
+	operatorVersionMu.Lock()
+	defer operatorVersionMu.Unlock()
+	if operatorVersion == "" {
+		version, err := GetCurrentOperatorAppVersion(ctx)
+		if err != nil {
+			return "", errors.Wrap(err, "Failed to get the operator version")
+		}
+		operatorVersion = version
+	}
+	return operatorVersion, nil
+}
+
+// SetOperatorVersion sets the version returned by CurrentOperatorVersion; an empty version makes it fetch the version again,
+// for e.g. once the operator charts have been upgraded
+func SetOperatorVersion(version string) {
+	operatorVersionMu.Lock()
+	defer operatorVersionMu.Unlock()
+	operatorVersion = version
+}
+
+// RegisterOperatorError adds an error to the catalogue of the provider, for e.g.
+//
+//	RegisterOperatorError("eks", "nodegroup version incompatible",
+//		ErrorMessage{Operator: "<=1.10", Pattern: `versions for cluster \[\S+\] and node group \[\S+\] are not compatible`},
+//		ErrorMessage{Pattern: `versions for cluster \[\S+\] and nodegroup \[\S+\] not compatible`})
+//
+// It panics if the name is already registered for the provider or if a pattern or constraint is invalid since the errors are declared as package variables.
+func RegisterOperatorError(provider, name string, messages ...ErrorMessage) OperatorError {
+	if len(messages) == 0 {
+		panic(fmt.Sprintf("%s operator error %q has no message", provider, name))
+	}
+	for i := range messages {
+		pattern, err := regexp.Compile(messages[i].Pattern)
+		if err != nil {
+			panic(fmt.Sprintf("invalid pattern of %s operator error %q: %v", provider, name, err))
+		}
+		messages[i].pattern = pattern
+		if messages[i].Operator == "" {
+			continue
+		}
+		if messages[i].operator, err = semver.NewConstraint(messages[i].Operator); err != nil {
+			panic(fmt.Sprintf("invalid operator version constraint %q of %s operator error %q: %v", messages[i].Operator, provider, name, err))
+		}
+	}
+
+	operatorErrorsMu.Lock()
+	defer operatorErrorsMu.Unlock()
+	for _, registered := range operatorErrors[provider] {
+		if registered.Name == name {
+			panic(fmt.Sprintf("%s operator error %q is already registered", provider, name))
+		}
+	}
+	operatorError := OperatorError{Provider: provider, Name: name, Messages: messages}
+	operatorErrors[provider] = append(operatorErrors[provider], operatorError)
+	return operatorError
+}
+
+// OperatorErrors returns the catalogue of the provider, sorted by name
+func OperatorErrors(provider string) []OperatorError {
+	operatorErrorsMu.RLock()
+	defer operatorErrorsMu.RUnlock()
+	catalogue := append([]OperatorError(nil), operatorErrors[provider]...)
+	sort.Slice(catalogue, func(i, j int) bool { return catalogue[i].Name < catalogue[j].Name })
+	return catalogue
+}
+
+func (e OperatorError) String() string {
+	return fmt.Sprintf("%s operator error %q", e.Provider, e.Name)
+}
+
+// ForOperator returns the error with the messages of the operator version only, for e.g. as returned by GetCurrentOperatorAppVersion;
+// the messages of all the versions are kept if the version cannot be parsed
+func (e OperatorError) ForOperator(version string) OperatorError {
+	parsed, err := ParseReleaseVersion(version)
+	if err != nil {
+		return e
+	}
+	restricted := e
+	restricted.Messages = nil
+	for _, message := range e.Messages {
+		if message.operator == nil || message.operator.Check(parsed) {
+			restricted.Messages = append(restricted.Messages, message)
+		}
+	}
+	return restricted
+}
+
+// Matches returns true if the message matches one of the messages of the error
+func (e OperatorError) Matches(message string) bool {
+	for _, m := range e.Messages {
+		if m.pattern.MatchString(message) {
+			return true
+		}
+	}
+	return false
+}
+
+// Regexp returns a regular expression matching any of the messages of the error, for e.g. for ClusterTimeline.WaitUntilEntered
+func (e OperatorError) Regexp() string {
+	patterns := make([]string, len(e.Messages))
+	for i, m := range e.Messages {
+		patterns[i] = "(?:" + m.Pattern + ")"
+	}
+	return strings.Join(patterns, "|")
+}
+
+// ClassifyOperatorError returns the name of the first error of the catalogue of the provider matching the message on the operator version,
+// or UnknownOperatorError; the messages of all the versions are matched if the version cannot be parsed
+func ClassifyOperatorError(provider, operatorVersion, message string) string {
+	for _, operatorError := range OperatorErrors(provider) {
+		if operatorError.ForOperator(operatorVersion).Matches(message) {
+			return operatorError.Name
+		}
+	}
+	return UnknownOperatorError
+}
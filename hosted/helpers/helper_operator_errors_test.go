@@ -0,0 +1,117 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakerancher"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
+)
+
+var (
+	errFakeSystemPool = helpers.RegisterOperatorError("fake", "system pool removal",
+		helpers.ErrorMessage{Pattern: `cannot remove node pool \[\S+\] with mode System`})
+	errFakeVersion = helpers.RegisterOperatorError("fake", "version incompatible",
+		helpers.ErrorMessage{Operator: "<=1.10", Pattern: `versions for cluster \[\S+\] and node group \[\S+\] are not compatible`},
+		helpers.ErrorMessage{Pattern: `versions for cluster \[\S+\] and nodegroup \[\S+\] not compatible`})
+	errFakeRemoved = helpers.RegisterOperatorError("fake", "removed error",
+		helpers.ErrorMessage{Operator: "<=1.10", Pattern: `nodegroup \[\S+\] is being deleted`})
+)
+
+// fakeOperatorVersion is the operator version of the suite, see helpers.SetOperatorVersion
+const fakeOperatorVersion = "v1.11.0"
+
+var _ = Describe("OperatorError", func() {
+	oldMessage := "versions for cluster [1.29] and node group [1.30] are not compatible: the node group version may only be up to three minor versions older"
+	newMessage := "versions for cluster [1.29] and nodegroup [1.30] not compatible: all nodegroup kubernetes versions must be equal to or one minor version lower"
+
+	It("selects the messages of the operator version", func() {
+		Expect(errFakeVersion.ForOperator("v1.10.2").Matches(oldMessage)).To(BeTrue())
+		Expect(errFakeVersion.ForOperator("v1.11.0-rc1").Matches(oldMessage)).To(BeFalse())
+		Expect(errFakeVersion.ForOperator("v1.11.0-rc1").Matches(newMessage)).To(BeTrue())
+		Expect(errFakeVersion.Matches(oldMessage)).To(BeTrue())
+		Expect(errFakeVersion.Regexp()).To(MatchRegexp(`^\(\?:.*\)\|\(\?:.*\)$`))
+	})
+
+	It("classifies the messages against the catalogue of the provider", func() {
+		Expect(helpers.ClassifyOperatorError("fake", "v1.11.0", newMessage)).To(Equal("version incompatible"))
+		Expect(helpers.ClassifyOperatorError("fake", "v1.11.0", oldMessage)).To(Equal(helpers.UnknownOperatorError))
+		Expect(helpers.ClassifyOperatorError("fake", "v1.10.1", oldMessage)).To(Equal("version incompatible"))
+		Expect(helpers.ClassifyOperatorError("fake", "", oldMessage)).To(Equal("version incompatible"))
+		Expect(helpers.ClassifyOperatorError("fake", "v1.11.0", "quota exceeded")).To(Equal(helpers.UnknownOperatorError))
+		Expect(helpers.OperatorErrors("fake")).To(HaveLen(3))
+		registerDuplicate := func() {
+			helpers.RegisterOperatorError("fake", "version incompatible", helpers.ErrorMessage{Pattern: "x"})
+		}
+		Expect(registerDuplicate).To(PanicWith(`fake operator error "version incompatible" is already registered`))
+		registerInvalid := func() { helpers.RegisterOperatorError("fake", "invalid", helpers.ErrorMessage{Pattern: "("}) }
+		Expect(registerInvalid).To(Panic())
+	})
+
+	It("matches the transitioning error of a cluster or the error of an update", func() {
+		cluster := &management.Cluster{Transitioning: "error", TransitioningMessage: "cannot remove node pool [np1] with mode System from cluster [c-1]"}
+		Expect(cluster).To(suite.HaveTransitionError(errFakeSystemPool))
+		Expect(errors.New(newMessage)).To(suite.HaveTransitionError(errFakeVersion))
+		Expect(&management.Cluster{Transitioning: "yes", TransitioningMessage: cluster.TransitioningMessage}).NotTo(suite.HaveTransitionError(errFakeSystemPool))
+
+		// the message of the older operator releases is rejected on the installed operator
+		matcher := suite.HaveTransitionError(errFakeVersion)
+		Expect(matcher.Match(&management.Cluster{Transitioning: "error", TransitioningMessage: oldMessage})).To(BeFalse())
+		Expect(matcher.FailureMessage(nil)).To(And(
+			ContainSubstring(`Expected the fake operator error "version incompatible" matching`),
+			ContainSubstring("on operator v1.11.0"),
+			ContainSubstring(oldMessage+`", classified as unknown`)))
+		Expect(matcher.Match(&management.Cluster{Transitioning: "error", TransitioningMessage: "quota exceeded"})).To(BeFalse())
+		Expect(matcher.FailureMessage(nil)).To(ContainSubstring(`"quota exceeded", classified as unknown`))
+		_, err := matcher.Match(42)
+		Expect(err).To(MatchError(ContainSubstring("HaveTransitionError expects")))
+	})
+
+	It("fails if the error has no message for the installed operator", func(ctx SpecContext) {
+		deleting := errors.New("nodegroup [ng-1] is being deleted")
+		_, err := suite.HaveTransitionError(errFakeRemoved).Match(deleting)
+		Expect(err).To(MatchError(`fake operator error "removed error" has no message for operator v1.11.0; add one to the catalogue`))
+
+		DeferCleanup(helpers.SetOperatorVersion, fakeOperatorVersion)
+		helpers.SetOperatorVersion("v1.10.3")
+		Expect(deleting).To(suite.HaveTransitionError(errFakeRemoved))
+		Expect(errors.New(oldMessage)).To(suite.HaveTransitionError(errFakeVersion))
+		Expect(suite.TransitionErrorRegexp(ctx, errFakeVersion)).To(MatchRegexp(`^\(\?:.*\)\|\(\?:.*\)$`))
+	})
+
+	It("labels the errors recorded by a timeline", func(ctx SpecContext) {
+		DeferCleanup(func(provider string) { helpers.Provider = provider }, helpers.Provider)
+		helpers.Provider = "fake"
+		id := server.AddCluster(&management.Cluster{Name: "fake-classify"}, fakerancher.StateActive)
+		cluster, err := client.Management.Cluster.ByID(id)
+		Expect(err).To(BeNil())
+
+		timeline := helpers.StartClusterTimeline(ctx, client, cluster)
+		Expect(server.ScriptClusterStates(id, fakerancher.StateError("quota exceeded"), fakerancher.StateError(newMessage))).To(Succeed())
+		Eventually(timeline).Should(suite.HaveTransitionError(errFakeVersion))
+
+		var classes []string
+		for _, event := range timeline.Events() {
+			classes = append(classes, event.ErrorClass)
+		}
+		Expect(classes).To(Equal([]string{"", helpers.UnknownOperatorError, "version incompatible"}))
+	})
+})
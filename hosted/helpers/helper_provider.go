@@ -0,0 +1,153 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// HostedProvider is the common set of operations supported by every hosted provider;
+// it allows a spec to be written once and run against any PROVIDER.
+// Implementations live in the provider helper packages and register themselves via RegisterHostedProvider.
+// The operations taking a context stop waiting, and stop the cloud CLI or API calls, once it is cancelled.
+type HostedProvider interface {
+	// Name returns the provider name as used by the PROVIDER env var, i.e. aks, eks or gke
+	Name() string
+
+	// GetK8sVersion returns the k8s version to be used by the test;
+	// it returns the second-highest minor version if forUpgrade is true
+	GetK8sVersion(ctx context.Context, client *rancher.Client, cloudCredentialID string, forUpgrade bool) (string, error)
+	// ListAvailableVersions lists the UI supported versions the cluster can be upgraded to
+	ListAvailableVersions(ctx context.Context, client *rancher.Client, cluster *management.Cluster) ([]string, error)
+
+	// CreateHostedCluster provisions a cluster via Rancher using the template defined in CATTLE_TEST_CONFIG file
+	CreateHostedCluster(ctx context.Context, client *rancher.Client, clusterName, cloudCredentialID, k8sVersion string) (*management.Cluster, error)
+	// ImportHostedCluster imports a cluster previously created via CreateClusterOnCloud
+	ImportHostedCluster(ctx context.Context, client *rancher.Client, clusterName, cloudCredentialID string) (*management.Cluster, error)
+	// DeleteHostedCluster deletes the cluster from Rancher
+	DeleteHostedCluster(ctx context.Context, cluster *management.Cluster, client *rancher.Client) error
+
+	// NodePoolCount returns the number of nodepools/nodegroups in the cluster config
+	NodePoolCount(cluster *management.Cluster) int
+	// NodeCount returns the node count of the first nodepool/nodegroup in the cluster config
+	NodeCount(cluster *management.Cluster) int64
+
+	// ScaleNodePool modifies the node count of all the nodepools/nodegroups
+	ScaleNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, nodeCount int64, wait, checkClusterConfig bool) (*management.Cluster, error)
+	// AddNodePool adds increaseBy nodepools/nodegroups using the template defined in CATTLE_TEST_CONFIG file
+	AddNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, increaseBy int, wait, checkClusterConfig bool) (*management.Cluster, error)
+	// DeleteNodePool deletes a nodepool/nodegroup
+	DeleteNodePool(ctx context.Context, cluster *management.Cluster, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error)
+
+	// UpgradeControlPlane upgrades the k8s version of the control plane only
+	UpgradeControlPlane(ctx context.Context, cluster *management.Cluster, client *rancher.Client, upgradeToVersion string, checkClusterConfig bool) (*management.Cluster, error)
+	// UpgradeNodePools upgrades the k8s version of all the nodepools/nodegroups
+	UpgradeNodePools(ctx context.Context, cluster *management.Cluster, client *rancher.Client, upgradeToVersion string, wait, checkClusterConfig bool) (*management.Cluster, error)
+	// UpdateClusterTags replaces the tags of the cluster (labels on GKE); it does not wait for the tags to be applied
+	UpdateClusterTags(ctx context.Context, cluster *management.Cluster, client *rancher.Client, tags map[string]string) (*management.Cluster, error)
+
+	// ConfigSpec returns the provider agnostic view of the cluster config
+	ConfigSpec(cluster *management.Cluster) ClusterSpec
+	// UpstreamSpec returns the provider agnostic view of the upstream spec, i.e. the cluster as synced by the operator from the cloud provider
+	UpstreamSpec(cluster *management.Cluster) ClusterSpec
+
+	// CreateClusterOnCloud creates a cluster directly on the cloud provider using its CLI
+	CreateClusterOnCloud(ctx context.Context, clusterName, k8sVersion string, nodeCount int64) error
+	// DeleteClusterOnCloud deletes the cluster and its related resources from the cloud provider using its CLI
+	DeleteClusterOnCloud(ctx context.Context, clusterName string) error
+	// ScaleNodePoolOnCloud modifies the node count of the first nodepool/nodegroup of a Rancher cluster directly on the cloud provider
+	ScaleNodePoolOnCloud(ctx context.Context, cluster *management.Cluster, nodeCount int64) error
+	// AddNodePoolOnCloud adds a nodepool/nodegroup with the default size of the cloud CLI to a Rancher cluster directly on the cloud provider
+	AddNodePoolOnCloud(ctx context.Context, cluster *management.Cluster, poolName string) error
+	// UpgradeControlPlaneOnCloud upgrades the k8s version of the control plane of a Rancher cluster directly on the cloud provider
+	UpgradeControlPlaneOnCloud(ctx context.Context, cluster *management.Cluster, upgradeToVersion string) error
+	// ClusterSpecOnCloud returns the provider agnostic view of a Rancher cluster as reported by the cloud provider API
+	ClusterSpecOnCloud(ctx context.Context, cluster *management.Cluster) (ClusterSpec, error)
+
+	// ListClustersOnCloud lists the clusters of the cloud account along with their labels/tags, for e.g. to find the clusters left behind by the tests
+	ListClustersOnCloud(ctx context.Context) ([]CloudCluster, error)
+	// DeleteListedClusterOnCloud deletes a cluster returned by ListClustersOnCloud and its related resources, i.e. its nodegroups on EKS,
+	// or its resource group on AKS if it is named after the cluster, as created by CreateClusterOnCloud
+	DeleteListedClusterOnCloud(ctx context.Context, cluster CloudCluster) error
+}
+
+// ClusterSpec is the part of a cluster config, upstream spec or cloud cluster that is common to every hosted provider;
+// the nil fields are not reported by the source
+type ClusterSpec struct {
+	KubernetesVersion string
+	NodePools         []NodePoolSpec
+	// Tags are the cluster tags on AKS and EKS, and the cluster labels on GKE
+	Tags map[string]string
+	// Logging lists the enabled log types on EKS, the logging service on GKE, and "monitoring" if enabled on AKS
+	Logging []string
+	// PrivateAccess is the private access to the API server endpoint, i.e. a private cluster on AKS and a private endpoint on GKE
+	PrivateAccess *bool
+	// PublicAccess is the public access to the API server endpoint; it is only reported on EKS
+	PublicAccess *bool
+}
+
+// NodePoolSpec is a nodepool/nodegroup of a ClusterSpec
+type NodePoolSpec struct {
+	Name              string
+	NodeCount         int64
+	KubernetesVersion string
+	Labels            map[string]string
+	// Autoscaling is not reported on EKS, where the nodegroups always have a min and max size
+	Autoscaling *bool
+	MinCount    *int64
+	MaxCount    *int64
+}
+
+// NodePool returns the nodepool/nodegroup with the given name
+func (s ClusterSpec) NodePool(name string) (NodePoolSpec, bool) {
+	for _, nodePool := range s.NodePools {
+		if nodePool.Name == name {
+			return nodePool, true
+		}
+	}
+	return NodePoolSpec{}, false
+}
+
+var (
+	hostedProvidersMu sync.RWMutex
+	hostedProviders   = map[string]HostedProvider{}
+)
+
+// RegisterHostedProvider makes a HostedProvider available by its name; it is usually called from the init function of a provider helper package
+func RegisterHostedProvider(provider HostedProvider) {
+	hostedProvidersMu.Lock()
+	defer hostedProvidersMu.Unlock()
+	if _, exists := hostedProviders[provider.Name()]; exists {
+		panic(fmt.Sprintf("hosted provider %s is already registered", provider.Name()))
+	}
+	hostedProviders[provider.Name()] = provider
+}
+
+// GetHostedProvider returns the registered HostedProvider for a given name;
+// the provider helper package must be imported for the provider to be registered
+func GetHostedProvider(name string) (HostedProvider, error) {
+	hostedProvidersMu.RLock()
+	defer hostedProvidersMu.RUnlock()
+	provider, ok := hostedProviders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown hosted provider %q; registered providers: %v", name, registeredHostedProviders())
+	}
+	return provider, nil
+}
+
+// CurrentHostedProvider returns the registered HostedProvider for the value of PROVIDER env var
+func CurrentHostedProvider() (HostedProvider, error) {
+	return GetHostedProvider(Provider)
+}
+
+func registeredHostedProviders() (names []string) {
+	for name := range hostedProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
@@ -0,0 +1,25 @@
+package helpers
+
+import (
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+const (
+	// ClusterNameReportEntry is the name of the report entries holding the name of the cluster of the spec
+	ClusterNameReportEntry = "ClusterName"
+	// K8sVersionReportEntry is the name of the report entries holding the k8s version of the cluster of the spec
+	K8sVersionReportEntry = "K8sVersion"
+)
+
+// ReportCluster attaches the cluster name and k8s version to the report of the current spec;
+// it is called by suite.ClusterIsReadyChecks and can be called again after an upgrade so that the report contains the latest k8s version
+func ReportCluster(cluster *management.Cluster) {
+	if cluster == nil {
+		return
+	}
+	hooks := GetSpecHooks()
+	hooks.AddReportEntry(ClusterNameReportEntry, cluster.Name, ReportEntryVisibilityNever)
+	if cluster.Version != nil && cluster.Version.GitVersion != "" {
+		hooks.AddReportEntry(K8sVersionReportEntry, cluster.Version.GitVersion, ReportEntryVisibilityNever)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakerancher"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
+)
+
+var _ = Describe("Run report", func() {
+	It("BuildSpecResult computes the duration of every step", func() {
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		at := func(seconds int) types.TimelineLocation {
+			return types.TimelineLocation{Time: start.Add(time.Duration(seconds) * time.Second)}
+		}
+		report := types.SpecReport{
+			LeafNodeText: "provisions a cluster",
+			State:        types.SpecStateFailed,
+			StartTime:    start,
+			EndTime:      start.Add(100 * time.Second),
+			RunTime:      100 * time.Second,
+			SpecEvents: types.SpecEvents{
+				{SpecEventType: types.SpecEventByStart, Message: "creating the cluster", TimelineLocation: at(0)},
+				{SpecEventType: types.SpecEventByEnd, Message: "creating the cluster", TimelineLocation: at(60), Duration: 60 * time.Second},
+				{SpecEventType: types.SpecEventByStart, Message: "checking the cluster", TimelineLocation: at(60)},
+				{SpecEventType: types.SpecEventByStart, Message: "deleting the cluster", TimelineLocation: at(90)},
+			},
+			ReportEntries: types.ReportEntries{
+				{Name: "ClusterName", Value: types.WrapEntryValue("hp-ci-abc")},
+				{Name: "K8sVersion", Value: types.WrapEntryValue("v1.30.1")},
+				{Name: "K8sVersion", Value: types.WrapEntryValue("v1.31.2")},
+			},
+			Failure: types.Failure{Message: "cluster is not ready"},
+		}
+
+		result := suite.BuildSpecResult(report, 42)
+		Expect(result.Spec).To(Equal("provisions a cluster"))
+		Expect(result.State).To(Equal("failed"))
+		Expect(result.QaseID).To(BeEquivalentTo(42))
+		Expect(result.ClusterName).To(Equal("hp-ci-abc"))
+		Expect(result.K8sVersion).To(Equal("v1.31.2"))
+		Expect(result.FailureMessage).To(Equal("cluster is not ready"))
+		Expect(result.Steps).To(Equal([]helpers.StepTiming{
+			{Text: "creating the cluster", StartTime: start, Duration: 60 * time.Second},
+			{Text: "checking the cluster", StartTime: start.Add(60 * time.Second), Duration: 30 * time.Second},
+			{Text: "deleting the cluster", StartTime: start.Add(90 * time.Second), Duration: 10 * time.Second},
+		}))
+	})
+
+	It("WriteRunReport writes the report of the current spec", func(ctx SpecContext) {
+		dir := GinkgoT().TempDir()
+		DeferCleanup(func(original string) { helpers.RunReportDir = original }, helpers.RunReportDir)
+		helpers.RunReportDir = dir
+
+		By("creating the cluster", func() {
+			helpers.ReportCluster(&management.Cluster{Name: "hp-ci-report", Version: &management.Info{GitVersion: "v1.31.2"}})
+		})
+		suite.WriteRunReport(ctx, CurrentSpecReport(), -1, client)
+
+		files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		Expect(err).To(BeNil())
+		Expect(files).To(HaveLen(1))
+		data, err := os.ReadFile(files[0])
+		Expect(err).To(BeNil())
+		var result helpers.SpecResult
+		Expect(json.Unmarshal(data, &result)).To(Succeed())
+		Expect(result.RancherVersion).To(Equal("v2.11.0"))
+		Expect(result.ClusterName).To(Equal("hp-ci-report"))
+		Expect(result.K8sVersion).To(Equal("v1.31.2"))
+		Expect(result.QaseID).To(BeZero())
+		Expect(result.Steps).To(ConsistOf(HaveField("Text", "creating the cluster")))
+	})
+
+	It("WriteRunReport writes the diagnostics of a failed spec next to the report", func(ctx SpecContext) {
+		dir := GinkgoT().TempDir()
+		DeferCleanup(func(original string) { helpers.RunReportDir = original }, helpers.RunReportDir)
+		helpers.RunReportDir = dir
+		id := server.AddCluster(&management.Cluster{Name: "hp-ci-diagnostics"}, fakerancher.StateProvisioning)
+		DeferCleanup(helpers.SetCommandRunner(helpers.CommandRunnerFunc(func(_ context.Context, _ string, args ...string) (string, error) {
+			if slices.Contains(args, "logs") {
+				return "operator reconciling " + id, nil
+			}
+			return "", errors.New("forbidden")
+		})))
+
+		report := types.SpecReport{
+			LeafNodeText:  "provisions a cluster",
+			State:         types.SpecStateFailed,
+			StartTime:     time.Now(),
+			ReportEntries: types.ReportEntries{{Name: "ClusterName", Value: types.WrapEntryValue("hp-ci-diagnostics")}},
+		}
+		suite.WriteRunReport(ctx, report, -1, client)
+
+		files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		Expect(err).To(BeNil())
+		Expect(files).To(HaveLen(1))
+		data, err := os.ReadFile(files[0])
+		Expect(err).To(BeNil())
+		var result helpers.SpecResult
+		Expect(json.Unmarshal(data, &result)).To(Succeed())
+		Expect(result.DiagnosticsBundle).To(Equal(strings.TrimSuffix(filepath.Base(files[0]), ".json") + "-diagnostics.tar.gz"))
+
+		bundle := readTarball(filepath.Join(dir, result.DiagnosticsBundle))
+		Expect(bundle).To(HaveKeyWithValue("1-hp-ci-diagnostics/operator.log", "operator reconciling "+id))
+		Expect(bundle).To(HaveKeyWithValue("1-hp-ci-diagnostics/cluster.json", ContainSubstring(`"transitioning": "yes"`)))
+		Expect(bundle).To(HaveKeyWithValue("1-hp-ci-diagnostics/errors.txt", ContainSubstring("events-cluster.txt: forbidden")))
+		Expect(bundle).ToNot(HaveKey("1-hp-ci-diagnostics/clusterconfig.yaml"))
+	})
+})
+
+// readTarball returns the content of the files of a gzipped tarball keyed by their name
+func readTarball(file string) map[string]string {
+	f, err := os.Open(file)
+	Expect(err).To(BeNil())
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	Expect(err).To(BeNil())
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		Expect(err).To(BeNil())
+		content, err := io.ReadAll(tr)
+		Expect(err).To(BeNil())
+		files[header.Name] = string(content)
+	}
+}
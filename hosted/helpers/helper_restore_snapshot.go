@@ -0,0 +1,296 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SnapshotResource is a kind of objects captured by TakeResourceSnapshot; the filters are combined
+type SnapshotResource struct {
+	// Resource is the resource as accepted by kubectl get, for e.g. clusters.management.cattle.io
+	Resource string
+	// Namespace restricts the objects to a namespace; the objects of all the namespaces are captured if empty
+	Namespace string
+	// Name restricts the objects to the one with this name, for e.g. the cloud credential of the cluster
+	Name string
+	// Selector restricts the objects to those matching the label selector, for e.g. the fleet cluster of the cluster
+	Selector string
+	// ProjectOf restricts the objects to those whose projectName is a project of the cluster ID, for e.g. the project role template bindings
+	ProjectOf string
+}
+
+// HostedClusterSnapshotResources returns the resources of Rancher related to the hosted cluster of the provider: the cluster,
+// its *ClusterConfig object, its cloud credential, its cluster registration tokens, its fleet cluster, and its projects and role bindings
+func HostedClusterSnapshotResources(provider, clusterID, cloudCredentialID string) []SnapshotResource {
+	// the cloud credential ID is namespace:name, for e.g. cattle-global-data:cc-abcde
+	_, cloudCredentialName, _ := strings.Cut(cloudCredentialID, ":")
+	return []SnapshotResource{
+		{Resource: "clusters.management.cattle.io", Name: clusterID},
+		{Resource: fmt.Sprintf("%sclusterconfigs.%s.cattle.io", provider, provider), Namespace: CattleGlobalDataNS, Name: clusterID},
+		{Resource: "secrets", Namespace: CattleGlobalDataNS, Name: cloudCredentialName},
+		{Resource: "clusterregistrationtokens.management.cattle.io", Namespace: clusterID},
+		{Resource: "clusters.fleet.cattle.io", Namespace: "fleet-default", Selector: "management.cattle.io/cluster-name=" + clusterID},
+		{Resource: "projects.management.cattle.io", Namespace: clusterID},
+		{Resource: "clusterroletemplatebindings.management.cattle.io", Namespace: clusterID},
+		{Resource: "projectroletemplatebindings.management.cattle.io", ProjectOf: clusterID},
+	}
+}
+
+// volatileMetadata are the metadata fields set by the API server, which differ once an object has been restored;
+// the finalizers are set by the controllers, which add them again once they handle the restored object
+var volatileMetadata = []string{"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields", "selfLink", "finalizers"}
+
+// controllerOwnedKeys are the prefixes of the annotations and labels rewritten by kubectl and by the controllers, for e.g. on every apply
+// or once they handle the restored object
+var controllerOwnedKeys = []string{
+	"kubectl.kubernetes.io/",
+	"objectset.rio.cattle.io/",
+	"lifecycle.cattle.io/",
+	"cattle.io/status",
+	"cattle.io/timestamp",
+	"authz.management.cattle.io/",
+	"clusters.management.cattle.io/",
+	"field.cattle.io/publicEndpoints",
+}
+
+// ResourceSnapshot is the state of the objects captured by TakeResourceSnapshot, normalized so that it can be compared with Diff:
+// the status, the volatile metadata, the controller-owned annotations and labels, and the UIDs of the owner references are removed
+type ResourceSnapshot struct {
+	TakenAt time.Time
+	// Objects are keyed by resource/namespace/name, or resource/name for the cluster-scoped objects
+	Objects map[string]map[string]any
+}
+
+// TakeResourceSnapshot captures the objects of the resources in the upstream cluster; the resources which are not served by the cluster,
+// for e.g. the *ClusterConfig of an operator which is not installed, are skipped
+func TakeResourceSnapshot(ctx context.Context, resources ...SnapshotResource) (*ResourceSnapshot, error) {
+	snapshot := &ResourceSnapshot{TakenAt: time.Now(), Objects: map[string]map[string]any{}}
+	for _, resource := range resources {
+		args := []string{"get", resource.Resource, "--output", "json"}
+		if resource.Namespace != "" {
+			args = append(args, "--namespace", resource.Namespace)
+		} else {
+			args = append(args, "--all-namespaces")
+		}
+		if resource.Selector != "" {
+			args = append(args, "--selector", resource.Selector)
+		}
+		out, err := kubectlCLI(ctx, args...)
+		if err != nil {
+			if strings.Contains(out, "the server doesn't have a resource type") {
+				continue
+			}
+			return nil, errors.Wrapf(err, "Failed to get %s: %s", resource.Resource, out)
+		}
+		var list struct {
+			Items []map[string]any `json:"items"`
+		}
+		if err = json.Unmarshal([]byte(out), &list); err != nil {
+			return nil, errors.Wrapf(err, "Failed to parse %s", resource.Resource)
+		}
+		for _, object := range list.Items {
+			if !resource.matches(object) {
+				continue
+			}
+			metadata, _ := object["metadata"].(map[string]any)
+			name, _ := metadata["name"].(string)
+			key := resource.Resource + "/" + name
+			if namespace, _ := metadata["namespace"].(string); namespace != "" {
+				key = resource.Resource + "/" + namespace + "/" + name
+			}
+			snapshot.Objects[key] = normalizeSnapshotObject(object)
+		}
+	}
+	return snapshot, nil
+}
+
+// matches returns true if the object passes the Name and ProjectOf filters, which kubectl does not support
+func (r SnapshotResource) matches(object map[string]any) bool {
+	metadata, _ := object["metadata"].(map[string]any)
+	if name, _ := metadata["name"].(string); r.Name != "" && name != r.Name {
+		return false
+	}
+	if r.ProjectOf != "" {
+		// projectName is clusterID:projectID
+		projectName, _ := object["projectName"].(string)
+		return strings.HasPrefix(projectName, r.ProjectOf+":")
+	}
+	return true
+}
+
+// normalizeSnapshotObject removes the fields of the object which differ once it has been restored
+func normalizeSnapshotObject(object map[string]any) map[string]any {
+	delete(object, "status")
+	metadata, _ := object["metadata"].(map[string]any)
+	for _, field := range volatileMetadata {
+		delete(metadata, field)
+	}
+	for _, field := range []string{"annotations", "labels"} {
+		values, ok := metadata[field].(map[string]any)
+		if !ok {
+			continue
+		}
+		for key := range values {
+			if isControllerOwnedKey(key) {
+				delete(values, key)
+			}
+		}
+		if len(values) == 0 {
+			delete(metadata, field)
+		}
+	}
+	if owners, ok := metadata["ownerReferences"].([]any); ok {
+		for _, owner := range owners {
+			if owner, ok := owner.(map[string]any); ok {
+				delete(owner, "uid")
+			}
+		}
+	}
+	return object
+}
+
+func isControllerOwnedKey(key string) bool {
+	for _, prefix := range controllerOwnedKeys {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SnapshotDiff is the difference between two snapshots of the same resources
+type SnapshotDiff struct {
+	// Lost are the objects of the first snapshot missing from the second one
+	Lost []string
+	// Added are the objects of the second snapshot missing from the first one; they do not fail Err since the restored Rancher can create some
+	Added []string
+	// Changed are the changed fields of the objects in both snapshots, keyed by object
+	Changed map[string][]string
+}
+
+// Diff returns the difference between the snapshot, for e.g. taken before a backup, and after, taken after the restore;
+// the values of the secrets are never shown
+func (s *ResourceSnapshot) Diff(after *ResourceSnapshot) SnapshotDiff {
+	diff := SnapshotDiff{Changed: map[string][]string{}}
+	for key, before := range s.Objects {
+		restored, ok := after.Objects[key]
+		if !ok {
+			diff.Lost = append(diff.Lost, key)
+			continue
+		}
+		redact := before["kind"] == "Secret"
+		if changes := diffValues("", before, restored, redact); len(changes) > 0 {
+			diff.Changed[key] = changes
+		}
+	}
+	for key := range after.Objects {
+		if _, ok := s.Objects[key]; !ok {
+			diff.Added = append(diff.Added, key)
+		}
+	}
+	sort.Strings(diff.Lost)
+	sort.Strings(diff.Added)
+	return diff
+}
+
+// diffValues returns the paths of the fields which differ between before and after, along with their values unless redact is set
+func diffValues(path string, before, after any, redact bool) []string {
+	beforeMap, beforeIsMap := before.(map[string]any)
+	afterMap, afterIsMap := after.(map[string]any)
+	if beforeIsMap && afterIsMap {
+		keys := map[string]bool{}
+		for key := range beforeMap {
+			keys[key] = true
+		}
+		for key := range afterMap {
+			keys[key] = true
+		}
+		var changes []string
+		for _, key := range sortedKeys(keys) {
+			changes = append(changes, diffValues(joinPath(path, key), beforeMap[key], afterMap[key], redact)...)
+		}
+		return changes
+	}
+
+	beforeSlice, beforeIsSlice := before.([]any)
+	afterSlice, afterIsSlice := after.([]any)
+	if beforeIsSlice && afterIsSlice && len(beforeSlice) == len(afterSlice) {
+		var changes []string
+		for i := range beforeSlice {
+			changes = append(changes, diffValues(fmt.Sprintf("%s[%d]", path, i), beforeSlice[i], afterSlice[i], redact)...)
+		}
+		return changes
+	}
+
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+	if redact {
+		return []string{path + " changed"}
+	}
+	return []string{fmt.Sprintf("%s: %s -> %s", path, formatSnapshotValue(before), formatSnapshotValue(after))}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(keys map[string]bool) []string {
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func formatSnapshotValue(value any) string {
+	if value == nil {
+		return "<none>"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// Err returns an error listing the lost and changed objects, or nil if there are none
+func (d SnapshotDiff) Err() error {
+	if len(d.Lost) == 0 && len(d.Changed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("the restored resources differ:\n%s", d)
+}
+
+func (d SnapshotDiff) String() string {
+	var lines []string
+	for _, key := range d.Lost {
+		lines = append(lines, "- "+key)
+	}
+	for _, key := range d.Added {
+		lines = append(lines, "+ "+key)
+	}
+	changed := make([]string, 0, len(d.Changed))
+	for key := range d.Changed {
+		changed = append(changed, key)
+	}
+	sort.Strings(changed)
+	for _, key := range changed {
+		lines = append(lines, "~ "+key)
+		for _, change := range d.Changed[key] {
+			lines = append(lines, "    "+change)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,112 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("ResourceSnapshot", func() {
+	// takeSnapshot captures the resources of cluster c-abcde as returned by kubectl, keyed by resource
+	takeSnapshot := func(ctx context.Context, outputs map[string]string) *helpers.ResourceSnapshot {
+		DeferCleanup(helpers.SetCommandRunner(helpers.CommandRunnerFunc(func(_ context.Context, _ string, args ...string) (string, error) {
+			resource := args[slices.Index(args, "get")+1]
+			if resource == "clusters.fleet.cattle.io" {
+				Expect(args).To(ContainElements("--selector", "management.cattle.io/cluster-name=c-abcde"))
+			}
+			if output, ok := outputs[resource]; ok {
+				return output, nil
+			}
+			return `error: the server doesn't have a resource type "` + resource + `"`, errors.New("exit status 1")
+		})))
+		snapshot, err := helpers.TakeResourceSnapshot(ctx,
+			helpers.HostedClusterSnapshotResources("aks", "c-abcde", "cattle-global-data:cc-xyz")...)
+		Expect(err).To(BeNil())
+		return snapshot
+	}
+
+	It("ignores the status, the volatile metadata and the controller-owned metadata", func(ctx SpecContext) {
+		before := takeSnapshot(ctx, map[string]string{
+			"clusters.management.cattle.io": `{"items":[{"kind":"Cluster","metadata":{"name":"c-abcde","uid":"1","resourceVersion":"10",
+				"annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{}","lifecycle.cattle.io/create.cluster-agent-controller":"true",
+				"field.cattle.io/creatorId":"user-1"},"labels":{"objectset.rio.cattle.io/hash":"abc"},"finalizers":["controller.cattle.io/cluster-agent-controller"],
+				"ownerReferences":[{"kind":"Project","name":"p-1","uid":"2"}]},
+				"spec":{"displayName":"hp-aks"},"status":{"conditions":[{"type":"Ready","status":"True"}]}}]}`,
+			"secrets": `{"items":[{"kind":"Secret","metadata":{"name":"cc-xyz","namespace":"cattle-global-data"},"data":{"clientSecret":"c2VjcmV0"}},
+				{"kind":"Secret","metadata":{"name":"other","namespace":"cattle-global-data"}}]}`,
+		})
+		Expect(before.Objects).To(HaveLen(2))
+		Expect(before.Objects).To(HaveKey("secrets/cattle-global-data/cc-xyz"))
+
+		after := takeSnapshot(ctx, map[string]string{
+			"clusters.management.cattle.io": `{"items":[{"kind":"Cluster","metadata":{"name":"c-abcde","uid":"3","resourceVersion":"1",
+				"annotations":{"field.cattle.io/creatorId":"user-1"},"ownerReferences":[{"kind":"Project","name":"p-1","uid":"4"}]},
+				"spec":{"displayName":"hp-aks"},"status":{"conditions":[]}}]}`,
+			"secrets":                  `{"items":[{"kind":"Secret","metadata":{"name":"cc-xyz","namespace":"cattle-global-data","uid":"5"},"data":{"clientSecret":"c2VjcmV0"}}]}`,
+			"clusters.fleet.cattle.io": `{"items":[{"kind":"Cluster","metadata":{"name":"c-abcde","namespace":"fleet-default"},"spec":{}}]}`,
+		})
+		diff := before.Diff(after)
+		Expect(diff.Err()).To(Succeed())
+		Expect(diff.Added).To(Equal([]string{"clusters.fleet.cattle.io/fleet-default/c-abcde"}))
+	})
+
+	It("fails on the lost and changed resources without showing the secrets", func(ctx SpecContext) {
+		before := takeSnapshot(ctx, map[string]string{
+			"clusters.management.cattle.io": `{"items":[{"kind":"Cluster","metadata":{"name":"c-abcde"},"spec":{"aksConfig":{"nodePools":[{"count":1}]}}}]}`,
+			"clusterroletemplatebindings.management.cattle.io": `{"items":[{"kind":"ClusterRoleTemplateBinding","metadata":{"name":"crtb-1","namespace":"c-abcde"},
+				"clusterName":"c-abcde","roleTemplateName":"cluster-owner"}]}`,
+			"secrets": `{"items":[{"kind":"Secret","metadata":{"name":"cc-xyz","namespace":"cattle-global-data"},"data":{"clientSecret":"c2VjcmV0"}}]}`,
+		})
+		after := takeSnapshot(ctx, map[string]string{
+			"clusters.management.cattle.io": `{"items":[{"kind":"Cluster","metadata":{"name":"c-abcde"},"spec":{"aksConfig":{"nodePools":[{"count":2}]}}}]}`,
+			"secrets":                       `{"items":[{"kind":"Secret","metadata":{"name":"cc-xyz","namespace":"cattle-global-data"},"data":{"clientSecret":"b3RoZXI="}}]}`,
+		})
+
+		diff := before.Diff(after)
+		Expect(diff.Lost).To(Equal([]string{"clusterroletemplatebindings.management.cattle.io/c-abcde/crtb-1"}))
+		Expect(diff.Changed).To(Equal(map[string][]string{
+			"clusters.management.cattle.io/c-abcde": {"spec.aksConfig.nodePools[0].count: 1 -> 2"},
+			"secrets/cattle-global-data/cc-xyz":     {"data.clientSecret changed"},
+		}))
+		Expect(diff.Err()).To(MatchError(And(ContainSubstring("- clusterroletemplatebindings.management.cattle.io/c-abcde/crtb-1"), Not(ContainSubstring("c2VjcmV0")))))
+	})
+
+	It("captures only the resources of the cluster", func(ctx SpecContext) {
+		snapshot := takeSnapshot(ctx, map[string]string{
+			"clusters.management.cattle.io": `{"items":[{"kind":"Cluster","metadata":{"name":"local"},"spec":{}},
+				{"kind":"Cluster","metadata":{"name":"c-abcde"},"spec":{}}]}`,
+			"projectroletemplatebindings.management.cattle.io": `{"items":[
+				{"kind":"ProjectRoleTemplateBinding","metadata":{"name":"prtb-1","namespace":"c-abcde-p-1"},"projectName":"c-abcde:p-1"},
+				{"kind":"ProjectRoleTemplateBinding","metadata":{"name":"prtb-2","namespace":"local-p-2"},"projectName":"local:p-2"}]}`,
+		})
+		Expect(snapshot.Objects).To(HaveLen(2))
+		Expect(snapshot.Objects).To(HaveKey("clusters.management.cattle.io/c-abcde"))
+		Expect(snapshot.Objects).To(HaveKey("projectroletemplatebindings.management.cattle.io/c-abcde-p-1/prtb-1"))
+	})
+
+	It("lists the resources of the cluster", func() {
+		Expect(helpers.HostedClusterSnapshotResources("eks", "c-abcde", "cattle-global-data:cc-xyz")).To(ContainElements(
+			helpers.SnapshotResource{Resource: "eksclusterconfigs.eks.cattle.io", Namespace: "cattle-global-data", Name: "c-abcde"},
+			helpers.SnapshotResource{Resource: "secrets", Namespace: "cattle-global-data", Name: "cc-xyz"},
+			helpers.SnapshotResource{Resource: "projects.management.cattle.io", Namespace: "c-abcde"}))
+	})
+})
@@ -0,0 +1,199 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// NextVersion is resolved to the first version the cluster can be upgraded to
+	NextVersion = "next"
+	// ControlPlaneVersion is resolved to the k8s version of the control plane, it can only be used to upgrade the nodepools
+	ControlPlaneVersion = "controlPlane"
+)
+
+// Scenario is a sequence of cluster operations loaded from a YAML file and run by a suite.ScenarioRunner
+type Scenario struct {
+	Name string `json:"name"`
+	// QaseIDs are the Qase IDs of the scenario for a provisioned cluster, keyed by provider; the scenario only runs for the listed providers
+	QaseIDs map[string]int64 `json:"qaseIDs,omitempty"`
+	// ImportQaseIDs are the Qase IDs of the scenario for an imported cluster, keyed by provider
+	ImportQaseIDs map[string]int64 `json:"importQaseIDs,omitempty"`
+	// Upgrade creates the cluster with a k8s version that can be upgraded
+	Upgrade bool           `json:"upgrade,omitempty"`
+	Steps   []ScenarioStep `json:"steps"`
+	// File is the file the scenario has been loaded from
+	File string `json:"-"`
+}
+
+// ScenarioStep is a single operation of a Scenario; exactly one of its actions must be set
+type ScenarioStep struct {
+	// ScalePool modifies the node count of every nodepool/nodegroup
+	ScalePool *int64 `json:"scalePool,omitempty"`
+	// AddPool adds the given number of nodepools/nodegroups
+	AddPool *int `json:"addPool,omitempty"`
+	// DeletePool deletes all the nodepools/nodegroups but the first one
+	DeletePool bool `json:"deletePool,omitempty"`
+	// UpgradeControlPlane upgrades the control plane to the given version or to NextVersion
+	UpgradeControlPlane string `json:"upgradeControlPlane,omitempty"`
+	// UpgradeNodePools upgrades the nodepools/nodegroups to the given version, to NextVersion or to ControlPlaneVersion
+	UpgradeNodePools string `json:"upgradeNodePools,omitempty"`
+	// UpdateTags adds or updates the tags (labels on GKE) of the cluster
+	UpdateTags map[string]string `json:"updateTags,omitempty"`
+	// RemoveTags removes the tags (labels on GKE) of the cluster
+	RemoveTags []string `json:"removeTags,omitempty"`
+	// SyncFromCloud modifies the cluster directly on the cloud provider and waits for Rancher to sync the change
+	SyncFromCloud *CloudStep `json:"syncFromCloud,omitempty"`
+
+	// ExpectError is a part of the error the action must fail with, either when applied or afterwards in the cluster transitioning message
+	ExpectError string `json:"expectError,omitempty"`
+}
+
+// CloudStep is an operation done directly on the cloud provider; exactly one of its actions must be set
+type CloudStep struct {
+	// ScalePool modifies the node count of the first nodepool/nodegroup
+	ScalePool *int64 `json:"scalePool,omitempty"`
+	// AddPool adds a nodepool/nodegroup with the given name
+	AddPool string `json:"addPool,omitempty"`
+	// UpgradeControlPlane upgrades the control plane to the given version or to NextVersion
+	UpgradeControlPlane string `json:"upgradeControlPlane,omitempty"`
+}
+
+// QaseIDFor returns the Qase ID of the scenario for the provider, and false if the scenario does not run for it
+func (s Scenario) QaseIDFor(provider string, isImport bool) (int64, bool) {
+	ids := s.QaseIDs
+	if isImport {
+		ids = s.ImportQaseIDs
+	}
+	id, ok := ids[provider]
+	return id, ok
+}
+
+// Validate checks that the scenario has a name and steps, and that every step has exactly one action
+func (s Scenario) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("scenario has no name")
+	}
+	if len(s.QaseIDs) == 0 && len(s.ImportQaseIDs) == 0 {
+		return fmt.Errorf("scenario %q has no qaseIDs nor importQaseIDs", s.Name)
+	}
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("scenario %q has no steps", s.Name)
+	}
+	for i, step := range s.Steps {
+		if err := step.validate(); err != nil {
+			return fmt.Errorf("scenario %q step %d: %w", s.Name, i+1, err)
+		}
+	}
+	return nil
+}
+
+func (s ScenarioStep) actions() []string {
+	var actions []string
+	if s.ScalePool != nil {
+		actions = append(actions, fmt.Sprintf("scalePool %d", *s.ScalePool))
+	}
+	if s.AddPool != nil {
+		actions = append(actions, fmt.Sprintf("addPool %d", *s.AddPool))
+	}
+	if s.DeletePool {
+		actions = append(actions, "deletePool")
+	}
+	if s.UpgradeControlPlane != "" {
+		actions = append(actions, "upgradeControlPlane "+s.UpgradeControlPlane)
+	}
+	if s.UpgradeNodePools != "" {
+		actions = append(actions, "upgradeNodePools "+s.UpgradeNodePools)
+	}
+	if len(s.UpdateTags) > 0 {
+		actions = append(actions, fmt.Sprintf("updateTags %v", s.UpdateTags))
+	}
+	if len(s.RemoveTags) > 0 {
+		actions = append(actions, fmt.Sprintf("removeTags %v", s.RemoveTags))
+	}
+	if s.SyncFromCloud != nil {
+		for _, action := range s.SyncFromCloud.actions() {
+			actions = append(actions, "syncFromCloud "+action)
+		}
+	}
+	return actions
+}
+
+func (s CloudStep) actions() []string {
+	var actions []string
+	if s.ScalePool != nil {
+		actions = append(actions, fmt.Sprintf("scalePool %d", *s.ScalePool))
+	}
+	if s.AddPool != "" {
+		actions = append(actions, "addPool "+s.AddPool)
+	}
+	if s.UpgradeControlPlane != "" {
+		actions = append(actions, "upgradeControlPlane "+s.UpgradeControlPlane)
+	}
+	return actions
+}
+
+func (s ScenarioStep) validate() error {
+	actions := s.actions()
+	if len(actions) != 1 {
+		return fmt.Errorf("expected exactly one action, got %d: %v", len(actions), actions)
+	}
+	if s.UpgradeControlPlane == ControlPlaneVersion || (s.SyncFromCloud != nil && s.SyncFromCloud.UpgradeControlPlane == ControlPlaneVersion) {
+		return fmt.Errorf("%s can only be used to upgrade the nodepools", ControlPlaneVersion)
+	}
+	if s.SyncFromCloud != nil && s.ExpectError != "" {
+		return fmt.Errorf("expectError cannot be used with syncFromCloud")
+	}
+	return nil
+}
+
+// String describes the step, e.g. `scalePool 2 (expecting error "...")`
+func (s ScenarioStep) String() string {
+	description := strings.Join(s.actions(), ", ")
+	if s.ExpectError != "" {
+		description += fmt.Sprintf(" (expecting error %q)", s.ExpectError)
+	}
+	return description
+}
+
+// LoadScenarios loads and validates the scenarios of all the YAML files of the directory, one scenario per file, sorted by file name
+func LoadScenarios(dir string) ([]Scenario, error) {
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	var scenarios []Scenario
+	names := map[string]string{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to read scenario")
+		}
+		var scenario Scenario
+		if err = yaml.UnmarshalStrict(data, &scenario); err != nil {
+			return nil, errors.Wrap(err, "Failed to parse scenario "+file)
+		}
+		if err = scenario.Validate(); err != nil {
+			return nil, errors.Wrap(err, "Invalid scenario "+file)
+		}
+		if other, ok := names[scenario.Name]; ok {
+			return nil, fmt.Errorf("scenario %q is defined in both %s and %s", scenario.Name, other, file)
+		}
+		names[scenario.Name] = file
+		scenario.File = file
+		scenarios = append(scenarios, scenario)
+	}
+	return scenarios, nil
+}
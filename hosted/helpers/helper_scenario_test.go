@@ -0,0 +1,210 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakerancher"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
+)
+
+// scenarioProvider implements the part of helpers.HostedProvider used by the scenarios on top of the AKS config of the fake server clusters
+type scenarioProvider struct {
+	helpers.HostedProvider
+}
+
+func (scenarioProvider) Name() string {
+	return "aks"
+}
+
+func (scenarioProvider) ListAvailableVersions(_ context.Context, _ *rancher.Client, _ *management.Cluster) ([]string, error) {
+	return []string{"1.32.1", "1.32.0"}, nil
+}
+
+func (scenarioProvider) ScaleNodePool(_ context.Context, cluster *management.Cluster, client *rancher.Client, nodeCount int64, _, _ bool) (*management.Cluster, error) {
+	for i := range *cluster.AKSConfig.NodePools {
+		(*cluster.AKSConfig.NodePools)[i].Count = pointer.Int64(nodeCount)
+	}
+	return client.Management.Cluster.Update(cluster, cluster)
+}
+
+func (scenarioProvider) UpdateClusterTags(_ context.Context, cluster *management.Cluster, client *rancher.Client, tags map[string]string) (*management.Cluster, error) {
+	cluster.AKSConfig.Tags = tags
+	return client.Management.Cluster.Update(cluster, cluster)
+}
+
+func (scenarioProvider) ConfigSpec(cluster *management.Cluster) helpers.ClusterSpec {
+	return aksSpec(cluster.AKSConfig)
+}
+
+func (scenarioProvider) UpstreamSpec(cluster *management.Cluster) helpers.ClusterSpec {
+	return aksSpec(cluster.AKSStatus.UpstreamSpec)
+}
+
+func (scenarioProvider) ScaleNodePoolOnCloud(_ context.Context, cluster *management.Cluster, nodeCount int64) error {
+	return server.UpdateCluster(cluster.ID, func(cluster *management.Cluster) {
+		(*cluster.AKSStatus.UpstreamSpec.NodePools)[0].Count = pointer.Int64(nodeCount)
+	})
+}
+
+func (scenarioProvider) UpgradeControlPlaneOnCloud(_ context.Context, cluster *management.Cluster, upgradeToVersion string) error {
+	return server.UpdateCluster(cluster.ID, func(cluster *management.Cluster) {
+		cluster.AKSStatus.UpstreamSpec.KubernetesVersion = pointer.String(upgradeToVersion)
+	})
+}
+
+func aksSpec(config *management.AKSClusterConfigSpec) helpers.ClusterSpec {
+	spec := helpers.ClusterSpec{KubernetesVersion: *config.KubernetesVersion, Tags: config.Tags}
+	for _, np := range *config.NodePools {
+		spec.NodePools = append(spec.NodePools, helpers.NodePoolSpec{Name: *np.Name, NodeCount: *np.Count})
+	}
+	return spec
+}
+
+var _ = Describe("Scenarios", func() {
+	Describe("LoadScenarios", func() {
+		var dir string
+
+		writeScenario := func(file, content string) {
+			Expect(os.WriteFile(filepath.Join(dir, file), []byte(content), 0o600)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			dir = GinkgoT().TempDir()
+		})
+
+		It("loads the scenarios sorted by file name", func() {
+			writeScenario("b.yml", `
+name: sync
+importQaseIDs: {aks: 2}
+steps:
+  - syncFromCloud: {scalePool: 3}
+`)
+			writeScenario("a.yaml", `
+name: tags
+qaseIDs: {aks: 1, eks: 3}
+upgrade: true
+steps:
+  - updateTags: {team: qa}
+  - scalePool: 0
+    expectError: "must be greater"
+`)
+			writeScenario("README.md", "not a scenario")
+
+			scenarios, err := helpers.LoadScenarios(dir)
+			Expect(err).To(BeNil())
+			Expect(scenarios).To(HaveLen(2))
+			Expect(scenarios[0].Name).To(Equal("tags"))
+			Expect(scenarios[0].Upgrade).To(BeTrue())
+			Expect(scenarios[0].Steps[0].UpdateTags).To(Equal(map[string]string{"team": "qa"}))
+			Expect(scenarios[0].Steps[1].String()).To(Equal(`scalePool 0 (expecting error "must be greater")`))
+			Expect(scenarios[1].Steps[0].String()).To(Equal("syncFromCloud scalePool 3"))
+
+			id, ok := scenarios[0].QaseIDFor("eks", false)
+			Expect(ok).To(BeTrue())
+			Expect(id).To(BeEquivalentTo(3))
+			_, ok = scenarios[0].QaseIDFor("eks", true)
+			Expect(ok).To(BeFalse())
+		})
+
+		DescribeTable("rejects the invalid scenarios",
+			func(content, expectedErr string) {
+				writeScenario("invalid.yaml", content)
+				_, err := helpers.LoadScenarios(dir)
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+			},
+			Entry("without name", "qaseIDs: {aks: 1}\nsteps: [{deletePool: true}]", "scenario has no name"),
+			Entry("without Qase ID", "name: s\nsteps: [{deletePool: true}]", "has no qaseIDs nor importQaseIDs"),
+			Entry("without steps", "name: s\nqaseIDs: {aks: 1}", "has no steps"),
+			Entry("with an unknown field", "name: s\nqaseIDs: {aks: 1}\nsteps: [{scalePools: 2}]", `unknown field "scalePools"`),
+			Entry("with a step without action", "name: s\nqaseIDs: {aks: 1}\nsteps: [{expectError: failed}]", "step 1: expected exactly one action, got 0"),
+			Entry("with a step with two actions", "name: s\nqaseIDs: {aks: 1}\nsteps: [{scalePool: 2, deletePool: true}]", "expected exactly one action, got 2"),
+			Entry("with an error expected from the cloud", "name: s\nqaseIDs: {aks: 1}\nsteps: [{syncFromCloud: {addPool: np}, expectError: failed}]", "expectError cannot be used with syncFromCloud"),
+		)
+
+		It("rejects the scenarios with the same name", func() {
+			writeScenario("a.yaml", "name: s\nqaseIDs: {aks: 1}\nsteps: [{deletePool: true}]")
+			writeScenario("b.yaml", "name: s\nqaseIDs: {aks: 1}\nsteps: [{deletePool: true}]")
+			_, err := helpers.LoadScenarios(dir)
+			Expect(err).To(MatchError(ContainSubstring(`scenario "s" is defined in both`)))
+		})
+	})
+
+	Describe("ScenarioRunner", func() {
+		var (
+			runner  suite.ScenarioRunner
+			cluster *management.Cluster
+		)
+
+		BeforeEach(func() {
+			runner = suite.ScenarioRunner{Provider: scenarioProvider{}, Client: client, PollInterval: 20 * time.Millisecond}
+			id := server.AddCluster(&management.Cluster{
+				Name: "fake-scenario",
+				AKSConfig: &management.AKSClusterConfigSpec{
+					ClusterName:       "fake-scenario",
+					KubernetesVersion: pointer.String("1.31.4"),
+					NodePools:         &[]management.AKSNodePool{{Name: pointer.String("np"), Count: pointer.Int64(1)}},
+					Tags:              map[string]string{"owner": "hosted-providers-e2e"},
+				},
+			}, fakerancher.StateActive)
+
+			var err error
+			cluster, err = client.Management.Cluster.ByID(id)
+			Expect(err).To(BeNil())
+		})
+
+		It("keeps the other tags when updating and removing tags", func(ctx SpecContext) {
+			cluster = runner.Run(ctx, cluster, helpers.Scenario{Name: "tags", Steps: []helpers.ScenarioStep{
+				{UpdateTags: map[string]string{"team": "qa", "env": "ci"}},
+				{RemoveTags: []string{"env"}},
+			}})
+
+			Expect(cluster.AKSStatus.UpstreamSpec.Tags).To(Equal(map[string]string{"owner": "hosted-providers-e2e", "team": "qa"}))
+		})
+
+		It("checks the error reported by the operator", func(ctx SpecContext) {
+			DeferCleanup(func(transitions []fakerancher.ClusterState) {
+				server.UpdateTransitions = transitions
+			}, server.UpdateTransitions)
+			server.UpdateTransitions = []fakerancher.ClusterState{fakerancher.StateUpdating, fakerancher.StateError("agentPoolProfile.count was 0. It must be greater or equal to minCount:1")}
+
+			cluster = runner.Run(ctx, cluster, helpers.Scenario{Name: "zero", Steps: []helpers.ScenarioStep{
+				{ScalePool: pointer.Int64(0), ExpectError: "It must be greater or equal to minCount:1"},
+			}})
+			Expect(cluster.Transitioning).To(Equal("error"))
+		})
+
+		It("waits for the changes done on the cloud to be synced", func(ctx SpecContext) {
+			cluster = runner.Run(ctx, cluster, helpers.Scenario{Name: "sync", Steps: []helpers.ScenarioStep{
+				{SyncFromCloud: &helpers.CloudStep{ScalePool: pointer.Int64(3)}},
+				{SyncFromCloud: &helpers.CloudStep{UpgradeControlPlane: helpers.NextVersion}},
+			}})
+
+			Expect(runner.Provider.UpstreamSpec(cluster).NodePools[0].NodeCount).To(BeEquivalentTo(3))
+			Expect(runner.Provider.UpstreamSpec(cluster).KubernetesVersion).To(Equal("1.32.1"))
+		})
+	})
+})
@@ -0,0 +1,73 @@
+package helpers
+
+import (
+	"context"
+	"sync"
+)
+
+// ReportEntryVisibility tells when a report entry is displayed, see SpecHooks.AddReportEntry
+type ReportEntryVisibility int
+
+const (
+	// ReportEntryVisibilityAlways displays the entry in every report
+	ReportEntryVisibilityAlways ReportEntryVisibility = iota
+	// ReportEntryVisibilityFailureOrVerbose displays the entry only if the spec failed or the run is verbose
+	ReportEntryVisibilityFailureOrVerbose
+	// ReportEntryVisibilityNever never displays the entry, it is only available to the reporters
+	ReportEntryVisibilityNever
+)
+
+// SpecHooks connects the helpers to the framework running the specs, so that the helpers do not depend on Ginkgo and can be used
+// by programs such as hpctl; the suite package installs the Ginkgo hooks, the default ones do nothing.
+// The offsets are the number of stack frames between the caller of the hook and the code location to report.
+type SpecHooks interface {
+	// Running returns true while a spec is running
+	Running() bool
+	// CanDeferCleanup returns true if DeferCleanup can be called, i.e. within a spec or a BeforeSuite
+	CanDeferCleanup() bool
+	// DeferCleanup runs teardown once the current spec or suite ends; it is given a context not cancelled when the spec is interrupted
+	DeferCleanup(teardown func(ctx context.Context) error, offset int)
+	// Failed returns true if the current spec failed
+	Failed() bool
+	// Location returns the file and the line of the current spec, if any
+	Location() (file string, line int)
+	// AddReportEntry attaches the value to the report of the current spec
+	AddReportEntry(name string, value any, visibility ReportEntryVisibility)
+	// Fail fails the current spec
+	Fail(message string, offset int)
+}
+
+type noSpecHooks struct{}
+
+func (noSpecHooks) Running() bool                                     { return false }
+func (noSpecHooks) CanDeferCleanup() bool                             { return false }
+func (noSpecHooks) DeferCleanup(func(ctx context.Context) error, int) {}
+func (noSpecHooks) Failed() bool                                      { return false }
+func (noSpecHooks) Location() (string, int)                           { return "", 0 }
+func (noSpecHooks) AddReportEntry(string, any, ReportEntryVisibility) {}
+func (noSpecHooks) Fail(string, int)                                  {}
+
+var (
+	specHooksMu sync.RWMutex
+	specHooks   SpecHooks = noSpecHooks{}
+)
+
+// SetSpecHooks replaces the SpecHooks used by the helpers and returns a function restoring the previous ones
+func SetSpecHooks(hooks SpecHooks) (restore func()) {
+	specHooksMu.Lock()
+	defer specHooksMu.Unlock()
+	previous := specHooks
+	specHooks = hooks
+	return func() {
+		specHooksMu.Lock()
+		defer specHooksMu.Unlock()
+		specHooks = previous
+	}
+}
+
+// GetSpecHooks returns the SpecHooks currently used by the helpers
+func GetSpecHooks() SpecHooks {
+	specHooksMu.RLock()
+	defer specHooksMu.RUnlock()
+	return specHooks
+}
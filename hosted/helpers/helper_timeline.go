@@ -0,0 +1,317 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/wrangler/pkg/summary"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ClusterTimelineReportEntry is the name of the report entries holding a ClusterTimelineReport
+const ClusterTimelineReportEntry = "ClusterTimeline"
+
+// TimelineEvent is the state of a cluster from the time it has been observed until the next event
+type TimelineEvent struct {
+	Time time.Time `json:"time"`
+	// State, Transitioning and TransitioningMessage are computed from the conditions as Rancher does for management.Cluster, for e.g. updating, error and the error of the operator
+	State                string              `json:"state"`
+	Transitioning        string              `json:"transitioning,omitempty"`
+	TransitioningMessage string              `json:"transitioningMessage,omitempty"`
+	Conditions           []TimelineCondition `json:"conditions,omitempty"`
+	Deleted              bool                `json:"deleted,omitempty"`
+	// ErrorClass is the name of the OperatorError matching the message when Transitioning is error, or UnknownOperatorError
+	ErrorClass string `json:"errorClass,omitempty"`
+}
+
+// TimelineCondition is a condition of the cluster
+type TimelineCondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+func (e TimelineEvent) String() string {
+	if e.Deleted {
+		return fmt.Sprintf("%s deleted", e.Time.Format("15:04:05.000"))
+	}
+	line := fmt.Sprintf("%s %s transitioning=%s", e.Time.Format("15:04:05.000"), e.State, e.Transitioning)
+	if e.TransitioningMessage != "" {
+		line += fmt.Sprintf(" message=%q", e.TransitioningMessage)
+	}
+	if e.ErrorClass != "" {
+		line += " class=" + e.ErrorClass
+	}
+	return line
+}
+
+// sameState returns true if the events only differ by their time
+func (e TimelineEvent) sameState(other TimelineEvent) bool {
+	return e.State == other.State && e.Transitioning == other.Transitioning && e.TransitioningMessage == other.TransitioningMessage &&
+		e.Deleted == other.Deleted && reflect.DeepEqual(e.Conditions, other.Conditions)
+}
+
+// ClusterTimelineReport is the timeline of a cluster as attached to the spec report and written to the run report
+type ClusterTimelineReport struct {
+	ClusterName string          `json:"clusterName"`
+	Events      []TimelineEvent `json:"events"`
+}
+
+func (r ClusterTimelineReport) String() string {
+	lines := []string{fmt.Sprintf("Timeline of cluster %s:", r.ClusterName)}
+	for _, event := range r.Events {
+		lines = append(lines, "  "+event.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ClusterTimeline records every change to the state, transitioning status and message, and conditions of a cluster,
+// so that the specs can assert on short-lived states that polling can miss; use StartClusterTimeline to create one.
+type ClusterTimeline struct {
+	cluster *management.Cluster
+	cancel  context.CancelFunc
+	done    chan struct{}
+	// operatorVersion is the version of the operator against which the errors are classified
+	operatorVersion string
+
+	mu      sync.Mutex
+	events  []TimelineEvent
+	stopped time.Time
+	changed chan struct{}
+}
+
+// StartClusterTimeline records the timeline of the cluster in the background until the cluster is deleted, ctx is cancelled or Stop is called.
+// Within a spec, the recording is stopped when the spec ends and the timeline is attached to its report.
+func StartClusterTimeline(ctx context.Context, client *rancher.Client, cluster *management.Cluster) *ClusterTimeline {
+	ctx, cancel := context.WithCancel(ctx)
+	t := &ClusterTimeline{cluster: cluster, cancel: cancel, done: make(chan struct{}), changed: make(chan struct{})}
+	var err error
+	if t.operatorVersion, err = CurrentOperatorVersion(ctx); err != nil {
+		Logger.Info(fmt.Sprintf("Classifying the errors of cluster %s against all the operator versions: %v", cluster.Name, err))
+	}
+	// the watch starts with the current state of the cluster, which is recorded before returning so that the changes made by the spec are not missed;
+	// this way every event is built from the same management.cattle.io object
+	watchInterface, deleted := t.startWatch(ctx, client)
+	go t.watch(ctx, client, watchInterface, deleted)
+
+	if hooks := GetSpecHooks(); hooks.CanDeferCleanup() {
+		hooks.DeferCleanup(func(context.Context) error {
+			t.Stop()
+			hooks.AddReportEntry(ClusterTimelineReportEntry, t.Report(), ReportEntryVisibilityFailureOrVerbose)
+			return nil
+		}, 1)
+	}
+	return t
+}
+
+// startWatch opens the watch of the cluster and records its first event; it returns a nil watch if it could not be opened,
+// and true if the cluster has been deleted
+func (t *ClusterTimeline) startWatch(ctx context.Context, client *rancher.Client) (watch.Interface, bool) {
+	watchInterface, err := client.GetManagementWatchInterface(management.ClusterType, metav1.ListOptions{FieldSelector: "metadata.name=" + t.cluster.ID})
+	if err != nil {
+		Logger.Info(fmt.Sprintf("Failed to watch cluster %s for its timeline: %v", t.cluster.Name, err))
+		return nil, false
+	}
+	select {
+	case <-ctx.Done():
+		watchInterface.Stop()
+		return nil, false
+	case <-time.After(30 * time.Second):
+	case event, open := <-watchInterface.ResultChan():
+		if open && t.recordWatchEvent(event) {
+			watchInterface.Stop()
+			return nil, true
+		}
+	}
+	return watchInterface, false
+}
+
+// watch records the events of the cluster, starting with watchInterface if not nil; the watch is restarted when it times out
+func (t *ClusterTimeline) watch(ctx context.Context, client *rancher.Client, watchInterface watch.Interface, deleted bool) {
+	defer close(t.done)
+	for !deleted && ctx.Err() == nil {
+		if watchInterface == nil {
+			watchInterface, deleted = t.startWatch(ctx, client)
+			if watchInterface == nil {
+				if !deleted {
+					select {
+					case <-ctx.Done():
+					case <-time.After(5 * time.Second):
+					}
+				}
+				continue
+			}
+		}
+		deleted = t.consume(ctx, watchInterface)
+		watchInterface.Stop()
+		watchInterface = nil
+	}
+}
+
+// consume records the events of the watch until it ends; it returns true if the cluster has been deleted
+func (t *ClusterTimeline) consume(ctx context.Context, watchInterface watch.Interface) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, open := <-watchInterface.ResultChan():
+			if !open || event.Type == watch.Error {
+				return false
+			}
+			if t.recordWatchEvent(event) {
+				return true
+			}
+		}
+	}
+}
+
+// recordWatchEvent records the event received by the watch; it returns true if the cluster has been deleted
+func (t *ClusterTimeline) recordWatchEvent(event watch.Event) bool {
+	switch event.Type {
+	case watch.Deleted:
+		t.record(TimelineEvent{Time: time.Now(), Deleted: true})
+		return true
+	case watch.Error:
+	default:
+		if object, ok := event.Object.(*unstructured.Unstructured); ok {
+			t.record(unstructuredClusterEvent(object))
+		}
+	}
+	return false
+}
+
+// unstructuredClusterEvent returns the event of a management.cattle.io cluster object received by a watch;
+// its state is computed from the conditions as Rancher does for the API
+func unstructuredClusterEvent(object *unstructured.Unstructured) TimelineEvent {
+	clusterSummary := summary.Summarize(object)
+	event := TimelineEvent{Time: time.Now(), State: clusterSummary.State, Transitioning: "no", TransitioningMessage: strings.Join(clusterSummary.Message, "; ")}
+	switch {
+	case clusterSummary.Error:
+		event.Transitioning = "error"
+	case clusterSummary.Transitioning:
+		event.Transitioning = "yes"
+	}
+	conditions, _, _ := unstructured.NestedSlice(object.Object, "status", "conditions")
+	for _, condition := range conditions {
+		fields, _ := condition.(map[string]any)
+		text := func(key string) string {
+			value, _ := fields[key].(string)
+			return value
+		}
+		event.Conditions = append(event.Conditions, TimelineCondition{Type: text("type"), Status: text("status"), Reason: text("reason"), Message: text("message")})
+	}
+	return event
+}
+
+// record appends the event if the state of the cluster changed
+func (t *ClusterTimeline) record(event TimelineEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.stopped.IsZero() || (len(t.events) > 0 && t.events[len(t.events)-1].sameState(event)) {
+		return
+	}
+	if event.Transitioning == "error" {
+		event.ErrorClass = ClassifyOperatorError(Provider, t.operatorVersion, event.TransitioningMessage)
+	}
+	t.events = append(t.events, event)
+	close(t.changed)
+	t.changed = make(chan struct{})
+}
+
+// Stop stops the recording; the timeline can still be queried
+func (t *ClusterTimeline) Stop() {
+	t.cancel()
+	<-t.done
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped.IsZero() {
+		t.stopped = time.Now()
+	}
+}
+
+// Events returns the events recorded so far
+func (t *ClusterTimeline) Events() []TimelineEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]TimelineEvent(nil), t.events...)
+}
+
+// Report returns the events recorded so far along with the name of the cluster
+func (t *ClusterTimeline) Report() ClusterTimelineReport {
+	return ClusterTimelineReport{ClusterName: t.cluster.Name, Events: t.Events()}
+}
+
+// EverEntered returns true if the cluster has been in the state, for e.g. updating, or in the transitioning status, for e.g. error,
+// with a transitioning message matching messageRegexp; an empty messageRegexp matches any message
+func (t *ClusterTimeline) EverEntered(state, messageRegexp string) bool {
+	_, found := t.find(state, regexp.MustCompile(messageRegexp))
+	return found
+}
+
+func (t *ClusterTimeline) find(state string, messageRegexp *regexp.Regexp) (TimelineEvent, bool) {
+	for _, event := range t.Events() {
+		if (event.State == state || event.Transitioning == state) && messageRegexp.MatchString(event.TransitioningMessage) {
+			return event, true
+		}
+	}
+	return TimelineEvent{}, false
+}
+
+// WaitUntilEntered waits until EverEntered returns true; it returns a TimeoutError listing the recorded events otherwise.
+func (t *ClusterTimeline) WaitUntilEntered(ctx context.Context, state, messageRegexp string, timeout time.Duration) error {
+	pattern := regexp.MustCompile(messageRegexp)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		t.mu.Lock()
+		changed := t.changed
+		t.mu.Unlock()
+		if _, found := t.find(state, pattern); found {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for cluster %s to enter %s: %w", t.cluster.Name, state, context.Cause(ctx))
+		case <-timer.C:
+			return &TimeoutError{
+				Description: fmt.Sprintf("cluster %s to enter %s with a message matching %q", t.cluster.Name, state, messageRegexp),
+				Timeout:     timeout,
+				Err:         fmt.Errorf("%s", t.Report()),
+			}
+		}
+	}
+}
+
+// DurationIn returns the time spent by the cluster in the state, for e.g. updating, or in the transitioning status, for e.g. error;
+// the last event lasts until now, or until the recording stopped
+func (t *ClusterTimeline) DurationIn(state string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	end := time.Now()
+	if !t.stopped.IsZero() {
+		end = t.stopped
+	}
+	var duration time.Duration
+	for i, event := range t.events {
+		if event.State != state && event.Transitioning != state {
+			continue
+		}
+		until := end
+		if i+1 < len(t.events) {
+			until = t.events[i+1].Time
+		}
+		duration += until.Sub(event.Time)
+	}
+	return duration
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakerancher"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
+)
+
+var _ = Describe("ClusterTimeline", Ordered, func() {
+	var timelines []helpers.ClusterTimelineReport
+
+	ReportAfterEach(func(report SpecReport) {
+		timelines = suite.BuildSpecResult(report, 0).Timelines
+	})
+
+	It("records the short-lived states of the cluster", func(ctx SpecContext) {
+		id := server.AddCluster(&management.Cluster{Name: "fake-timeline"}, fakerancher.StateActive)
+		cluster, err := client.Management.Cluster.ByID(id)
+		Expect(err).To(BeNil())
+
+		timeline := helpers.StartClusterTimeline(ctx, client, cluster)
+		Expect(server.ScriptClusterStates(id, fakerancher.StateUpdating, fakerancher.StateError("node pool quota exceeded in region eastus"),
+			fakerancher.StateUpdating, fakerancher.StateActive)).To(Succeed())
+		Expect(timeline.WaitUntilEntered(ctx, "error", "quota exceeded in region \\w+", time.Second)).To(Succeed())
+		Eventually(timeline.Events).Should(HaveLen(5))
+		timeline.Stop()
+
+		Expect(timeline.EverEntered("error", "quota exceeded")).To(BeTrue())
+		Expect(timeline.EverEntered("error", "cannot remove node pool")).To(BeFalse())
+		Expect(timeline.DurationIn("updating")).To(BeNumerically(">=", 100*time.Millisecond))
+		Expect(timeline.DurationIn("provisioning")).To(BeZero())
+
+		var transitions []string
+		for _, event := range timeline.Events() {
+			transitions = append(transitions, event.Transitioning)
+		}
+		Expect(transitions).To(Equal([]string{"no", "yes", "error", "yes", "no"}))
+	})
+
+	It("attaches the timeline to the report", func() {
+		Expect(timelines).To(HaveLen(1))
+		Expect(timelines[0].ClusterName).To(Equal("fake-timeline"))
+		Expect(timelines[0].Events).To(ContainElement(HaveField("TransitioningMessage", "node pool quota exceeded in region eastus")))
+	})
+
+	It("records the initial state like the watch events", func(ctx SpecContext) {
+		id := server.AddCluster(&management.Cluster{Name: "fake-timeline-initial"}, fakerancher.StateError("cannot remove node pool"))
+		// the events are built from the conditions only, whatever the API computed from them
+		Expect(server.UpdateCluster(id, func(cluster *management.Cluster) { cluster.TransitioningMessage = "" })).To(Succeed())
+		cluster, err := client.Management.Cluster.ByID(id)
+		Expect(err).To(BeNil())
+
+		timeline := helpers.StartClusterTimeline(ctx, client, cluster)
+		Expect(server.SetClusterState(id, fakerancher.StateActive)).To(Succeed())
+		Expect(timeline.WaitUntilEntered(ctx, "active", "", time.Second)).To(Succeed())
+		timeline.Stop()
+
+		// the first watch event has the state of the cluster when the timeline started, hence it is not recorded again
+		events := timeline.Events()
+		Expect(events).To(HaveLen(2))
+		Expect(events[0].Transitioning).To(Equal("error"))
+		Expect(timeline.EverEntered("error", "^cannot remove node pool$")).To(BeTrue())
+	})
+
+	It("reports the recorded events when the state is not entered in time", func(ctx SpecContext) {
+		id := server.AddCluster(&management.Cluster{Name: "fake-timeline-timeout"}, fakerancher.StateActive)
+		cluster, err := client.Management.Cluster.ByID(id)
+		Expect(err).To(BeNil())
+
+		timeline := helpers.StartClusterTimeline(ctx, client, cluster)
+		Expect(server.SetClusterState(id, fakerancher.StateUpdating)).To(Succeed())
+		err = timeline.WaitUntilEntered(ctx, "error", "", 200*time.Millisecond)
+		Expect(err).To(MatchError(ContainSubstring(`timed out after 200ms waiting for cluster fake-timeline-timeout to enter error with a message matching ""`)))
+		Expect(err).To(MatchError(ContainSubstring("updating transitioning=yes")))
+	})
+})
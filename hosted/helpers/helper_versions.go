@@ -0,0 +1,318 @@
+package helpers
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+	"github.com/rancher/shepherd/clients/rancher"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed assets/k8s-versions.yaml
+var defaultVersionCatalogue []byte
+
+const (
+	// VersionCatalogueSchemaVersion is the schemaVersion of the catalogue files supported by ParseVersionCatalogue
+	VersionCatalogueSchemaVersion = 1
+	// RancherCatalogueSource is the value of K8S_VERSION_CATALOGUE that builds the catalogue from the settings of the running Rancher, see RancherVersionCatalogue
+	RancherCatalogueSource = "rancher"
+	// uiSupportedVersionsSetting is the Rancher setting holding the range of the k8s versions offered for the hosted clusters
+	uiSupportedVersionsSetting = "ui-k8s-supported-versions-range"
+)
+
+// VersionCatalogue lists the k8s minor versions supported by the hosted providers per Rancher minor version
+type VersionCatalogue struct {
+	SchemaVersion int `json:"schemaVersion"`
+	// Providers are the entries of each provider, sorted by Rancher version
+	Providers map[string][]CatalogueEntry `json:"providers"`
+}
+
+// CatalogueEntry is the list of k8s minor versions supported by a provider from a Rancher minor version, in descending order
+type CatalogueEntry struct {
+	Rancher  string   `json:"rancher"`
+	Versions []string `json:"versions"`
+}
+
+// UpgradePath is the upgrade of a cluster from a k8s minor version to the next one
+type UpgradePath struct {
+	From string
+	To   string
+}
+
+func (p UpgradePath) String() string {
+	return fmt.Sprintf("%s -> %s", p.From, p.To)
+}
+
+// ParseVersionCatalogue parses and validates a catalogue file such as assets/k8s-versions.yaml
+func ParseVersionCatalogue(data []byte) (*VersionCatalogue, error) {
+	var catalogue VersionCatalogue
+	if err := yaml.UnmarshalStrict(data, &catalogue); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse the version catalogue")
+	}
+	if err := catalogue.Validate(); err != nil {
+		return nil, err
+	}
+	return &catalogue, nil
+}
+
+// Validate checks the schema version of the catalogue and the order of its entries and versions
+func (c *VersionCatalogue) Validate() error {
+	if c.SchemaVersion != VersionCatalogueSchemaVersion {
+		return fmt.Errorf("unsupported version catalogue schemaVersion %d, expected %d", c.SchemaVersion, VersionCatalogueSchemaVersion)
+	}
+	for provider, entries := range c.Providers {
+		var previous *semver.Version
+		for i, entry := range entries {
+			rancherVersion, err := ParseReleaseVersion(entry.Rancher)
+			if err != nil {
+				return errors.Wrapf(err, "Invalid %s entry of the version catalogue", provider)
+			}
+			if previous != nil && !rancherVersion.GreaterThan(previous) {
+				return fmt.Errorf("the %s entries of the version catalogue must be sorted by Rancher version, got %s after %s", provider, entry.Rancher, entries[i-1].Rancher)
+			}
+			previous = rancherVersion
+			if len(entry.Versions) == 0 {
+				return fmt.Errorf("the %s entry for Rancher %s of the version catalogue has no version", provider, entry.Rancher)
+			}
+			if err = checkDescending(entry.Versions); err != nil {
+				return errors.Wrapf(err, "Invalid %s entry for Rancher %s of the version catalogue", provider, entry.Rancher)
+			}
+		}
+	}
+	return nil
+}
+
+func checkDescending(versions []string) error {
+	var previous *semver.Version
+	for i, version := range versions {
+		parsed, err := ParseReleaseVersion(version)
+		if err != nil {
+			return err
+		}
+		if previous != nil && !parsed.LessThan(previous) {
+			return fmt.Errorf("versions must be in descending order, got %s after %s", version, versions[i-1])
+		}
+		previous = parsed
+	}
+	return nil
+}
+
+// entry returns the entry of the provider for the Rancher version: the entry of the same minor version or of the closest older one;
+// a Rancher version older than the first entry uses the first entry, and an unversioned build uses the last entry.
+func (c *VersionCatalogue) entry(provider, rancherVersion string) (CatalogueEntry, bool) {
+	entries := c.Providers[provider]
+	if len(entries) == 0 {
+		return CatalogueEntry{}, false
+	}
+	parsed, err := ParseReleaseVersion(rancherVersion)
+	if err != nil {
+		return entries[len(entries)-1], true
+	}
+	selected := entries[0]
+	for _, entry := range entries[1:] {
+		// the entries have been validated
+		entryVersion, _ := ParseReleaseVersion(entry.Rancher)
+		if entryVersion.Major() < parsed.Major() || (entryVersion.Major() == parsed.Major() && entryVersion.Minor() <= parsed.Minor()) {
+			selected = entry
+		}
+	}
+	return selected, true
+}
+
+// Versions returns the k8s minor versions supported by the provider on the Rancher version, in descending order
+func (c *VersionCatalogue) Versions(provider, rancherVersion string) ([]string, error) {
+	entry, ok := c.entry(provider, rancherVersion)
+	if !ok {
+		return nil, fmt.Errorf("the version catalogue has no k8s version for provider %s", provider)
+	}
+	return append([]string(nil), entry.Versions...), nil
+}
+
+// UpgradePaths returns the upgrades from a supported k8s minor version n to n+1, from the oldest version
+func (c *VersionCatalogue) UpgradePaths(provider, rancherVersion string) ([]UpgradePath, error) {
+	versions, err := c.Versions(provider, rancherVersion)
+	if err != nil {
+		return nil, err
+	}
+	var paths []UpgradePath
+	for i := len(versions) - 1; i > 0; i-- {
+		from, _ := ParseReleaseVersion(versions[i])
+		to, _ := ParseReleaseVersion(versions[i-1])
+		if to.Major() == from.Major() && to.Minor() == from.Minor()+1 {
+			paths = append(paths, UpgradePath{From: versions[i], To: versions[i-1]})
+		}
+	}
+	return paths, nil
+}
+
+// DefaultVersion returns the k8s version to be used by the test, as picked by DefaultK8sVersion
+func (c *VersionCatalogue) DefaultVersion(provider, rancherVersion string, forUpgrade bool) (string, error) {
+	versions, err := c.Versions(provider, rancherVersion)
+	if err != nil {
+		return "", err
+	}
+	return DefaultK8sVersion(versions, forUpgrade)
+}
+
+// Filter returns the versions, for e.g. 1.31.5, whose minor version is supported by the provider on the Rancher version;
+// it returns all the versions if the provider has no entry.
+func (c *VersionCatalogue) Filter(provider, rancherVersion string, versions []string) ([]string, error) {
+	entry, ok := c.entry(provider, rancherVersion)
+	if !ok {
+		return versions, nil
+	}
+	supported := map[string]bool{}
+	for _, version := range entry.Versions {
+		parsed, _ := ParseReleaseVersion(version)
+		supported[fmt.Sprintf("%d.%d", parsed.Major(), parsed.Minor())] = true
+	}
+	var filtered []string
+	for _, version := range versions {
+		parsed, err := ParseReleaseVersion(version)
+		if err != nil {
+			return nil, err
+		}
+		if supported[fmt.Sprintf("%d.%d", parsed.Major(), parsed.Minor())] {
+			filtered = append(filtered, version)
+		}
+	}
+	return filtered, nil
+}
+
+// ParseSupportedVersionsRange returns the k8s minor versions of a range such as the value of the `ui-k8s-supported-versions-range` setting,
+// for e.g. [1.32 1.31 1.30] for ">=v1.30.x <=v1.32.x", in descending order
+func ParseSupportedVersionsRange(versionsRange string) ([]string, error) {
+	var lower, upper *semver.Version
+	for _, bound := range strings.Fields(versionsRange) {
+		operator, version := bound[:min(2, len(bound))], strings.TrimSuffix(bound[min(2, len(bound)):], ".x")
+		parsed, err := ParseReleaseVersion(version)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid bound %q of the k8s versions range", bound)
+		}
+		switch operator {
+		case ">=":
+			lower = parsed
+		case "<=":
+			upper = parsed
+		default:
+			return nil, fmt.Errorf("invalid bound %q of the k8s versions range, expected >= or <=", bound)
+		}
+	}
+	if lower == nil || upper == nil {
+		return nil, fmt.Errorf("k8s versions range %q must have a lower and an upper bound", versionsRange)
+	}
+	if lower.Major() != upper.Major() || lower.Minor() > upper.Minor() {
+		return nil, fmt.Errorf("invalid k8s versions range %q", versionsRange)
+	}
+	var versions []string
+	for minor := upper.Minor(); minor >= lower.Minor(); minor-- {
+		versions = append(versions, fmt.Sprintf("%d.%d", upper.Major(), minor))
+		if minor == 0 {
+			break
+		}
+	}
+	return versions, nil
+}
+
+// RancherVersionCatalogue builds the catalogue of the running Rancher: the k8s minor versions offered for the hosted clusters
+// by its `ui-k8s-supported-versions-range` setting, with the same entry for each provider
+func RancherVersionCatalogue(client *rancher.Client, providers ...string) (*VersionCatalogue, error) {
+	serverVersion, err := GetRancherServerVersion(client)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get the server-version setting")
+	}
+	rancherVersion, err := ParseReleaseVersion(serverVersion)
+	if err != nil {
+		return nil, err
+	}
+	setting, err := client.Management.Setting.ByID(uiSupportedVersionsSetting)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the %s setting", uiSupportedVersionsSetting)
+	}
+	versions, err := ParseSupportedVersionsRange(setting.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := CatalogueEntry{Rancher: fmt.Sprintf("%d.%d", rancherVersion.Major(), rancherVersion.Minor()), Versions: versions}
+	catalogue := &VersionCatalogue{SchemaVersion: VersionCatalogueSchemaVersion, Providers: map[string][]CatalogueEntry{}}
+	for _, provider := range providers {
+		catalogue.Providers[provider] = []CatalogueEntry{entry}
+	}
+	return catalogue, nil
+}
+
+// LoadVersionCatalogue loads the catalogue selected by K8S_VERSION_CATALOGUE: the catalogue embedded from assets/k8s-versions.yaml if empty,
+// the catalogue of the running Rancher if set to RancherCatalogueSource, or a catalogue file otherwise
+func LoadVersionCatalogue(ctx context.Context, client *rancher.Client) (*VersionCatalogue, error) {
+	switch K8sVersionCatalogue {
+	case "":
+		return ParseVersionCatalogue(defaultVersionCatalogue)
+	case RancherCatalogueSource:
+		return RancherVersionCatalogue(client, "aks", "eks", "gke")
+	default:
+		data, err := os.ReadFile(K8sVersionCatalogue)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to read the version catalogue")
+		}
+		return ParseVersionCatalogue(data)
+	}
+}
+
+// ListCatalogueVersions returns the k8s minor versions of the catalogue supported by the provider on the running Rancher, in descending order
+func ListCatalogueVersions(ctx context.Context, client *rancher.Client, provider string) ([]string, error) {
+	catalogue, serverVersion, err := loadCatalogueForServer(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return catalogue.Versions(provider, serverVersion)
+}
+
+// FilterCatalogueVersions returns the versions supported by the provider on the running Rancher according to the catalogue
+func FilterCatalogueVersions(ctx context.Context, client *rancher.Client, provider string, versions []string) ([]string, error) {
+	catalogue, serverVersion, err := loadCatalogueForServer(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return catalogue.Filter(provider, serverVersion, versions)
+}
+
+// loadedCatalogue is the catalogue of a source along with the version of the Rancher server it is used for
+type loadedCatalogue struct {
+	once          sync.Once
+	catalogue     *VersionCatalogue
+	serverVersion string
+	err           error
+}
+
+var (
+	loadedCataloguesMu sync.Mutex
+	loadedCatalogues   = map[string]*loadedCatalogue{}
+)
+
+// loadCatalogueForServer returns the catalogue of K8S_VERSION_CATALOGUE and the Rancher server version; they are loaded only once per source
+func loadCatalogueForServer(ctx context.Context, client *rancher.Client) (*VersionCatalogue, string, error) {
+	loadedCataloguesMu.Lock()
+	loaded, ok := loadedCatalogues[K8sVersionCatalogue]
+	if !ok {
+		loaded = &loadedCatalogue{}
+		loadedCatalogues[K8sVersionCatalogue] = loaded
+	}
+	loadedCataloguesMu.Unlock()
+
+	loaded.once.Do(func() {
+		loaded.catalogue, loaded.err = LoadVersionCatalogue(ctx, client)
+		if loaded.err != nil {
+			return
+		}
+		loaded.serverVersion, loaded.err = GetRancherServerVersion(client)
+		loaded.err = errors.Wrap(loaded.err, "Failed to get the server-version setting")
+	})
+	return loaded.catalogue, loaded.serverVersion, loaded.err
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("VersionCatalogue", func() {
+	var catalogue *helpers.VersionCatalogue
+
+	BeforeEach(func() {
+		data, err := os.ReadFile("testdata/k8s-versions.yaml")
+		Expect(err).To(BeNil())
+		catalogue, err = helpers.ParseVersionCatalogue(data)
+		Expect(err).To(BeNil())
+	})
+
+	DescribeTable("selects the entry of the Rancher minor version",
+		func(rancherVersion string, expected []string) {
+			Expect(catalogue.Versions("eks", rancherVersion)).To(Equal(expected))
+		},
+		Entry("with the same minor", "v2.9.4", []string{"1.30", "1.29", "1.27"}),
+		Entry("with a minor between two entries", "v2.10.3", []string{"1.30", "1.29", "1.27"}),
+		Entry("with an older minor", "v2.8.0", []string{"1.30", "1.29", "1.27"}),
+		Entry("with a newer head build", "v2.13-3f2a1b-head", []string{"1.32"}),
+		Entry("with an unversioned build", "head", []string{"1.32"}),
+	)
+
+	It("returns the upgrade paths and the default versions", func() {
+		Expect(catalogue.UpgradePaths("eks", "v2.9.0")).To(Equal([]helpers.UpgradePath{{From: "1.29", To: "1.30"}}))
+		Expect(catalogue.UpgradePaths("aks", "v2.11.0")).To(Equal([]helpers.UpgradePath{{From: "1.30", To: "1.31"}, {From: "1.31", To: "1.32"}}))
+		Expect(catalogue.DefaultVersion("eks", "v2.9.0", false)).To(Equal("1.30"))
+		Expect(catalogue.DefaultVersion("eks", "v2.9.0", true)).To(Equal("1.29"))
+		_, err := catalogue.DefaultVersion("eks", "v2.11.0", true)
+		Expect(err).To(MatchError(ContainSubstring("no versions available for upgrade")))
+		_, err = catalogue.Versions("gke", "v2.11.0")
+		Expect(err).To(MatchError("the version catalogue has no k8s version for provider gke"))
+	})
+
+	It("filters the versions of the providers with entries only", func() {
+		versions := []string{"1.33.0", "1.32.1", "1.31.5-gke.100", "1.29.2"}
+		Expect(catalogue.Filter("aks", "v2.11.0", versions)).To(Equal([]string{"1.32.1", "1.31.5-gke.100"}))
+		Expect(catalogue.Filter("gke", "v2.11.0", versions)).To(Equal(versions))
+	})
+
+	DescribeTable("rejects an invalid catalogue",
+		func(data, message string) {
+			_, err := helpers.ParseVersionCatalogue([]byte(data))
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("with another schema version", "schemaVersion: 2", "unsupported version catalogue schemaVersion 2, expected 1"),
+		Entry("with an unknown field", "schemaVersion: 1\nprovider: {}", `unknown field "provider"`),
+		Entry("with unsorted entries", `{"schemaVersion": 1, "providers": {"eks": [{"rancher": "2.10", "versions": ["1.31"]}, {"rancher": "2.9", "versions": ["1.30"]}]}}`,
+			"the eks entries of the version catalogue must be sorted by Rancher version, got 2.9 after 2.10"),
+		Entry("with ascending versions", `{"schemaVersion": 1, "providers": {"eks": [{"rancher": "2.10", "versions": ["1.30", "1.31"]}]}}`,
+			"versions must be in descending order, got 1.31 after 1.30"),
+		Entry("with no version", `{"schemaVersion": 1, "providers": {"eks": [{"rancher": "2.10"}]}}`,
+			"the eks entry for Rancher 2.10 of the version catalogue has no version"),
+	)
+
+	It("embeds a valid default catalogue", func(ctx SpecContext) {
+		DeferCleanup(func(source string) { helpers.K8sVersionCatalogue = source }, helpers.K8sVersionCatalogue)
+		helpers.K8sVersionCatalogue = ""
+		defaultCatalogue, err := helpers.LoadVersionCatalogue(ctx, client)
+		Expect(err).To(BeNil())
+		Expect(defaultCatalogue.Versions("eks", "v2.7.15")).To(Equal([]string{"1.27", "1.26", "1.25", "1.24"}))
+		Expect(helpers.ListCatalogueVersions(ctx, client, "eks")).To(Equal([]string{"1.32", "1.31", "1.30"}))
+	})
+})
+
+var _ = Describe("RancherVersionCatalogue", func() {
+	DescribeTable("ParseSupportedVersionsRange",
+		func(versionsRange string, expected []string) {
+			Expect(helpers.ParseSupportedVersionsRange(versionsRange)).To(Equal(expected))
+		},
+		Entry("with a range of several minors", ">=v1.30.x <=v1.32.x", []string{"1.32", "1.31", "1.30"}),
+		Entry("with a single minor", ">=v1.31.x <=v1.31.x", []string{"1.31"}),
+	)
+
+	DescribeTable("ParseSupportedVersionsRange fails",
+		func(versionsRange, expectedError string) {
+			_, err := helpers.ParseSupportedVersionsRange(versionsRange)
+			Expect(err).To(MatchError(ContainSubstring(expectedError)))
+		},
+		Entry("without upper bound", ">=v1.30.x", "must have a lower and an upper bound"),
+		Entry("with an unknown operator", ">v1.30.x <=v1.32.x", `invalid bound ">v1.30.x"`),
+		Entry("with reversed bounds", ">=v1.32.x <=v1.30.x", "invalid k8s versions range"),
+	)
+
+	It("loads the k8s versions offered by the running Rancher", func(ctx SpecContext) {
+		Expect(helpers.OverrideSetting(client, "ui-k8s-supported-versions-range", ">=v1.31.x <=v1.33.x")).To(Succeed())
+		DeferCleanup(func(source string) { helpers.K8sVersionCatalogue = source }, helpers.K8sVersionCatalogue)
+		helpers.K8sVersionCatalogue = helpers.RancherCatalogueSource
+
+		Expect(helpers.ListCatalogueVersions(ctx, client, "eks")).To(Equal([]string{"1.33", "1.32", "1.31"}))
+		Expect(helpers.FilterCatalogueVersions(ctx, client, "gke", []string{"1.32.2-gke.1", "1.30.9-gke.2"})).To(Equal([]string{"1.32.2-gke.1"}))
+
+		By("loading the catalogue only once", func() {
+			Expect(helpers.OverrideSetting(client, "ui-k8s-supported-versions-range", ">=v1.30.x <=v1.30.x")).To(Succeed())
+			Expect(helpers.ListCatalogueVersions(ctx, client, "aks")).To(Equal([]string{"1.33", "1.32", "1.31"}))
+		})
+	})
+})
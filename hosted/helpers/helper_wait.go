@@ -0,0 +1,129 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/defaults"
+	"github.com/rancher/shepherd/pkg/wait"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Logger is the logger of the helpers that return errors instead of asserting, so that they can be used outside a Ginkgo spec;
+// the common suite setups replace it with ginkgo.GinkgoLogr so that the logs are attached to the specs
+var Logger = logr.FromSlogHandler(slog.NewTextHandler(os.Stdout, nil))
+
+// ConfigMismatchError is returned when a field of a cluster does not have the expected value after an update
+type ConfigMismatchError struct {
+	ClusterName string
+	// Field is the path of the field, for e.g. AKSConfig.KubernetesVersion or AKSStatus.UpstreamSpec.NodePools[np1].Count
+	Field    string
+	Expected any
+	Actual   any
+}
+
+func (e *ConfigMismatchError) Error() string {
+	return fmt.Sprintf("cluster %s: %s is %v, expected %v", e.ClusterName, e.Field, e.Actual, e.Expected)
+}
+
+// CheckField returns a ConfigMismatchError if the actual value of the cluster field is not the expected one;
+// the values must have the same type, pointers are compared by the value they point to
+func CheckField(cluster *management.Cluster, field string, expected, actual any) error {
+	if reflect.DeepEqual(expected, actual) {
+		return nil
+	}
+	return &ConfigMismatchError{ClusterName: cluster.Name, Field: field, Expected: indirect(expected), Actual: indirect(actual)}
+}
+
+// indirect returns the value pointed to by v so that the errors do not show addresses
+func indirect(v any) any {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return v
+	}
+	return value.Elem().Interface()
+}
+
+// TimeoutError is returned by WaitFor when its condition is not met in time; it wraps the last error returned by the condition, if any
+type TimeoutError struct {
+	Description string
+	Timeout     time.Duration
+	Err         error
+}
+
+func (e *TimeoutError) Error() string {
+	message := fmt.Sprintf("timed out after %s waiting for %s", e.Timeout, e.Description)
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// WaitFor polls the condition every interval until it returns true or the timeout, scaled as per tools.SetTimeout, expires;
+// the condition is polled again after returning an error, the last one is wrapped in the returned TimeoutError.
+// It stops as soon as ctx is cancelled and returns the context error, wrapped along with the last error of the condition.
+func WaitFor(ctx context.Context, description string, timeout, interval time.Duration, condition func() (bool, error)) error {
+	timeout = tools.SetTimeout(timeout)
+	deadline := time.Now().Add(timeout)
+	for {
+		Logger.Info(fmt.Sprintf("Waiting for %s ...", description))
+		done, err := condition()
+		if done && err == nil {
+			return nil
+		}
+		if time.Now().Add(interval).After(deadline) {
+			return &TimeoutError{Description: description, Timeout: timeout, Err: err}
+		}
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("stopped waiting for %s: %w (last error: %v)", description, context.Cause(ctx), err)
+			}
+			return fmt.Errorf("stopped waiting for %s: %w", description, context.Cause(ctx))
+		case <-time.After(interval):
+		}
+	}
+}
+
+// clusterWatchOptions returns the options of a watch on the cluster; the watch ends at the deadline of ctx if it is sooner than the default watch timeout
+func clusterWatchOptions(ctx context.Context, clusterID string) metav1.ListOptions {
+	timeoutSeconds := defaults.WatchTimeoutSeconds
+	if deadline, ok := ctx.Deadline(); ok {
+		timeoutSeconds = min(timeoutSeconds, max(int64(time.Until(deadline).Seconds()), 1))
+	}
+	return metav1.ListOptions{FieldSelector: "metadata.name=" + clusterID, TimeoutSeconds: &timeoutSeconds}
+}
+
+// watchWait is wait.WatchWait which also stops the watch and returns the context error once ctx is cancelled
+func watchWait(ctx context.Context, watchInterface watch.Interface, check wait.WatchCheckFunc) error {
+	defer watchInterface.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case event, open := <-watchInterface.ResultChan():
+			if !open {
+				return fmt.Errorf(wait.TimeoutError)
+			}
+			if event.Type == watch.Error {
+				return fmt.Errorf(wait.WatchConnectionError)
+			}
+			done, err := check(event)
+			if err != nil || done {
+				return err
+			}
+		}
+	}
+}
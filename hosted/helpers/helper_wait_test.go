@@ -0,0 +1,85 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("WaitFor", func() {
+	cluster := &management.Cluster{Name: "fake-wait"}
+
+	It("returns once the condition is met", func(ctx SpecContext) {
+		polls := 0
+		err := helpers.WaitFor(ctx, "the third poll", time.Second, time.Millisecond, func() (bool, error) {
+			polls++
+			return polls == 3, nil
+		})
+		Expect(err).To(BeNil())
+		Expect(polls).To(Equal(3))
+	})
+
+	It("wraps the last error of the condition on timeout", func(ctx SpecContext) {
+		err := helpers.WaitFor(ctx, "the node count", 50*time.Millisecond, 10*time.Millisecond, func() (bool, error) {
+			return false, helpers.CheckField(cluster, "AKSConfig.NodePools[np].Count", pointer.Int64(3), pointer.Int64(1))
+		})
+
+		var timeoutErr *helpers.TimeoutError
+		Expect(errors.As(err, &timeoutErr)).To(BeTrue())
+		Expect(timeoutErr.Description).To(Equal("the node count"))
+		var mismatchErr *helpers.ConfigMismatchError
+		Expect(errors.As(err, &mismatchErr)).To(BeTrue())
+		Expect(mismatchErr.Field).To(Equal("AKSConfig.NodePools[np].Count"))
+		Expect(err).To(MatchError(ContainSubstring("cluster fake-wait: AKSConfig.NodePools[np].Count is 1, expected 3")))
+	})
+
+	It("stops as soon as the context is cancelled", func(ctx SpecContext) {
+		cancelCtx, cancel := context.WithCancel(ctx)
+		polls := 0
+		err := helpers.WaitFor(cancelCtx, "the cancelled poll", time.Hour, time.Minute, func() (bool, error) {
+			polls++
+			cancel()
+			return false, nil
+		})
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(err).To(MatchError(ContainSubstring("stopped waiting for the cancelled poll")))
+		Expect(polls).To(Equal(1))
+	})
+})
+
+var _ = Describe("CheckField", func() {
+	It("compares the pointers by the value they point to", func() {
+		cluster := &management.Cluster{Name: "fake-check"}
+		Expect(helpers.CheckField(cluster, "AKSConfig.KubernetesVersion", pointer.String("1.31.4"), pointer.String("1.31.4"))).To(Succeed())
+		Expect(helpers.CheckField(cluster, "AKSConfig.KubernetesVersion", pointer.String("1.32.1"), pointer.String("1.31.4"))).To(
+			MatchError("cluster fake-check: AKSConfig.KubernetesVersion is 1.31.4, expected 1.32.1"))
+	})
+})
+
+var _ = Describe("VersionCompare", func() {
+	It("returns an error for an invalid version", func() {
+		_, err := helpers.VersionCompare("1.2.3", "not-a-version")
+		Expect(err).To(HaveOccurred())
+	})
+})
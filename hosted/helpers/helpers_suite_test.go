@@ -0,0 +1,64 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/shepherd/clients/rancher"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fakerancher"
+	// installs the Ginkgo hooks so that the teardowns registered by the helpers run when the specs end
+	_ "github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
+)
+
+var (
+	server     *fakerancher.Server
+	client     *rancher.Client
+	configPath string
+)
+
+func TestHelpers(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Helpers Suite")
+}
+
+var _ = BeforeSuite(func() {
+	server = fakerancher.NewServer()
+
+	var err error
+	configPath, err = server.WriteConfig(map[string]any{
+		"azureCredentials":  map[string]any{"clientId": "id", "clientSecret": "secret", "subscriptionId": "subscription", "environment": "AzurePublicCloud"},
+		"awsCredentials":    map[string]any{"accessKey": "access", "secretKey": "secret", "defaultRegion": "us-west-2"},
+		"googleCredentials": map[string]any{"authEncodedJson": "{}"},
+		// only gke has a budget so that the other specs never exceed it
+		"lifecycleBudgets": map[string]any{"tolerance": 0, "baselines": map[string]any{"gke": map[string]any{"provision": "1ms"}}},
+	})
+	Expect(err).To(BeNil())
+
+	client, err = server.NewClient()
+	Expect(err).To(BeNil())
+	// no operator is installed, the operator errors are matched against this version
+	helpers.SetOperatorVersion(fakeOperatorVersion)
+})
+
+var _ = AfterSuite(func() {
+	server.Close()
+	Expect(os.Remove(configPath)).To(Succeed())
+})
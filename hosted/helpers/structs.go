@@ -2,10 +2,7 @@ package helpers
 
 import (
 	"fmt"
-	"os"
 	"os/user"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/rancher/shepherd/clients/rancher"
@@ -17,42 +14,29 @@ const (
 	CattleSystemNS = "cattle-system"
 )
 
+// The variables below are the values of Config used throughout the specs; the tests can override them
 var (
-	RancherPassword   = os.Getenv("RANCHER_PASSWORD")
-	RancherHostname   = os.Getenv("RANCHER_HOSTNAME")
-	Provider          = os.Getenv("PROVIDER")
-	testuser, _       = user.Current()
-	clusterCleanup, _ = strconv.ParseBool(os.Getenv("DOWNSTREAM_CLUSTER_CLEANUP"))
-	ClusterNamePrefix = func() string {
-		if clusterCleanup {
-			return fmt.Sprintf("%s-hp-ci", Provider)
-		} else {
-			return fmt.Sprintf("%s-%s-hp-ci", Provider, testuser.Username)
-		}
-	}()
-	RancherFullVersion        = os.Getenv("RANCHER_VERSION")
-	RancherUpgradeFullVersion = os.Getenv("RANCHER_UPGRADE_VERSION")
-	Kubeconfig                = os.Getenv("KUBECONFIG")
+	RancherPassword           = Config.Rancher.Password
+	RancherHostname           = Config.Rancher.Hostname
+	Provider                  = Config.Provider
+	testuser, _               = user.Current()
+	clusterCleanup            = Config.DownstreamClusterCleanup
+	ClusterNamePrefix         = ClusterNamePrefixFor(Provider)
+	RancherFullVersion        = Config.Rancher.Version
+	RancherUpgradeFullVersion = Config.Rancher.UpgradeVersion
+	Kubeconfig                = Config.Rancher.Kubeconfig
 	DownstreamKubeconfig      = func(clusterName string) string {
 		return fmt.Sprintf("%s_KUBECONFIG", clusterName)
 	}
-	K8sUpgradedMinorVersion   = os.Getenv("K8S_UPGRADE_MINOR_VERSION")
-	DownstreamK8sMinorVersion = os.Getenv("DOWNSTREAM_K8S_MINOR_VERSION")
-	IsImport                  = func() bool {
-		if strings.Contains(os.Getenv("CATTLE_TEST_CONFIG"), "import") {
-			return true
-		}
-		return false
-	}()
-	SkipUpgradeTests = func() bool {
-		// Skip upgrade tests since lowest k8s version not available
-		return strings.Contains((RancherFullVersion), "2.8")
-	}()
-	SkipTest = func() bool {
-		// Some features not available on v2.8, v2.9
-		return strings.Contains((RancherFullVersion), "2.8") || strings.Contains((RancherFullVersion), "2.9")
-	}()
-	SkipUpgradeTestsLog = "Skipping upgrade tests since only one minor k8s version is supported by the current rancher version ..."
+	K8sUpgradedMinorVersion   = Config.K8sUpgradeMinorVersion
+	DownstreamK8sMinorVersion = Config.DownstreamK8sMinorVersion
+	IsImport                  = Config.IsImport()
+	// CloudBackend selects how the *OnAzure, *OnAWS and *OnGCloud helpers reach the cloud; see UseCloudSDK
+	CloudBackend = Config.CloudBackend
+	// RunReportDir is the directory in which suite.WriteRunReport writes a JSON report per spec; no report is written if it is empty
+	RunReportDir = Config.RunReportDir
+	// K8sVersionCatalogue is the source of the k8s versions supported per provider and Rancher version; see LoadVersionCatalogue
+	K8sVersionCatalogue = Config.K8sVersionCatalogue
 )
 
 type HelmChart struct {
@@ -68,6 +52,8 @@ type RancherContext struct {
 	Session            *session.Session
 	ClusterCleanup     bool
 	CloudCredID        string
+	// Cleanups holds the teardown of the resources created by the helpers; see CleanupRegistry
+	Cleanups *CleanupRegistry
 }
 
 type RancherVersionInfo struct {
@@ -76,3 +62,37 @@ type RancherVersionInfo struct {
 	RancherPrime string
 	Devel        bool
 }
+
+// SpecResult is the machine-readable report of a spec written by suite.WriteRunReport
+type SpecResult struct {
+	Provider             string            `json:"provider"`
+	Import               bool              `json:"import"`
+	RancherVersion       string            `json:"rancherVersion,omitempty"`
+	OperatorChartVersion string            `json:"operatorChartVersion,omitempty"`
+	K8sVersion           string            `json:"k8sVersion,omitempty"`
+	ClusterName          string            `json:"clusterName,omitempty"`
+	QaseID               int64             `json:"qaseID,omitempty"`
+	Spec                 string            `json:"spec"`
+	Labels               []string          `json:"labels,omitempty"`
+	State                string            `json:"state"`
+	StartTime            time.Time         `json:"startTime"`
+	EndTime              time.Time         `json:"endTime"`
+	Duration             time.Duration     `json:"duration"`
+	Steps                []StepTiming      `json:"steps,omitempty"`
+	Operations           []OperationTiming `json:"operations,omitempty"`
+	FailureMessage       string            `json:"failureMessage,omitempty"`
+	FailureLocation      string            `json:"failureLocation,omitempty"`
+	// SkipReason is the message of Skip, for e.g. the feature gate not satisfied by the versions under test
+	SkipReason string `json:"skipReason,omitempty"`
+	// DiagnosticsBundle is the name of the tarball written next to the report if the spec failed; see CollectDiagnostics
+	DiagnosticsBundle string `json:"diagnosticsBundle,omitempty"`
+	// Timelines are the transitions of the clusters recorded by StartClusterTimeline
+	Timelines []ClusterTimelineReport `json:"timelines,omitempty"`
+}
+
+// StepTiming is the duration of a `By` step of a spec
+type StepTiming struct {
+	Text      string        `json:"text"`
+	StartTime time.Time     `json:"startTime"`
+	Duration  time.Duration `json:"duration"`
+}
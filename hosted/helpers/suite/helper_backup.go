@@ -0,0 +1,245 @@
+package suite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+	"github.com/rancher-sandbox/ele-testhelpers/rancher"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+/*
+Install Backup Operator
+  - @param ctx, stops the installation once cancelled
+  - @param k kubectl structure
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func InstallBackupOperator(ctx context.Context, k *kubectl.Kubectl) {
+	// Set specific operator version if defined
+	backupRestoreVersion := helpers.Config.Install.BackupOperatorVersion
+	chartRepo := "rancher-chart"
+	if backupRestoreVersion != "" {
+		chartRepo = "https://github.com/rancher/backup-restore-operator/releases/download/" + backupRestoreVersion
+	} else {
+		RunHelmCmdWithRetry(ctx, "repo", "add", chartRepo, "https://charts.rancher.io")
+		RunHelmCmdWithRetry(ctx, "repo", "update")
+	}
+
+	for _, chart := range []string{"rancher-backup-crd", "rancher-backup"} {
+		// Set the filename in chart if a custom version is defined
+		chartName := chart
+		if backupRestoreVersion != "" {
+			chartName = chart + "-" + strings.TrimPrefix(backupRestoreVersion, "v") + ".tgz"
+		}
+
+		// Global installation flags
+		flags := []string{
+			"upgrade", "--install", chart, chartRepo + "/" + chartName,
+			"--namespace", helpers.BackupNamespace,
+			"--create-namespace",
+			"--wait", "--wait-for-jobs",
+		}
+
+		// Add specific options for the rancher-backup chart
+		if chart == "rancher-backup" {
+			if helpers.Config.Backup.Storage == helpers.BackupStorageS3 {
+				storage, err := helpers.GetS3Storage(ctx)
+				Expect(err).To(Not(HaveOccurred()))
+				Expect(storage.CreateCredentialSecret(ctx)).To(Succeed())
+				flags = append(flags, storage.ChartValues()...)
+			} else {
+				flags = append(flags,
+					"--set", "persistence.enabled=true",
+					"--set", "persistence.storageClass=local-path",
+				)
+			}
+		}
+
+		RunHelmCmdWithRetry(ctx, flags...)
+
+		Eventually(ctx, func() error {
+			return rancher.CheckPod(k, [][]string{{"cattle-resources-system", "app.kubernetes.io/name=rancher-backup"}})
+		}, tools.SetTimeout(5*time.Minute), 30*time.Second).Should(Not(HaveOccurred()))
+	}
+}
+
+/*
+Check Backup or Restore
+  - @param ctx, stops the waits once cancelled
+  - @param resourceName, Backup/Restore name
+  - @param message, string to check in operator logs
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckOperation(ctx context.Context, resourceName string, message string) {
+	var operation string
+	if strings.Contains(resourceName, "backup") {
+		operation = "backup"
+	} else {
+		operation = "restore"
+	}
+	Eventually(ctx, func() string {
+		out, _ := kubectl.RunWithoutErr("get", operation, resourceName,
+			"-o", "jsonpath={.metadata.name}")
+		return out
+	}, tools.SetTimeout(5*time.Minute), 10*time.Second).Should(ContainSubstring(resourceName))
+
+	// Wait for operation to be done
+	Eventually(ctx, func() string {
+		out, _ := kubectl.RunWithoutErr("logs", "-l app.kubernetes.io/name=rancher-backup",
+			"--tail=-1", "--since=5m",
+			"--namespace", "cattle-resources-system")
+		return out
+	}, tools.SetTimeout(5*time.Minute), 10*time.Second).Should(ContainSubstring(message))
+}
+
+/*
+Execute Backup
+  - @param ctx, stops the installation and the waits once cancelled
+  - @param k kubectl structure
+  - @param backup, Backup resource to apply, for e.g. NewBackup
+  - @returns Backup file
+*/
+func ExecuteBackup(ctx context.Context, k *kubectl.Kubectl, backup *helpers.Backup) string {
+	var err error
+	var backupFile string
+
+	By("Installing rancher-backup-operator", func() {
+		InstallBackupOperator(ctx, k)
+		Expect(helpers.ApplyEncryptionConfig(ctx, backup.EncryptionConfigSecretName)).To(Succeed())
+	})
+
+	By("Adding a backup resource", func() {
+		manifest, err := backup.Manifest()
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(helpers.ApplyManifest(ctx, manifest)).To(Succeed())
+	})
+
+	By("Checking that the backup has been done", func() {
+		CheckOperation(ctx, backup.Name, "Done with backup")
+	})
+
+	// Get the backup file from the previous backup
+	backupFile, err = kubectl.RunWithoutErr("get", "backup", backup.Name, "-o", "jsonpath={.status.filename}")
+	Expect(err).To(Not(HaveOccurred()))
+	if helpers.Config.Backup.Storage == helpers.BackupStorageS3 {
+		// the backup stays in the bucket, from which the restore reads it
+		return backupFile
+	}
+
+	By("Copying the backup file", func() {
+
+		// Get local storage path
+		localPath, err := helpers.GetLocalPath(ctx)
+		Expect(err).To(Not(HaveOccurred()))
+
+		// Copy backup file
+		out, err := helpers.RunCLI(ctx, "sudo", "cp", localPath+"/"+backupFile, ".")
+		Expect(err).To(Not(HaveOccurred()), out)
+	})
+	return backupFile
+}
+
+/*
+Execute Scheduled Backup
+  - @param ctx, stops the installation and the waits once cancelled
+  - @param k kubectl structure
+  - @param backup, Backup resource with a Schedule and a RetentionCount
+  - @param snapshots, number of backups to wait for, usually greater than RetentionCount to check the retention
+  - @returns Retained backup files, oldest first
+*/
+func ExecuteScheduledBackup(ctx context.Context, k *kubectl.Kubectl, backup *helpers.Backup, snapshots int) []string {
+	Expect(backup.Schedule).NotTo(BeEmpty(), "backup %s has no schedule", backup.Name)
+	var backupFiles []string
+
+	By("Installing rancher-backup-operator", func() {
+		InstallBackupOperator(ctx, k)
+		Expect(helpers.ApplyEncryptionConfig(ctx, backup.EncryptionConfigSecretName)).To(Succeed())
+	})
+
+	By("Adding a scheduled backup resource", func() {
+		manifest, err := backup.Manifest()
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(helpers.ApplyManifest(ctx, manifest)).To(Succeed())
+	})
+
+	By(fmt.Sprintf("Waiting for %d backups to be taken", snapshots), func() {
+		// the status holds the file of the last backup only
+		taken := map[string]bool{}
+		Eventually(ctx, func() int {
+			out, _ := kubectl.RunWithoutErr("get", "backup", backup.Name, "-o", "jsonpath={.status.filename}")
+			if out != "" {
+				taken[out] = true
+			}
+			return len(taken)
+		}, tools.SetTimeout(time.Duration(snapshots)*5*time.Minute), 10*time.Second).Should(BeNumerically(">=", snapshots))
+	})
+
+	By("Stopping the schedule", func() {
+		// the retained backups must not be pruned by a later backup before the restore
+		_, err := kubectl.RunWithoutErr("delete", "backup", backup.Name)
+		Expect(err).To(Not(HaveOccurred()))
+	})
+
+	By("Checking the retention of the backups", func() {
+		Eventually(ctx, func() ([]string, error) {
+			var err error
+			backupFiles, err = helpers.ListBackupFiles(ctx, backup.Name)
+			return backupFiles, err
+		}, tools.SetTimeout(2*time.Minute), 10*time.Second).Should(HaveLen(min(snapshots, backup.RetentionCount)))
+	})
+
+	if helpers.Config.Backup.Storage == helpers.BackupStorageLocal {
+		By("Copying the backup files", func() {
+			localPath, err := helpers.GetLocalPath(ctx)
+			Expect(err).To(Not(HaveOccurred()))
+			for _, backupFile := range backupFiles {
+				out, err := helpers.RunCLI(ctx, "sudo", "cp", localPath+"/"+backupFile, ".")
+				Expect(err).To(Not(HaveOccurred()), out)
+			}
+		})
+	}
+	return backupFiles
+}
+
+/*
+Execute Restore
+  - @param ctx, stops the installation and the waits once cancelled
+  - @param k kubectl structure
+  - @param restore, Restore resource to apply, for e.g. NewRestore with the file returned by ExecuteBackup
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func ExecuteRestore(ctx context.Context, k *kubectl.Kubectl, restore *helpers.Restore) {
+	By("Installing rancher-backup-operator", func() {
+		InstallBackupOperator(ctx, k)
+		Expect(helpers.ApplyEncryptionConfig(ctx, restore.EncryptionConfigSecretName)).To(Succeed())
+	})
+
+	if helpers.Config.Backup.Storage == helpers.BackupStorageLocal {
+		By("Copying backup file to restore", func() {
+			// Get local storage path
+			localPath, err := helpers.GetLocalPath(ctx)
+			Expect(err).To(Not(HaveOccurred()))
+
+			// Copy backup file
+			out, err := helpers.RunCLI(ctx, "sudo", "cp", restore.BackupFilename, localPath)
+			Expect(err).To(Not(HaveOccurred()), out)
+		})
+	}
+
+	By("Adding a restore resource", func() {
+		manifest, err := restore.Manifest()
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(helpers.ApplyManifest(ctx, manifest)).To(Succeed())
+	})
+
+	By("Checking that the restore has been done", func() {
+		CheckOperation(ctx, restore.Name, "Done restoring")
+	})
+}
@@ -0,0 +1,257 @@
+package suite
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+const (
+	backupResourceName  = "hp-backup"
+	restoreResourceName = "hp-restore"
+	// the encrypted scheduled backups keep backupRetentionCount backups
+	encryptionSecretName = "hp-backup-encryption"
+	backupSchedule       = "@every 2m"
+	backupRetentionCount = 2
+	// migrationReportEntry is the name of the report entry stating where the migration specs restored Rancher
+	migrationReportEntry = "Migration"
+)
+
+// BackupRestoreSpec is the hosted cluster checked by the backup-restore suites of the providers
+type BackupRestoreSpec struct {
+	Kubectl *kubectl.Kubectl
+	// Client is switched in place to the migrated Rancher by MigrationBackupRestoreChecks
+	Client          *rancher.Client
+	CloudCredential string
+	Cluster         *management.Cluster
+	ClusterName     string
+	// NodesChecks checks that the restored cluster can still be modified, for e.g. by scaling up and adding a nodepool
+	NodesChecks func(ctx context.Context, cluster *management.Cluster, client *rancher.Client, clusterName string)
+}
+
+/*
+Backup the Rancher resources, restore them on a new k3s and check the hosted cluster
+  - @param ctx, stops the checks once cancelled
+  - @param spec the hosted cluster
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func BackupRestoreChecks(ctx context.Context, spec BackupRestoreSpec) {
+	By("Checking hosted cluster is ready", func() {
+		ClusterIsReadyChecks(ctx, spec.Cluster, spec.Client, spec.ClusterName)
+	})
+
+	before := snapshotHostedClusterResources(ctx, spec)
+	var backupFile string
+	By("Performing a backup", func() {
+		backupFile = ExecuteBackup(ctx, spec.Kubectl, helpers.NewBackup(backupResourceName))
+	})
+
+	// the backups of BACKUP_S3_ENDPOINT cannot be read
+	if helpers.Config.Backup.Storage != helpers.BackupStorageS3 || helpers.Config.Backup.S3Endpoint == "" {
+		By("Checking the cloud credential is in plain text in the unencrypted backup", func() {
+			cloudCredentialInBackupsChecks(ctx, spec, true, backupFile)
+		})
+	}
+
+	restoreChecks(ctx, spec, helpers.NewRestore(restoreResourceName, backupFile), helpers.RancherHostname, before)
+	restoredClusterChecks(ctx, spec)
+}
+
+/*
+Take encrypted scheduled backups of the Rancher resources, restore one of the retained backups on a new k3s and check the hosted cluster
+  - @param ctx, stops the checks once cancelled
+  - @param spec the hosted cluster
+  - @param restoredBackup the index of the restored backup among the retained ones, oldest first; it must be lower than the retention count
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func EncryptedScheduledBackupRestoreChecks(ctx context.Context, spec BackupRestoreSpec, restoredBackup int) {
+	if helpers.Config.Backup.Storage == helpers.BackupStorageS3 && helpers.Config.Backup.S3Endpoint != "" {
+		Skip("the backups of BACKUP_S3_ENDPOINT can neither be listed nor read, the encrypted scheduled backups require the local MinIO or the local storage")
+	}
+	Expect(restoredBackup).To(And(BeNumerically(">=", 0), BeNumerically("<", backupRetentionCount)),
+		"the restored backup must be one of the %d retained backups", backupRetentionCount)
+
+	By("Checking hosted cluster is ready", func() {
+		ClusterIsReadyChecks(ctx, spec.Cluster, spec.Client, spec.ClusterName)
+	})
+
+	encryption, err := helpers.NewEncryptionConfig(encryptionSecretName)
+	Expect(err).To(BeNil())
+	backup := helpers.NewBackup(backupResourceName)
+	backup.Schedule = backupSchedule
+	backup.RetentionCount = backupRetentionCount
+	backup.EncryptionConfigSecretName = encryption.SecretName
+
+	before := snapshotHostedClusterResources(ctx, spec)
+	var backupFiles []string
+	By("Performing encrypted scheduled backups", func() {
+		backupFiles = ExecuteScheduledBackup(ctx, spec.Kubectl, backup, backupRetentionCount+1)
+	})
+
+	By("Checking the cloud credential is encrypted in the backups", func() {
+		cloudCredentialInBackupsChecks(ctx, spec, false, backupFiles...)
+	})
+
+	restore := helpers.NewRestore(restoreResourceName, backupFiles[restoredBackup])
+	restore.EncryptionConfigSecretName = encryption.SecretName
+	restoreChecks(ctx, spec, restore, helpers.RancherHostname, before)
+	restoredClusterChecks(ctx, spec)
+}
+
+/*
+Backup the Rancher resources and restore them with Rancher installed on MigrationHostname, on the upstream cluster of RANCHER_MIGRATION_KUBECONFIG
+once the original Rancher is stopped. If RANCHER_MIGRATION_KUBECONFIG is not set, k3s is reinstalled on the same node instead,
+so that only the hostname of Rancher changes but not its node nor its address; the limitation is added to the spec report
+  - @param ctx, stops the checks once cancelled
+  - @param spec the hosted cluster; spec.Client points to the migrated Rancher until the spec ends
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func MigrationBackupRestoreChecks(ctx context.Context, spec BackupRestoreSpec) {
+	By("Checking hosted cluster is ready", func() {
+		ClusterIsReadyChecks(ctx, spec.Cluster, spec.Client, spec.ClusterName)
+	})
+
+	var clusterConfigSpec map[string]any
+	By("Getting the cluster config", func() {
+		var err error
+		clusterConfigSpec, err = helpers.GetClusterConfigSpec(ctx, spec.Cluster)
+		Expect(err).To(BeNil())
+	})
+
+	before := snapshotHostedClusterResources(ctx, spec)
+	var backupFile string
+	By("Performing a backup", func() {
+		backupFile = ExecuteBackup(ctx, spec.Kubectl, helpers.NewBackup(backupResourceName))
+	})
+
+	migrationHostname := helpers.MigrationHostname()
+	restore := helpers.NewRestore(restoreResourceName, backupFile)
+	if target := helpers.Config.Rancher.MigrationKubeconfig; target != "" {
+		By("Stopping the original Rancher", func() {
+			Expect(helpers.StopRancher(ctx)).To(Succeed())
+		})
+
+		By("Switching to the target upstream", func() {
+			Expect(helpers.SwitchUpstream(target)).To(Succeed())
+		})
+
+		restoreRancherChecks(ctx, spec, restore, migrationHostname, before)
+	} else {
+		AddReportEntry(migrationReportEntry, "RANCHER_MIGRATION_KUBECONFIG is not set: Rancher is restored on the same node and only its hostname changes")
+		restoreChecks(ctx, spec, restore, migrationHostname, before)
+	}
+
+	By("Switching to the migrated Rancher", func() {
+		Expect(helpers.SwitchRancherHost(spec.Client, migrationHostname)).To(Succeed())
+		Expect(helpers.SetServerURL(spec.Client, "https://"+migrationHostname)).To(Succeed())
+	})
+
+	By("Checking the cluster agent connects to the migrated Rancher", func() {
+		Expect(helpers.WaitForClusterAgentServerURL(ctx, spec.Client, spec.Cluster, "https://"+migrationHostname, 15*time.Minute)).To(Succeed())
+	})
+
+	By("Checking the cluster config is intact", func() {
+		clusterConfig, err := helpers.GetClusterConfigSpec(ctx, spec.Cluster)
+		Expect(err).To(BeNil())
+		Expect(clusterConfig).To(Equal(clusterConfigSpec))
+	})
+
+	restoredClusterChecks(ctx, spec)
+}
+
+// restoreChecks restores the backup on a new k3s with Rancher installed on rancherHostname, and checks that the hosted cluster resources are those of before
+func restoreChecks(ctx context.Context, spec BackupRestoreSpec, restore *helpers.Restore, rancherHostname string, before *helpers.ResourceSnapshot) {
+	By("Perform restore pre-requisites: Uninstalling k3s", func() {
+		out, err := helpers.RunCLI(ctx, "k3s-uninstall.sh")
+		Expect(err).To(Not(HaveOccurred()), out)
+	})
+
+	By("Perform restore pre-requisites: Getting k3s ready", func() {
+		InstallK3S(ctx, spec.Kubectl, helpers.Config.Install.K3sVersion, "none", "none")
+	})
+
+	restoreRancherChecks(ctx, spec, restore, rancherHostname, before)
+}
+
+// restoreRancherChecks restores the backup on the current upstream and installs Rancher on rancherHostname, and checks that the hosted cluster
+// resources are those of before
+func restoreRancherChecks(ctx context.Context, spec BackupRestoreSpec, restore *helpers.Restore, rancherHostname string, before *helpers.ResourceSnapshot) {
+	By("Performing a restore", func() {
+		ExecuteRestore(ctx, spec.Kubectl, restore)
+	})
+
+	By("Performing post migration installations: Installing CertManager", func() {
+		InstallCertManager(ctx, spec.Kubectl, "none", "none")
+	})
+
+	By("Performing post migration installations: Installing Rancher Manager", func() {
+		rancherChannel, rancherVersion, rancherHeadVersion := GetRancherVersions(helpers.RancherFullVersion)
+		InstallRancherManager(ctx, spec.Kubectl, rancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "none", "none")
+	})
+
+	By("Performing post migration installations: Checking Rancher Deployments", func() {
+		CheckRancherDeployments(ctx, spec.Kubectl)
+	})
+
+	By("Checking the hosted cluster resources have been restored", func() {
+		// the controllers of the restored Rancher may still be updating the objects
+		Eventually(ctx, func() error {
+			after, err := helpers.TakeResourceSnapshot(ctx, hostedClusterSnapshotResources(spec)...)
+			if err != nil {
+				return err
+			}
+			return before.Diff(after).Err()
+		}, tools.SetTimeout(5*time.Minute), 15*time.Second).Should(Succeed())
+	})
+}
+
+// cloudCredentialInBackupsChecks checks whether the backups contain the values of the cloud credential in plain text;
+// the unencrypted backups must contain them, so that the check of the encrypted ones cannot pass if the values are not found at all
+func cloudCredentialInBackupsChecks(ctx context.Context, spec BackupRestoreSpec, plainText bool, backupFiles ...string) {
+	values, err := helpers.CloudCredentialData(spec.Client, spec.CloudCredential)
+	Expect(err).To(BeNil())
+	for _, backupFile := range backupFiles {
+		file, err := helpers.FetchBackupFile(ctx, backupFile)
+		Expect(err).To(BeNil())
+		contains, err := helpers.BackupFileContains(file, values...)
+		Expect(err).To(BeNil())
+		if plainText {
+			Expect(contains).To(BeTrue(), "backup %s does not contain the cloud credential in plain text", backupFile)
+		} else {
+			Expect(contains).To(BeFalse(), "backup %s contains the cloud credential in plain text", backupFile)
+		}
+	}
+}
+
+// snapshotHostedClusterResources captures the resources of Rancher related to the hosted cluster, to be compared once restored
+func snapshotHostedClusterResources(ctx context.Context, spec BackupRestoreSpec) (snapshot *helpers.ResourceSnapshot) {
+	By("Taking a snapshot of the hosted cluster resources", func() {
+		var err error
+		snapshot, err = helpers.TakeResourceSnapshot(ctx, hostedClusterSnapshotResources(spec)...)
+		Expect(err).To(BeNil())
+	})
+	return snapshot
+}
+
+func hostedClusterSnapshotResources(spec BackupRestoreSpec) []helpers.SnapshotResource {
+	return helpers.HostedClusterSnapshotResources(helpers.Provider, spec.Cluster.ID, spec.CloudCredential)
+}
+
+func restoredClusterChecks(ctx context.Context, spec BackupRestoreSpec) {
+	By("Checking the cloud credential has been restored", func() {
+		_, err := helpers.CloudCredentialData(spec.Client, spec.CloudCredential)
+		Expect(err).To(BeNil())
+	})
+
+	By("Checking hosted cluster can be modified", func() {
+		spec.NodesChecks(ctx, spec.Cluster, spec.Client, spec.ClusterName)
+	})
+}
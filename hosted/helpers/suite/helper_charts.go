@@ -0,0 +1,97 @@
+package suite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher/catalog"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// AddRancherCharts adds the repo from which rancher operator charts can be installed
+func AddRancherCharts(ctx context.Context) {
+	err := runHelm(ctx, "repo", "add", catalog.RancherChartRepo, "https://charts.rancher.io")
+	Expect(err).To(BeNil())
+}
+
+func DowngradeProviderChart(ctx context.Context, downgradeChartVersion string) {
+	currentChartVersion, err := helpers.GetCurrentOperatorChartVersion(ctx)
+	Expect(err).To(BeNil())
+	Expect(currentChartVersion).ToNot(BeEmpty())
+	UpdateOperatorChartsVersion(ctx, downgradeChartVersion)
+	Expect(helpers.VersionCompare(downgradeChartVersion, currentChartVersion)).To(Equal(-1))
+}
+
+// WaitUntilOperatorChartInstallation waits until the current operator chart version compares to the input chartVersion using the comparator.
+// comparator values can be >,<,<=,>=,==,!=
+// compareTo value can be 0 if current == input; -1 if current < input; 1 if current > input; defaults to 0
+// compareTo is helpful in case either of the chart version is unknown;
+// for e.g. after a rancher upgrade, if only the old chart version is known then we can wait until the current chart version is greater than it
+func WaitUntilOperatorChartInstallation(ctx context.Context, chartVersion, comparator string, compareTo int) {
+	if comparator == "" {
+		comparator = "=="
+	}
+
+	if !(compareTo >= -1 && compareTo <= 1) {
+		compareTo = 0
+	}
+	Eventually(ctx, func(g Gomega) int {
+		currentChartVersion, err := helpers.GetCurrentOperatorChartVersion(ctx)
+		g.Expect(err).To(BeNil())
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("CurrentChartVersion: %s; comparing with %s to %s %d", currentChartVersion, chartVersion, comparator, compareTo))
+		if currentChartVersion == "" {
+			return 10
+		}
+		comparison, err := helpers.VersionCompare(currentChartVersion, chartVersion)
+		g.Expect(err).To(BeNil())
+		return comparison
+	}, tools.SetTimeout(4*time.Minute), 3*time.Second).Should(BeNumerically(comparator, compareTo))
+	helpers.SetOperatorVersion("")
+}
+
+// UpdateOperatorChartsVersion updates the operator charts to a given chart version and validates that the current version is same as provided
+func UpdateOperatorChartsVersion(ctx context.Context, updateChartVersion string) {
+	charts, err := helpers.ListOperatorChart(ctx)
+	Expect(err).To(BeNil())
+	// the errors must be matched against the messages of the new operator
+	defer helpers.SetOperatorVersion("")
+	for _, chart := range charts {
+		err := runHelm(ctx, "upgrade", "--install", chart.Name, fmt.Sprintf("%s/%s", catalog.RancherChartRepo, chart.Name), "--namespace", helpers.CattleSystemNS, "--version", updateChartVersion, "--wait")
+		if err != nil {
+			Expect(err).To(BeNil(), "UpdateOperatorChartsVersion Failed")
+		}
+	}
+	currentChartVersion, err := helpers.GetCurrentOperatorChartVersion(ctx)
+	Expect(err).To(BeNil())
+	Expect(helpers.VersionCompare(currentChartVersion, updateChartVersion)).To(Equal(0))
+}
+
+// UninstallOperatorCharts uninstalls the operator charts
+func UninstallOperatorCharts(ctx context.Context) {
+	charts, err := helpers.ListOperatorChart(ctx)
+	Expect(err).To(BeNil())
+	defer helpers.SetOperatorVersion("")
+	for _, chart := range charts {
+		args := []string{"uninstall", chart.Name, "--namespace", helpers.CattleSystemNS}
+		err := runHelm(ctx, args...)
+		if err != nil {
+			Expect(err).To(BeNil(), "Failed to uninstall chart %s", chart.Name)
+		}
+	}
+}
+
+// runHelm runs the helm command and returns an error containing its output if it fails; the command is stopped once ctx is cancelled
+func runHelm(ctx context.Context, args ...string) error {
+	out, err := helpers.ProcRunner.Run(ctx, "helm", args...)
+	if err != nil {
+		return errors.Wrapf(err, "helm %s failed: %s", strings.Join(args, " "), out)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package suite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/rancher/tests/v2/actions/clusters"
+	"github.com/rancher/rancher/tests/v2/actions/pipeline"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/cloudcredentials"
+	nodestat "github.com/rancher/shepherd/extensions/nodes"
+	"github.com/rancher/shepherd/extensions/users"
+	password "github.com/rancher/shepherd/extensions/users/passwordgenerator"
+	"github.com/rancher/shepherd/extensions/workloads/pods"
+	"github.com/rancher/shepherd/pkg/config"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	"github.com/rancher/shepherd/pkg/session"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+func CommonSynchronizedBeforeSuite() {
+	ginkgo.GinkgoLogr.Info("Using Common SynchronizedBeforeSuite ...")
+	// attach the logs of the helpers returning errors to the specs
+	helpers.Logger = ginkgo.GinkgoLogr
+	// report every missing variable at once instead of failing on the first one used
+	Expect(helpers.Config.Validate(helpers.SuiteHosted)).To(Succeed())
+
+	rancherConfig := new(rancher.Config)
+
+	// Attempt at manually loading and updating the rancher config to avoid `nil map entry assignment`
+	config.LoadConfig(rancher.ConfigurationFileKey, rancherConfig)
+
+	rancherConfig.Host = helpers.RancherHostname
+	time.Sleep(2 * time.Second)
+
+	token, err := pipeline.CreateAdminToken(helpers.RancherPassword, rancherConfig)
+	Expect(err).To(BeNil())
+	rancherConfig.AdminToken = token
+
+	config.UpdateConfig(rancher.ConfigurationFileKey, rancherConfig)
+
+	switch helpers.Provider {
+	case "aks":
+		credentialConfig := new(cloudcredentials.AzureCredentialConfig)
+		config.LoadAndUpdateConfig("azureCredentials", credentialConfig, func() {
+			credentialConfig.ClientID = helpers.Config.AKS.ClientID
+			credentialConfig.SubscriptionID = helpers.Config.AKS.SubscriptionID
+			credentialConfig.ClientSecret = helpers.Config.AKS.ClientSecret
+		})
+	case "eks":
+		credentialConfig := new(cloudcredentials.AmazonEC2CredentialConfig)
+		config.LoadAndUpdateConfig("awsCredentials", credentialConfig, func() {
+			credentialConfig.AccessKey = helpers.Config.EKS.AccessKeyID
+			credentialConfig.SecretKey = helpers.Config.EKS.SecretAccessKey
+			credentialConfig.DefaultRegion = helpers.GetEKSRegion()
+		})
+
+	case "gke":
+		credentialConfig := new(cloudcredentials.GoogleCredentialConfig)
+		config.LoadAndUpdateConfig("googleCredentials", credentialConfig, func() {
+			credentialConfig.AuthEncodedJSON = helpers.Config.GKE.Credentials
+		})
+	}
+
+}
+
+func CommonBeforeSuite() helpers.RancherContext {
+	ginkgo.GinkgoLogr.Info("Using Common BeforeSuite ...")
+	helpers.Logger = ginkgo.GinkgoLogr
+
+	rancherConfig := new(rancher.Config)
+	config.LoadConfig(rancher.ConfigurationFileKey, rancherConfig)
+
+	testSession := session.NewSession()
+	rancherAdminClient, err := rancher.NewClient(rancherConfig.AdminToken, testSession)
+	Expect(err).To(BeNil())
+
+	// This is done by testhelpers already
+	//setting := new(management.Setting)
+	//resp, err := rancherAdminClient.Management.Setting.ByID("server-url")
+	//Expect(err).To(BeNil())
+
+	//setting.Source = "env"
+	//setting.Value = fmt.Sprintf("https://%s", RancherHostname)
+	//_, err = rancherAdminClient.Management.Setting.Update(resp, setting)
+	//Expect(err).To(BeNil())
+
+	// deferred first so that Ginkgo logs the summary once the other teardowns have run
+	if canDeferCleanup() {
+		ginkgo.DeferCleanup(helpers.Cleanups.LogSummary)
+	}
+
+	cloudCredID, err := helpers.CreateCloudCredentials(rancherAdminClient)
+	Expect(err).To(BeNil())
+
+	_, err = getRancherVersion(rancherAdminClient)
+	Expect(err).To(BeNil())
+
+	return helpers.RancherContext{
+		RancherAdminClient: rancherAdminClient,
+		Session:            testSession,
+		ClusterCleanup:     helpers.Config.DownstreamClusterCleanup,
+		CloudCredID:        cloudCredID,
+		Cleanups:           helpers.Cleanups,
+	}
+}
+
+func CreateStdUserClient(ctx *helpers.RancherContext) {
+	ginkgo.GinkgoLogr.Info("Creating Std User client ...")
+
+	var stduser = namegen.AppendRandomString("stduser-")
+	var stduserpassword = password.GenerateUserPassword("testpass-")
+	newuser := &management.User{
+		Username: stduser,
+		Password: stduserpassword,
+		Name:     stduser,
+		Enabled:  pointer.Bool(true),
+	}
+
+	stdUser, err := users.CreateUserWithRole(ctx.RancherAdminClient, newuser, "user")
+	Expect(err).To(BeNil())
+	helpers.RegisterClusterCleanup(helpers.CleanupKey("user", stdUser.ID), fmt.Sprintf("std user %s", stduser), func(context.Context) error {
+		return ctx.RancherAdminClient.Management.User.Delete(stdUser)
+	})
+
+	stdUser.Password = newuser.Password
+	stdUserClient, err := ctx.RancherAdminClient.AsUser(stdUser)
+	Expect(err).To(BeNil())
+
+	cloudCredID, err := helpers.CreateCloudCredentials(stdUserClient)
+	Expect(err).To(BeNil())
+
+	ctx.StdUserClient = stdUserClient
+	ctx.CloudCredID = cloudCredID
+}
+
+// ClusterIsReadyChecks runs the basic checks on a cluster such as cluster name, service account, nodes and pods check
+func ClusterIsReadyChecks(ctx context.Context, cluster *management.Cluster, client *rancher.Client, clusterName string) {
+	helpers.ReportCluster(cluster)
+
+	ginkgo.By("checking cluster name is same", func() {
+		Expect(cluster.Name).To(BeEquivalentTo(clusterName))
+	})
+
+	ginkgo.By("checking service account token secret", func() {
+		success, err := clusters.CheckServiceAccountTokenSecret(client, clusterName)
+		Expect(err).To(BeNil())
+		Expect(success).To(BeTrue())
+	})
+
+	ginkgo.By("checking all management nodes are ready", func() {
+		err := nodestat.AllManagementNodeReady(client, cluster.ID, helpers.Timeout)
+		Expect(err).To(BeNil())
+	})
+
+	ginkgo.By("checking all pods are ready", func() {
+		Eventually(ctx, func() []error {
+			return pods.StatusPods(client, cluster.ID)
+		}, tools.SetTimeout(helpers.Timeout), 30*time.Second).Should(BeEmpty(), "All pods are not running")
+	})
+}
+
+func GetRancherVersions(rancherFullVersion string) (string, string, string) {
+	var rancherChannel, rancherVersion, rancherHeadVersion string
+	// Extract Rancher Manager channel/version to install
+	s := strings.Split(rancherFullVersion, "/")
+	Expect(len(s)).To(BeNumerically(">=", 2), "RANCHER_VERSION must contain at least two strings separated by '/'")
+	rancherChannel = s[0]
+	rancherVersion = s[1] // This can be either a string like "2.9.3[-rc4]", "devel", or "latest"
+	if len(s) > 2 {
+		rancherHeadVersion = s[2]
+	}
+	return rancherChannel, rancherVersion, rancherHeadVersion
+}
@@ -0,0 +1,27 @@
+package suite
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// ExpectNoDrift waits until the cluster config, its upstream spec and the cloud agree on every field but the ignored ones, see DriftReport.Ignoring;
+// it can be used after any operation since Rancher syncs the cluster from the cloud periodically
+func ExpectNoDrift(ctx context.Context, provider helpers.HostedProvider, client *rancher.Client, cluster *management.Cluster, ignoredFields ...string) {
+	Eventually(ctx, func(g Gomega) {
+		helpers.Logger.Info("Waiting for the cluster to match the cloud ...")
+		updatedCluster, err := client.Management.Cluster.ByID(cluster.ID)
+		g.Expect(err).To(BeNil())
+		report, err := helpers.DetectDrift(ctx, provider, updatedCluster)
+		g.Expect(err).To(BeNil())
+		report = report.Ignoring(ignoredFields...)
+		g.Expect(report.Drifts).To(BeEmpty(), report.String())
+	}, tools.SetTimeout(10*time.Minute), 15*time.Second).Should(Succeed())
+}
@@ -0,0 +1,59 @@
+package suite
+
+import (
+	"context"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/rancher/shepherd/clients/rancher"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// featureGateReportEntry is the name of the report entry added when a spec is skipped by a feature gate
+const featureGateReportEntry = "Feature gate"
+
+// suiteRancherVersion is the version of the Rancher server of the suite, resolved once by CommonBeforeSuite or the first SkipUnlessSupported;
+// it is reset when Rancher is installed again, see InstallRancherManager
+var suiteRancherVersion *helpers.RancherVersionInfo
+
+// getRancherVersion returns the version of the Rancher server, fetching it only if it has not been resolved yet
+func getRancherVersion(client *rancher.Client) (helpers.RancherVersionInfo, error) {
+	if suiteRancherVersion == nil {
+		info, err := helpers.GetRancherVersionInfo(client)
+		if err != nil {
+			return helpers.RancherVersionInfo{}, err
+		}
+		suiteRancherVersion = &info
+	}
+	return *suiteRancherVersion, nil
+}
+
+// SkipUnlessSupported skips the spec if one of the features is not supported by the running Rancher or operator,
+// with a uniform reason that is also added to the spec report; it fails the spec if the versions cannot be fetched.
+func SkipUnlessSupported(ctx context.Context, client *rancher.Client, features ...helpers.Feature) {
+	var versions helpers.ComponentVersions
+	for _, feature := range features {
+		for _, gate := range feature.Gates {
+			var err error
+			switch {
+			case gate.Component == helpers.ComponentRancher && versions.Rancher == "":
+				var info helpers.RancherVersionInfo
+				info, err = getRancherVersion(client)
+				versions.Rancher = info.Version
+			case gate.Component == helpers.ComponentOperator && versions.Operator == "":
+				versions.Operator, err = helpers.CurrentOperatorVersion(ctx)
+			}
+			Expect(err).To(BeNil(), "Failed to get the %s version", gate.Component)
+		}
+
+		var unsupported *helpers.UnsupportedFeatureError
+		err := feature.Check(versions)
+		if errors.As(err, &unsupported) {
+			ginkgo.AddReportEntry(featureGateReportEntry, unsupported.Error(), ginkgo.ReportEntryVisibilityNever)
+			ginkgo.Skip("Unsupported: "+unsupported.Error(), 1)
+		}
+		Expect(err).To(BeNil())
+	}
+}
@@ -1,6 +1,7 @@
-package helpers
+package suite
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,16 +13,18 @@ import (
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
 	"github.com/rancher-sandbox/ele-testhelpers/rancher"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 )
 
 /**
- * Execute RunHelmBinaryWithCustomErr within a loop with timeout
- * @param s options to pass to RunHelmBinaryWithCustomErr command
+ * Execute the helm command within a loop with timeout
+ * @param ctx, stops the loop and the running command once cancelled
+ * @param s options to pass to the helm command
  * @returns Nothing, the function will fail through Ginkgo in case of issue
  */
-func RunHelmCmdWithRetry(s ...string) {
-	Eventually(func() error {
-		output, err := kubectl.RunHelmBinaryWithOutput(s...)
+func RunHelmCmdWithRetry(ctx context.Context, s ...string) {
+	Eventually(ctx, func() error {
+		output, err := helpers.ProcRunner.Run(ctx, "helm", s...)
 		GinkgoWriter.Write([]byte(output))
 		if err != nil {
 			return err
@@ -33,13 +36,14 @@ func RunHelmCmdWithRetry(s ...string) {
 /*
 *
 Install k3s
+  - @param ctx, stops the installation once cancelled
   - @param k kubectl structure
   - @param k3sVersion version to install
   - @param proxy, enable proxy
   - @param proxyHost, if proxy enabled
   - @returns Nothing, the function will fail through Ginkgo in case of issue
 */
-func InstallK3S(k *kubectl.Kubectl, k3sVersion, proxy, proxyHost string) {
+func InstallK3S(ctx context.Context, k *kubectl.Kubectl, k3sVersion, proxy, proxyHost string) {
 	if proxy == "enabled" {
 		By("Run local squid proxy in docker", func() {
 			// Configure proxy before k3s installation if requested
@@ -48,7 +52,7 @@ func InstallK3S(k *kubectl.Kubectl, k3sVersion, proxy, proxyHost string) {
 			cwd, _ := os.Getwd()
 			GinkgoLogr.Info("Current working directory: " + cwd)
 
-			out, err := exec.Command("docker", "run", "-d", "--rm", "--name", "squid_proxy",
+			out, err := exec.CommandContext(ctx, "docker", "run", "-d", "--rm", "--name", "squid_proxy",
 				"--volume", cwd+"/.github/scripts/squid.conf:/etc/squid/squid.conf",
 				"-p", "3128:3128", "ubuntu/squid").CombinedOutput()
 			GinkgoWriter.Println(string(out))
@@ -61,20 +65,19 @@ func InstallK3S(k *kubectl.Kubectl, k3sVersion, proxy, proxyHost string) {
 HTTPS_PROXY=http://%s
 NO_PROXY=127.0.0.0/8,10.0.0.0/8,cattle-system.svc,172.16.0.0/12,192.168.0.0/16,.svc,.cluster.local`, proxyHost, proxyHost)
 			// Write the k3s proxy config file as root
-			out, err := exec.Command("sh", "-c", fmt.Sprintf("echo '%s' | sudo tee %s", k3sConfig, k3sConfigPath)).CombinedOutput()
+			out, err := exec.CommandContext(ctx, "sh", "-c", fmt.Sprintf("echo '%s' | sudo tee %s", k3sConfig, k3sConfigPath)).CombinedOutput()
 			GinkgoWriter.Println(string(out))
 			Expect(err).To(Not(HaveOccurred()))
 		})
 	}
 
 	By("Getting k3s ready", func() {
-		installCmd := exec.Command("sh", "-c", "curl -sfL https://get.k3s.io | sh -s - server --cluster-init")
-		installCmd.Env = append(os.Environ(), "INSTALL_K3S_VERSION="+k3sVersion, "INSTALL_K3S_EXEC=--write-kubeconfig-mode 644")
-
 		// Execute k3s installation
 		count := 1
-		Eventually(func() error {
-			// Execute k3s installation
+		Eventually(ctx, func() error {
+			// Execute k3s installation; a command can only be run once
+			installCmd := exec.CommandContext(ctx, "sh", "-c", "curl -sfL https://get.k3s.io | sh -s - server --cluster-init")
+			installCmd.Env = append(os.Environ(), "INSTALL_K3S_VERSION="+k3sVersion, "INSTALL_K3S_EXEC=--write-kubeconfig-mode 644")
 			out, err := installCmd.CombinedOutput()
 			GinkgoWriter.Printf("K3s installation loop %d:\n%s\n", count, out)
 			count++
@@ -91,7 +94,7 @@ NO_PROXY=127.0.0.0/8,10.0.0.0/8,cattle-system.svc,172.16.0.0/12,192.168.0.0/16,.
 			{"kube-system", "app.kubernetes.io/name=traefik"},
 			{"kube-system", "svccontroller.k3s.cattle.io/svcname=traefik"},
 		}
-		Eventually(func() error {
+		Eventually(ctx, func() error {
 			return rancher.CheckPod(k, checkList)
 		}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil(), "K3s pods are not running")
 	})
@@ -100,15 +103,16 @@ NO_PROXY=127.0.0.0/8,10.0.0.0/8,cattle-system.svc,172.16.0.0/12,192.168.0.0/16,.
 /*
 *
 Install CertManager
+  - @param ctx, stops the installation once cancelled
   - @param k kubectl structure
   - @param proxy, enable proxy
   - @param proxyHost, if proxy enabled
   - @returns Nothing, the function will fail through Ginkgo in case of issue
 */
-func InstallCertManager(k *kubectl.Kubectl, proxy, proxyHost string) {
+func InstallCertManager(ctx context.Context, k *kubectl.Kubectl, proxy, proxyHost string) {
 	By("Installing CertManager", func() {
-		RunHelmCmdWithRetry("repo", "add", "jetstack", "https://charts.jetstack.io")
-		RunHelmCmdWithRetry("repo", "update")
+		RunHelmCmdWithRetry(ctx, "repo", "add", "jetstack", "https://charts.jetstack.io")
+		RunHelmCmdWithRetry(ctx, "repo", "update")
 
 		// Set flags for cert-manager installation
 		flags := []string{
@@ -125,14 +129,14 @@ func InstallCertManager(k *kubectl.Kubectl, proxy, proxyHost string) {
 				"--set", "no_proxy=127.0.0.0/8\\,10.0.0.0/8\\,cattle-system.svc\\,172.16.0.0/12\\,192.168.0.0/16\\,.svc\\,.cluster.local")
 		}
 		GinkgoWriter.Printf("Helm flags: %v\n", flags)
-		RunHelmCmdWithRetry(flags...)
+		RunHelmCmdWithRetry(ctx, flags...)
 
 		checkList := [][]string{
 			{"cert-manager", "app.kubernetes.io/component=controller"},
 			{"cert-manager", "app.kubernetes.io/component=webhook"},
 			{"cert-manager", "app.kubernetes.io/component=cainjector"},
 		}
-		Eventually(func() error {
+		Eventually(ctx, func() error {
 			return rancher.CheckPod(k, checkList)
 		}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil(), "CertManager pods are not running")
 	})
@@ -142,6 +146,8 @@ func InstallCertManager(k *kubectl.Kubectl, proxy, proxyHost string) {
 *
 Install Rancher Manager
 
+  - @param ctx, stops the wait for the Rancher pod once cancelled
+
   - @param k kubectl structure
 
   - @param rancherHostname, Rancher Hostname
@@ -158,7 +164,7 @@ Install Rancher Manager
 
   - @returns Nothing, the function will fail through Ginkgo in case of issue
 */
-func InstallRancherManager(k *kubectl.Kubectl, rancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, proxy, nightlyChart string) {
+func InstallRancherManager(ctx context.Context, k *kubectl.Kubectl, rancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, proxy, nightlyChart string) {
 	var proxyEnabled string
 	if proxy == "enabled" {
 		proxyEnabled = "rancher"
@@ -183,12 +189,15 @@ func InstallRancherManager(k *kubectl.Kubectl, rancherHostname, rancherChannel,
 
 	err := rancher.DeployRancherManager(rancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "none", proxyEnabled, extraFlags)
 	Expect(err).To(Not(HaveOccurred()))
+	// the feature gates and the operator errors must not use the versions of the Rancher that was replaced
+	suiteRancherVersion = nil
+	helpers.SetOperatorVersion("")
 
 	// Wait for all pods to be started
 	checkList := [][]string{
 		{"cattle-system", "app=rancher"},
 	}
-	Eventually(func() error {
+	Eventually(ctx, func() error {
 		return rancher.CheckPod(k, checkList)
 	}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil(), "Rancher pod is not running")
 }
@@ -196,14 +205,15 @@ func InstallRancherManager(k *kubectl.Kubectl, rancherHostname, rancherChannel,
 /*
 *
 Check Rancher Deployments
+  - @param ctx, stops the waits once cancelled
   - @param k kubectl structure
   - @returns Nothing, the function will fail through Ginkgo in case of issue
 */
-func CheckRancherDeployments(k *kubectl.Kubectl) {
+func CheckRancherDeployments(ctx context.Context, k *kubectl.Kubectl) {
 	By("Waiting for fleet", func() {
 		// Wait until the kubectl command returns exit code 0
 		count := 1
-		Eventually(func() error {
+		Eventually(ctx, func() error {
 			out, err := kubectl.Run("rollout", "status",
 				"--namespace", "cattle-fleet-system",
 				"deployment", "fleet-controller",
@@ -216,7 +226,7 @@ func CheckRancherDeployments(k *kubectl.Kubectl) {
 		checkList := [][]string{
 			{"cattle-fleet-system", "app=fleet-controller"},
 		}
-		Eventually(func() error {
+		Eventually(ctx, func() error {
 			return rancher.CheckPod(k, checkList)
 		}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil(), "Fleet-controller pod is not running")
 	})
@@ -224,7 +234,7 @@ func CheckRancherDeployments(k *kubectl.Kubectl) {
 	By("Waiting for rancher-webhook", func() {
 		// Wait unit the kubectl command returns exit code 0
 		count := 1
-		Eventually(func() error {
+		Eventually(ctx, func() error {
 			out, err := kubectl.Run("rollout", "status",
 				"--namespace", "cattle-system",
 				"deployment", "rancher-webhook",
@@ -237,7 +247,7 @@ func CheckRancherDeployments(k *kubectl.Kubectl) {
 		checkList := [][]string{
 			{"cattle-system", "app=rancher-webhook"},
 		}
-		Eventually(func() error {
+		Eventually(ctx, func() error {
 			return rancher.CheckPod(k, checkList)
 		}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil(), "Rancher-webhook pod is not running")
 	})
@@ -245,7 +255,7 @@ func CheckRancherDeployments(k *kubectl.Kubectl) {
 	By("Waiting for capi-controller-manager", func() {
 		// Wait unit the kubectl command returns exit code 0
 		count := 1
-		Eventually(func() error {
+		Eventually(ctx, func() error {
 			out, err := kubectl.Run("rollout", "status",
 				"--namespace", "cattle-provisioning-capi-system",
 				"deployment", "capi-controller-manager",
@@ -258,7 +268,7 @@ func CheckRancherDeployments(k *kubectl.Kubectl) {
 		checkList := [][]string{
 			{"cattle-provisioning-capi-system", "cluster.x-k8s.io/provider=cluster-api"},
 		}
-		Eventually(func() error {
+		Eventually(ctx, func() error {
 			return rancher.CheckPod(k, checkList)
 		}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil(), "Capi-controller-manager pod is not running")
 	})
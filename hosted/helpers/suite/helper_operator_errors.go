@@ -0,0 +1,109 @@
+package suite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// HaveTransitionError succeeds if the actual value carries the error:
+//   - a *management.Cluster with Transitioning "error" and a matching TransitioningMessage
+//   - a *ClusterTimeline which has ever entered Transitioning "error" with a matching message
+//   - an error, for e.g. the rejection of an update by the webhook, or a string with a matching message
+//
+// Only the messages of the installed operator are matched, see helpers.CurrentOperatorVersion; the match fails if the error has none.
+// On failure, the message actually found is classified against the catalogue, so that a change of the message in an operator release
+// fails with the unknown message instead of a timeout.
+func HaveTransitionError(expected helpers.OperatorError) types.GomegaMatcher {
+	return &transitionErrorMatcher{expected: expected}
+}
+
+type transitionErrorMatcher struct {
+	expected helpers.OperatorError
+	// operatorVersion is the version of the operator the messages have been matched against
+	operatorVersion string
+	// found are the error messages of the last actual value
+	found []string
+}
+
+// forCurrentOperator returns the messages of the error on the installed operator; it returns an error if there are none
+func forCurrentOperator(ctx context.Context, expected helpers.OperatorError) (helpers.OperatorError, string, error) {
+	operatorVersion, err := helpers.CurrentOperatorVersion(ctx)
+	if err != nil {
+		return helpers.OperatorError{}, "", err
+	}
+	narrowed := expected.ForOperator(operatorVersion)
+	if len(narrowed.Messages) == 0 {
+		return helpers.OperatorError{}, operatorVersion, fmt.Errorf("%s has no message for operator %s; add one to the catalogue", expected, operatorVersion)
+	}
+	return narrowed, operatorVersion, nil
+}
+
+func (m *transitionErrorMatcher) Match(actual any) (bool, error) {
+	m.found = nil
+	expected, operatorVersion, err := forCurrentOperator(context.Background(), m.expected)
+	if err != nil {
+		return false, err
+	}
+	m.operatorVersion = operatorVersion
+	switch actual := actual.(type) {
+	case *management.Cluster:
+		if actual == nil {
+			return false, fmt.Errorf("HaveTransitionError expects a non-nil cluster")
+		}
+		if actual.Transitioning == "error" {
+			m.found = append(m.found, actual.TransitioningMessage)
+		}
+	case *helpers.ClusterTimeline:
+		for _, event := range actual.Events() {
+			if event.Transitioning == "error" {
+				m.found = append(m.found, event.TransitioningMessage)
+			}
+		}
+	case error:
+		if actual != nil {
+			m.found = append(m.found, actual.Error())
+		}
+	case string:
+		m.found = append(m.found, actual)
+	default:
+		return false, fmt.Errorf("HaveTransitionError expects a *management.Cluster, a *helpers.ClusterTimeline, an error or a string, got:\n%s", format.Object(actual, 1))
+	}
+	for _, message := range m.found {
+		if expected.Matches(message) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *transitionErrorMatcher) FailureMessage(_ any) string {
+	expected := m.expected.ForOperator(m.operatorVersion)
+	if len(m.found) == 0 {
+		return fmt.Sprintf("Expected the %s matching %s on operator %s, found no error", m.expected, expected.Regexp(), m.operatorVersion)
+	}
+	var classified []string
+	for _, message := range m.found {
+		classified = append(classified, fmt.Sprintf("  %q, classified as %s", message, helpers.ClassifyOperatorError(m.expected.Provider, m.operatorVersion, message)))
+	}
+	return fmt.Sprintf("Expected the %s matching %s on operator %s, found:\n%s", m.expected, expected.Regexp(), m.operatorVersion, strings.Join(classified, "\n"))
+}
+
+func (m *transitionErrorMatcher) NegatedFailureMessage(_ any) string {
+	return fmt.Sprintf("Expected no %s, found:\n  %s", m.expected, strings.Join(m.found, "\n  "))
+}
+
+// TransitionErrorRegexp returns the regular expression matching the messages of the error on the installed operator,
+// for e.g. for ClusterTimeline.WaitUntilEntered; it fails the spec if the error has no message for it.
+func TransitionErrorRegexp(ctx context.Context, expected helpers.OperatorError) string {
+	narrowed, _, err := forCurrentOperator(ctx, expected)
+	Expect(err).To(BeNil())
+	return narrowed.Regexp()
+}
@@ -0,0 +1,64 @@
+package suite
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// P0NodesChecks checks the cluster is ready, then scales its first nodepool up and down, adds a nodepool and deletes it
+func P0NodesChecks(specCtx context.Context, provider helpers.HostedProvider, cluster *management.Cluster, client *rancher.Client, clusterName string) {
+	ClusterIsReadyChecks(specCtx, cluster, client, clusterName)
+	initialNodeCount := provider.NodeCount(cluster)
+
+	By("scaling up the nodepool", func() {
+		var err error
+		cluster, err = provider.ScaleNodePool(specCtx, cluster, client, initialNodeCount+1, true, true)
+		Expect(err).To(BeNil())
+	})
+
+	By("scaling down the nodepool", func() {
+		var err error
+		cluster, err = provider.ScaleNodePool(specCtx, cluster, client, initialNodeCount, true, true)
+		Expect(err).To(BeNil())
+	})
+
+	By("adding a nodepool", func() {
+		var err error
+		cluster, err = provider.AddNodePool(specCtx, cluster, client, 1, true, true)
+		Expect(err).To(BeNil())
+	})
+
+	By("deleting the nodepool", func() {
+		var err error
+		cluster, err = provider.DeleteNodePool(specCtx, cluster, client, true, true)
+		Expect(err).To(BeNil())
+	})
+}
+
+// P0UpgradeK8sVersionChecks checks the cluster is ready, then upgrades its control plane and its nodepools to the first available version
+func P0UpgradeK8sVersionChecks(specCtx context.Context, provider helpers.HostedProvider, cluster *management.Cluster, client *rancher.Client, clusterName string) {
+	ClusterIsReadyChecks(specCtx, cluster, client, clusterName)
+
+	versions, err := provider.ListAvailableVersions(specCtx, client, cluster)
+	Expect(err).To(BeNil())
+	Expect(versions).ToNot(BeEmpty())
+	upgradeToVersion := versions[0]
+	GinkgoLogr.Info(fmt.Sprintf("Upgrading cluster to %s version %s", provider.Name(), upgradeToVersion))
+
+	By("upgrading the ControlPlane", func() {
+		cluster, err = provider.UpgradeControlPlane(specCtx, cluster, client, upgradeToVersion, true)
+		Expect(err).To(BeNil())
+	})
+
+	By("upgrading the NodePools", func() {
+		cluster, err = provider.UpgradeNodePools(specCtx, cluster, client, upgradeToVersion, true, true)
+		Expect(err).To(BeNil())
+	})
+}
@@ -0,0 +1,157 @@
+package suite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/rancher/shepherd/clients/rancher"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var reportFileNameRegexp = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// BuildSpecResult converts the Ginkgo report of a spec into a SpecResult;
+// the Rancher and operator chart versions are not set since they must be fetched from the cluster.
+func BuildSpecResult(report ginkgo.SpecReport, testCaseID int64) helpers.SpecResult {
+	result := helpers.SpecResult{
+		Provider:  helpers.Provider,
+		Import:    helpers.IsImport,
+		Spec:      report.FullText(),
+		Labels:    report.Labels(),
+		State:     report.State.String(),
+		StartTime: report.StartTime,
+		EndTime:   report.EndTime,
+		Duration:  report.RunTime,
+		Steps:     stepTimings(report),
+	}
+	if testCaseID > 0 {
+		result.QaseID = testCaseID
+	}
+	// the last entries win, for e.g. the k8s version after an upgrade
+	for _, entry := range report.ReportEntries {
+		switch entry.Name {
+		case helpers.ClusterNameReportEntry:
+			result.ClusterName = entry.StringRepresentation()
+		case helpers.K8sVersionReportEntry:
+			result.K8sVersion = entry.StringRepresentation()
+		case helpers.LifecycleOperationReportEntry:
+			if timing, ok := entry.GetRawValue().(helpers.OperationTiming); ok {
+				result.Operations = append(result.Operations, timing)
+			}
+		case helpers.ClusterTimelineReportEntry:
+			if timeline, ok := entry.GetRawValue().(helpers.ClusterTimelineReport); ok {
+				result.Timelines = append(result.Timelines, timeline)
+			}
+		}
+	}
+	if report.Failed() {
+		result.FailureMessage = report.Failure.Message
+		result.FailureLocation = report.Failure.Location.String()
+	}
+	if report.State == types.SpecStateSkipped {
+		result.SkipReason = report.Failure.Message
+	}
+	return result
+}
+
+// stepTimings returns the duration of every `By` step; a step without callback lasts until the next step or the end of the spec
+func stepTimings(report ginkgo.SpecReport) []helpers.StepTiming {
+	var steps []helpers.StepTiming
+	// open holds the index in steps of the `By` steps with a callback that have not ended yet
+	open := map[string]int{}
+	// last is the index of the last `By` step without callback, -1 if there is none
+	last := -1
+	for _, event := range report.SpecEvents {
+		key := event.CodeLocation.String() + event.Message
+		switch event.SpecEventType {
+		case types.SpecEventByStart:
+			if last >= 0 {
+				steps[last].Duration = event.TimelineLocation.Time.Sub(steps[last].StartTime)
+				last = -1
+			}
+			steps = append(steps, helpers.StepTiming{Text: event.Message, StartTime: event.TimelineLocation.Time})
+			open[key] = len(steps) - 1
+			last = len(steps) - 1
+		case types.SpecEventByEnd:
+			if i, ok := open[key]; ok {
+				steps[i].Duration = event.Duration
+				delete(open, key)
+				if i == last {
+					last = -1
+				}
+			}
+		}
+	}
+	if last >= 0 && !report.EndTime.IsZero() {
+		steps[last].Duration = report.EndTime.Sub(steps[last].StartTime)
+	}
+	return steps
+}
+
+// WriteRunReport writes the report of a spec as JSON to RUN_REPORT_DIR; it must be called in ReportAfterEach, next to Qase.
+// If the spec failed, it also writes the DiagnosticsBundle of its clusters as a tarball next to the report; see CollectDiagnostics.
+// client is used to fetch the Rancher server version and can be nil, for e.g. if the suite setup failed.
+func WriteRunReport(ctx context.Context, report ginkgo.SpecReport, testCaseID int64, client *rancher.Client) {
+	if helpers.RunReportDir == "" {
+		return
+	}
+	result := BuildSpecResult(report, testCaseID)
+
+	if client != nil {
+		if version, err := helpers.GetRancherServerVersion(client); err == nil {
+			result.RancherVersion = version
+		} else {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to get the Rancher server version for the run report: %v", err))
+		}
+	}
+	// the report must not fail the spec, hence the failures to list the chart are only logged
+	if version, err := helpers.GetCurrentOperatorChartVersion(ctx); err == nil {
+		result.OperatorChartVersion = version
+	} else {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to get the operator chart version for the run report: %v", err))
+	}
+
+	baseName := reportBaseName(result)
+	if bundles := helpers.TakeDiagnostics(ctx, report.Failed(), client, result.ClusterName); len(bundles) > 0 {
+		if err := os.MkdirAll(helpers.RunReportDir, 0o755); err != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to write the diagnostics: %v", err))
+		} else if err = helpers.WriteDiagnostics(filepath.Join(helpers.RunReportDir, baseName+"-diagnostics.tar.gz"), bundles...); err != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to write the diagnostics: %v", err))
+		} else {
+			result.DiagnosticsBundle = baseName + "-diagnostics.tar.gz"
+		}
+	}
+
+	if err := writeSpecResult(helpers.RunReportDir, baseName, result); err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to write the run report: %v", err))
+	}
+}
+
+// reportBaseName returns the name of the files of a spec in RUN_REPORT_DIR; it is unique across parallel processes
+func reportBaseName(result helpers.SpecResult) string {
+	name := strings.Trim(reportFileNameRegexp.ReplaceAllString(strings.ToLower(result.Spec), "-"), "-")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	return fmt.Sprintf("%s-%s-%d-p%d", result.Provider, name, result.StartTime.UnixNano(), ginkgo.GinkgoParallelProcess())
+}
+
+// writeSpecResult writes the result to baseName.json in dir
+func writeSpecResult(dir, baseName string, result helpers.SpecResult) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, baseName+".json"), data, 0o644)
+}
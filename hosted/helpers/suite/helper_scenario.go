@@ -0,0 +1,192 @@
+package suite
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// ScenarioRunner runs the steps of a Scenario against a cluster using the helpers of the HostedProvider
+type ScenarioRunner struct {
+	Provider helpers.HostedProvider
+	Client   *rancher.Client
+	// PollInterval overrides the polling interval of the waits done by the runner, e.g. in unit tests
+	PollInterval time.Duration
+}
+
+func (r ScenarioRunner) pollInterval(interval time.Duration) time.Duration {
+	if r.PollInterval > 0 {
+		return r.PollInterval
+	}
+	return interval
+}
+
+// Run runs every step of the scenario in order and returns the updated cluster; a step failing fails the spec
+func (r ScenarioRunner) Run(ctx context.Context, cluster *management.Cluster, scenario helpers.Scenario) *management.Cluster {
+	for i, step := range scenario.Steps {
+		ginkgo.By(fmt.Sprintf("step %d: %s", i+1, step), func() {
+			switch {
+			case step.SyncFromCloud != nil:
+				cluster = r.syncFromCloud(ctx, cluster, *step.SyncFromCloud)
+			case step.ExpectError != "":
+				cluster = r.expectError(ctx, cluster, step)
+			default:
+				var err error
+				cluster, err = r.apply(ctx, cluster, step, true)
+				Expect(err).To(BeNil())
+			}
+		})
+	}
+	return cluster
+}
+
+// apply runs the action of the step; if check is true, it waits for the action to be applied and checks the cluster config
+func (r ScenarioRunner) apply(ctx context.Context, cluster *management.Cluster, step helpers.ScenarioStep, check bool) (*management.Cluster, error) {
+	var (
+		version string
+		err     error
+	)
+	switch {
+	case step.ScalePool != nil:
+		cluster, err = r.Provider.ScaleNodePool(ctx, cluster, r.Client, *step.ScalePool, check, check)
+	case step.AddPool != nil:
+		cluster, err = r.Provider.AddNodePool(ctx, cluster, r.Client, *step.AddPool, check, check)
+	case step.DeletePool:
+		cluster, err = r.Provider.DeleteNodePool(ctx, cluster, r.Client, check, check)
+	case step.UpgradeControlPlane != "":
+		if version, err = r.resolveVersion(ctx, cluster, step.UpgradeControlPlane); err == nil {
+			cluster, err = r.Provider.UpgradeControlPlane(ctx, cluster, r.Client, version, check)
+		}
+	case step.UpgradeNodePools != "":
+		if version, err = r.resolveVersion(ctx, cluster, step.UpgradeNodePools); err == nil {
+			cluster, err = r.Provider.UpgradeNodePools(ctx, cluster, r.Client, version, check, check)
+		}
+	default:
+		cluster, err = r.updateTags(ctx, cluster, step.UpdateTags, step.RemoveTags, check)
+	}
+	return cluster, err
+}
+
+// updateTags adds, updates and removes the tags of the cluster while keeping the others, e.g. the common metadata labels
+func (r ScenarioRunner) updateTags(ctx context.Context, cluster *management.Cluster, updateTags map[string]string, removeTags []string, check bool) (*management.Cluster, error) {
+	tags := map[string]string{}
+	maps.Copy(tags, r.Provider.ConfigSpec(cluster).Tags)
+	maps.Copy(tags, updateTags)
+	for _, key := range removeTags {
+		delete(tags, key)
+	}
+
+	cluster, err := r.Provider.UpdateClusterTags(ctx, cluster, r.Client, tags)
+	if err != nil || !check {
+		return cluster, err
+	}
+
+	if err = checkTags(cluster, "config", r.Provider.ConfigSpec(cluster).Tags, tags); err != nil {
+		return cluster, err
+	}
+	err = helpers.WaitFor(ctx, "the tags to be updated in the upstream spec", 10*time.Minute, r.pollInterval(10*time.Second), func() (bool, error) {
+		if cluster, err = r.Client.Management.Cluster.ByID(cluster.ID); err != nil {
+			return false, err
+		}
+		return true, checkTags(cluster, "upstream spec", r.Provider.UpstreamSpec(cluster).Tags, tags)
+	})
+	if err != nil {
+		return cluster, err
+	}
+	return helpers.SyncImportedClusterConfig(cluster), nil
+}
+
+// checkTags returns a ConfigMismatchError if the tags of the given spec are not exactly the expected ones
+func checkTags(cluster *management.Cluster, spec string, tags, expectedTags map[string]string) error {
+	if maps.Equal(tags, expectedTags) {
+		return nil
+	}
+	return &helpers.ConfigMismatchError{ClusterName: cluster.Name, Field: "tags of the " + spec, Expected: helpers.FormatMap(expectedTags), Actual: helpers.FormatMap(tags)}
+}
+
+// expectError applies the step without waiting and checks that it fails with the expected error, either immediately or in the cluster transitioning message
+func (r ScenarioRunner) expectError(ctx context.Context, cluster *management.Cluster, step helpers.ScenarioStep) *management.Cluster {
+	clusterID := cluster.ID
+	if _, err := r.apply(ctx, cluster, step, false); err != nil {
+		Expect(err).To(MatchError(ContainSubstring(step.ExpectError)))
+	} else {
+		Eventually(ctx, func() bool {
+			var err error
+			cluster, err = r.Client.Management.Cluster.ByID(clusterID)
+			Expect(err).To(BeNil())
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("cluster.Transitioning=%s cluster.TransitioningMessage=%s", cluster.Transitioning, cluster.TransitioningMessage))
+			return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, step.ExpectError)
+		}, tools.SetTimeout(5*time.Minute), r.pollInterval(5*time.Second)).Should(BeTrue())
+	}
+
+	cluster, err := r.Client.Management.Cluster.ByID(clusterID)
+	Expect(err).To(BeNil())
+	return helpers.SyncImportedClusterConfig(cluster)
+}
+
+// syncFromCloud modifies the cluster on the cloud provider and waits for the change to appear in the upstream spec
+func (r ScenarioRunner) syncFromCloud(ctx context.Context, cluster *management.Cluster, step helpers.CloudStep) *management.Cluster {
+	var (
+		err    error
+		synced func(helpers.ClusterSpec) bool
+	)
+	switch {
+	case step.ScalePool != nil:
+		nodeCount := *step.ScalePool
+		err = r.Provider.ScaleNodePoolOnCloud(ctx, cluster, nodeCount)
+		synced = func(spec helpers.ClusterSpec) bool {
+			return len(spec.NodePools) > 0 && spec.NodePools[0].NodeCount == nodeCount
+		}
+	case step.AddPool != "":
+		err = r.Provider.AddNodePoolOnCloud(ctx, cluster, step.AddPool)
+		synced = func(spec helpers.ClusterSpec) bool {
+			_, ok := spec.NodePool(step.AddPool)
+			return ok
+		}
+	default:
+		var version string
+		version, err = r.resolveVersion(ctx, cluster, step.UpgradeControlPlane)
+		Expect(err).To(BeNil())
+		err = r.Provider.UpgradeControlPlaneOnCloud(ctx, cluster, version)
+		synced = func(spec helpers.ClusterSpec) bool {
+			return spec.KubernetesVersion == version
+		}
+	}
+	Expect(err).To(BeNil())
+
+	Eventually(ctx, func() bool {
+		ginkgo.GinkgoLogr.Info("Waiting for the change on the cloud provider to be synced in the upstream spec ...")
+		cluster, err = r.Client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).To(BeNil())
+		return synced(r.Provider.UpstreamSpec(cluster))
+	}, tools.SetTimeout(10*time.Minute), r.pollInterval(15*time.Second)).Should(BeTrue())
+	return helpers.SyncImportedClusterConfig(cluster)
+}
+
+// resolveVersion resolves NextVersion and ControlPlaneVersion, any other version is returned as is
+func (r ScenarioRunner) resolveVersion(ctx context.Context, cluster *management.Cluster, version string) (string, error) {
+	switch version {
+	case helpers.NextVersion:
+		versions, err := r.Provider.ListAvailableVersions(ctx, r.Client, cluster)
+		if err != nil {
+			return "", err
+		}
+		if len(versions) == 0 {
+			return "", fmt.Errorf("no version to upgrade cluster %s to", cluster.Name)
+		}
+		return versions[0], nil
+	case helpers.ControlPlaneVersion:
+		return r.Provider.ConfigSpec(cluster).KubernetesVersion, nil
+	}
+	return version, nil
+}
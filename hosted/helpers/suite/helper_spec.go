@@ -0,0 +1,72 @@
+// Package suite holds the Ginkgo and Gomega layer of the helpers: the suite setups, the checks failing the spec through Gomega and the run reports.
+// Importing it installs the Ginkgo SpecHooks, so that the teardowns, the timings and the cluster timelines of the helpers are attached to the specs.
+package suite
+
+import (
+	"context"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+func init() {
+	helpers.SetSpecHooks(ginkgoHooks{})
+}
+
+// ginkgoHooks are the helpers.SpecHooks of the specs run by Ginkgo; the offsets are increased by one to skip the hooks themselves
+type ginkgoHooks struct{}
+
+func (ginkgoHooks) Running() bool {
+	return !ginkgo.CurrentSpecReport().StartTime.IsZero()
+}
+
+func (ginkgoHooks) CanDeferCleanup() bool {
+	return canDeferCleanup()
+}
+
+func (ginkgoHooks) DeferCleanup(teardown func(ctx context.Context) error, offset int) {
+	ginkgo.DeferCleanup(func(ctx ginkgo.SpecContext) error {
+		return teardown(ctx)
+	}, ginkgo.Offset(offset+1))
+}
+
+func (ginkgoHooks) Failed() bool {
+	return ginkgo.CurrentSpecReport().Failed()
+}
+
+func (ginkgoHooks) Location() (string, int) {
+	report := ginkgo.CurrentSpecReport()
+	return report.FileName(), report.LineNumber()
+}
+
+func (ginkgoHooks) AddReportEntry(name string, value any, visibility helpers.ReportEntryVisibility) {
+	args := []any{reportEntryVisibility(visibility)}
+	if value != nil {
+		args = append(args, value)
+	}
+	ginkgo.AddReportEntry(name, args...)
+}
+
+func (ginkgoHooks) Fail(message string, offset int) {
+	ginkgo.Fail(message, offset+1)
+}
+
+func reportEntryVisibility(visibility helpers.ReportEntryVisibility) ginkgo.ReportEntryVisibility {
+	switch visibility {
+	case helpers.ReportEntryVisibilityFailureOrVerbose:
+		return ginkgo.ReportEntryVisibilityFailureOrVerbose
+	case helpers.ReportEntryVisibilityNever:
+		return ginkgo.ReportEntryVisibilityNever
+	}
+	return ginkgo.ReportEntryVisibilityAlways
+}
+
+// canDeferCleanup returns true if ginkgo.DeferCleanup can be called, i.e. within a spec or a BeforeSuite but not within a cleanup or report node;
+// Ginkgo exits if it is called anywhere else.
+func canDeferCleanup() bool {
+	report := ginkgo.CurrentSpecReport()
+	return !report.StartTime.IsZero() && report.EndTime.IsZero() &&
+		report.LeafNodeType.Is(types.NodeTypeIt|types.NodeTypeBeforeSuite|types.NodeTypeSynchronizedBeforeSuite)
+}
@@ -21,28 +21,28 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
 
-	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/suite"
 )
 
 var _ = Describe("Provision k3s cluster and Rancher", Label("install"), func() {
 	k := kubectl.New()
 
-	It("Install upstream k3s cluster", func() {
+	It("Install upstream k3s cluster", func(specCtx SpecContext) {
 		By("Installing K3S", func() {
-			helpers.InstallK3S(k, k3sVersion, proxy, proxyHost)
+			suite.InstallK3S(specCtx, k, k3sVersion, proxy, proxyHost)
 		})
 
 		By("Installing CertManager", func() {
-			helpers.InstallCertManager(k, proxy, proxyHost)
+			suite.InstallCertManager(specCtx, k, proxy, proxyHost)
 		})
 
-		if skipInstallRancher != "true" {
+		if !skipInstallRancher {
 			By("Installing Rancher Manager", func() {
-				helpers.InstallRancherManager(k, rancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, proxy, nightlyChart)
+				suite.InstallRancherManager(specCtx, k, rancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, proxy, nightlyChart)
 			})
 
 			By("Checking Rancher Deployments", func() {
-				helpers.CheckRancherDeployments(k)
+				suite.CheckRancherDeployments(specCtx, k)
 			})
 		} else {
 			GinkgoLogr.Info("Skipping Rancher Manager installation; SKIP_RANCHER_INSTALL=\"true\"")
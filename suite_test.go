@@ -15,7 +15,6 @@ limitations under the License.
 package e2e_test
 
 import (
-	"os"
 	"strings"
 	"testing"
 	"time"
@@ -24,6 +23,8 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 )
 
 var (
@@ -37,7 +38,7 @@ var (
 	providerOperator   string
 	kubeConfig         string
 	k3sVersion         string
-	skipInstallRancher string
+	skipInstallRancher bool
 )
 
 /**
@@ -68,22 +69,16 @@ func TestE2E(t *testing.T) {
 
 var _ = BeforeSuite(func() {
 	// Extract environment variables
-	rancherHostname = os.Getenv("RANCHER_HOSTNAME")
-	Expect(rancherHostname).ToNot(BeEmpty(), "RANCHER_HOSTNAME environment variable is required")
-	rancherVersion = os.Getenv("RANCHER_VERSION")
-	Expect(rancherVersion).ToNot(BeEmpty(), "RANCHER_VERSION environment variable is required")
-	kubeConfig = os.Getenv("KUBECONFIG")
-	Expect(kubeConfig).ToNot(BeEmpty(), "KUBECONFIG environment variable is required")
-	k3sVersion = os.Getenv("INSTALL_K3S_VERSION")
-	Expect(k3sVersion).ToNot(BeEmpty(), "INSTALL_K3S_VERSION environment variable is required")
-	proxy = os.Getenv("RANCHER_BEHIND_PROXY")
-	proxyHost = os.Getenv("PROXY_HOST")
-	if proxyHost == "" {
-		proxyHost = "172.17.0.1:3128"
-	}
-	nightlyChart = os.Getenv("NIGHTLY_CHART")
-	providerOperator = os.Getenv("PROVIDER")
-	skipInstallRancher = os.Getenv("SKIP_RANCHER_INSTALL")
+	Expect(helpers.Config.Validate(helpers.SuiteSetup)).To(Succeed())
+	rancherHostname = helpers.Config.Rancher.Hostname
+	rancherVersion = helpers.Config.Rancher.Version
+	kubeConfig = helpers.Config.Rancher.Kubeconfig
+	k3sVersion = helpers.Config.Install.K3sVersion
+	proxy = helpers.Config.Install.Proxy
+	proxyHost = helpers.Config.Install.ProxyHost
+	nightlyChart = helpers.Config.Install.NightlyChart
+	providerOperator = helpers.Config.Provider
+	skipInstallRancher = helpers.Config.Install.SkipRancherInstall
 
 	// Extract Rancher Manager channel/version to install
 	s := strings.Split(rancherVersion, "/")